@@ -75,7 +75,10 @@ type Account struct {
 	Address      *felt.Felt
 	publicKey    string
 	CairoVersion CairoVersion
-	ks           Keystore
+	// An optional policy checked before signing the transactions. If nil,
+	// the transactions are signed without any check.
+	Policy *SigningPolicy
+	ks     Keystore
 }
 
 // CairoVersion represents the version of Cairo used by the account contract.
@@ -0,0 +1,421 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var ErrInvalidCalldata = errors.New("invalid multicall calldata")
+
+// DecodedCall is a function call decoded from the calldata of an invoke
+// transaction, with its function name when it could be resolved.
+type DecodedCall struct {
+	// The address of the contract to invoke
+	ContractAddress *felt.Felt `json:"contract_address"`
+	// The name of the function to invoke. Empty if unknown.
+	FunctionName string `json:"function_name,omitempty"`
+	// The selector of the function to invoke
+	EntryPointSelector *felt.Felt `json:"entry_point_selector"`
+	// The parameters passed to the function
+	Calldata []*felt.Felt `json:"calldata"`
+}
+
+// FunctionCall returns the call as a rpc.FunctionCall.
+func (call *DecodedCall) FunctionCall() rpc.FunctionCall {
+	return rpc.FunctionCall{
+		ContractAddress:    call.ContractAddress,
+		EntryPointSelector: call.EntryPointSelector,
+		Calldata:           call.Calldata,
+	}
+}
+
+// DecodeCallDataCairo0 decodes calldata generated by FmtCallDataCairo0 back
+// into its function calls.
+//
+// Parameters:
+//   - calldata: the `__execute__` calldata in the Cairo 0 format
+//
+// Returns:
+//   - []rpc.FunctionCall: the decoded function calls
+//   - error: ErrInvalidCalldata if the calldata is not in the Cairo 0 format
+func DecodeCallDataCairo0(calldata []*felt.Felt) ([]rpc.FunctionCall, error) {
+	const callArrayItemLen = 4 // address, selector, data offset, data length
+
+	reader := calldataReader{data: calldata}
+	callsLen, err := reader.readLen()
+	if err != nil {
+		return nil, err
+	}
+
+	type callArrayItem struct {
+		address, selector *felt.Felt
+		offset, length    int
+	}
+	callArray := make([]callArrayItem, callsLen)
+	if callsLen*callArrayItemLen > len(calldata) {
+		return nil, fmt.Errorf("%w: call array is longer than the calldata", ErrInvalidCalldata)
+	}
+	for i := range callArray {
+		address, selector := reader.read(), reader.read()
+		offset, err := reader.readLen()
+		if err != nil {
+			return nil, err
+		}
+		length, err := reader.readLen()
+		if err != nil {
+			return nil, err
+		}
+		callArray[i] = callArrayItem{address, selector, offset, length}
+	}
+
+	totalLen, err := reader.readLen()
+	if err != nil {
+		return nil, err
+	}
+	data := reader.remaining()
+	if totalLen != len(data) {
+		return nil, fmt.Errorf(
+			"%w: expected %d calldata items, got %d",
+			ErrInvalidCalldata,
+			totalLen,
+			len(data),
+		)
+	}
+
+	calls := make([]rpc.FunctionCall, len(callArray))
+	expectedOffset := 0
+	for i, item := range callArray {
+		if item.offset != expectedOffset || item.offset+item.length > len(data) {
+			return nil, fmt.Errorf("%w: invalid data offset of call %d", ErrInvalidCalldata, i)
+		}
+		expectedOffset += item.length
+
+		calls[i] = rpc.FunctionCall{
+			ContractAddress:    item.address,
+			EntryPointSelector: item.selector,
+			Calldata:           data[item.offset : item.offset+item.length],
+		}
+	}
+	if expectedOffset != totalLen {
+		return nil, fmt.Errorf("%w: unused calldata items", ErrInvalidCalldata)
+	}
+
+	return calls, nil
+}
+
+// DecodeCallDataCairo2 decodes calldata generated by FmtCallDataCairo2 back
+// into its function calls.
+//
+// Parameters:
+//   - calldata: the `__execute__` calldata in the Cairo 2 format
+//
+// Returns:
+//   - []rpc.FunctionCall: the decoded function calls
+//   - error: ErrInvalidCalldata if the calldata is not in the Cairo 2 format
+func DecodeCallDataCairo2(calldata []*felt.Felt) ([]rpc.FunctionCall, error) {
+	const minCallLen = 3 // address, selector, data length
+
+	reader := calldataReader{data: calldata}
+	callsLen, err := reader.readLen()
+	if err != nil {
+		return nil, err
+	}
+	if callsLen*minCallLen > len(calldata) {
+		return nil, fmt.Errorf("%w: more calls than calldata items", ErrInvalidCalldata)
+	}
+
+	calls := make([]rpc.FunctionCall, callsLen)
+	for i := range calls {
+		address, selector := reader.read(), reader.read()
+		length, err := reader.readLen()
+		if err != nil {
+			return nil, err
+		}
+		data, err := reader.readN(length)
+		if err != nil {
+			return nil, err
+		}
+
+		calls[i] = rpc.FunctionCall{
+			ContractAddress:    address,
+			EntryPointSelector: selector,
+			Calldata:           data,
+		}
+	}
+	if len(reader.remaining()) != 0 {
+		return nil, fmt.Errorf("%w: unused calldata items", ErrInvalidCalldata)
+	}
+
+	return calls, nil
+}
+
+// DecodeCallData decodes multicall calldata back into its function calls,
+// detecting whether it is in the Cairo 0 or Cairo 2 format. The encoding given
+// as hint is tried first, so it is used when the calldata is valid in both formats.
+//
+// Parameters:
+//   - calldata: the `__execute__` calldata
+//   - hint: the Cairo version of the account that most likely generated the calldata
+//
+// Returns:
+//   - []rpc.FunctionCall: the decoded function calls
+//   - CairoVersion: the detected format of the calldata
+//   - error: ErrInvalidCalldata if the calldata is in none of the formats
+func DecodeCallData(
+	calldata []*felt.Felt,
+	hint CairoVersion,
+) ([]rpc.FunctionCall, CairoVersion, error) {
+	versions := []CairoVersion{CairoV2, CairoV0}
+	if hint == CairoV0 {
+		versions = []CairoVersion{CairoV0, CairoV2}
+	}
+
+	var errs []error
+	for _, version := range versions {
+		var calls []rpc.FunctionCall
+		var err error
+		if version == CairoV0 {
+			calls, err = DecodeCallDataCairo0(calldata)
+		} else {
+			calls, err = DecodeCallDataCairo2(calldata)
+		}
+		if err == nil {
+			return calls, version, nil
+		}
+		errs = append(errs, fmt.Errorf("cairo %d: %w", version, err))
+	}
+
+	return nil, hint, errors.Join(errs...)
+}
+
+// DecodeInvokeTxnCalls decodes the function calls of an invoke transaction.
+// The calldata format is detected automatically, v1 transactions being decoded
+// in the Cairo 0 format first and v3 transactions in the Cairo 2 format first.
+//
+// Parameters:
+//   - txn: the invoke transaction, of type rpc.InvokeTxnV0, rpc.InvokeTxnV1 or
+//     rpc.InvokeTxnV3 (or a pointer to them)
+//   - names: used to resolve the function names of the calls. Can be nil.
+//
+// Returns:
+//   - []DecodedCall: the decoded function calls
+//   - error: an error if the transaction type is unsupported or the calldata is invalid
+func DecodeInvokeTxnCalls(txn rpc.InvokeTxnType, names SelectorNames) ([]DecodedCall, error) {
+	var calls []rpc.FunctionCall
+	var err error
+
+	switch tx := txn.(type) {
+	case rpc.InvokeTxnV0:
+		calls = []rpc.FunctionCall{tx.FunctionCall}
+	case *rpc.InvokeTxnV0:
+		calls = []rpc.FunctionCall{tx.FunctionCall}
+	case rpc.InvokeTxnV1:
+		calls, _, err = DecodeCallData(tx.Calldata, CairoV0)
+	case *rpc.InvokeTxnV1:
+		calls, _, err = DecodeCallData(tx.Calldata, CairoV0)
+	case rpc.InvokeTxnV3:
+		calls, _, err = DecodeCallData(tx.Calldata, CairoV2)
+	case *rpc.InvokeTxnV3:
+		calls, _, err = DecodeCallData(tx.Calldata, CairoV2)
+	default:
+		return nil, fmt.Errorf(
+			"%w: got '%T' instead of a valid invoke txn type",
+			ErrTxnTypeUnSupported,
+			txn,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return names.Resolve(calls), nil
+}
+
+// SelectorNames maps entry point selectors to their function names.
+type SelectorNames map[felt.Felt]string
+
+// commonFunctionNames are the names of functions commonly called on Starknet,
+// used by CommonSelectorNames.
+var commonFunctionNames = []string{
+	// ERC20
+	"transfer", "transfer_from", "transferFrom", "approve",
+	"increase_allowance", "increaseAllowance", "decrease_allowance", "decreaseAllowance",
+	"mint", "burn",
+	// ERC721 and ERC1155
+	"safe_transfer_from", "safeTransferFrom", "set_approval_for_all", "setApprovalForAll",
+	"safe_batch_transfer_from", "safeBatchTransferFrom",
+	// accounts and upgrades
+	"__execute__", "__validate__", "upgrade", "set_public_key", "setPublicKey",
+	"change_owner", "add_signers", "remove_signers", "change_threshold",
+	// UDC
+	"deployContract", "deploy_contract",
+	// DeFi
+	"swap", "multi_route_swap", "multihop_swap", "deposit", "withdraw", "claim", "stake",
+	"unstake", "add_liquidity", "remove_liquidity", "lock", "unlock",
+}
+
+// CommonSelectorNames returns a dictionary of the selectors of functions commonly
+// called on Starknet, such as the ERC20 and ERC721 ones.
+func CommonSelectorNames() SelectorNames {
+	return NewSelectorNames(commonFunctionNames...)
+}
+
+// NewSelectorNames returns a dictionary of the selectors of the given function names.
+func NewSelectorNames(functionNames ...string) SelectorNames {
+	names := make(SelectorNames, len(functionNames))
+	names.Add(functionNames...)
+
+	return names
+}
+
+// SelectorNamesFromABI returns a dictionary of the selectors of the functions
+// of a Cairo 0 contract ABI.
+func SelectorNamesFromABI(abi contracts.ABI) SelectorNames {
+	names := make(SelectorNames)
+	for _, entry := range abi {
+		if function, ok := entry.(*contracts.FunctionABIEntry); ok {
+			names.Add(function.Name)
+		}
+	}
+
+	return names
+}
+
+// SelectorNamesFromSierraABI returns a dictionary of the selectors of the
+// functions of a Sierra contract ABI, including the ones declared in interfaces.
+//
+// Parameters:
+//   - abi: the ABI of a contracts.ContractClass
+//
+// Returns:
+//   - SelectorNames: the dictionary of the ABI functions
+//   - error: an error if the ABI is not valid JSON
+func SelectorNamesFromSierraABI(abi contracts.NestedString) (SelectorNames, error) {
+	type abiEntry struct {
+		Type  string     `json:"type"`
+		Name  string     `json:"name"`
+		Items []abiEntry `json:"items"`
+	}
+
+	var entries []abiEntry
+	if err := json.Unmarshal([]byte(abi), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse the Sierra ABI: %w", err)
+	}
+
+	names := make(SelectorNames)
+	var addEntries func(entries []abiEntry)
+	addEntries = func(entries []abiEntry) {
+		for _, entry := range entries {
+			switch entry.Type {
+			case "function", "l1_handler", "constructor":
+				names.Add(entry.Name)
+			case "interface":
+				addEntries(entry.Items)
+			}
+		}
+	}
+	addEntries(entries)
+
+	return names, nil
+}
+
+// Add adds the selectors of the given function names to the dictionary.
+func (names SelectorNames) Add(functionNames ...string) {
+	for _, name := range functionNames {
+		names[*utils.GetSelectorFromNameFelt(name)] = name
+	}
+}
+
+// Merge adds all the selectors of the other dictionary to this one.
+func (names SelectorNames) Merge(other SelectorNames) {
+	for selector, name := range other {
+		names[selector] = name
+	}
+}
+
+// Name returns the function name of the given selector, and whether it was found.
+func (names SelectorNames) Name(selector *felt.Felt) (string, bool) {
+	if selector == nil {
+		return "", false
+	}
+	name, ok := names[*selector]
+
+	return name, ok
+}
+
+// Resolve converts the function calls to DecodedCalls, resolving their function
+// names with the dictionary. The name is left empty when the selector is unknown.
+// It can be called on a nil dictionary, in which case no name is resolved.
+func (names SelectorNames) Resolve(calls []rpc.FunctionCall) []DecodedCall {
+	decoded := make([]DecodedCall, len(calls))
+	for i, call := range calls {
+		name, _ := names.Name(call.EntryPointSelector)
+		decoded[i] = DecodedCall{
+			ContractAddress:    call.ContractAddress,
+			FunctionName:       name,
+			EntryPointSelector: call.EntryPointSelector,
+			Calldata:           call.Calldata,
+		}
+	}
+
+	return decoded
+}
+
+// calldataReader reads calldata items sequentially. Reading past the end of the
+// calldata returns nil items, which are caught by the length checks.
+type calldataReader struct {
+	data []*felt.Felt
+	pos  int
+}
+
+// read returns the next item, or nil if there is none.
+func (r *calldataReader) read() *felt.Felt {
+	if r.pos >= len(r.data) {
+		r.pos++
+
+		return nil
+	}
+	item := r.data[r.pos]
+	r.pos++
+
+	return item
+}
+
+// readLen reads the next item as a length, which can't exceed the calldata length.
+func (r *calldataReader) readLen() (int, error) {
+	item := r.read()
+	if item == nil {
+		return 0, fmt.Errorf("%w: unexpected end of calldata", ErrInvalidCalldata)
+	}
+	if item.Cmp(new(felt.Felt).SetUint64(uint64(len(r.data)))) > 0 {
+		return 0, fmt.Errorf("%w: length %s exceeds the calldata length", ErrInvalidCalldata, item)
+	}
+
+	return int(item.Uint64()), nil
+}
+
+// readN returns the next n items.
+func (r *calldataReader) readN(n int) ([]*felt.Felt, error) {
+	if r.pos+n > len(r.data) {
+		return nil, fmt.Errorf("%w: unexpected end of calldata", ErrInvalidCalldata)
+	}
+	items := r.data[r.pos : r.pos+n]
+	r.pos += n
+
+	return items, nil
+}
+
+// remaining returns the items not read yet.
+func (r *calldataReader) remaining() []*felt.Felt {
+	if r.pos >= len(r.data) {
+		return []*felt.Felt{}
+	}
+
+	return r.data[r.pos:]
+}
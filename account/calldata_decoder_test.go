@@ -0,0 +1,196 @@
+package account_test
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/internal/tests"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecodeCallData tests that DecodeCallData is the inverse of the
+// FmtCallDataCairo0 and FmtCallDataCairo2 functions.
+func TestDecodeCallData(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	fnCalls := []rpc.FunctionCall{
+		{
+			ContractAddress:    internalUtils.TestHexToFelt(t, "0x1234"),
+			EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+			Calldata: []*felt.Felt{
+				internalUtils.DeadBeef,
+				new(felt.Felt).SetUint64(100),
+				new(felt.Felt),
+			},
+		},
+		{
+			ContractAddress:    internalUtils.TestHexToFelt(t, "0x5678"),
+			EntryPointSelector: utils.GetSelectorFromNameFelt("get_balance"),
+			Calldata:           []*felt.Felt{},
+		},
+		{
+			ContractAddress:    internalUtils.TestHexToFelt(t, "0x9abc"),
+			EntryPointSelector: utils.GetSelectorFromNameFelt("approve"),
+			Calldata:           []*felt.Felt{internalUtils.DeadBeef, new(felt.Felt).SetUint64(5)},
+		},
+	}
+
+	testSet := []struct {
+		name     string
+		calldata []*felt.Felt
+		hint     account.CairoVersion
+		expected account.CairoVersion
+	}{
+		{
+			name:     "Cairo 2 calldata",
+			calldata: account.FmtCallDataCairo2(fnCalls),
+			hint:     account.CairoV2,
+			expected: account.CairoV2,
+		},
+		{
+			name:     "Cairo 2 calldata, wrong hint",
+			calldata: account.FmtCallDataCairo2(fnCalls),
+			hint:     account.CairoV0,
+			expected: account.CairoV2,
+		},
+		{
+			name:     "Cairo 0 calldata",
+			calldata: account.FmtCallDataCairo0(fnCalls),
+			hint:     account.CairoV0,
+			expected: account.CairoV0,
+		},
+		{
+			name:     "Cairo 0 calldata, wrong hint",
+			calldata: account.FmtCallDataCairo0(fnCalls),
+			hint:     account.CairoV2,
+			expected: account.CairoV0,
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.name, func(t *testing.T) {
+			calls, version, err := account.DecodeCallData(test.calldata, test.hint)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, version)
+			assert.Equal(t, fnCalls, calls)
+		})
+	}
+
+	t.Run("explicit decoders", func(t *testing.T) {
+		calls, err := account.DecodeCallDataCairo0(account.FmtCallDataCairo0(fnCalls))
+		require.NoError(t, err)
+		assert.Equal(t, fnCalls, calls)
+
+		calls, err = account.DecodeCallDataCairo2(account.FmtCallDataCairo2(fnCalls))
+		require.NoError(t, err)
+		assert.Equal(t, fnCalls, calls)
+
+		_, err = account.DecodeCallDataCairo2(account.FmtCallDataCairo0(fnCalls))
+		require.ErrorIs(t, err, account.ErrInvalidCalldata)
+		_, err = account.DecodeCallDataCairo0(account.FmtCallDataCairo2(fnCalls))
+		require.ErrorIs(t, err, account.ErrInvalidCalldata)
+	})
+
+	t.Run("invalid calldata", func(t *testing.T) {
+		feltMax := new(felt.Felt).Sub(new(felt.Felt), new(felt.Felt).SetUint64(1))
+		invalidSet := map[string][]*felt.Felt{
+			"empty":              {},
+			"huge calls length":  {internalUtils.DeadBeef},
+			"truncated":          account.FmtCallDataCairo2(fnCalls)[:6],
+			"extra items":        append(account.FmtCallDataCairo2(fnCalls), new(felt.Felt)),
+			"felt max as length": {feltMax, new(felt.Felt)},
+		}
+
+		for name, calldata := range invalidSet {
+			t.Run(name, func(t *testing.T) {
+				_, _, err := account.DecodeCallData(calldata, account.CairoV2)
+				require.ErrorIs(t, err, account.ErrInvalidCalldata)
+			})
+		}
+	})
+}
+
+// TestDecodeInvokeTxnCalls tests decoding the calls of invoke transactions and
+// resolving their function names.
+func TestDecodeInvokeTxnCalls(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	sierraClass := internalUtils.TestUnmarshalJSONFileToType[contracts.ContractClass](
+		t,
+		"./testData/contracts_v2_HelloStarknet.sierra.json",
+	)
+	helloNames, err := account.SelectorNamesFromSierraABI(sierraClass.ABI)
+	require.NoError(t, err)
+	_, ok := helloNames.Name(utils.GetSelectorFromNameFelt("increase_balance"))
+	assert.True(t, ok)
+
+	names := account.CommonSelectorNames()
+	names.Merge(helloNames)
+
+	fnCalls := []rpc.FunctionCall{
+		{
+			ContractAddress:    internalUtils.DeadBeef,
+			EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+			Calldata:           []*felt.Felt{internalUtils.DeadBeef, new(felt.Felt).SetUint64(1)},
+		},
+		{
+			ContractAddress:    internalUtils.DeadBeef,
+			EntryPointSelector: utils.GetSelectorFromNameFelt("increase_balance"),
+			Calldata:           []*felt.Felt{new(felt.Felt).SetUint64(2)},
+		},
+		{
+			ContractAddress:    internalUtils.DeadBeef,
+			EntryPointSelector: utils.GetSelectorFromNameFelt("unknown_function"),
+			Calldata:           []*felt.Felt{},
+		},
+	}
+	expectedNames := []string{"transfer", "increase_balance", ""}
+
+	testSet := []struct {
+		name string
+		txn  rpc.InvokeTxnType
+	}{
+		{
+			name: "invoke v3",
+			txn:  &rpc.InvokeTxnV3{Calldata: account.FmtCallDataCairo2(fnCalls)},
+		},
+		{
+			name: "invoke v1, Cairo 0 account",
+			txn:  rpc.InvokeTxnV1{Calldata: account.FmtCallDataCairo0(fnCalls)},
+		},
+		{
+			name: "invoke v1, Cairo 2 account",
+			txn:  &rpc.InvokeTxnV1{Calldata: account.FmtCallDataCairo2(fnCalls)},
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.name, func(t *testing.T) {
+			calls, err := account.DecodeInvokeTxnCalls(test.txn, names)
+			require.NoError(t, err)
+			require.Len(t, calls, len(fnCalls))
+
+			for i, call := range calls {
+				assert.Equal(t, expectedNames[i], call.FunctionName)
+				assert.Equal(t, fnCalls[i], call.FunctionCall())
+			}
+		})
+	}
+
+	t.Run("invoke v0", func(t *testing.T) {
+		calls, err := account.DecodeInvokeTxnCalls(
+			rpc.InvokeTxnV0{FunctionCall: fnCalls[0]},
+			nil,
+		)
+		require.NoError(t, err)
+		require.Len(t, calls, 1)
+		assert.Empty(t, calls[0].FunctionName)
+		assert.Equal(t, fnCalls[0], calls[0].FunctionCall())
+	})
+}
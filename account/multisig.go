@@ -0,0 +1,449 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+var (
+	ErrMultisigInvalidThreshold   = errors.New("invalid multisig threshold")
+	ErrMultisigDuplicateSigner    = errors.New("duplicate multisig signer")
+	ErrMultisigUnknownSigner      = errors.New("signer is not part of the multisig")
+	ErrMultisigDuplicateSignature = errors.New("signer has already signed the transaction")
+	ErrMultisigInvalidSignature   = errors.New("invalid signature for the transaction hash")
+	ErrMultisigThresholdNotMet    = errors.New("multisig threshold not met")
+	ErrMultisigHashMismatch       = errors.New("transaction hash does not match the transaction")
+	ErrMultisigDirectSigning      = errors.New(
+		"a multisig account cannot sign on its own, collect the signatures with a MultisigTxn",
+	)
+)
+
+// starknetSignerGUIDPrefix is the short string used by the Argent multisig to
+// compute the GUID of a Starknet signer.
+var starknetSignerGUIDPrefix = new(felt.Felt).SetBytes([]byte("Starknet Signer"))
+
+// MultisigSignatureFormat represents how the signatures of the signers are
+// serialised into the transaction signature expected by the multisig contract.
+type MultisigSignatureFormat int
+
+// Multisig signature format constants
+const (
+	// MultisigFormatSignerSignature is the `Array<SignerSignature>` format used by
+	// the Argent multisig (v0.2.0 and later): the number of signatures, followed by
+	// `[0 (Starknet signer variant), public key, r, s]` for each signer, sorted by
+	// signer GUID.
+	MultisigFormatSignerSignature MultisigSignatureFormat = iota
+	// MultisigFormatConcatenated is the `[public key, r, s]` format repeated for
+	// each signer and sorted by public key, used by the OpenZeppelin and legacy
+	// Argent multisig accounts.
+	MultisigFormatConcatenated
+)
+
+// MultisigAccount is an account controlled by several Starknet signers, of
+// which Threshold must sign a transaction for it to be valid.
+//
+// The embedded Account is used to compute the nonce, format the calldata,
+// hash and send transactions. Its signing methods are not usable, since no
+// single key controls the account: signatures are gathered with a MultisigTxn.
+type MultisigAccount struct {
+	*Account
+	// The public keys of the signers of the multisig
+	Signers []*felt.Felt
+	// The number of signatures required to execute a transaction
+	Threshold int
+	// The signature format expected by the multisig contract
+	SignatureFormat MultisigSignatureFormat
+}
+
+// NewMultisigAccount creates a new MultisigAccount instance.
+//
+// Parameters:
+//   - provider: the provider to use
+//   - accountAddress: the multisig account address
+//   - signers: the public keys of the signers of the multisig
+//   - threshold: the number of signatures required to execute a transaction
+//   - format: the signature format expected by the multisig contract
+//   - cairoVersion: the cairo version of the account (CairoV0 or CairoV2)
+//
+// Returns:
+//   - *MultisigAccount: a pointer to the newly created MultisigAccount
+//   - error: an error if any
+func NewMultisigAccount(
+	provider rpc.RPCProvider,
+	accountAddress *felt.Felt,
+	signers []*felt.Felt,
+	threshold int,
+	format MultisigSignatureFormat,
+	cairoVersion CairoVersion,
+) (*MultisigAccount, error) {
+	if threshold <= 0 || threshold > len(signers) {
+		return nil, fmt.Errorf(
+			"%w: %d for %d signers",
+			ErrMultisigInvalidThreshold,
+			threshold,
+			len(signers),
+		)
+	}
+	for i, signer := range signers {
+		if slices.ContainsFunc(signers[:i], signer.Equal) {
+			return nil, fmt.Errorf("%w: %s", ErrMultisigDuplicateSigner, signer)
+		}
+	}
+
+	acc, err := NewAccount(provider, accountAddress, "", multisigKeystore{}, cairoVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MultisigAccount{
+		Account:         acc,
+		Signers:         slices.Clone(signers),
+		Threshold:       threshold,
+		SignatureFormat: format,
+	}, nil
+}
+
+// BuildInvokeTxn builds a v3 invoke transaction with the given function calls,
+// ready to be signed by the multisig signers. It automatically calculates the
+// nonce, formats the calldata and estimates the fees. Since no signature is
+// available yet, the fee is estimated with the `SKIP_VALIDATE` simulation flag,
+// so the fee multiplier should account for the cost of the signature validation.
+//
+// Parameters:
+//   - ctx: The context.Context for the request.
+//   - functionCalls: A slice of rpc.InvokeFunctionCall representing the function
+//     calls for the transaction.
+//   - opts: options for building/estimating the transaction. Pass `nil` to use
+//     default values.
+//
+// Returns:
+//   - *MultisigTxn: the transaction waiting for the signers' signatures
+//   - error: An error if the transaction building fails.
+func (multisig *MultisigAccount) BuildInvokeTxn(
+	ctx context.Context,
+	functionCalls []rpc.InvokeFunctionCall,
+	opts *TxnOptions,
+) (*MultisigTxn, error) {
+	broadcastInvokeTxnV3, err := multisig.buildUnsignedInvokeTxn(ctx, functionCalls, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return multisig.NewMultisigTxn(broadcastInvokeTxnV3)
+}
+
+// NewMultisigTxn wraps an already built invoke transaction of the multisig
+// account, computing its hash once so that the signers' signatures can be
+// collected. Any signature already set in the transaction is ignored.
+//
+// Parameters:
+//   - txn: the invoke transaction to be signed
+//
+// Returns:
+//   - *MultisigTxn: the transaction waiting for the signers' signatures
+//   - error: an error if the transaction hash can't be computed
+func (multisig *MultisigAccount) NewMultisigTxn(
+	txn *rpc.BroadcastInvokeTxnV3,
+) (*MultisigTxn, error) {
+	txnHash, err := multisig.TransactionHashInvoke(txn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MultisigTxn{
+		Transaction:     txn,
+		TransactionHash: txnHash,
+		Signatures:      []MultisigSignature{},
+		multisig:        multisig,
+	}, nil
+}
+
+// MultisigSignature is the signature of a transaction hash by one of the
+// multisig signers.
+type MultisigSignature struct {
+	// The public key of the signer
+	Signer *felt.Felt `json:"signer"`
+	R      *felt.Felt `json:"r"`
+	S      *felt.Felt `json:"s"`
+}
+
+// MultisigSigningRequest contains everything a signer needs to check and sign
+// a multisig transaction. It can be serialised to JSON and sent to the signers.
+type MultisigSigningRequest struct {
+	AccountAddress  *felt.Felt                `json:"account_address"`
+	ChainID         *felt.Felt                `json:"chain_id"`
+	TransactionHash *felt.Felt                `json:"transaction_hash"`
+	Signers         []*felt.Felt              `json:"signers"`
+	Threshold       int                       `json:"threshold"`
+	Transaction     *rpc.BroadcastInvokeTxnV3 `json:"transaction"`
+}
+
+// Verify checks that the transaction hash of the request matches its transaction
+// and chain ID, so that a signer doesn't blindly sign a hash.
+//
+// Returns:
+//   - error: ErrMultisigHashMismatch if the hash doesn't match, or an error if
+//     the hash can't be computed
+func (req *MultisigSigningRequest) Verify() error {
+	acc := &Account{ChainID: req.ChainID, Address: req.AccountAddress}
+	txnHash, err := acc.TransactionHashInvoke(req.Transaction)
+	if err != nil {
+		return err
+	}
+	if !txnHash.Equal(req.TransactionHash) {
+		return fmt.Errorf(
+			"%w: expected %s, got %s",
+			ErrMultisigHashMismatch,
+			txnHash,
+			req.TransactionHash,
+		)
+	}
+
+	return nil
+}
+
+// Sign verifies the request and signs its transaction hash with the given
+// keystore, using the signer public key as the keystore id.
+//
+// Parameters:
+//   - ctx: the context used for the signing operation
+//   - ks: the keystore holding the signer private key
+//   - signer: the public key of the signer
+//
+// Returns:
+//   - MultisigSignature: the signature of the signer
+//   - error: an error if any
+func (req *MultisigSigningRequest) Sign(
+	ctx context.Context,
+	ks Keystore,
+	signer *felt.Felt,
+) (MultisigSignature, error) {
+	if !slices.ContainsFunc(req.Signers, signer.Equal) {
+		return MultisigSignature{}, fmt.Errorf("%w: %s", ErrMultisigUnknownSigner, signer)
+	}
+	if err := req.Verify(); err != nil {
+		return MultisigSignature{}, err
+	}
+
+	return signWithKeystore(ctx, ks, signer, req.TransactionHash)
+}
+
+// MultisigTxn is a multisig invoke transaction whose signatures are being
+// collected. It is not safe for concurrent use.
+type MultisigTxn struct {
+	Transaction     *rpc.BroadcastInvokeTxnV3
+	TransactionHash *felt.Felt
+	Signatures      []MultisigSignature
+
+	multisig *MultisigAccount
+}
+
+// SigningRequest returns the request to be sent to the signers of the multisig.
+func (txn *MultisigTxn) SigningRequest() *MultisigSigningRequest {
+	return &MultisigSigningRequest{
+		AccountAddress:  txn.multisig.Address,
+		ChainID:         txn.multisig.ChainID,
+		TransactionHash: txn.TransactionHash,
+		Signers:         slices.Clone(txn.multisig.Signers),
+		Threshold:       txn.multisig.Threshold,
+		Transaction:     txn.Transaction,
+	}
+}
+
+// AddSignature adds the signature of one of the multisig signers, after
+// checking that it is a valid signature of the transaction hash.
+//
+// Parameters:
+//   - sig: the signature to add
+//
+// Returns:
+//   - error: an error if the signer is unknown, has already signed, or the
+//     signature is invalid
+func (txn *MultisigTxn) AddSignature(sig MultisigSignature) error {
+	if sig.Signer == nil || sig.R == nil || sig.S == nil {
+		return fmt.Errorf("%w: missing signer, r or s", ErrMultisigInvalidSignature)
+	}
+	if !slices.ContainsFunc(txn.multisig.Signers, sig.Signer.Equal) {
+		return fmt.Errorf("%w: %s", ErrMultisigUnknownSigner, sig.Signer)
+	}
+	if slices.ContainsFunc(txn.Signatures, func(s MultisigSignature) bool {
+		return s.Signer.Equal(sig.Signer)
+	}) {
+		return fmt.Errorf("%w: %s", ErrMultisigDuplicateSignature, sig.Signer)
+	}
+
+	valid, err := curve.VerifyFelts(txn.TransactionHash, sig.R, sig.S, sig.Signer)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMultisigInvalidSignature, err)
+	}
+	if !valid {
+		return fmt.Errorf("%w: signer %s", ErrMultisigInvalidSignature, sig.Signer)
+	}
+
+	txn.Signatures = append(txn.Signatures, sig)
+
+	return nil
+}
+
+// SignWith signs the transaction hash with the given keystore, using the signer
+// public key as the keystore id, and adds the resulting signature.
+//
+// Parameters:
+//   - ctx: the context used for the signing operation
+//   - ks: the keystore holding the signer private key
+//   - signer: the public key of the signer
+//
+// Returns:
+//   - error: an error if any
+func (txn *MultisigTxn) SignWith(ctx context.Context, ks Keystore, signer *felt.Felt) error {
+	if !slices.ContainsFunc(txn.multisig.Signers, signer.Equal) {
+		return fmt.Errorf("%w: %s", ErrMultisigUnknownSigner, signer)
+	}
+
+	sig, err := signWithKeystore(ctx, ks, signer, txn.TransactionHash)
+	if err != nil {
+		return err
+	}
+
+	return txn.AddSignature(sig)
+}
+
+// MissingSignatures returns the number of signatures still required to meet
+// the multisig threshold.
+func (txn *MultisigTxn) MissingSignatures() int {
+	return max(txn.multisig.Threshold-len(txn.Signatures), 0)
+}
+
+// Signature returns the combined signature of the transaction, serialised in
+// the format expected by the multisig contract. Only the first Threshold
+// signatures collected are used.
+//
+// Returns:
+//   - []*felt.Felt: the combined signature
+//   - error: ErrMultisigThresholdNotMet if not enough signatures were collected
+func (txn *MultisigTxn) Signature() ([]*felt.Felt, error) {
+	if missing := txn.MissingSignatures(); missing > 0 {
+		return nil, fmt.Errorf(
+			"%w: %d of %d signatures collected",
+			ErrMultisigThresholdNotMet,
+			len(txn.Signatures),
+			txn.multisig.Threshold,
+		)
+	}
+
+	sigs := slices.Clone(txn.Signatures[:txn.multisig.Threshold])
+
+	switch txn.multisig.SignatureFormat {
+	case MultisigFormatSignerSignature:
+		slices.SortFunc(sigs, func(a, b MultisigSignature) int {
+			return starknetSignerGUID(a.Signer).Cmp(starknetSignerGUID(b.Signer))
+		})
+
+		//nolint:mnd // length prefix, and variant, signer, r and s for each signature
+		signature := make([]*felt.Felt, 0, 1+len(sigs)*4)
+		signature = append(signature, new(felt.Felt).SetUint64(uint64(len(sigs))))
+		for _, sig := range sigs {
+			// 0 is the index of the Starknet variant of the Signer enum
+			signature = append(signature, new(felt.Felt), sig.Signer, sig.R, sig.S)
+		}
+
+		return signature, nil
+	case MultisigFormatConcatenated:
+		slices.SortFunc(sigs, func(a, b MultisigSignature) int {
+			return a.Signer.Cmp(b.Signer)
+		})
+
+		//nolint:mnd // signer, r and s for each signature
+		signature := make([]*felt.Felt, 0, len(sigs)*3)
+		for _, sig := range sigs {
+			signature = append(signature, sig.Signer, sig.R, sig.S)
+		}
+
+		return signature, nil
+	default:
+		return nil, fmt.Errorf(
+			"unsupported multisig signature format %d",
+			txn.multisig.SignatureFormat,
+		)
+	}
+}
+
+// Send sets the combined signature of the transaction and sends it. The
+// transaction hash is computed again to make sure the transaction was not
+// modified after being signed.
+//
+// Parameters:
+//   - ctx: The context.Context for the request.
+//
+// Returns:
+//   - rpc.AddInvokeTransactionResponse: the response of the submitted transaction.
+//   - error: an error if the threshold is not met or the transaction fails to be sent
+func (txn *MultisigTxn) Send(ctx context.Context) (rpc.AddInvokeTransactionResponse, error) {
+	var response rpc.AddInvokeTransactionResponse
+
+	txnHash, err := txn.multisig.TransactionHashInvoke(txn.Transaction)
+	if err != nil {
+		return response, err
+	}
+	if !txnHash.Equal(txn.TransactionHash) {
+		return response, fmt.Errorf(
+			"%w: expected %s, got %s",
+			ErrMultisigHashMismatch,
+			txnHash,
+			txn.TransactionHash,
+		)
+	}
+
+	signature, err := txn.Signature()
+	if err != nil {
+		return response, err
+	}
+	txn.Transaction.Signature = signature
+
+	return txn.multisig.Provider.AddInvokeTransaction(ctx, txn.Transaction)
+}
+
+// starknetSignerGUID returns the GUID of a Starknet signer, as computed by the
+// Argent multisig: poseidon('Starknet Signer', pubkey).
+func starknetSignerGUID(pubKey *felt.Felt) *felt.Felt {
+	return curve.PoseidonArray(starknetSignerGUIDPrefix, pubKey)
+}
+
+// signWithKeystore signs the message hash with the key of the given signer.
+func signWithKeystore(
+	ctx context.Context,
+	ks Keystore,
+	signer, msgHash *felt.Felt,
+) (MultisigSignature, error) {
+	r, s, err := ks.Sign(ctx, signer.String(), internalUtils.FeltToBigInt(msgHash))
+	if err != nil {
+		return MultisigSignature{}, err
+	}
+
+	return MultisigSignature{
+		Signer: signer,
+		R:      internalUtils.BigIntToFelt(r),
+		S:      internalUtils.BigIntToFelt(s),
+	}, nil
+}
+
+// multisigKeystore is the keystore of the account embedded in a MultisigAccount,
+// which refuses to sign since the signatures must be collected from the signers.
+type multisigKeystore struct{}
+
+// Sign always returns ErrMultisigDirectSigning.
+func (multisigKeystore) Sign(
+	ctx context.Context,
+	id string,
+	msgHash *big.Int,
+) (x, y *big.Int, err error) {
+	return nil, nil, ErrMultisigDirectSigning
+}
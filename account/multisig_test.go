@@ -0,0 +1,302 @@
+package account_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/internal/tests"
+	"github.com/NethermindEth/starknet.go/internal/tests/mocks/rpcv10mock"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// multisigSigner is a multisig signer along with its keystore.
+type multisigSigner struct {
+	ks     *account.MemKeystore
+	pubKey *felt.Felt
+}
+
+// newMultisigSigners generates n random multisig signers.
+func newMultisigSigners(n int) []multisigSigner {
+	signers := make([]multisigSigner, n)
+	for i := range signers {
+		ks, pubKey, _ := account.GetRandomKeys()
+		signers[i] = multisigSigner{ks: ks, pubKey: pubKey}
+	}
+
+	return signers
+}
+
+// TestNewMultisigAccount tests the validation of the multisig signers and threshold.
+func TestNewMultisigAccount(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	mockRPCProvider := rpcv10mock.NewMockRPCProvider(gomock.NewController(t))
+	mockRPCProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil).AnyTimes()
+
+	signers := newMultisigSigners(3)
+	pubKeys := []*felt.Felt{signers[0].pubKey, signers[1].pubKey, signers[2].pubKey}
+
+	testSet := []struct {
+		name        string
+		signers     []*felt.Felt
+		threshold   int
+		expectedErr error
+	}{
+		{
+			name:      "valid 2 of 3",
+			signers:   pubKeys,
+			threshold: 2,
+		},
+		{
+			name:        "zero threshold",
+			signers:     pubKeys,
+			threshold:   0,
+			expectedErr: account.ErrMultisigInvalidThreshold,
+		},
+		{
+			name:        "threshold above the number of signers",
+			signers:     pubKeys,
+			threshold:   4,
+			expectedErr: account.ErrMultisigInvalidThreshold,
+		},
+		{
+			name:        "duplicate signer",
+			signers:     []*felt.Felt{pubKeys[0], pubKeys[1], pubKeys[0]},
+			threshold:   2,
+			expectedErr: account.ErrMultisigDuplicateSigner,
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.name, func(t *testing.T) {
+			multisig, err := account.NewMultisigAccount(
+				mockRPCProvider,
+				internalUtils.DeadBeef,
+				test.signers,
+				test.threshold,
+				account.MultisigFormatSignerSignature,
+				account.CairoV2,
+			)
+			if test.expectedErr != nil {
+				require.ErrorIs(t, err, test.expectedErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.threshold, multisig.Threshold)
+
+			// the multisig can't sign on its own
+			_, err = multisig.Sign(t.Context(), internalUtils.DeadBeef)
+			require.ErrorIs(t, err, account.ErrMultisigDirectSigning)
+		})
+	}
+}
+
+// TestMultisigTxn tests building a multisig invoke transaction, collecting the
+// signatures of the signers and sending it with the combined signature.
+func TestMultisigTxn(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	signers := newMultisigSigners(3)
+	pubKeys := []*felt.Felt{signers[0].pubKey, signers[1].pubKey, signers[2].pubKey}
+	starknetSignerPrefix := new(felt.Felt).SetBytes([]byte("Starknet Signer"))
+
+	testSet := []struct {
+		name   string
+		format account.MultisigSignatureFormat
+		// asserts the combined signature, made by the given signers
+		assertSignature func(t *testing.T, signature []*felt.Felt, signedBy []*felt.Felt)
+	}{
+		{
+			name:   "signer signature format",
+			format: account.MultisigFormatSignerSignature,
+			assertSignature: func(t *testing.T, signature []*felt.Felt, signedBy []*felt.Felt) {
+				require.Len(t, signature, 1+len(signedBy)*4)
+				assert.Equal(t, uint64(len(signedBy)), signature[0].Uint64())
+
+				var prevGUID *felt.Felt
+				for i := 1; i < len(signature); i += 4 {
+					assert.True(t, signature[i].IsZero(), "expected the Starknet signer variant")
+					assert.Contains(t, signedBy, signature[i+1])
+
+					guid := curve.PoseidonArray(starknetSignerPrefix, signature[i+1])
+					if prevGUID != nil {
+						assert.Equal(t, 1, guid.Cmp(prevGUID), "signatures not sorted by GUID")
+					}
+					prevGUID = guid
+				}
+			},
+		},
+		{
+			name:   "concatenated format",
+			format: account.MultisigFormatConcatenated,
+			assertSignature: func(t *testing.T, signature []*felt.Felt, signedBy []*felt.Felt) {
+				require.Len(t, signature, len(signedBy)*3)
+
+				for i := 0; i < len(signature); i += 3 {
+					assert.Contains(t, signedBy, signature[i])
+					if i > 0 {
+						assert.Equal(
+							t,
+							1,
+							signature[i].Cmp(signature[i-3]),
+							"signatures not sorted by public key",
+						)
+					}
+				}
+			},
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.name, func(t *testing.T) {
+			mockRPCProvider := rpcv10mock.NewMockRPCProvider(gomock.NewController(t))
+			mockRPCProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+			mockRPCProvider.EXPECT().
+				Nonce(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(new(felt.Felt).SetUint64(4), nil)
+			mockRPCProvider.EXPECT().
+				EstimateFee(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(
+					func(_, request, flags, _ any) ([]rpc.FeeEstimation, error) {
+						// the signatures are not collected yet, so the validation is skipped
+						assert.Contains(t, flags, rpc.SkipValidate)
+
+						reqArr, ok := request.([]rpc.BroadcastTxn)
+						require.True(t, ok)
+						txn, ok := reqArr[0].(*rpc.BroadcastInvokeTxnV3)
+						require.True(t, ok)
+						assert.Empty(t, txn.Signature)
+
+						return []rpc.FeeEstimation{
+							{
+								FeeEstimationCommon: rpc.FeeEstimationCommon{
+									L1GasPrice:        new(felt.Felt).SetUint64(10),
+									L1GasConsumed:     new(felt.Felt).SetUint64(100),
+									L1DataGasPrice:    new(felt.Felt).SetUint64(5),
+									L1DataGasConsumed: new(felt.Felt).SetUint64(50),
+									L2GasPrice:        new(felt.Felt).SetUint64(3),
+									L2GasConsumed:     new(felt.Felt).SetUint64(200),
+								},
+							},
+						}, nil
+					},
+				)
+
+			multisig, err := account.NewMultisigAccount(
+				mockRPCProvider,
+				internalUtils.DeadBeef,
+				pubKeys,
+				2,
+				test.format,
+				account.CairoV2,
+			)
+			require.NoError(t, err)
+
+			mTxn, err := multisig.BuildInvokeTxn(t.Context(), []rpc.InvokeFunctionCall{
+				{
+					ContractAddress: internalUtils.DeadBeef,
+					FunctionName:    "transfer",
+				},
+			}, &account.TxnOptions{CustomTip: "0x0"})
+			require.NoError(t, err)
+			assert.Equal(t, rpc.TransactionV3, mTxn.Transaction.Version)
+
+			expectedHash, err := multisig.TransactionHashInvoke(mTxn.Transaction)
+			require.NoError(t, err)
+			assert.Equal(t, expectedHash, mTxn.TransactionHash)
+
+			// sending without enough signatures
+			_, err = mTxn.Send(t.Context())
+			require.ErrorIs(t, err, account.ErrMultisigThresholdNotMet)
+			assert.Equal(t, 2, mTxn.MissingSignatures())
+
+			// first signer signs locally with its keystore
+			require.NoError(t, mTxn.SignWith(t.Context(), signers[0].ks, signers[0].pubKey))
+			err = mTxn.SignWith(t.Context(), signers[0].ks, signers[0].pubKey)
+			require.ErrorIs(t, err, account.ErrMultisigDuplicateSignature)
+
+			// an outsider can't sign
+			outsider := newMultisigSigners(1)[0]
+			err = mTxn.SignWith(t.Context(), outsider.ks, outsider.pubKey)
+			require.ErrorIs(t, err, account.ErrMultisigUnknownSigner)
+
+			// second signer signs remotely from the JSON signing request
+			rawReq, err := json.Marshal(mTxn.SigningRequest())
+			require.NoError(t, err)
+			var req account.MultisigSigningRequest
+			require.NoError(t, json.Unmarshal(rawReq, &req))
+
+			sig, err := req.Sign(t.Context(), signers[2].ks, signers[2].pubKey)
+			require.NoError(t, err)
+
+			// a signature of the wrong signer is rejected
+			wrongSig := sig
+			wrongSig.Signer = signers[1].pubKey
+			err = mTxn.AddSignature(wrongSig)
+			require.ErrorIs(t, err, account.ErrMultisigInvalidSignature)
+
+			require.NoError(t, mTxn.AddSignature(sig))
+			assert.Equal(t, 0, mTxn.MissingSignatures())
+
+			mockRPCProvider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_, txn any) (rpc.AddInvokeTransactionResponse, error) {
+					bcTxn, ok := txn.(*rpc.BroadcastInvokeTxnV3)
+					require.True(t, ok)
+					test.assertSignature(
+						t,
+						bcTxn.Signature,
+						[]*felt.Felt{signers[0].pubKey, signers[2].pubKey},
+					)
+
+					return rpc.AddInvokeTransactionResponse{Hash: expectedHash}, nil
+				},
+			)
+
+			resp, err := mTxn.Send(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, expectedHash, resp.Hash)
+		})
+	}
+}
+
+// TestMultisigSigningRequestVerify tests that a signer refuses to sign a request
+// whose hash does not match its transaction.
+func TestMultisigSigningRequestVerify(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	mockRPCProvider := rpcv10mock.NewMockRPCProvider(gomock.NewController(t))
+	mockRPCProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+
+	signers := newMultisigSigners(2)
+	multisig, err := account.NewMultisigAccount(
+		mockRPCProvider,
+		internalUtils.DeadBeef,
+		[]*felt.Felt{signers[0].pubKey, signers[1].pubKey},
+		1,
+		account.MultisigFormatConcatenated,
+		account.CairoV2,
+	)
+	require.NoError(t, err)
+
+	txn := internalUtils.TestUnmarshalJSONFileToType[rpc.InvokeTxnV3](
+		t,
+		"./testData/fakeInvokeTxn.json",
+	)
+	mTxn, err := multisig.NewMultisigTxn(&txn)
+	require.NoError(t, err)
+
+	req := mTxn.SigningRequest()
+	require.NoError(t, req.Verify())
+
+	req.TransactionHash = internalUtils.DeadBeef
+	_, err = req.Sign(t.Context(), signers[0].ks, signers[0].pubKey)
+	require.ErrorIs(t, err, account.ErrMultisigHashMismatch)
+}
@@ -0,0 +1,237 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	ErrEnvelopeMismatch      = errors.New("invoke txn envelope does not match its transaction")
+	ErrEnvelopeNotSigned     = errors.New("invoke txn envelope is not signed")
+	ErrEnvelopeWrongAccount  = errors.New("invoke txn envelope is for another account or chain")
+	ErrEnvelopeBadSignature  = errors.New("invalid invoke txn envelope signature")
+	ErrEnvelopeUnknownFormat = errors.New("unknown invoke txn envelope format version")
+)
+
+// InvokeTxnEnvelopeVersion is the current format version of the InvokeTxnEnvelope.
+const InvokeTxnEnvelopeVersion = 1
+
+// InvokeTxnEnvelope is a serialisable v3 invoke transaction prepared by an
+// online machine, to be signed on an offline (air-gapped) one, and broadcast
+// by the online machine afterwards.
+//
+// Besides the transaction itself, it holds its chain ID, nonce, resource bounds
+// and calls in a readable form, so they can be reviewed before signing. All of
+// them are checked against the transaction when verifying the envelope.
+type InvokeTxnEnvelope struct {
+	// The format version of the envelope
+	Version         int                        `json:"envelope_version"`
+	ChainID         *felt.Felt                 `json:"chain_id"`
+	SenderAddress   *felt.Felt                 `json:"sender_address"`
+	Nonce           *felt.Felt                 `json:"nonce"`
+	ResourceBounds  *rpc.ResourceBoundsMapping `json:"resource_bounds"`
+	CairoVersion    CairoVersion               `json:"cairo_version"`
+	Calls           []DecodedCall              `json:"calls"`
+	TransactionHash *felt.Felt                 `json:"transaction_hash"`
+	Transaction     *rpc.BroadcastInvokeTxnV3  `json:"transaction"`
+}
+
+// PrepareInvokeTxn builds an unsigned v3 invoke transaction with the given
+// function calls, wrapped in an envelope to be signed offline. It automatically
+// calculates the nonce, formats the calldata and estimates the fees. Since no
+// signature is available, the fee is estimated with the `SKIP_VALIDATE`
+// simulation flag.
+//
+// Parameters:
+//   - ctx: The context.Context for the request.
+//   - functionCalls: A slice of rpc.InvokeFunctionCall representing the function
+//     calls for the transaction.
+//   - opts: options for building/estimating the transaction. Pass `nil` to use
+//     default values.
+//
+// Returns:
+//   - *InvokeTxnEnvelope: the envelope to be signed offline
+//   - error: An error if the transaction building fails.
+func (account *Account) PrepareInvokeTxn(
+	ctx context.Context,
+	functionCalls []rpc.InvokeFunctionCall,
+	opts *TxnOptions,
+) (*InvokeTxnEnvelope, error) {
+	txn, err := account.buildUnsignedInvokeTxn(ctx, functionCalls, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	txnHash, err := account.TransactionHashInvoke(txn)
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]DecodedCall, len(functionCalls))
+	for i, call := range functionCalls {
+		calls[i] = DecodedCall{
+			ContractAddress:    call.ContractAddress,
+			FunctionName:       call.FunctionName,
+			EntryPointSelector: utils.GetSelectorFromNameFelt(call.FunctionName),
+			Calldata:           call.CallData,
+		}
+		if calls[i].Calldata == nil {
+			calls[i].Calldata = []*felt.Felt{}
+		}
+	}
+
+	return &InvokeTxnEnvelope{
+		Version:         InvokeTxnEnvelopeVersion,
+		ChainID:         account.ChainID,
+		SenderAddress:   account.Address,
+		Nonce:           txn.Nonce,
+		ResourceBounds:  txn.ResourceBounds,
+		CairoVersion:    account.CairoVersion,
+		Calls:           calls,
+		TransactionHash: txnHash,
+		Transaction:     txn,
+	}, nil
+}
+
+// Verify checks that the fields of the envelope match its transaction, and that
+// the transaction hash matches the transaction and chain ID. It doesn't need a
+// provider, so it can be used on the offline machine.
+//
+// Returns:
+//   - error: ErrEnvelopeMismatch if any field doesn't match, or an error if the
+//     hash can't be computed
+func (env *InvokeTxnEnvelope) Verify() error {
+	if env.Version != InvokeTxnEnvelopeVersion {
+		return fmt.Errorf("%w: %d", ErrEnvelopeUnknownFormat, env.Version)
+	}
+
+	txn := env.Transaction
+	if txn == nil || env.ChainID == nil || env.TransactionHash == nil {
+		return fmt.Errorf(
+			"%w: missing transaction, chain ID or transaction hash",
+			ErrEnvelopeMismatch,
+		)
+	}
+	if env.SenderAddress == nil || !env.SenderAddress.Equal(txn.SenderAddress) {
+		return fmt.Errorf("%w: sender address", ErrEnvelopeMismatch)
+	}
+	if env.Nonce == nil || !env.Nonce.Equal(txn.Nonce) {
+		return fmt.Errorf("%w: nonce", ErrEnvelopeMismatch)
+	}
+	if env.ResourceBounds == nil || txn.ResourceBounds == nil ||
+		*env.ResourceBounds != *txn.ResourceBounds {
+		return fmt.Errorf("%w: resource bounds", ErrEnvelopeMismatch)
+	}
+
+	fnCalls := make([]rpc.FunctionCall, len(env.Calls))
+	for i, call := range env.Calls {
+		if call.FunctionName != "" &&
+			!utils.GetSelectorFromNameFelt(call.FunctionName).Equal(call.EntryPointSelector) {
+			return fmt.Errorf(
+				"%w: selector of call %d is not the one of '%s'",
+				ErrEnvelopeMismatch,
+				i,
+				call.FunctionName,
+			)
+		}
+		fnCalls[i] = call.FunctionCall()
+	}
+	acc := &Account{
+		ChainID:      env.ChainID,
+		Address:      env.SenderAddress,
+		CairoVersion: env.CairoVersion,
+	}
+	callData, err := acc.FmtCalldata(fnCalls)
+	if err != nil {
+		return err
+	}
+	if !slices.EqualFunc(callData, txn.Calldata, (*felt.Felt).Equal) {
+		return fmt.Errorf("%w: calls", ErrEnvelopeMismatch)
+	}
+
+	txnHash, err := acc.TransactionHashInvoke(txn)
+	if err != nil {
+		return err
+	}
+	if !txnHash.Equal(env.TransactionHash) {
+		return fmt.Errorf(
+			"%w: expected transaction hash %s, got %s",
+			ErrEnvelopeMismatch,
+			txnHash,
+			env.TransactionHash,
+		)
+	}
+
+	return nil
+}
+
+// Sign verifies the envelope and signs its transaction with the given keystore,
+// setting the `[r, s]` signature in the transaction. It doesn't need a provider,
+// so it can be used on the offline machine.
+//
+// Parameters:
+//   - ctx: the context used for the signing operation
+//   - ks: the keystore holding the account private key
+//   - publicKey: the public key of the account, used as the keystore id
+//
+// Returns:
+//   - error: an error if the envelope is invalid or the signing fails
+func (env *InvokeTxnEnvelope) Sign(ctx context.Context, ks Keystore, publicKey string) error {
+	if err := env.Verify(); err != nil {
+		return err
+	}
+
+	acc := &Account{ChainID: env.ChainID, Address: env.SenderAddress, publicKey: publicKey, ks: ks}
+	signature, err := acc.Sign(ctx, env.TransactionHash)
+	if err != nil {
+		return err
+	}
+	env.Transaction.Signature = signature
+
+	return nil
+}
+
+// SendInvokeTxnEnvelope verifies an envelope signed offline and broadcasts its
+// transaction. The envelope must be for this account and chain, and its signature
+// must be valid for the account public key.
+//
+// Parameters:
+//   - ctx: The context.Context for the request.
+//   - env: the signed envelope
+//
+// Returns:
+//   - rpc.AddInvokeTransactionResponse: the response of the submitted transaction.
+//   - error: an error if the envelope is invalid or the transaction fails to be sent
+func (account *Account) SendInvokeTxnEnvelope(
+	ctx context.Context,
+	env *InvokeTxnEnvelope,
+) (rpc.AddInvokeTransactionResponse, error) {
+	var response rpc.AddInvokeTransactionResponse
+
+	if err := env.Verify(); err != nil {
+		return response, err
+	}
+	if !env.ChainID.Equal(account.ChainID) || !env.SenderAddress.Equal(account.Address) {
+		return response, ErrEnvelopeWrongAccount
+	}
+
+	//nolint:mnd // the signature must be [r, s]
+	if len(env.Transaction.Signature) != 2 {
+		return response, ErrEnvelopeNotSigned
+	}
+	valid, err := account.Verify(env.TransactionHash, env.Transaction.Signature)
+	if err != nil {
+		return response, fmt.Errorf("%w: %w", ErrEnvelopeBadSignature, err)
+	}
+	if !valid {
+		return response, ErrEnvelopeBadSignature
+	}
+
+	return account.Provider.AddInvokeTransaction(ctx, env.Transaction)
+}
@@ -0,0 +1,168 @@
+package account_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/internal/tests"
+	"github.com/NethermindEth/starknet.go/internal/tests/mocks/rpcv10mock"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// TestInvokeTxnEnvelope tests preparing an invoke transaction online, signing it
+// offline without a provider, and broadcasting it online.
+func TestInvokeTxnEnvelope(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	ks, pubKey, _ := account.GetRandomKeys()
+
+	mockRPCProvider := rpcv10mock.NewMockRPCProvider(gomock.NewController(t))
+	mockRPCProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+	mockRPCProvider.EXPECT().
+		Nonce(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(new(felt.Felt).SetUint64(7), nil)
+	mockRPCProvider.EXPECT().
+		EstimateFee(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(
+			func(_, _, flags, _ any) ([]rpc.FeeEstimation, error) {
+				// no signature is available when preparing the envelope
+				assert.Contains(t, flags, rpc.SkipValidate)
+
+				return []rpc.FeeEstimation{
+					{
+						FeeEstimationCommon: rpc.FeeEstimationCommon{
+							L1GasPrice:        new(felt.Felt).SetUint64(10),
+							L1GasConsumed:     new(felt.Felt).SetUint64(100),
+							L1DataGasPrice:    new(felt.Felt).SetUint64(5),
+							L1DataGasConsumed: new(felt.Felt).SetUint64(50),
+							L2GasPrice:        new(felt.Felt).SetUint64(3),
+							L2GasConsumed:     new(felt.Felt).SetUint64(200),
+						},
+					},
+				}, nil
+			},
+		)
+
+	// online side
+	onlineAcc, err := account.NewAccount(
+		mockRPCProvider,
+		internalUtils.DeadBeef,
+		pubKey.String(),
+		account.NewMemKeystore(),
+		account.CairoV2,
+	)
+	require.NoError(t, err)
+
+	env, err := onlineAcc.PrepareInvokeTxn(t.Context(), []rpc.InvokeFunctionCall{
+		{
+			ContractAddress: internalUtils.DeadBeef,
+			FunctionName:    "transfer",
+			CallData:        []*felt.Felt{internalUtils.DeadBeef, new(felt.Felt).SetUint64(1)},
+		},
+	}, &account.TxnOptions{CustomTip: "0x0"})
+	require.NoError(t, err)
+	require.NoError(t, env.Verify())
+	assert.Equal(t, uint64(7), env.Nonce.Uint64())
+	assert.Equal(t, "transfer", env.Calls[0].FunctionName)
+
+	rawEnv, err := json.Marshal(env)
+	require.NoError(t, err)
+
+	t.Run("not signed", func(t *testing.T) {
+		_, err := onlineAcc.SendInvokeTxnEnvelope(t.Context(), env)
+		require.ErrorIs(t, err, account.ErrEnvelopeNotSigned)
+	})
+
+	t.Run("tampered envelopes", func(t *testing.T) {
+		testSet := []struct {
+			name   string
+			tamper func(env *account.InvokeTxnEnvelope)
+		}{
+			{
+				name: "reviewed call differs from the calldata",
+				tamper: func(env *account.InvokeTxnEnvelope) {
+					env.Calls[0].Calldata[1] = new(felt.Felt).SetUint64(1000)
+				},
+			},
+			{
+				name: "function name differs from the selector",
+				tamper: func(env *account.InvokeTxnEnvelope) {
+					env.Calls[0].FunctionName = "approve"
+				},
+			},
+			{
+				name: "nonce differs from the transaction",
+				tamper: func(env *account.InvokeTxnEnvelope) {
+					env.Nonce = new(felt.Felt).SetUint64(8)
+				},
+			},
+			{
+				name: "transaction modified after hashing",
+				tamper: func(env *account.InvokeTxnEnvelope) {
+					env.Transaction.Tip = "0x1000"
+				},
+			},
+			{
+				name: "resource bounds differ from the transaction",
+				tamper: func(env *account.InvokeTxnEnvelope) {
+					env.ResourceBounds.L2Gas.MaxAmount = "0x1"
+				},
+			},
+		}
+
+		for _, test := range testSet {
+			t.Run(test.name, func(t *testing.T) {
+				var tampered account.InvokeTxnEnvelope
+				require.NoError(t, json.Unmarshal(rawEnv, &tampered))
+				test.tamper(&tampered)
+
+				err := tampered.Sign(t.Context(), ks, pubKey.String())
+				require.ErrorIs(t, err, account.ErrEnvelopeMismatch)
+			})
+		}
+	})
+
+	t.Run("sign offline and send", func(t *testing.T) {
+		// offline side, only the JSON envelope and the keystore are available
+		var offlineEnv account.InvokeTxnEnvelope
+		require.NoError(t, json.Unmarshal(rawEnv, &offlineEnv))
+		require.NoError(t, offlineEnv.Sign(t.Context(), ks, pubKey.String()))
+
+		rawSigned, err := json.Marshal(offlineEnv)
+		require.NoError(t, err)
+
+		// back to the online side
+		var signedEnv account.InvokeTxnEnvelope
+		require.NoError(t, json.Unmarshal(rawSigned, &signedEnv))
+
+		mockRPCProvider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_, txn any) (rpc.AddInvokeTransactionResponse, error) {
+				bcTxn, ok := txn.(*rpc.BroadcastInvokeTxnV3)
+				require.True(t, ok)
+				assert.Equal(t, rpc.TransactionV3, bcTxn.Version)
+				assert.Len(t, bcTxn.Signature, 2)
+
+				return rpc.AddInvokeTransactionResponse{Hash: signedEnv.TransactionHash}, nil
+			},
+		)
+
+		resp, err := onlineAcc.SendInvokeTxnEnvelope(t.Context(), &signedEnv)
+		require.NoError(t, err)
+		assert.Equal(t, env.TransactionHash, resp.Hash)
+
+		// signed by another key
+		otherKs, otherPubKey, _ := account.GetRandomKeys()
+		var wrongEnv account.InvokeTxnEnvelope
+		require.NoError(t, json.Unmarshal(rawEnv, &wrongEnv))
+		require.NoError(t, wrongEnv.Sign(t.Context(), otherKs, otherPubKey.String()))
+
+		_, err = onlineAcc.SendInvokeTxnEnvelope(t.Context(), &wrongEnv)
+		require.ErrorIs(t, err, account.ErrEnvelopeBadSignature)
+	})
+}
@@ -0,0 +1,641 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/paymaster"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/typeddata"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	ErrPaymasterTypedDataMismatch = errors.New(
+		"paymaster typed data does not match the requested transaction",
+	)
+	ErrPaymasterFeeTooHigh     = errors.New("paymaster fee transfer exceeds the max fee")
+	ErrPaymasterTxnDropped     = errors.New("paymaster transaction was dropped")
+	ErrPaymasterInvalidRequest = errors.New("invalid paymaster request")
+)
+
+// outsideExecutionDomainName is the domain name of the SNIP-9 outside
+// execution typed data.
+const outsideExecutionDomainName = "Account.execute_from_outside"
+
+// anyCaller is the SNIP-9 caller allowing any address to execute an outside
+// execution, the short string 'ANY_CALLER'.
+var anyCaller = new(felt.Felt).SetBytes([]byte("ANY_CALLER"))
+
+// DefaultPaymasterPollInterval is the interval used to poll the status of a
+// paymaster transaction when none is specified.
+const DefaultPaymasterPollInterval = 2 * time.Second
+
+// PaymasterProvider is the subset of the SNIP-29 paymaster methods used by the
+// Account to send transactions through a paymaster. It is implemented by
+// *paymaster.Paymaster.
+type PaymasterProvider interface {
+	BuildTransaction(
+		ctx context.Context,
+		request *paymaster.BuildTransactionRequest,
+	) (paymaster.BuildTransactionResponse, error)
+	ExecuteTransaction(
+		ctx context.Context,
+		request *paymaster.ExecuteTransactionRequest,
+	) (paymaster.ExecuteTransactionResponse, error)
+	TrackingIDToLatestHash(
+		ctx context.Context,
+		trackingID *felt.Felt,
+	) (paymaster.TrackingIDResponse, error)
+}
+
+var _ PaymasterProvider = (*paymaster.Paymaster)(nil)
+
+// PaymasterOptions are the options of the transactions sent through a
+// paymaster. The zero value uses the default values.
+type PaymasterOptions struct {
+	// The maximum fee, in gas token units, the account accepts to pay to the
+	// paymaster. If nil, the `suggested_max_fee_in_gas_token` returned by the
+	// paymaster is used. Ignored in the sponsored fee mode, where no fee
+	// transfer is accepted.
+	MaxFee *felt.Felt
+	// The time bounds of the transaction. If nil, the paymaster defaults are used.
+	TimeBounds *paymaster.TimeBounds
+	// The interval used to poll the status of the transaction. If 0,
+	// DefaultPaymasterPollInterval is used.
+	PollInterval time.Duration
+	// If true, the transaction is returned as soon as the paymaster accepts to
+	// execute it, without waiting for it to be accepted on L2.
+	SkipWait bool
+	// The address the paymaster executes the outside execution from, e.g. its
+	// relayer or forwarder. The typed data caller must be this address or
+	// ANY_CALLER. If nil, only ANY_CALLER is accepted.
+	Caller *felt.Felt
+}
+
+// SendViaPaymaster sends an invoke transaction through a SNIP-29 paymaster.
+// It builds the transaction with the paymaster, verifies the returned typed
+// data with VerifyPaymasterTypedData, signs it, executes it, and waits for
+// the paymaster to report it as accepted on L2.
+//
+// The account must support the SNIP-9 outside execution.
+//
+// Parameters:
+//   - ctx: The context.Context for the requests
+//   - pm: the paymaster, e.g. a *paymaster.Paymaster
+//   - functionCalls: the function calls of the transaction
+//   - feeMode: how the transaction is paid, with a gas token or sponsored
+//   - opts: the options of the transaction. Pass `nil` to use default values.
+//
+// Returns:
+//   - paymaster.ExecuteTransactionResponse: the tracking ID of the request and
+//     the hash of the latest transaction sent by the paymaster
+//   - error: an error if the typed data doesn't match the calls, the paymaster
+//     fails or the transaction is dropped
+func (account *Account) SendViaPaymaster(
+	ctx context.Context,
+	pm PaymasterProvider,
+	functionCalls []rpc.InvokeFunctionCall,
+	feeMode paymaster.FeeMode,
+	opts *PaymasterOptions,
+) (paymaster.ExecuteTransactionResponse, error) {
+	return account.sendViaPaymaster(ctx, pm, nil, functionCalls, feeMode, opts)
+}
+
+// SendViaPaymasterWithDeployment deploys the account and sends an invoke
+// transaction in a single `deploy_and_invoke` paymaster request, with the same
+// checks as SendViaPaymaster. The deployment data returned by the paymaster
+// must match the given one.
+//
+// Parameters:
+//   - ctx: The context.Context for the requests
+//   - pm: the paymaster, e.g. a *paymaster.Paymaster
+//   - deployment: the deployment data of the account. Its address must be the
+//     account address.
+//   - functionCalls: the function calls of the transaction
+//   - feeMode: how the transaction is paid, with a gas token or sponsored
+//   - opts: the options of the transaction. Pass `nil` to use default values.
+//
+// Returns:
+//   - paymaster.ExecuteTransactionResponse: the tracking ID of the request and
+//     the hash of the latest transaction sent by the paymaster
+//   - error: an error if the typed data or the deployment data don't match the
+//     request, the paymaster fails or the transaction is dropped
+func (account *Account) SendViaPaymasterWithDeployment(
+	ctx context.Context,
+	pm PaymasterProvider,
+	deployment *paymaster.AccountDeploymentData,
+	functionCalls []rpc.InvokeFunctionCall,
+	feeMode paymaster.FeeMode,
+	opts *PaymasterOptions,
+) (paymaster.ExecuteTransactionResponse, error) {
+	if deployment == nil || deployment.Address == nil ||
+		!deployment.Address.Equal(account.Address) {
+		return paymaster.ExecuteTransactionResponse{}, fmt.Errorf(
+			"%w: the deployment address must be the account address",
+			ErrPaymasterInvalidRequest,
+		)
+	}
+
+	return account.sendViaPaymaster(ctx, pm, deployment, functionCalls, feeMode, opts)
+}
+
+func (account *Account) sendViaPaymaster(
+	ctx context.Context,
+	pm PaymasterProvider,
+	deployment *paymaster.AccountDeploymentData,
+	functionCalls []rpc.InvokeFunctionCall,
+	feeMode paymaster.FeeMode,
+	opts *PaymasterOptions,
+) (paymaster.ExecuteTransactionResponse, error) {
+	var response paymaster.ExecuteTransactionResponse
+	if opts == nil {
+		opts = new(PaymasterOptions)
+	}
+	if feeMode.Mode == paymaster.FeeModeDefault && feeMode.GasToken == nil {
+		return response, fmt.Errorf(
+			"%w: the default fee mode requires a gas token",
+			ErrPaymasterInvalidRequest,
+		)
+	}
+
+	calls := make([]paymaster.Call, len(functionCalls))
+	for i, call := range functionCalls {
+		calls[i] = paymaster.Call{
+			To:       call.ContractAddress,
+			Selector: utils.GetSelectorFromNameFelt(call.FunctionName),
+			Calldata: call.CallData,
+		}
+	}
+
+	txnType := paymaster.UserTxnInvoke
+	if deployment != nil {
+		txnType = paymaster.UserTxnDeployAndInvoke
+	}
+	params := paymaster.UserParameters{
+		Version:    paymaster.UserParamV1,
+		FeeMode:    feeMode,
+		TimeBounds: opts.TimeBounds,
+	}
+
+	built, err := pm.BuildTransaction(ctx, &paymaster.BuildTransactionRequest{
+		Transaction: paymaster.UserTransaction{
+			Type:       txnType,
+			Deployment: deployment,
+			Invoke:     &paymaster.UserInvoke{UserAddress: account.Address, Calls: calls},
+		},
+		Parameters: params,
+	})
+	if err != nil {
+		return response, fmt.Errorf("failed to build the paymaster transaction: %w", err)
+	}
+
+	if built.Type != txnType {
+		return response, fmt.Errorf(
+			"%w: expected a '%s' transaction, got '%s'",
+			ErrPaymasterTypedDataMismatch,
+			txnType,
+			built.Type,
+		)
+	}
+	if deployment != nil && !sameDeployment(deployment, built.Deployment) {
+		return response, fmt.Errorf(
+			"%w: the deployment data differs from the requested one",
+			ErrPaymasterTypedDataMismatch,
+		)
+	}
+
+	signature, err := account.signPaymasterTypedData(ctx, &built, calls, feeMode, opts)
+	if err != nil {
+		return response, err
+	}
+
+	response, err = pm.ExecuteTransaction(ctx, &paymaster.ExecuteTransactionRequest{
+		Transaction: paymaster.ExecutableUserTransaction{
+			Type:       txnType,
+			Deployment: built.Deployment,
+			Invoke: &paymaster.ExecutableUserInvoke{
+				UserAddress: account.Address,
+				TypedData:   built.TypedData,
+				Signature:   signature,
+			},
+		},
+		Parameters: params,
+	})
+	if err != nil || opts.SkipWait {
+		return response, err
+	}
+
+	pollInterval := opts.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = DefaultPaymasterPollInterval
+	}
+
+	return waitForPaymasterTxn(ctx, pm, response, pollInterval)
+}
+
+// signPaymasterTypedData verifies the typed data built by a paymaster, checks
+// its calls against the signing policy of the account, and signs it.
+func (account *Account) signPaymasterTypedData(
+	ctx context.Context,
+	built *paymaster.BuildTransactionResponse,
+	calls []paymaster.Call,
+	feeMode paymaster.FeeMode,
+	opts *PaymasterOptions,
+) ([]*felt.Felt, error) {
+	var maxFee *felt.Felt
+	if feeMode.Mode != paymaster.FeeModeSponsored {
+		maxFee = opts.MaxFee
+		if maxFee == nil && built.Fee != nil {
+			maxFee = built.Fee.SuggestedMaxFeeInGasToken
+		}
+		if maxFee == nil {
+			return nil, fmt.Errorf(
+				"%w: no max fee specified nor suggested by the paymaster",
+				ErrPaymasterInvalidRequest,
+			)
+		}
+	}
+	if err := VerifyPaymasterTypedData(
+		built.TypedData,
+		account.ChainID,
+		opts.Caller,
+		calls,
+		feeMode.GasToken,
+		maxFee,
+	); err != nil {
+		return nil, err
+	}
+
+	record, err := account.checkPaymasterPolicy(built.TypedData, len(calls))
+	if err != nil {
+		return nil, err
+	}
+
+	msgHash, err := built.TypedData.GetMessageHash(account.Address.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash the paymaster typed data: %w", err)
+	}
+	signature, err := account.Sign(ctx, msgHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign the paymaster typed data: %w", err)
+	}
+	if err := record(); err != nil {
+		return nil, err
+	}
+
+	return signature, nil
+}
+
+// checkPaymasterPolicy checks the calls of a verified paymaster typed data
+// against the signing policy of the account, if any. The call following the
+// requested ones, if any, is the fee transfer. The returned function records
+// the outside execution once it is signed.
+func (account *Account) checkPaymasterPolicy(
+	typedData *typeddata.TypedData,
+	requested int,
+) (func() error, error) {
+	if account.Policy == nil {
+		return func() error { return nil }, nil
+	}
+
+	signedCalls, err := outsideExecutionCalls(typedData.Message)
+	if err != nil {
+		return nil, err
+	}
+	calls := make([]rpc.FunctionCall, len(signedCalls))
+	for i, call := range signedCalls {
+		calls[i] = rpc.FunctionCall{
+			ContractAddress:    call.To,
+			EntryPointSelector: call.Selector,
+			Calldata:           call.Calldata,
+		}
+	}
+
+	var feeTransfer *rpc.FunctionCall
+	if len(calls) > requested {
+		feeTransfer = &calls[requested]
+	}
+
+	return account.Policy.checkOutsideExecution(calls[:requested], feeTransfer)
+}
+
+// waitForPaymasterTxn polls the status of a paymaster request until it is
+// accepted or dropped, updating the transaction hash of the response.
+func waitForPaymasterTxn(
+	ctx context.Context,
+	pm PaymasterProvider,
+	response paymaster.ExecuteTransactionResponse,
+	pollInterval time.Duration,
+) (paymaster.ExecuteTransactionResponse, error) {
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return response, ctx.Err()
+		case <-t.C:
+			status, err := pm.TrackingIDToLatestHash(ctx, response.TrackingID)
+			if err != nil {
+				return response, err
+			}
+			if status.TransactionHash != nil {
+				response.TransactionHash = status.TransactionHash
+			}
+
+			switch status.Status {
+			case paymaster.TxnStatusAccepted:
+				return response, nil
+			case paymaster.TxnStatusDropped:
+				return response, fmt.Errorf(
+					"%w: tracking ID %s",
+					ErrPaymasterTxnDropped,
+					response.TrackingID,
+				)
+			default:
+				continue
+			}
+		}
+	}
+}
+
+// VerifyPaymasterTypedData verifies that the SNIP-9 outside execution typed
+// data returned by a paymaster encodes exactly the requested calls, in order,
+// optionally followed by a single fee transfer of the gas token bounded by a
+// max fee, and can only be executed by the expected caller. It protects the
+// account from a malicious or buggy paymaster adding, removing or altering calls.
+//
+// Parameters:
+//   - typedData: the typed data returned by the `paymaster_buildTransaction` method
+//   - chainID: the chain ID of the account, checked against the typed data domain.
+//     Skipped if nil.
+//   - caller: the address the paymaster executes the outside execution from. The
+//     typed data caller must be this address or ANY_CALLER. If nil, only
+//     ANY_CALLER is accepted.
+//   - calls: the requested calls
+//   - gasToken: the token the fee is paid with. Ignored if maxFee is nil.
+//   - maxFee: the maximum amount of the fee transfer. If nil, no fee transfer
+//     is accepted, as in the sponsored fee mode.
+//
+// Returns:
+//   - error: an error wrapping ErrPaymasterTypedDataMismatch or
+//     ErrPaymasterFeeTooHigh if the typed data can't be accepted
+func VerifyPaymasterTypedData(
+	typedData *typeddata.TypedData,
+	chainID, caller *felt.Felt,
+	calls []paymaster.Call,
+	gasToken, maxFee *felt.Felt,
+) error {
+	if err := verifyOutsideExecutionEnvelope(typedData, chainID, caller); err != nil {
+		return err
+	}
+
+	signedCalls, err := outsideExecutionCalls(typedData.Message)
+	if err != nil {
+		return err
+	}
+
+	expected := len(calls)
+	if maxFee != nil && len(signedCalls) == expected+1 {
+		if err := checkFeeTransfer(signedCalls[expected], gasToken, maxFee); err != nil {
+			return err
+		}
+		signedCalls = signedCalls[:expected]
+	}
+	if len(signedCalls) != expected {
+		return fmt.Errorf(
+			"%w: expected %d calls, got %d",
+			ErrPaymasterTypedDataMismatch,
+			expected,
+			len(signedCalls),
+		)
+	}
+
+	for i, call := range calls {
+		if !sameCall(call, signedCalls[i]) {
+			return fmt.Errorf("%w: call %d differs", ErrPaymasterTypedDataMismatch, i)
+		}
+	}
+
+	return nil
+}
+
+// verifyOutsideExecutionEnvelope verifies the fields of an outside execution
+// typed data other than its calls: the primary type, the domain and the caller.
+func verifyOutsideExecutionEnvelope(
+	typedData *typeddata.TypedData,
+	chainID, caller *felt.Felt,
+) error {
+	if typedData == nil {
+		return fmt.Errorf("%w: no typed data", ErrPaymasterTypedDataMismatch)
+	}
+	if typedData.PrimaryType != "OutsideExecution" {
+		return fmt.Errorf(
+			"%w: unexpected primary type '%s'",
+			ErrPaymasterTypedDataMismatch,
+			typedData.PrimaryType,
+		)
+	}
+	if typedData.Domain.Name != outsideExecutionDomainName {
+		return fmt.Errorf(
+			"%w: unexpected domain name '%s'",
+			ErrPaymasterTypedDataMismatch,
+			typedData.Domain.Name,
+		)
+	}
+	if chainID != nil {
+		domainChainID, err := typedDataFelt(typedData.Domain.ChainID, true)
+		if err != nil || !domainChainID.Equal(chainID) {
+			return fmt.Errorf(
+				"%w: the typed data is for chain '%s'",
+				ErrPaymasterTypedDataMismatch,
+				typedData.Domain.ChainID,
+			)
+		}
+	}
+
+	signedCaller, err := messageFelt(messageField(typedData.Message, "Caller"), false)
+	if err != nil {
+		return fmt.Errorf("%w: caller: %w", ErrPaymasterTypedDataMismatch, err)
+	}
+	if !signedCaller.Equal(anyCaller) && !sameFelt(signedCaller, caller) {
+		return fmt.Errorf(
+			"%w: unexpected caller %s",
+			ErrPaymasterTypedDataMismatch,
+			signedCaller,
+		)
+	}
+
+	return nil
+}
+
+// outsideExecutionCalls decodes the calls of an outside execution message.
+// Both the SNIP-9 v1 (snake_case) and v2 (Title Case) field names are supported.
+func outsideExecutionCalls(message map[string]any) ([]paymaster.Call, error) {
+	rawCalls, ok := messageField(message, "Calls").([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: the message has no calls", ErrPaymasterTypedDataMismatch)
+	}
+
+	calls := make([]paymaster.Call, len(rawCalls))
+	for i, rawCall := range rawCalls {
+		fields, ok := rawCall.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid call %d", ErrPaymasterTypedDataMismatch, i)
+		}
+
+		to, err := messageFelt(messageField(fields, "To"), false)
+		if err != nil {
+			return nil, fmt.Errorf("%w: call %d: %w", ErrPaymasterTypedDataMismatch, i, err)
+		}
+		// a selector can also be given by its function name
+		selector, err := messageFelt(messageField(fields, "Selector"), true)
+		if err != nil {
+			return nil, fmt.Errorf("%w: call %d: %w", ErrPaymasterTypedDataMismatch, i, err)
+		}
+		rawCalldata, ok := messageField(fields, "Calldata").([]any)
+		if !ok && messageField(fields, "Calldata") != nil {
+			return nil, fmt.Errorf(
+				"%w: call %d: invalid calldata",
+				ErrPaymasterTypedDataMismatch,
+				i,
+			)
+		}
+		calldata := make([]*felt.Felt, len(rawCalldata))
+		for j, value := range rawCalldata {
+			if calldata[j], err = messageFelt(value, false); err != nil {
+				return nil, fmt.Errorf("%w: call %d: %w", ErrPaymasterTypedDataMismatch, i, err)
+			}
+		}
+
+		calls[i] = paymaster.Call{To: to, Selector: selector, Calldata: calldata}
+	}
+
+	return calls, nil
+}
+
+// checkFeeTransfer checks that a call is an ERC-20 transfer of the gas token
+// not exceeding the max fee.
+func checkFeeTransfer(call paymaster.Call, gasToken, maxFee *felt.Felt) error {
+	isTransfer := sameFelt(call.To, gasToken) && sameFelt(call.Selector, transferSelector)
+	// recipient, amount low, amount high
+	if gasToken == nil || !isTransfer || len(call.Calldata) != 3 { //nolint:mnd // transfer calldata
+		return fmt.Errorf(
+			"%w: the last call is not a transfer of the gas token",
+			ErrPaymasterTypedDataMismatch,
+		)
+	}
+
+	amount, err := utils.AmountFromU256(call.Calldata[1], call.Calldata[2], 0)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPaymasterTypedDataMismatch, err)
+	}
+	if amount.Value.Cmp(maxFee.BigInt(new(big.Int))) > 0 {
+		return fmt.Errorf(
+			"%w: the fee transfer of %s exceeds %s",
+			ErrPaymasterFeeTooHigh,
+			amount,
+			maxFee.BigInt(new(big.Int)),
+		)
+	}
+
+	return nil
+}
+
+// messageField returns a field of a typed data message object, ignoring the
+// case and replacing the spaces with underscores, so that e.g. "Execute After"
+// matches "execute_after".
+func messageField(fields map[string]any, name string) any {
+	if value, ok := fields[name]; ok {
+		return value
+	}
+	normalise := func(key string) string {
+		return strings.ReplaceAll(strings.ToLower(key), " ", "_")
+	}
+	for key, value := range fields {
+		if normalise(key) == normalise(name) {
+			return value
+		}
+	}
+
+	return nil
+}
+
+// messageFelt converts a value of a typed data message to a felt. If isName is
+// true, a string that isn't a number is taken as a function name or a short
+// string, depending on the caller.
+func messageFelt(value any, isName bool) (*felt.Felt, error) {
+	switch value := value.(type) {
+	case string:
+		if isName {
+			if result, err := typedDataFelt(value, false); err == nil {
+				return result, nil
+			}
+
+			return utils.GetSelectorFromNameFelt(value), nil
+		}
+
+		return typedDataFelt(value, false)
+	case float64:
+		return jsonNumberFelt(value)
+	default:
+		return nil, fmt.Errorf("invalid felt value '%v'", value)
+	}
+}
+
+// jsonNumberFelt converts a JSON number to a felt the same way the typeddata
+// package hashes it, so the checked value is the signed one. Only natural
+// numbers are accepted, as other numbers are hashed as short strings.
+func jsonNumberFelt(value float64) (*felt.Felt, error) {
+	text := strconv.FormatFloat(value, 'f', -1, 64)
+	if float64(int64(value)) == value {
+		text = strconv.FormatInt(int64(value), 10)
+	}
+	number, ok := new(big.Int).SetString(text, 10)
+	if !ok || number.Sign() < 0 {
+		return nil, fmt.Errorf("invalid felt value '%s'", text)
+	}
+
+	return new(felt.Felt).SetString(text)
+}
+
+// typedDataFelt converts a hexadecimal or decimal string to a felt. If
+// shortString is true, any other string is encoded as a Cairo short string.
+func typedDataFelt(value string, shortString bool) (*felt.Felt, error) {
+	number, ok := new(big.Int).SetString(value, 0)
+	if ok {
+		return new(felt.Felt).SetBigInt(number), nil
+	}
+	if shortString && value != "" {
+		return new(felt.Felt).SetBytes([]byte(value)), nil
+	}
+
+	return nil, fmt.Errorf("invalid felt value '%s'", value)
+}
+
+func sameCall(a, b paymaster.Call) bool {
+	return sameFelt(a.To, b.To) && sameFelt(a.Selector, b.Selector) &&
+		slices.EqualFunc(a.Calldata, b.Calldata, sameFelt)
+}
+
+func sameDeployment(a, b *paymaster.AccountDeploymentData) bool {
+	return b != nil && sameFelt(a.Address, b.Address) && sameFelt(a.ClassHash, b.ClassHash) &&
+		sameFelt(a.Salt, b.Salt) && slices.EqualFunc(a.Calldata, b.Calldata, sameFelt)
+}
+
+// sameFelt returns true if both felts are equal. A nil felt is only equal to
+// another nil felt.
+func sameFelt(a, b *felt.Felt) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(b)
+}
@@ -0,0 +1,494 @@
+package account_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/internal/tests"
+	"github.com/NethermindEth/starknet.go/internal/tests/mocks/rpcv10mock"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/NethermindEth/starknet.go/paymaster"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/typeddata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// fakePaymaster is a paymaster returning the given typed data calls, and
+// reporting the given statuses for the tracking ID.
+type fakePaymaster struct {
+	chainID    string
+	domainName string
+	caller     *felt.Felt
+	calls      []paymaster.Call
+	deployment *paymaster.AccountDeploymentData
+	maxFee     *felt.Felt
+	statuses   []paymaster.TxnStatus
+	// feeAmount replaces the encoded amount of the fee transfer, the last call,
+	// e.g. to return it as a JSON number
+	feeAmount string
+
+	executed *paymaster.ExecuteTransactionRequest
+}
+
+func (pm *fakePaymaster) BuildTransaction(
+	_ context.Context,
+	request *paymaster.BuildTransactionRequest,
+) (paymaster.BuildTransactionResponse, error) {
+	callsJSON := make([]string, len(pm.calls))
+	for i, call := range pm.calls {
+		calldata := make([]string, len(call.Calldata))
+		for j, value := range call.Calldata {
+			calldata[j] = fmt.Sprintf("%q", value)
+		}
+		if pm.feeAmount != "" && i == len(pm.calls)-1 {
+			calldata[1] = pm.feeAmount
+		}
+		callsJSON[i] = fmt.Sprintf(
+			`{"To": %q, "Selector": %q, "Calldata": [%s]}`,
+			call.To,
+			call.Selector,
+			strings.Join(calldata, ","),
+		)
+	}
+	domainName := pm.domainName
+	if domainName == "" {
+		domainName = "Account.execute_from_outside"
+	}
+	caller := pm.caller
+	if caller == nil {
+		caller = new(felt.Felt).SetBytes([]byte("ANY_CALLER"))
+	}
+	rawTypedData := fmt.Sprintf(`{
+		"types": {
+			"StarknetDomain": [
+				{"name": "name", "type": "shortstring"},
+				{"name": "version", "type": "shortstring"},
+				{"name": "chainId", "type": "shortstring"},
+				{"name": "revision", "type": "shortstring"}
+			],
+			"OutsideExecution": [
+				{"name": "Caller", "type": "ContractAddress"},
+				{"name": "Nonce", "type": "felt"},
+				{"name": "Execute After", "type": "u128"},
+				{"name": "Execute Before", "type": "u128"},
+				{"name": "Calls", "type": "Call*"}
+			],
+			"Call": [
+				{"name": "To", "type": "ContractAddress"},
+				{"name": "Selector", "type": "selector"},
+				{"name": "Calldata", "type": "felt*"}
+			]
+		},
+		"domain": {
+			"name": %q,
+			"version": "2",
+			"chainId": %q,
+			"revision": "1"
+		},
+		"primaryType": "OutsideExecution",
+		"message": {
+			"Caller": %q,
+			"Nonce": "0x1",
+			"Execute After": "0x1",
+			"Execute Before": "0x68cb0db5",
+			"Calls": [%s]
+		}
+	}`, domainName, pm.chainID, caller, strings.Join(callsJSON, ","))
+
+	var typedData typeddata.TypedData
+	if err := json.Unmarshal([]byte(rawTypedData), &typedData); err != nil {
+		return paymaster.BuildTransactionResponse{}, err
+	}
+
+	deployment := request.Transaction.Deployment
+	if pm.deployment != nil {
+		deployment = pm.deployment
+	}
+
+	return paymaster.BuildTransactionResponse{
+		Type:       request.Transaction.Type,
+		Deployment: deployment,
+		Parameters: &request.Parameters,
+		TypedData:  &typedData,
+		Fee:        &paymaster.FeeEstimate{SuggestedMaxFeeInGasToken: pm.maxFee},
+	}, nil
+}
+
+func (pm *fakePaymaster) ExecuteTransaction(
+	_ context.Context,
+	request *paymaster.ExecuteTransactionRequest,
+) (paymaster.ExecuteTransactionResponse, error) {
+	pm.executed = request
+
+	return paymaster.ExecuteTransactionResponse{
+		TrackingID:      new(felt.Felt).SetUint64(1),
+		TransactionHash: new(felt.Felt).SetUint64(100),
+	}, nil
+}
+
+func (pm *fakePaymaster) TrackingIDToLatestHash(
+	_ context.Context,
+	_ *felt.Felt,
+) (paymaster.TrackingIDResponse, error) {
+	status := pm.statuses[0]
+	if len(pm.statuses) > 1 {
+		pm.statuses = pm.statuses[1:]
+	}
+
+	return paymaster.TrackingIDResponse{
+		TransactionHash: new(felt.Felt).SetUint64(101),
+		Status:          status,
+	}, nil
+}
+
+// TestSendViaPaymaster tests sending transactions through a paymaster, and that
+// the typed data not matching the requested calls is never signed.
+func TestSendViaPaymaster(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	ks, pubKey, _ := account.GetRandomKeys()
+	mockRPCProvider := rpcv10mock.NewMockRPCProvider(gomock.NewController(t))
+	mockRPCProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+	acc, err := account.NewAccount(
+		mockRPCProvider,
+		internalUtils.DeadBeef,
+		pubKey.String(),
+		ks,
+		account.CairoV2,
+	)
+	require.NoError(t, err)
+
+	gasToken := internalUtils.TestHexToFelt(
+		t,
+		"0x4718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d",
+	)
+	mintContract := internalUtils.TestHexToFelt(
+		t,
+		"0x669e24364ce0ae7ec2864fb03eedbe60cfbc9d1c74438d10fa4b86552907d54",
+	)
+	relayer := internalUtils.TestHexToFelt(
+		t,
+		"0x75a180e18e56da1b1cae181c92a288f586f5fe22c18df21cf97886f1e4b316c",
+	)
+
+	functionCalls := []rpc.InvokeFunctionCall{
+		{
+			ContractAddress: mintContract,
+			FunctionName:    "mint",
+			CallData:        []*felt.Felt{new(felt.Felt).SetUint64(10000), new(felt.Felt)},
+		},
+	}
+	requested := paymaster.Call{
+		To:       mintContract,
+		Selector: internalUtils.GetSelectorFromNameFelt("mint"),
+		Calldata: functionCalls[0].CallData,
+	}
+	feeTransfer := func(amount uint64) paymaster.Call {
+		return paymaster.Call{
+			To:       gasToken,
+			Selector: internalUtils.GetSelectorFromNameFelt("transfer"),
+			Calldata: []*felt.Felt{relayer, new(felt.Felt).SetUint64(amount), new(felt.Felt)},
+		}
+	}
+	defaultFeeMode := paymaster.FeeMode{Mode: paymaster.FeeModeDefault, GasToken: gasToken}
+	opts := &account.PaymasterOptions{PollInterval: time.Millisecond}
+
+	t.Run("invoke", func(t *testing.T) {
+		pm := &fakePaymaster{
+			chainID:  "SN_SEPOLIA",
+			calls:    []paymaster.Call{requested, feeTransfer(1000)},
+			maxFee:   new(felt.Felt).SetUint64(1000),
+			statuses: []paymaster.TxnStatus{paymaster.TxnStatusActive, paymaster.TxnStatusAccepted},
+		}
+
+		resp, err := acc.SendViaPaymaster(t.Context(), pm, functionCalls, defaultFeeMode, opts)
+		require.NoError(t, err)
+		assert.Equal(t, new(felt.Felt).SetUint64(1), resp.TrackingID)
+		// the latest transaction hash reported by the paymaster
+		assert.Equal(t, new(felt.Felt).SetUint64(101), resp.TransactionHash)
+
+		require.NotNil(t, pm.executed)
+		invoke := pm.executed.Transaction.Invoke
+		msgHash, err := invoke.TypedData.GetMessageHash(acc.Address.String())
+		require.NoError(t, err)
+		valid, err := acc.Verify(msgHash, invoke.Signature)
+		require.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("deploy and invoke", func(t *testing.T) {
+		deployment := &paymaster.AccountDeploymentData{
+			Address:   acc.Address,
+			ClassHash: internalUtils.TestHexToFelt(t, "0x1234"),
+			Salt:      pubKey,
+			Calldata:  []*felt.Felt{pubKey},
+			Version:   paymaster.Cairo1,
+		}
+		pm := &fakePaymaster{
+			chainID:  "SN_SEPOLIA",
+			calls:    []paymaster.Call{requested},
+			statuses: []paymaster.TxnStatus{paymaster.TxnStatusAccepted},
+		}
+		sponsored := paymaster.FeeMode{Mode: paymaster.FeeModeSponsored}
+
+		_, err := acc.SendViaPaymasterWithDeployment(
+			t.Context(), pm, deployment, functionCalls, sponsored, opts,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, paymaster.UserTxnDeployAndInvoke, pm.executed.Transaction.Type)
+		assert.Equal(t, deployment, pm.executed.Transaction.Deployment)
+
+		// the paymaster changes the deployment data
+		pm = &fakePaymaster{
+			chainID:    "SN_SEPOLIA",
+			calls:      []paymaster.Call{requested},
+			deployment: &paymaster.AccountDeploymentData{Address: acc.Address},
+		}
+		_, err = acc.SendViaPaymasterWithDeployment(
+			t.Context(), pm, deployment, functionCalls, sponsored, opts,
+		)
+		require.ErrorIs(t, err, account.ErrPaymasterTypedDataMismatch)
+		assert.Nil(t, pm.executed)
+
+		otherDeployment := *deployment
+		otherDeployment.Address = internalUtils.TestHexToFelt(t, "0x1")
+		_, err = acc.SendViaPaymasterWithDeployment(
+			t.Context(), pm, &otherDeployment, functionCalls, sponsored, opts,
+		)
+		require.ErrorIs(t, err, account.ErrPaymasterInvalidRequest)
+	})
+
+	t.Run("caller", func(t *testing.T) {
+		pm := &fakePaymaster{
+			chainID:  "SN_SEPOLIA",
+			caller:   relayer,
+			calls:    []paymaster.Call{requested, feeTransfer(10)},
+			maxFee:   new(felt.Felt).SetUint64(1000),
+			statuses: []paymaster.TxnStatus{paymaster.TxnStatusAccepted},
+		}
+		callerOpts := &account.PaymasterOptions{PollInterval: time.Millisecond, Caller: relayer}
+
+		_, err := acc.SendViaPaymaster(t.Context(), pm, functionCalls, defaultFeeMode, callerOpts)
+		require.NoError(t, err)
+	})
+
+	t.Run("signing policy", func(t *testing.T) {
+		ks, pubKey, _ := account.GetRandomKeys()
+		mockRPCProvider := rpcv10mock.NewMockRPCProvider(gomock.NewController(t))
+		mockRPCProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+		policyAcc, err := account.NewAccount(
+			mockRPCProvider,
+			internalUtils.DeadBeef,
+			pubKey.String(),
+			ks,
+			account.CairoV2,
+		)
+		require.NoError(t, err)
+		policyAcc.Policy = new(account.SigningPolicy).Allow(mintContract, "mint")
+		policyAcc.Policy.TransferLimits = map[felt.Felt]account.TransferLimit{
+			*gasToken: {Max: big.NewInt(100), Window: time.Hour},
+		}
+		newPaymaster := func(calls ...paymaster.Call) *fakePaymaster {
+			return &fakePaymaster{
+				chainID:  "SN_SEPOLIA",
+				calls:    calls,
+				maxFee:   new(felt.Felt).SetUint64(1000),
+				statuses: []paymaster.TxnStatus{paymaster.TxnStatusAccepted},
+			}
+		}
+
+		// the fee transfer isn't checked against the allowed targets
+		pm := newPaymaster(requested, feeTransfer(60))
+		_, err = policyAcc.SendViaPaymaster(t.Context(), pm, functionCalls, defaultFeeMode, opts)
+		require.NoError(t, err)
+
+		// but counts towards the transfer limits
+		pm = newPaymaster(requested, feeTransfer(60))
+		_, err = policyAcc.SendViaPaymaster(t.Context(), pm, functionCalls, defaultFeeMode, opts)
+		require.ErrorIs(t, err, account.ErrTransferLimitExceeded)
+		assert.Nil(t, pm.executed, "the typed data must not be signed")
+
+		// the requested calls are checked against the allowed targets
+		approve := paymaster.Call{
+			To:       gasToken,
+			Selector: internalUtils.GetSelectorFromNameFelt("approve"),
+			Calldata: []*felt.Felt{relayer, new(felt.Felt).SetUint64(1), new(felt.Felt)},
+		}
+		pm = newPaymaster(approve)
+		_, err = policyAcc.SendViaPaymaster(
+			t.Context(),
+			pm,
+			[]rpc.InvokeFunctionCall{{
+				ContractAddress: gasToken,
+				FunctionName:    "approve",
+				CallData:        approve.Calldata,
+			}},
+			paymaster.FeeMode{Mode: paymaster.FeeModeSponsored},
+			opts,
+		)
+		require.ErrorIs(t, err, account.ErrTargetNotAllowed)
+		assert.Nil(t, pm.executed, "the typed data must not be signed")
+	})
+
+	type testSetType struct {
+		Name        string
+		ChainID     string
+		DomainName  string
+		Caller      *felt.Felt
+		Calls       []paymaster.Call
+		FeeAmount   string
+		MaxFee      *felt.Felt
+		FeeMode     paymaster.FeeMode
+		Opts        *account.PaymasterOptions
+		Statuses    []paymaster.TxnStatus
+		ExpectedErr error
+	}
+	alteredCall := requested
+	alteredCall.Calldata = []*felt.Felt{new(felt.Felt).SetUint64(99999), new(felt.Felt)}
+
+	testSet := []testSetType{
+		{
+			Name:        "injected call",
+			ChainID:     "SN_SEPOLIA",
+			Calls:       []paymaster.Call{requested, feeTransfer(10), feeTransfer(10)},
+			FeeMode:     defaultFeeMode,
+			ExpectedErr: account.ErrPaymasterTypedDataMismatch,
+		},
+		{
+			Name:        "altered calldata",
+			ChainID:     "SN_SEPOLIA",
+			Calls:       []paymaster.Call{alteredCall, feeTransfer(10)},
+			FeeMode:     defaultFeeMode,
+			ExpectedErr: account.ErrPaymasterTypedDataMismatch,
+		},
+		{
+			Name:        "missing call",
+			ChainID:     "SN_SEPOLIA",
+			Calls:       []paymaster.Call{feeTransfer(10)},
+			FeeMode:     defaultFeeMode,
+			ExpectedErr: account.ErrPaymasterTypedDataMismatch,
+		},
+		{
+			Name:        "fee above the suggested max fee",
+			ChainID:     "SN_SEPOLIA",
+			Calls:       []paymaster.Call{requested, feeTransfer(1001)},
+			FeeMode:     defaultFeeMode,
+			ExpectedErr: account.ErrPaymasterFeeTooHigh,
+		},
+		{
+			Name:    "fee above the user max fee",
+			ChainID: "SN_SEPOLIA",
+			Calls:   []paymaster.Call{requested, feeTransfer(600)},
+			FeeMode: defaultFeeMode,
+			Opts: &account.PaymasterOptions{
+				MaxFee:       new(felt.Felt).SetUint64(500),
+				PollInterval: time.Millisecond,
+			},
+			ExpectedErr: account.ErrPaymasterFeeTooHigh,
+		},
+		{
+			// the amount is hashed as 10^30, and must not be checked as a
+			// truncated uint64
+			Name:        "fee amount as a JSON number",
+			ChainID:     "SN_SEPOLIA",
+			Calls:       []paymaster.Call{requested, feeTransfer(0)},
+			FeeAmount:   "1e30",
+			MaxFee:      new(felt.Felt).Exp(new(felt.Felt).SetUint64(2), big.NewInt(64)),
+			FeeMode:     defaultFeeMode,
+			ExpectedErr: account.ErrPaymasterFeeTooHigh,
+		},
+		{
+			Name:        "fractional fee amount",
+			ChainID:     "SN_SEPOLIA",
+			Calls:       []paymaster.Call{requested, feeTransfer(0)},
+			FeeAmount:   "10.5",
+			FeeMode:     defaultFeeMode,
+			ExpectedErr: account.ErrPaymasterTypedDataMismatch,
+		},
+		{
+			Name:        "fee transfer when sponsored",
+			ChainID:     "SN_SEPOLIA",
+			Calls:       []paymaster.Call{requested, feeTransfer(10)},
+			FeeMode:     paymaster.FeeMode{Mode: paymaster.FeeModeSponsored},
+			ExpectedErr: account.ErrPaymasterTypedDataMismatch,
+		},
+		{
+			Name:        "other chain",
+			ChainID:     "SN_MAIN",
+			Calls:       []paymaster.Call{requested, feeTransfer(10)},
+			FeeMode:     defaultFeeMode,
+			ExpectedErr: account.ErrPaymasterTypedDataMismatch,
+		},
+		{
+			Name:        "other domain name",
+			ChainID:     "SN_SEPOLIA",
+			DomainName:  "Account.execute_from_inside",
+			Calls:       []paymaster.Call{requested, feeTransfer(10)},
+			FeeMode:     defaultFeeMode,
+			ExpectedErr: account.ErrPaymasterTypedDataMismatch,
+		},
+		{
+			Name:        "unexpected caller",
+			ChainID:     "SN_SEPOLIA",
+			Caller:      relayer,
+			Calls:       []paymaster.Call{requested, feeTransfer(10)},
+			FeeMode:     defaultFeeMode,
+			ExpectedErr: account.ErrPaymasterTypedDataMismatch,
+		},
+		{
+			Name:    "dropped",
+			ChainID: "SN_SEPOLIA",
+			Calls:   []paymaster.Call{requested, feeTransfer(10)},
+			FeeMode: defaultFeeMode,
+			Statuses: []paymaster.TxnStatus{
+				paymaster.TxnStatusActive,
+				paymaster.TxnStatusDropped,
+			},
+			ExpectedErr: account.ErrPaymasterTxnDropped,
+		},
+		{
+			Name:        "no gas token",
+			ChainID:     "SN_SEPOLIA",
+			Calls:       []paymaster.Call{requested, feeTransfer(10)},
+			FeeMode:     paymaster.FeeMode{Mode: paymaster.FeeModeDefault},
+			ExpectedErr: account.ErrPaymasterInvalidRequest,
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Name, func(t *testing.T) {
+			maxFee := test.MaxFee
+			if maxFee == nil {
+				maxFee = new(felt.Felt).SetUint64(1000)
+			}
+			pm := &fakePaymaster{
+				chainID:    test.ChainID,
+				domainName: test.DomainName,
+				caller:     test.Caller,
+				calls:      test.Calls,
+				maxFee:     maxFee,
+				statuses:   test.Statuses,
+				feeAmount:  test.FeeAmount,
+			}
+			txnOpts := test.Opts
+			if txnOpts == nil {
+				txnOpts = opts
+			}
+
+			_, err := acc.SendViaPaymaster(t.Context(), pm, functionCalls, test.FeeMode, txnOpts)
+			require.ErrorIs(t, err, test.ExpectedErr)
+			if test.Statuses == nil {
+				assert.Nil(t, pm.executed, "the typed data must not be signed")
+			}
+		})
+	}
+}
@@ -0,0 +1,406 @@
+package account
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	// ErrPolicyViolation is wrapped by all the errors returned when a transaction
+	// is rejected by the signing policy.
+	ErrPolicyViolation = errors.New("signing policy violation")
+
+	ErrPolicyUnsupportedTxn   = fmt.Errorf("%w: unsupported transaction", ErrPolicyViolation)
+	ErrMaxFeeExceeded         = fmt.Errorf("%w: max fee exceeded", ErrPolicyViolation)
+	ErrTargetNotAllowed       = fmt.Errorf("%w: target contract not allowed", ErrPolicyViolation)
+	ErrSelectorNotAllowed     = fmt.Errorf("%w: selector not allowed", ErrPolicyViolation)
+	ErrRateLimitExceeded      = fmt.Errorf("%w: rate limit exceeded", ErrPolicyViolation)
+	ErrTransferLimitExceeded  = fmt.Errorf("%w: transfer limit exceeded", ErrPolicyViolation)
+	ErrUndecodableTransaction = fmt.Errorf("%w: calldata can't be decoded", ErrPolicyViolation)
+)
+
+// transferSelector is the selector of the ERC-20 `transfer` function.
+var transferSelector = utils.GetSelectorFromNameFelt("transfer")
+
+// RateLimit limits the number of transactions signed within a time window.
+type RateLimit struct {
+	// The maximum number of transactions signed within the window
+	MaxTxns int
+	// The length of the sliding window
+	Window time.Duration
+}
+
+// TransferLimit limits the cumulative amount of a token transferred within a
+// time window.
+type TransferLimit struct {
+	// The maximum cumulative amount, in base units of the token
+	Max *big.Int
+	// The length of the sliding window. If 0, the limit applies to each
+	// transaction only.
+	Window time.Duration
+}
+
+// SigningPolicy is a set of rules checked by the Sign*Transaction methods of an
+// Account before signing a transaction, to protect the account from bugs in
+// the fee estimation or the calldata building. Set it in the Account.Policy
+// field. A violation returns an error wrapping ErrPolicyViolation, and the
+// transaction isn't signed.
+//
+// Only v3 transactions are supported. The transactions used for the fee
+// estimation, with a query version or zero resource bounds, are checked
+// against the allowlist but don't count towards the limits. The other
+// transactions count towards the limits once they are signed, so a failed
+// signature doesn't use up the quota.
+//
+// The invoke calldata is decoded in the format of the Cairo version of the
+// account only. The outside executions signed by the SendViaPaymaster methods
+// are checked against the allowed targets, the rate limit and the transfer
+// limits, their fee transfer counting towards the transfer limits.
+//
+// The zero value of each field disables its rule. A SigningPolicy is safe for
+// concurrent use, but its fields should not be modified after it is in use.
+type SigningPolicy struct {
+	// The maximum overall fee of a transaction in FRI, computed from its
+	// resource bounds and tip
+	MaxFee *felt.Felt
+	// The contracts the invoke transactions can call, each mapped to the
+	// selectors it can be called with. A contract with no selectors can be
+	// called with any selector. Use the Allow method to fill it. If nil, any
+	// contract can be called.
+	AllowedTargets map[felt.Felt][]*felt.Felt
+	// The limit of signed transactions
+	RateLimit *RateLimit
+	// The limits of the amounts transferred by the ERC-20 `transfer` calls of
+	// the invoke transactions, by token address
+	TransferLimits map[felt.Felt]TransferLimit
+	// Returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	signedAt  []time.Time
+	transfers map[felt.Felt][]transferRecord
+}
+
+// transferRecord is an amount transferred at a given time.
+type transferRecord struct {
+	at     time.Time
+	amount *big.Int
+}
+
+// Allow adds a contract to the allowed targets of the policy, with the
+// functions it can be called with. If no function is given, the contract can
+// be called with any selector.
+//
+// Parameters:
+//   - contract: the contract address
+//   - functionNames: the names of the allowed functions, e.g. "transfer"
+//
+// Returns:
+//   - *SigningPolicy: the policy, to chain the calls
+func (policy *SigningPolicy) Allow(contract *felt.Felt, functionNames ...string) *SigningPolicy {
+	if policy.AllowedTargets == nil {
+		policy.AllowedTargets = make(map[felt.Felt][]*felt.Felt)
+	}
+	selectors := policy.AllowedTargets[*contract]
+	for _, name := range functionNames {
+		selectors = append(selectors, utils.GetSelectorFromNameFelt(name))
+	}
+	policy.AllowedTargets[*contract] = selectors
+
+	return policy
+}
+
+// Check checks a transaction against the policy. It is called by the
+// Sign*Transaction methods of the Account before signing the transaction.
+//
+// Unless it is a fee estimation transaction, the transaction must then be
+// recorded for the rate and transfer limits by calling the returned function,
+// once it is signed. The function checks the limits again, as other
+// transactions may have been signed in the meantime, so the signature must be
+// discarded if it returns an error.
+//
+// Parameters:
+//   - txn: a pointer to a v3 invoke, declare or deploy account transaction
+//   - cairoVersion: the Cairo version of the signing account, giving the format
+//     of the invoke calldata
+//
+// Returns:
+//   - func() error: records the signed transaction
+//   - error: an error wrapping ErrPolicyViolation if the transaction is rejected
+func (policy *SigningPolicy) Check(txn any, cairoVersion CairoVersion) (func() error, error) {
+	var resBounds *rpc.ResourceBoundsMapping
+	var tip rpc.U64
+	var version rpc.TransactionVersion
+	var calls []rpc.FunctionCall
+
+	switch tx := txn.(type) {
+	case *rpc.InvokeTxnV3:
+		resBounds, tip, version = tx.ResourceBounds, tx.Tip, tx.Version
+		if policy.AllowedTargets != nil || len(policy.TransferLimits) > 0 {
+			var err error
+			calls, err = decodeAccountCalldata(tx.Calldata, cairoVersion)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrUndecodableTransaction, err)
+			}
+		}
+	case *rpc.DeclareTxnV3:
+		resBounds, tip, version = tx.ResourceBounds, tx.Tip, tx.Version
+	case *rpc.BroadcastDeclareTxnV3:
+		resBounds, tip, version = tx.ResourceBounds, tx.Tip, tx.Version
+	case *rpc.DeployAccountTxnV3:
+		resBounds, tip, version = tx.ResourceBounds, tx.Tip, tx.Version
+	default:
+		return nil, fmt.Errorf(
+			"%w: only v3 transactions are supported, got %T",
+			ErrPolicyUnsupportedTxn,
+			txn,
+		)
+	}
+
+	fee, err := overallFee(resBounds, tip)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPolicyUnsupportedTxn, err)
+	}
+	if policy.MaxFee != nil && fee.Cmp(policy.MaxFee.BigInt(new(big.Int))) > 0 {
+		return nil, fmt.Errorf(
+			"%w: the fee %s exceeds %s FRI",
+			ErrMaxFeeExceeded,
+			fee,
+			policy.MaxFee,
+		)
+	}
+
+	// a transaction with a query version or no fee can't be executed, it is
+	// only used for the fee estimation
+	estimation := version == rpc.TransactionV3WithQueryBit || fee.Sign() == 0
+
+	return policy.checkCalls(calls, nil, estimation)
+}
+
+// checkOutsideExecution checks the calls of an outside execution signed by the
+// account, e.g. built by a paymaster, against the policy. The fee transfer, if
+// any, is exempt from the allowed targets but counts towards the transfer
+// limits. The returned function records the outside execution once it is signed.
+func (policy *SigningPolicy) checkOutsideExecution(
+	calls []rpc.FunctionCall,
+	feeTransfer *rpc.FunctionCall,
+) (func() error, error) {
+	return policy.checkCalls(calls, feeTransfer, false)
+}
+
+// checkCalls checks the calls of a transaction against the allowed targets and
+// the limits, the fee transfer, if any, counting towards the transfer limits
+// only. Unless estimation is set, the previous transactions count towards the
+// limits, and the returned function records the transaction.
+func (policy *SigningPolicy) checkCalls(
+	calls []rpc.FunctionCall,
+	feeTransfer *rpc.FunctionCall,
+	estimation bool,
+) (func() error, error) {
+	if err := policy.checkTargets(calls); err != nil {
+		return nil, err
+	}
+
+	transferred := calls
+	if feeTransfer != nil {
+		transferred = append(slices.Clone(calls), *feeTransfer)
+	}
+	transfers, err := transferAmounts(transferred)
+	if err != nil {
+		return nil, err
+	}
+
+	if estimation {
+		return func() error { return nil }, policy.checkTransferAmounts(transfers)
+	}
+	if err := policy.record(transfers, false); err != nil {
+		return nil, err
+	}
+
+	return func() error { return policy.record(transfers, true) }, nil
+}
+
+// decodeAccountCalldata decodes the `__execute__` calldata of an account in the
+// format of its Cairo version only, so the calls can't be read in the other
+// format.
+func decodeAccountCalldata(
+	calldata []*felt.Felt,
+	cairoVersion CairoVersion,
+) ([]rpc.FunctionCall, error) {
+	switch cairoVersion {
+	case CairoV0:
+		return DecodeCallDataCairo0(calldata)
+	case CairoV2:
+		return DecodeCallDataCairo2(calldata)
+	default:
+		return nil, fmt.Errorf("account cairo version '%d' not supported", cairoVersion)
+	}
+}
+
+// checkTargets checks the calls against the allowed targets.
+func (policy *SigningPolicy) checkTargets(calls []rpc.FunctionCall) error {
+	if policy.AllowedTargets == nil {
+		return nil
+	}
+
+	for _, call := range calls {
+		selectors, ok := policy.AllowedTargets[*call.ContractAddress]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrTargetNotAllowed, call.ContractAddress)
+		}
+		if len(selectors) > 0 && !slices.ContainsFunc(selectors, call.EntryPointSelector.Equal) {
+			return fmt.Errorf(
+				"%w: %s on %s",
+				ErrSelectorNotAllowed,
+				call.EntryPointSelector,
+				call.ContractAddress,
+			)
+		}
+	}
+
+	return nil
+}
+
+// checkTransferAmounts checks the transfers of a single transaction against the
+// transfer limits, ignoring the previous transfers.
+func (policy *SigningPolicy) checkTransferAmounts(transfers map[felt.Felt]*big.Int) error {
+	for token, amount := range transfers {
+		limit, ok := policy.TransferLimits[token]
+		if ok && limit.Max != nil && amount.Cmp(limit.Max) > 0 {
+			return fmt.Errorf(
+				"%w: transferring %s of token %s exceeds %s",
+				ErrTransferLimitExceeded,
+				amount,
+				&token,
+				limit.Max,
+			)
+		}
+	}
+
+	return nil
+}
+
+// record checks the rate and transfer limits including the previous
+// transactions and, if commit is set, records the transaction if they are
+// respected.
+func (policy *SigningPolicy) record(transfers map[felt.Felt]*big.Int, commit bool) error {
+	policy.mu.Lock()
+	defer policy.mu.Unlock()
+
+	now := time.Now()
+	if policy.Now != nil {
+		now = policy.Now()
+	}
+
+	if policy.RateLimit != nil {
+		policy.signedAt = slices.DeleteFunc(policy.signedAt, func(at time.Time) bool {
+			return now.Sub(at) >= policy.RateLimit.Window
+		})
+		if len(policy.signedAt) >= policy.RateLimit.MaxTxns {
+			return fmt.Errorf(
+				"%w: %d transactions already signed in the last %s",
+				ErrRateLimitExceeded,
+				len(policy.signedAt),
+				policy.RateLimit.Window,
+			)
+		}
+	}
+
+	if policy.transfers == nil {
+		policy.transfers = make(map[felt.Felt][]transferRecord)
+	}
+	for token, amount := range transfers {
+		limit, ok := policy.TransferLimits[token]
+		if !ok || limit.Max == nil {
+			continue
+		}
+		records := slices.DeleteFunc(policy.transfers[token], func(record transferRecord) bool {
+			return now.Sub(record.at) >= limit.Window
+		})
+		policy.transfers[token] = records
+
+		total := new(big.Int).Set(amount)
+		for _, record := range records {
+			total.Add(total, record.amount)
+		}
+		if total.Cmp(limit.Max) > 0 {
+			return fmt.Errorf(
+				"%w: transferring %s of token %s would bring the total to %s, exceeding %s",
+				ErrTransferLimitExceeded,
+				amount,
+				&token,
+				total,
+				limit.Max,
+			)
+		}
+	}
+	if !commit {
+		return nil
+	}
+
+	if policy.RateLimit != nil {
+		policy.signedAt = append(policy.signedAt, now)
+	}
+	for token, amount := range transfers {
+		if limit, ok := policy.TransferLimits[token]; ok && limit.Window > 0 {
+			policy.transfers[token] = append(
+				policy.transfers[token],
+				transferRecord{at: now, amount: amount},
+			)
+		}
+	}
+
+	return nil
+}
+
+// overallFee returns the maximum overall fee of a v3 transaction.
+func overallFee(resBounds *rpc.ResourceBoundsMapping, tip rpc.U64) (*big.Int, error) {
+	if tip == "" {
+		tip = "0x0"
+	}
+	fee, err := utils.ResBoundsMapToOverallFee(resBounds, 1, tip)
+	if err != nil {
+		return nil, err
+	}
+
+	return fee.BigInt(new(big.Int)), nil
+}
+
+// transferAmounts sums the amounts of the ERC-20 `transfer` calls by token.
+func transferAmounts(calls []rpc.FunctionCall) (map[felt.Felt]*big.Int, error) {
+	transfers := make(map[felt.Felt]*big.Int)
+	for _, call := range calls {
+		if !call.EntryPointSelector.Equal(transferSelector) {
+			continue
+		}
+		// recipient, amount low, amount high
+		if len(call.Calldata) != 3 { //nolint:mnd // transfer calldata length
+			return nil, fmt.Errorf(
+				"%w: invalid transfer calldata on %s",
+				ErrUndecodableTransaction,
+				call.ContractAddress,
+			)
+		}
+		amount, err := utils.AmountFromU256(call.Calldata[1], call.Calldata[2], 0)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUndecodableTransaction, err)
+		}
+
+		total, ok := transfers[*call.ContractAddress]
+		if !ok {
+			total = new(big.Int)
+			transfers[*call.ContractAddress] = total
+		}
+		total.Add(total, amount.Value)
+	}
+
+	return transfers, nil
+}
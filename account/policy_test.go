@@ -0,0 +1,236 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/internal/tests"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errKeystore = errors.New("keystore failure")
+
+// failingKeystore is a keystore failing to sign.
+type failingKeystore struct{}
+
+func (failingKeystore) Sign(context.Context, string, *big.Int) (x, y *big.Int, err error) {
+	return nil, nil, errKeystore
+}
+
+// TestSigningPolicy tests that the signing policy of an account rejects the
+// transactions breaking its rules, without signing them.
+func TestSigningPolicy(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+	t.Parallel()
+
+	token := internalUtils.TestHexToFelt(t, "0x1234")
+	other := internalUtils.TestHexToFelt(t, "0x5678")
+	recipient := internalUtils.TestHexToFelt(t, "0xabc")
+
+	resBounds := func(l2Amount uint64) *rpc.ResourceBoundsMapping {
+		return &rpc.ResourceBoundsMapping{
+			L1Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+			L1DataGas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+			L2Gas: rpc.ResourceBounds{
+				MaxAmount:       rpc.U64(new(felt.Felt).SetUint64(l2Amount).String()),
+				MaxPricePerUnit: "0x1",
+			},
+		}
+	}
+	transfer := func(contract *felt.Felt, amount uint64) rpc.FunctionCall {
+		return rpc.FunctionCall{
+			ContractAddress:    contract,
+			EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+			Calldata:           []*felt.Felt{recipient, new(felt.Felt).SetUint64(amount), {}},
+		}
+	}
+	invokeTxn := func(fee uint64, calls ...rpc.FunctionCall) *rpc.InvokeTxnV3 {
+		return &rpc.InvokeTxnV3{
+			Type:                  rpc.TransactionTypeInvoke,
+			Version:               rpc.TransactionV3,
+			SenderAddress:         internalUtils.DeadBeef,
+			Nonce:                 new(felt.Felt).SetUint64(1),
+			Calldata:              FmtCallDataCairo2(calls),
+			ResourceBounds:        resBounds(fee),
+			Tip:                   "0x0",
+			PayMasterData:         []*felt.Felt{},
+			AccountDeploymentData: []*felt.Felt{},
+			NonceDataMode:         rpc.DAModeL1,
+			FeeMode:               rpc.DAModeL1,
+		}
+	}
+
+	newAccount := func(policy *SigningPolicy) *Account {
+		ks, pubKey, _ := GetRandomKeys()
+
+		return &Account{
+			ChainID:      new(felt.Felt).SetBytes([]byte("SN_SEPOLIA")),
+			Address:      internalUtils.DeadBeef,
+			publicKey:    pubKey.String(),
+			CairoVersion: CairoV2,
+			Policy:       policy,
+			ks:           ks,
+		}
+	}
+
+	t.Run("max fee", func(t *testing.T) {
+		t.Parallel()
+		acc := newAccount(&SigningPolicy{MaxFee: new(felt.Felt).SetUint64(1000)})
+
+		txn := invokeTxn(1001, transfer(token, 1))
+		err := acc.SignInvokeTransaction(t.Context(), txn)
+		require.ErrorIs(t, err, ErrMaxFeeExceeded)
+		require.ErrorIs(t, err, ErrPolicyViolation)
+		assert.Empty(t, txn.Signature)
+
+		txn = invokeTxn(1000, transfer(token, 1))
+		require.NoError(t, acc.SignInvokeTransaction(t.Context(), txn))
+		assert.Len(t, txn.Signature, 2)
+	})
+
+	t.Run("allowed targets", func(t *testing.T) {
+		t.Parallel()
+		policy := new(SigningPolicy).Allow(token, "transfer").Allow(other)
+		acc := newAccount(policy)
+
+		require.NoError(t, acc.SignInvokeTransaction(
+			t.Context(),
+			invokeTxn(10, transfer(token, 1), transfer(other, 1)),
+		))
+
+		approve := rpc.FunctionCall{
+			ContractAddress:    token,
+			EntryPointSelector: utils.GetSelectorFromNameFelt("approve"),
+			Calldata:           []*felt.Felt{recipient, new(felt.Felt).SetUint64(1), {}},
+		}
+		err := acc.SignInvokeTransaction(t.Context(), invokeTxn(10, approve))
+		require.ErrorIs(t, err, ErrSelectorNotAllowed)
+
+		err = acc.SignInvokeTransaction(t.Context(), invokeTxn(10, transfer(recipient, 1)))
+		require.ErrorIs(t, err, ErrTargetNotAllowed)
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		t.Parallel()
+		now := time.Unix(1_700_000_000, 0)
+		acc := newAccount(&SigningPolicy{
+			RateLimit: &RateLimit{MaxTxns: 2, Window: time.Minute},
+			Now:       func() time.Time { return now },
+		})
+
+		for range 2 {
+			require.NoError(t, acc.SignInvokeTransaction(t.Context(), invokeTxn(10)))
+		}
+		// the fee estimation transactions don't count
+		require.NoError(t, acc.SignInvokeTransaction(t.Context(), invokeTxn(0)))
+
+		err := acc.SignInvokeTransaction(t.Context(), invokeTxn(10))
+		require.ErrorIs(t, err, ErrRateLimitExceeded)
+
+		now = now.Add(time.Minute)
+		require.NoError(t, acc.SignInvokeTransaction(t.Context(), invokeTxn(10)))
+	})
+
+	t.Run("transfer limit", func(t *testing.T) {
+		t.Parallel()
+		now := time.Unix(1_700_000_000, 0)
+		acc := newAccount(&SigningPolicy{
+			TransferLimits: map[felt.Felt]TransferLimit{
+				*token: {Max: big.NewInt(100), Window: time.Hour},
+			},
+			Now: func() time.Time { return now },
+		})
+
+		// a single transaction above the limit is rejected, even when estimated
+		err := acc.SignInvokeTransaction(t.Context(), invokeTxn(0, transfer(token, 101)))
+		require.ErrorIs(t, err, ErrTransferLimitExceeded)
+
+		require.NoError(t, acc.SignInvokeTransaction(
+			t.Context(),
+			invokeTxn(10, transfer(token, 30), transfer(token, 30)),
+		))
+		// the other tokens aren't limited
+		require.NoError(t, acc.SignInvokeTransaction(
+			t.Context(),
+			invokeTxn(10, transfer(other, 1000)),
+		))
+
+		err = acc.SignInvokeTransaction(t.Context(), invokeTxn(10, transfer(token, 41)))
+		require.ErrorIs(t, err, ErrTransferLimitExceeded)
+		require.NoError(
+			t,
+			acc.SignInvokeTransaction(t.Context(), invokeTxn(10, transfer(token, 40))),
+		)
+
+		now = now.Add(time.Hour)
+		require.NoError(
+			t,
+			acc.SignInvokeTransaction(t.Context(), invokeTxn(10, transfer(token, 100))),
+		)
+	})
+
+	t.Run("cairo 0 account", func(t *testing.T) {
+		t.Parallel()
+		acc := newAccount(new(SigningPolicy).Allow(token, "transfer"))
+		acc.CairoVersion = CairoV0
+
+		txn := invokeTxn(10)
+		txn.Calldata = FmtCallDataCairo0([]rpc.FunctionCall{transfer(token, 1)})
+		require.NoError(t, acc.SignInvokeTransaction(t.Context(), txn))
+
+		txn = invokeTxn(10)
+		txn.Calldata = FmtCallDataCairo0([]rpc.FunctionCall{transfer(other, 1)})
+		err := acc.SignInvokeTransaction(t.Context(), txn)
+		require.ErrorIs(t, err, ErrTargetNotAllowed)
+
+		// the Cairo 2 calldata is not decoded for a Cairo 0 account
+		err = acc.SignInvokeTransaction(t.Context(), invokeTxn(10, transfer(token, 1)))
+		require.ErrorIs(t, err, ErrUndecodableTransaction)
+	})
+
+	t.Run("failed signature", func(t *testing.T) {
+		t.Parallel()
+		acc := newAccount(&SigningPolicy{
+			RateLimit: &RateLimit{MaxTxns: 1, Window: time.Minute},
+			TransferLimits: map[felt.Felt]TransferLimit{
+				*token: {Max: big.NewInt(100), Window: time.Hour},
+			},
+		})
+		ks := acc.ks
+		acc.ks = failingKeystore{}
+
+		txn := invokeTxn(10, transfer(token, 100))
+		err := acc.SignInvokeTransaction(t.Context(), txn)
+		require.ErrorIs(t, err, errKeystore)
+		assert.Empty(t, txn.Signature)
+
+		// the failed signature doesn't count towards the limits
+		acc.ks = ks
+		require.NoError(t, acc.SignInvokeTransaction(t.Context(), txn))
+		assert.Len(t, txn.Signature, 2)
+
+		err = acc.SignInvokeTransaction(t.Context(), invokeTxn(10))
+		require.ErrorIs(t, err, ErrRateLimitExceeded)
+	})
+
+	t.Run("unsupported transaction", func(t *testing.T) {
+		t.Parallel()
+		acc := newAccount(&SigningPolicy{})
+
+		err := acc.SignInvokeTransaction(t.Context(), &rpc.InvokeTxnV1{
+			Version:       rpc.TransactionV1,
+			SenderAddress: internalUtils.DeadBeef,
+			MaxFee:        new(felt.Felt).SetUint64(1),
+			Nonce:         new(felt.Felt),
+		})
+		require.ErrorIs(t, err, ErrPolicyUnsupportedTxn)
+	})
+}
@@ -45,21 +45,26 @@ func (account *Account) SignInvokeTransaction(
 	ctx context.Context,
 	invokeTx rpc.InvokeTxnType,
 ) error {
+	record, err := account.checkPolicy(invokeTx)
+	if err != nil {
+		return err
+	}
+
 	switch invoke := invokeTx.(type) {
 	case *rpc.InvokeTxnV0:
-		signature, err := signInvokeTransaction(ctx, account, invoke)
+		signature, err := signInvokeTransaction(ctx, account, invoke, record)
 		if err != nil {
 			return err
 		}
 		invoke.Signature = signature
 	case *rpc.InvokeTxnV1:
-		signature, err := signInvokeTransaction(ctx, account, invoke)
+		signature, err := signInvokeTransaction(ctx, account, invoke, record)
 		if err != nil {
 			return err
 		}
 		invoke.Signature = signature
 	case *rpc.InvokeTxnV3:
-		signature, err := signInvokeTransaction(ctx, account, invoke)
+		signature, err := signInvokeTransaction(ctx, account, invoke, record)
 		if err != nil {
 			return err
 		}
@@ -79,6 +84,7 @@ func signInvokeTransaction[T rpc.InvokeTxnType](
 	ctx context.Context,
 	account *Account,
 	invokeTx *T,
+	record func() error,
 ) ([]*felt.Felt, error) {
 	txHash, err := account.TransactionHashInvoke(*invokeTx)
 	if err != nil {
@@ -88,6 +94,9 @@ func signInvokeTransaction[T rpc.InvokeTxnType](
 	if err != nil {
 		return nil, err
 	}
+	if err := record(); err != nil {
+		return nil, err
+	}
 
 	return signature, nil
 }
@@ -106,15 +115,32 @@ func (account *Account) SignDeployAccountTransaction(
 	tx rpc.DeployAccountType,
 	precomputeAddress *felt.Felt,
 ) error {
+	record, err := account.checkPolicy(tx)
+	if err != nil {
+		return err
+	}
+
 	switch deployAcc := tx.(type) {
 	case *rpc.DeployAccountTxnV1:
-		signature, err := signDeployAccountTransaction(ctx, account, deployAcc, precomputeAddress)
+		signature, err := signDeployAccountTransaction(
+			ctx,
+			account,
+			deployAcc,
+			precomputeAddress,
+			record,
+		)
 		if err != nil {
 			return err
 		}
 		deployAcc.Signature = signature
 	case *rpc.DeployAccountTxnV3:
-		signature, err := signDeployAccountTransaction(ctx, account, deployAcc, precomputeAddress)
+		signature, err := signDeployAccountTransaction(
+			ctx,
+			account,
+			deployAcc,
+			precomputeAddress,
+			record,
+		)
 		if err != nil {
 			return err
 		}
@@ -136,6 +162,7 @@ func signDeployAccountTransaction[T rpc.DeployAccountType](
 	account *Account,
 	tx *T,
 	precomputeAddress *felt.Felt,
+	record func() error,
 ) ([]*felt.Felt, error) {
 	txHash, err := account.TransactionHashDeployAccount(*tx, precomputeAddress)
 	if err != nil {
@@ -145,6 +172,9 @@ func signDeployAccountTransaction[T rpc.DeployAccountType](
 	if err != nil {
 		return nil, err
 	}
+	if err := record(); err != nil {
+		return nil, err
+	}
 
 	return signature, nil
 }
@@ -158,27 +188,32 @@ func signDeployAccountTransaction[T rpc.DeployAccountType](
 // Returns:
 //   - error: an error if any
 func (account *Account) SignDeclareTransaction(ctx context.Context, tx rpc.DeclareTxnType) error {
+	record, err := account.checkPolicy(tx)
+	if err != nil {
+		return err
+	}
+
 	switch declare := tx.(type) {
 	case *rpc.DeclareTxnV1:
-		signature, err := signDeclareTransaction(ctx, account, declare)
+		signature, err := signDeclareTransaction(ctx, account, declare, record)
 		if err != nil {
 			return err
 		}
 		declare.Signature = signature
 	case *rpc.DeclareTxnV2:
-		signature, err := signDeclareTransaction(ctx, account, declare)
+		signature, err := signDeclareTransaction(ctx, account, declare, record)
 		if err != nil {
 			return err
 		}
 		declare.Signature = signature
 	case *rpc.DeclareTxnV3:
-		signature, err := signDeclareTransaction(ctx, account, declare)
+		signature, err := signDeclareTransaction(ctx, account, declare, record)
 		if err != nil {
 			return err
 		}
 		declare.Signature = signature
 	case *rpc.BroadcastDeclareTxnV3:
-		signature, err := signDeclareTransaction(ctx, account, declare)
+		signature, err := signDeclareTransaction(ctx, account, declare, record)
 		if err != nil {
 			return err
 		}
@@ -199,6 +234,7 @@ func signDeclareTransaction[T rpc.DeclareTxnType](
 	ctx context.Context,
 	account *Account,
 	tx *T,
+	record func() error,
 ) ([]*felt.Felt, error) {
 	txHash, err := account.TransactionHashDeclare(*tx)
 	if err != nil {
@@ -208,6 +244,9 @@ func signDeclareTransaction[T rpc.DeclareTxnType](
 	if err != nil {
 		return nil, err
 	}
+	if err := record(); err != nil {
+		return nil, err
+	}
 
 	return signature, nil
 }
@@ -230,3 +269,13 @@ func (account *Account) Verify(msgHash *felt.Felt, signature []*felt.Felt) (bool
 
 	return curve.VerifyFelts(msgHash, signature[0], signature[1], publicKeyFelt)
 }
+
+// checkPolicy checks a transaction against the signing policy of the account, if
+// any. The returned function records the transaction once it is signed.
+func (account *Account) checkPolicy(txn any) (func() error, error) {
+	if account.Policy == nil {
+		return func() error { return nil }, nil
+	}
+
+	return account.Policy.Check(txn, account.CairoVersion)
+}
@@ -3,6 +3,7 @@ package account
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
@@ -82,10 +83,10 @@ func (account *Account) BuildAndSendInvokeTxn(
 		return response, err
 	}
 	txnFee := estimateFee[0]
-	broadcastInvokeTxnV3.ResourceBounds = utils.FeeEstToResBoundsMap(
-		txnFee,
-		opts.FmtFeeMultiplier(),
-	)
+	broadcastInvokeTxnV3.ResourceBounds, err = calculateResourceBounds(ctx, opts, txnFee)
+	if err != nil {
+		return response, err
+	}
 
 	// assuring the signed txn version will be rpc.TransactionV3, since queryBit
 	// txn version is only used for estimation/simulation
@@ -183,10 +184,10 @@ func (account *Account) BuildAndSendDeclareTxn(
 		return response, err
 	}
 	txnFee := estimateFee[0]
-	broadcastDeclareTxnV3.ResourceBounds = utils.FeeEstToResBoundsMap(
-		txnFee,
-		opts.FmtFeeMultiplier(),
-	)
+	broadcastDeclareTxnV3.ResourceBounds, err = calculateResourceBounds(ctx, opts, txnFee)
+	if err != nil {
+		return response, err
+	}
 
 	// assuring the signed txn version will be rpc.TransactionV3, since queryBit
 	// txn version is only used for estimation/simulation
@@ -276,10 +277,10 @@ func (account *Account) BuildAndEstimateDeployAccountTxn(
 		return nil, nil, err
 	}
 	txnFee := estimateFee[0]
-	broadcastDepAccTxnV3.ResourceBounds = utils.FeeEstToResBoundsMap(
-		txnFee,
-		opts.FmtFeeMultiplier(),
-	)
+	broadcastDepAccTxnV3.ResourceBounds, err = calculateResourceBounds(ctx, opts, txnFee)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// assuring the signed txn version will be rpc.TransactionV3, since queryBit
 	// txn version is only used for estimation/simulation
@@ -296,8 +297,8 @@ func (account *Account) BuildAndEstimateDeployAccountTxn(
 }
 
 // calculateTip returns the tip to be used in the transaction. If a custom tip is
-// provided, it returns it. Otherwise, it estimates the tip using the provider
-// based on the tip multiplier.
+// provided, it returns it. Otherwise, it uses the fee strategy if set, or estimates
+// the tip using the provider based on the tip multiplier.
 func calculateTip(
 	ctx context.Context,
 	provider rpc.RPCProvider,
@@ -307,6 +308,15 @@ func calculateTip(
 		return opts.CustomTip, nil
 	}
 
+	if opts.FeeStrategy != nil {
+		tip, err := opts.FeeStrategy.Tip(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get tip from the fee strategy: %w", err)
+		}
+
+		return tip, nil
+	}
+
 	tip, err := rpc.EstimateTip(ctx, provider, opts.FmtTipMultiplier())
 	if err != nil {
 		return "", fmt.Errorf("failed to estimate tip: %w", err)
@@ -315,6 +325,91 @@ func calculateTip(
 	return tip, nil
 }
 
+// calculateResourceBounds returns the resource bounds of the transaction from its
+// fee estimate, using the fee strategy if set, or the fee multiplier otherwise.
+func calculateResourceBounds(
+	ctx context.Context,
+	opts *TxnOptions,
+	estimate rpc.FeeEstimation,
+) (*rpc.ResourceBoundsMapping, error) {
+	if opts.FeeStrategy == nil {
+		return utils.FeeEstToResBoundsMap(estimate, opts.FmtFeeMultiplier()), nil
+	}
+
+	resBounds, err := opts.FeeStrategy.ResourceBounds(ctx, estimate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource bounds from the fee strategy: %w", err)
+	}
+
+	return resBounds, nil
+}
+
+// buildUnsignedInvokeTxn builds a v3 invoke transaction with the given function
+// calls, without signing it. The nonce, tip and fees are fetched from the
+// provider, the fee being estimated with the `SKIP_VALIDATE` simulation flag
+// since no signature is available.
+func (account *Account) buildUnsignedInvokeTxn(
+	ctx context.Context,
+	functionCalls []rpc.InvokeFunctionCall,
+	opts *TxnOptions,
+) (*rpc.BroadcastInvokeTxnV3, error) {
+	nonce, err := account.Nonce(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	callData, err := account.FmtCalldata(utils.InvokeFuncCallsToFunctionCalls(functionCalls))
+	if err != nil {
+		return nil, err
+	}
+
+	if opts == nil {
+		opts = new(TxnOptions)
+	}
+	tip, err := calculateTip(ctx, account.Provider, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	broadcastInvokeTxnV3 := utils.BuildInvokeTxn(
+		account.Address,
+		nonce,
+		callData,
+		makeResourceBoundsMapWithZeroValues(),
+		&utils.TxnOptions{
+			Tip:            tip,
+			UseQueryBit:    opts.UseQueryBit,
+			UseBlake2sHash: false,
+		},
+	)
+
+	// the txn can't be signed yet, so its validation is skipped
+	simulationFlags := opts.SimulationFlags()
+	if !slices.Contains(simulationFlags, rpc.SkipValidate) {
+		simulationFlags = append(simulationFlags, rpc.SkipValidate)
+	}
+
+	estimateFee, err := account.Provider.EstimateFee(
+		ctx,
+		[]rpc.BroadcastTxn{broadcastInvokeTxnV3},
+		simulationFlags,
+		opts.BlockID(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	broadcastInvokeTxnV3.ResourceBounds, err = calculateResourceBounds(ctx, opts, estimateFee[0])
+	if err != nil {
+		return nil, err
+	}
+
+	// assuring the signed txn version will be rpc.TransactionV3, since queryBit
+	// txn version is only used for estimation/simulation
+	broadcastInvokeTxnV3.Version = rpc.TransactionV3
+
+	return broadcastInvokeTxnV3, nil
+}
+
 // A helper to deploy a contract from an existing class using UDC.
 //
 // Parameters:
@@ -492,16 +587,11 @@ func (account *Account) WaitForTransactionReceipt(
 //   - bool: whether to use the Blake2s hash function for the compiled class hash
 //   - error: an error if any
 func shouldUseBlake2sHash(ctx context.Context, provider rpc.RPCProvider) (bool, error) {
-	block, err := provider.BlockWithTxHashes(ctx, rpc.WithBlockTag(rpc.BlockTagLatest))
+	blockTxHashes, err := provider.ConfirmedBlockWithTxHashes(ctx, rpc.WithLatestBlock())
 	if err != nil {
 		return false, fmt.Errorf("failed to get block with tx hashes: %w", err)
 	}
 
-	blockTxHashes, ok := block.(*rpc.BlockTxHashes)
-	if !ok {
-		return false, fmt.Errorf("block is not a BlockTxHashes: %T", block)
-	}
-
 	upgradeVersion := semver.MustParse("0.14.1")
 
 	currentVersion, err := semver.NewVersion(blockTxHashes.StarknetVersion)
@@ -226,7 +226,7 @@ func TestBuildAndSendDeclareTxnMock(t *testing.T) {
 					Return(new(felt.Felt).SetUint64(1), nil).
 					Times(1)
 				mockRPCProvider.EXPECT().
-					BlockWithTxs(t.Context(), rpc.WithBlockTag(rpc.BlockTagLatest)).
+					ConfirmedBlockWithTxs(t.Context(), rpc.WithLatestBlock()).
 					Return(&rpc.Block{}, nil).Times(1)
 				mockRPCProvider.EXPECT().
 					EstimateFee(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
@@ -250,7 +250,7 @@ func TestBuildAndSendDeclareTxnMock(t *testing.T) {
 					// if txnOptions is nil, the code should call the BlockWithTxHashes method to get the
 					// Starknet version and decide whether to use the Blake2s hash function
 					mockRPCProvider.EXPECT().
-						BlockWithTxHashes(gomock.Any(), rpc.WithBlockTag(rpc.BlockTagLatest)).
+						ConfirmedBlockWithTxHashes(gomock.Any(), rpc.WithLatestBlock()).
 						Return(&rpc.BlockTxHashes{
 							BlockHeader: rpc.BlockHeader{StarknetVersion: test.starknetVersion},
 						}, nil).Times(1)
@@ -528,7 +528,7 @@ func TestBuildAndSendMethodsWithQueryBit(t *testing.T) {
 		)
 		// called when estimating the tip
 		mockRPCProvider.EXPECT().
-			BlockWithTxs(t.Context(), rpc.WithBlockTag(rpc.BlockTagLatest)).
+			ConfirmedBlockWithTxs(t.Context(), rpc.WithLatestBlock()).
 			Return(&rpc.Block{
 				BlockHeader: rpc.BlockHeader{},
 				Status:      rpc.BlockStatusAcceptedOnL2,
@@ -570,7 +570,7 @@ func TestBuildAndSendMethodsWithQueryBit(t *testing.T) {
 
 		t.Run("BuildAndSendDeclareTxn", func(t *testing.T) {
 			mockRPCProvider.EXPECT().
-				BlockWithTxHashes(gomock.Any(), rpc.WithBlockTag(rpc.BlockTagLatest)).
+				ConfirmedBlockWithTxHashes(gomock.Any(), rpc.WithLatestBlock()).
 				Return(&rpc.BlockTxHashes{
 					BlockHeader: rpc.BlockHeader{StarknetVersion: "0.14.1"},
 				}, nil).Times(1)
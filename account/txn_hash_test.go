@@ -567,3 +567,80 @@ func TestTransactionHashdeployAccount(t *testing.T) {
 		assert.Equal(t, hashResp, hash2)
 	}
 }
+
+// TestTransactionHashL1HandlerV0 tests the hashes of L1 handler transactions.
+func TestTransactionHashL1HandlerV0(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv, tests.TestnetEnv)
+
+	testSet := []struct {
+		Description  string
+		ChainID      string
+		Txn          rpc.L1HandlerTxn
+		ExpectedHash string
+	}{
+		{
+			// https://voyager.online/tx/0xc470e30f97f64255a62215633e35a7c6ae10332a9011776dde1143ab0202c3
+			Description: "mainnet",
+			ChainID:     "SN_MAIN",
+			Txn: rpc.L1HandlerTxn{
+				Type:    rpc.TransactionTypeL1Handler,
+				Version: rpc.TransactionV0,
+				Nonce:   "0x195c3c",
+				FunctionCall: rpc.FunctionCall{
+					ContractAddress: internalUtils.TestHexToFelt(
+						t, "0x38862e1b15526eda31ed6fd26805c40748458db8e420cb3be3bc65c332c023b",
+					),
+					EntryPointSelector: internalUtils.TestHexToFelt(
+						t, "0x3593216f3a8b22f4cf375e5486e3d13bfde9d0f26976d20ac6f653c73f7e507",
+					),
+					Calldata: internalUtils.TestHexArrToFelt(t, []string{
+						"0x7ad94e71308bb65c6bc9df35cc69cc9f953d69e5",
+						"0xc3b49b03a6d9d71f8d3fa6582437374e650f3c46",
+						"0x3a1bf949fa7424b4bd48661a62ded82bc6f6e3c5f5c6d5904c07e6143187d1b",
+						"0x61",
+					}),
+				},
+			},
+			ExpectedHash: "0xc470e30f97f64255a62215633e35a7c6ae10332a9011776dde1143ab0202c3",
+		},
+		{
+			// https://sepolia.voyager.online/tx/0x304c78cccf0569159d4b2aff2117f060509b7c6d590ae740d2031d1eb507b10
+			Description: "sepolia",
+			ChainID:     "SN_SEPOLIA",
+			Txn: rpc.L1HandlerTxn{
+				Type:    rpc.TransactionTypeL1Handler,
+				Version: rpc.TransactionV0,
+				Nonce:   "0x2cb2",
+				FunctionCall: rpc.FunctionCall{
+					ContractAddress: internalUtils.TestHexToFelt(
+						t, "0x4c5772d1914fe6ce891b64eb35bf3522aeae1315647314aac58b01137607f3f",
+					),
+					EntryPointSelector: internalUtils.TestHexToFelt(
+						t, "0x1b64b1b3b690b43b9b514fb81377518f4039cd3e4f4914d8a6bdf01d679fb19",
+					),
+					Calldata: internalUtils.TestHexArrToFelt(t, []string{
+						"0x8453fc6cd1bcfe8d4dfc069c400b433054d47bdc",
+						"0x455448",
+						"0x970e0b4240684ce331384023bcd4b82fbe20d5e0",
+						"0x3cb6a861f04962186d6a9faf3f88256fae18fc62ed0afc77564db72c4441a22",
+						"0x6a94d74f430000",
+						"0x0",
+					}),
+				},
+			},
+			ExpectedHash: "0x304c78cccf0569159d4b2aff2117f060509b7c6d590ae740d2031d1eb507b10",
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Description, func(t *testing.T) {
+			chainID := new(felt.Felt).SetBytes([]byte(test.ChainID))
+			hashResult, err := hash.TransactionHashL1HandlerV0(&test.Txn, chainID)
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedHash, hashResult.String())
+		})
+	}
+
+	_, err := hash.TransactionHashL1HandlerV0(&rpc.L1HandlerTxn{}, new(felt.Felt))
+	require.ErrorIs(t, err, hash.ErrNotAllParametersSet)
+}
@@ -1,6 +1,8 @@
 package account
 
 import (
+	"context"
+
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/NethermindEth/starknet.go/utils"
 )
@@ -40,6 +42,12 @@ type TxnOptions struct {
 	// If FeeMultiplier <= 0, it'll be set to 1.5.
 	FeeMultiplier float64
 
+	// A strategy computing the tip and the resource bounds of the transaction,
+	// e.g. a feeoracle.Strategy. If set, it replaces the TipMultiplier and
+	// FeeMultiplier fields. The CustomTip field still takes precedence over
+	// the tip of the strategy.
+	FeeStrategy FeeStrategy
+
 	// A boolean flag indicating whether to use the latest block tag
 	// when estimating fees instead of the pre_confirmed block. Default: `false`.
 	UseLatest bool
@@ -96,4 +104,16 @@ func (opts *TxnOptions) FmtTipMultiplier() float64 {
 	return opts.TipMultiplier
 }
 
+// FeeStrategy computes the tip and the resource bounds of the transactions
+// built by the BuildAndSend* account methods, in place of the fixed multipliers.
+type FeeStrategy interface {
+	// Tip returns the tip of the transaction, in FRI.
+	Tip(ctx context.Context) (rpc.U64, error)
+	// ResourceBounds returns the resource bounds of the transaction from its fee estimate.
+	ResourceBounds(
+		ctx context.Context,
+		estimate rpc.FeeEstimation,
+	) (*rpc.ResourceBoundsMapping, error)
+}
+
 type UDCOptions = utils.UDCOptions
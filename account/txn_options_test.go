@@ -1,11 +1,13 @@
 package account
 
 import (
+	"context"
 	"testing"
 
 	"github.com/NethermindEth/starknet.go/internal/tests"
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestTxnOptions tests the methods of the TxnOptions struct,
@@ -205,3 +207,46 @@ func TestTxnOptions(t *testing.T) {
 		}
 	})
 }
+
+// fixedFeeStrategy is a FeeStrategy returning fixed values.
+type fixedFeeStrategy struct {
+	tip       rpc.U64
+	resBounds *rpc.ResourceBoundsMapping
+}
+
+func (s fixedFeeStrategy) Tip(context.Context) (rpc.U64, error) {
+	return s.tip, nil
+}
+
+func (s fixedFeeStrategy) ResourceBounds(
+	context.Context,
+	rpc.FeeEstimation,
+) (*rpc.ResourceBoundsMapping, error) {
+	return s.resBounds, nil
+}
+
+// TestFeeStrategy tests that the fee strategy of the TxnOptions replaces the
+// multipliers, with the custom tip still taking precedence.
+func TestFeeStrategy(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+	t.Parallel()
+
+	strategy := fixedFeeStrategy{
+		tip:       "0x64",
+		resBounds: &rpc.ResourceBoundsMapping{L2Gas: rpc.ResourceBounds{MaxAmount: "0x1"}},
+	}
+	opts := &TxnOptions{FeeStrategy: strategy, FeeMultiplier: 3}
+
+	tip, err := calculateTip(t.Context(), nil, opts)
+	require.NoError(t, err)
+	assert.Equal(t, strategy.tip, tip)
+
+	opts.CustomTip = "0x1"
+	tip, err = calculateTip(t.Context(), nil, opts)
+	require.NoError(t, err)
+	assert.Equal(t, rpc.U64("0x1"), tip)
+
+	resBounds, err := calculateResourceBounds(t.Context(), opts, rpc.FeeEstimation{})
+	require.NoError(t, err)
+	assert.Equal(t, strategy.resBounds, resBounds)
+}
@@ -89,6 +89,11 @@ type Client struct {
 	batchItemLimit       int
 	batchResponseMaxSize int
 
+	// wraps the outgoing requests, nil if there are no interceptors
+	interceptor Interceptor
+	// called on the connection events
+	hooks ConnectionHooks
+
 	// writeConn is used for writing to the connection on the caller's goroutine. It should
 	// only be accessed outside of dispatch, with the write lock held. The write lock is
 	// taken by sending on reqInit and released by sending on reqSent.
@@ -191,7 +196,7 @@ func (op *requestOp) wait(ctx context.Context, c *Client) ([]*jsonrpcMessage, er
 //
 // The currently supported URL schemes are "http", "https", "ws" and "wss". If rawurl is a
 // file name with no URL scheme, a local socket connection is established using UNIX
-// domain sockets on supported platforms and named pipes on Windows.
+// domain sockets. IPC isn't supported on Windows.
 //
 // If you want to further configure the transport, use DialOptions instead of this
 // function.
@@ -212,7 +217,8 @@ func DialContext(ctx context.Context, rawurl string) (*Client, error) {
 }
 
 // DialOptions creates a new RPC client for the given URL. You can supply any of the
-// pre-defined client options to configure the underlying transport.
+// pre-defined client options to configure the underlying transport. As with Dial, a
+// file name with no URL scheme is dialed as an IPC socket.
 //
 // The context is used to cancel or time out the initial connection establishment. It does
 // not affect subsequent interactions with the client.
@@ -239,6 +245,8 @@ func DialOptions(ctx context.Context, rawurl string, options ...ClientOption) (*
 			return nil, err
 		}
 		reconnect = rc
+	case "":
+		reconnect = newClientTransportIPC(rawurl)
 	default:
 		return nil, fmt.Errorf("no known transport for URL scheme %q", u.Scheme)
 	}
@@ -273,6 +281,8 @@ func initClient(conn ServerCodec, services *serviceRegistry, cfg *clientConfig)
 		idgen:                cfg.idgen,
 		batchItemLimit:       cfg.batchItemLimit,
 		batchResponseMaxSize: cfg.batchResponseLimit,
+		interceptor:          chainInterceptors(cfg.interceptors),
+		hooks:                cfg.hooks,
 		writeConn:            conn,
 		close:                make(chan struct{}),
 		closing:              make(chan struct{}),
@@ -373,6 +383,18 @@ func (c *Client) CallContextWithSliceArgs(ctx context.Context, result interface{
 // The result must be a pointer so that package json can unmarshal into it. You
 // can also pass nil, in which case the result is ignored.
 func (c *Client) CallContext(ctx context.Context, result interface{}, method string, args interface{}) error {
+	if c.interceptor == nil {
+		return c.callContext(ctx, result, method, args)
+	}
+	call := &Call{Kind: CallKindCall, Method: method, Params: args, Result: result}
+
+	return c.interceptor(ctx, call, func(ctx context.Context) error {
+		return c.callContext(ctx, result, method, args)
+	})
+}
+
+// callContext performs a JSON-RPC call, without the interceptors.
+func (c *Client) callContext(ctx context.Context, result interface{}, method string, args interface{}) error {
 	if result != nil && reflect.TypeOf(result).Kind() != reflect.Ptr {
 		return fmt.Errorf("call result parameter must be pointer or nil interface: %v", result)
 	}
@@ -437,6 +459,18 @@ func (c *Client) BatchCall(b []BatchElem) error {
 //
 // Note that batch calls may not be executed atomically on the server side.
 func (c *Client) BatchCallContext(ctx context.Context, b []BatchElem) error {
+	if c.interceptor == nil {
+		return c.batchCallContext(ctx, b)
+	}
+	call := &Call{Kind: CallKindBatch, Batch: b}
+
+	return c.interceptor(ctx, call, func(ctx context.Context) error {
+		return c.batchCallContext(ctx, b)
+	})
+}
+
+// batchCallContext sends a batch of requests, without the interceptors.
+func (c *Client) batchCallContext(ctx context.Context, b []BatchElem) error {
 	var (
 		msgs = make([]*jsonrpcMessage, len(b))
 		byID = make(map[string]int, len(b))
@@ -560,6 +594,35 @@ func (c *Client) Subscribe(
 	methodSuffix string,
 	channel interface{},
 	args interface{},
+) (*ClientSubscription, error) {
+	if c.interceptor == nil {
+		return c.subscribe(ctx, namespace, methodSuffix, channel, args)
+	}
+	var sub *ClientSubscription
+	call := &Call{Kind: CallKindSubscribe, Method: namespace + methodSuffix, Params: args}
+	err := c.interceptor(ctx, call, func(ctx context.Context) error {
+		var err error
+		sub, err = c.subscribe(ctx, namespace, methodSuffix, channel, args)
+		if err == nil {
+			call.Result = sub
+		}
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// subscribe registers a subscription, without the interceptors.
+func (c *Client) subscribe(
+	ctx context.Context,
+	namespace string,
+	methodSuffix string,
+	channel interface{},
+	args interface{},
 ) (*ClientSubscription, error) {
 	// Check type of channel first.
 	chanVal := reflect.ValueOf(channel)
@@ -582,6 +645,7 @@ func (c *Client) Subscribe(
 		resp: make(chan []*jsonrpcMessage, 1),
 		sub:  newClientSubscription(c, namespace, chanVal),
 	}
+	op.sub.method = namespace + methodSuffix
 
 	// Send the subscription request.
 	// The arrival and validity of the response is signalled on sub.quit.
@@ -661,6 +725,9 @@ func (c *Client) reconnect(ctx context.Context) error {
 		defer cancel()
 	}
 	newconn, err := c.reconnectFunc(ctx)
+	if c.hooks.OnReconnect != nil {
+		c.hooks.OnReconnect(err)
+	}
 	if err != nil {
 		log.Trace("RPC client reconnect failed", "err", err)
 
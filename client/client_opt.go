@@ -41,6 +41,11 @@ type clientConfig struct {
 	idgen              func() ID
 	batchItemLimit     int
 	batchResponseLimit int
+
+	// The interceptors of the outgoing requests
+	interceptors []Interceptor
+	// The hooks of the connection events
+	hooks ConnectionHooks
 }
 
 func (cfg *clientConfig) initHeaders() {
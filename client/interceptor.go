@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// CallKind is the kind of an outgoing JSON-RPC request seen by an Interceptor.
+type CallKind int
+
+const (
+	// CallKindCall is a single method call, made with CallContext
+	CallKindCall CallKind = iota
+	// CallKindBatch is a batch of calls, made with BatchCallContext
+	CallKindBatch
+	// CallKindSubscribe is a subscription request, made with Subscribe
+	CallKindSubscribe
+)
+
+// Call is an outgoing JSON-RPC request seen by an Interceptor. Interceptors
+// must not modify it.
+type Call struct {
+	Kind CallKind
+	// The method name, e.g. "starknet_blockNumber". Empty for batches.
+	Method string
+	// The params of the call or subscription, as passed to the client
+	Params any
+	// The pointer the result of the call is unmarshalled into, filled once the
+	// invoker returns without error. For subscriptions, the *ClientSubscription.
+	Result any
+	// The calls of a batch. Their Result and Error fields are filled once the
+	// invoker returns.
+	Batch []BatchElem
+}
+
+// Invoker sends the request to the server, or calls the next interceptor.
+type Invoker func(ctx context.Context) error
+
+// Interceptor wraps the outgoing requests of a Client. It must call the
+// invoker to send the request, and can pass it a derived context, e.g. with
+// NewContextWithHeaders to set the HTTP headers of the request. It returns the
+// error of the invoker, or its own error.
+//
+// The interceptors must be safe for concurrent use.
+type Interceptor func(ctx context.Context, call *Call, invoke Invoker) error
+
+// WithInterceptors adds interceptors to the requests of the client, e.g. to
+// record metrics or traces, log the requests or set per-call headers. The
+// interceptors wrap the calls made with CallContext, BatchCallContext and
+// Subscribe, and so with the methods built upon them. The first interceptor
+// is the outermost one. The option can be used several times, appending the
+// interceptors.
+func WithInterceptors(interceptors ...Interceptor) ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.interceptors = append(cfg.interceptors, interceptors...)
+	})
+}
+
+// Observe returns an Interceptor calling the given function after each
+// request, with its error and duration, e.g. to record metrics or log the
+// requests.
+func Observe(
+	observe func(ctx context.Context, call *Call, err error, duration time.Duration),
+) Interceptor {
+	return func(ctx context.Context, call *Call, invoke Invoker) error {
+		start := time.Now()
+		err := invoke(ctx)
+		observe(ctx, call, err, time.Since(start))
+
+		return err
+	}
+}
+
+// chainInterceptors returns an interceptor calling the interceptors in order,
+// or nil if there are none.
+func chainInterceptors(interceptors []Interceptor) Interceptor {
+	switch len(interceptors) {
+	case 0:
+		return nil
+	case 1:
+		return interceptors[0]
+	}
+
+	return func(ctx context.Context, call *Call, invoke Invoker) error {
+		next := invoke
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context) error {
+				return interceptor(ctx, call, inner)
+			}
+		}
+
+		return next(ctx)
+	}
+}
+
+// ConnectionHooks are called on the connection events of a client, e.g. to
+// record metrics. They are called on the client goroutines and must not block.
+type ConnectionHooks struct {
+	// Called after each attempt to reconnect a websocket or IPC client, with the
+	// error of the attempt
+	OnReconnect func(err error)
+	// Called for each message delivered to a subscription, with the subscribe
+	// method of the subscription, e.g. "starknet_subscribeNewHeads"
+	OnSubscriptionMessage func(method string)
+}
+
+// WithConnectionHooks sets the connection hooks of the client.
+func WithConnectionHooks(hooks ConnectionHooks) ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.hooks = hooks
+	})
+}
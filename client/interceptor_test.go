@@ -0,0 +1,181 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// observedCall is a request recorded by an Observe interceptor.
+type observedCall struct {
+	call     *Call
+	err      error
+	duration time.Duration
+}
+
+// callRecorder records the requests seen by an Observe interceptor.
+type callRecorder struct {
+	mu    sync.Mutex
+	calls []observedCall
+}
+
+func (recorder *callRecorder) interceptor() Interceptor {
+	return Observe(func(_ context.Context, call *Call, err error, duration time.Duration) {
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		recorder.calls = append(recorder.calls, observedCall{call: call, err: err, duration: duration})
+	})
+}
+
+func (recorder *callRecorder) last() observedCall {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	return recorder.calls[len(recorder.calls)-1]
+}
+
+// TestInterceptors tests the interceptors of the HTTP and websocket clients.
+func TestInterceptors(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	defer server.Stop()
+
+	var headerMu sync.Mutex
+	var gotHeader string
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headerMu.Lock()
+		gotHeader = r.Header.Get("X-Request-Method")
+		headerMu.Unlock()
+		server.ServeHTTP(w, r)
+	}))
+	defer httpServer.Close()
+	wsServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer wsServer.Close()
+
+	// sets a header with the method of each call
+	headerInjector := func(ctx context.Context, call *Call, invoke Invoker) error {
+		return invoke(NewContextWithHeaders(ctx, http.Header{"X-Request-Method": {call.Method}}))
+	}
+	var order []string
+	ordered := func(name string) Interceptor {
+		return func(ctx context.Context, _ *Call, invoke Invoker) error {
+			order = append(order, name+" before")
+			err := invoke(ctx)
+			order = append(order, name+" after")
+
+			return err
+		}
+	}
+
+	t.Run("call", func(t *testing.T) {
+		recorder := &callRecorder{}
+		client, err := DialOptions(
+			t.Context(),
+			httpServer.URL,
+			WithInterceptors(headerInjector, recorder.interceptor()),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		var result echoResult
+		require.NoError(t, client.CallContextWithSliceArgs(t.Context(), &result, "test_echo", "x", 1))
+		observed := recorder.last()
+		assert.Equal(t, CallKindCall, observed.call.Kind)
+		assert.Equal(t, "test_echo", observed.call.Method)
+		assert.Equal(t, []any{"x", 1}, observed.call.Params)
+		assert.Equal(t, &echoResult{String: "x", Int: 1}, observed.call.Result)
+		require.NoError(t, observed.err)
+		assert.Positive(t, observed.duration)
+		headerMu.Lock()
+		assert.Equal(t, "test_echo", gotHeader)
+		headerMu.Unlock()
+
+		err = client.CallContext(t.Context(), nil, "test_returnError", nil)
+		require.Error(t, err)
+		assert.Equal(t, err, recorder.last().err)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		recorder := &callRecorder{}
+		client, err := DialOptions(t.Context(), httpServer.URL, WithInterceptors(recorder.interceptor()))
+		require.NoError(t, err)
+		defer client.Close()
+
+		var first, second string
+		batch := []BatchElem{
+			{Method: "test_repeat", Args: []any{"a", 2}, Result: &first},
+			{Method: "test_repeat", Args: []any{"b", 1}, Result: &second},
+		}
+		require.NoError(t, client.BatchCallContext(t.Context(), batch))
+		assert.Equal(t, "aa", first)
+		assert.Equal(t, "b", second)
+
+		observed := recorder.last()
+		assert.Equal(t, CallKindBatch, observed.call.Kind)
+		assert.Empty(t, observed.call.Method)
+		require.Len(t, observed.call.Batch, 2)
+		assert.Equal(t, "test_repeat", observed.call.Batch[1].Method)
+		assert.Equal(t, &second, observed.call.Batch[1].Result)
+	})
+
+	t.Run("subscription", func(t *testing.T) {
+		recorder := &callRecorder{}
+		wsURL := "ws" + strings.TrimPrefix(wsServer.URL, "http")
+		client, err := DialOptions(t.Context(), wsURL, WithInterceptors(recorder.interceptor()))
+		require.NoError(t, err)
+		defer client.Close()
+
+		values := make(chan int)
+		sub, err := client.SubscribeWithSliceArgs(
+			t.Context(),
+			"nftest",
+			subscribeMethodSuffix,
+			values,
+			"someSubscription",
+			2,
+			0,
+		)
+		require.NoError(t, err)
+		defer sub.Unsubscribe()
+		assert.Equal(t, 0, <-values)
+
+		observed := recorder.last()
+		assert.Equal(t, CallKindSubscribe, observed.call.Kind)
+		assert.Equal(t, "nftest"+subscribeMethodSuffix, observed.call.Method)
+		assert.Equal(t, sub, observed.call.Result)
+	})
+
+	t.Run("order and short-circuit", func(t *testing.T) {
+		errDenied := errors.New("denied")
+		client, err := DialOptions(
+			t.Context(),
+			httpServer.URL,
+			WithInterceptors(ordered("first")),
+			WithInterceptors(ordered("second"), func(context.Context, *Call, Invoker) error {
+				return errDenied
+			}),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		headerMu.Lock()
+		gotHeader = ""
+		headerMu.Unlock()
+		err = client.CallContext(t.Context(), nil, "test_echo", nil)
+		require.ErrorIs(t, err, errDenied)
+		assert.Equal(t, []string{"first before", "second before", "second after", "first after"}, order)
+		// the request wasn't sent
+		headerMu.Lock()
+		assert.Empty(t, gotHeader)
+		headerMu.Unlock()
+	})
+}
@@ -0,0 +1,59 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// DialIPC creates a new IPC client that connects to the given endpoint, the
+// path of a Unix domain socket, e.g. the IPC socket of a Juno node.
+//
+// The context is used for the initial connection establishment. It does not
+// affect subsequent interactions with the client.
+func DialIPC(ctx context.Context, endpoint string) (*Client, error) {
+	return DialOptions(ctx, endpoint)
+}
+
+// ServeIPC listens on a Unix domain socket at the given endpoint, and serves
+// JSON-RPC on the accepted connections until the returned listener is closed.
+// The parent directories of the endpoint are created, and a leftover socket
+// file is removed. The socket is only accessible to the current user.
+func (s *Server) ServeIPC(endpoint string) (net.Listener, error) {
+	listener, err := ipcListen(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on IPC endpoint %q: %w", endpoint, err)
+	}
+	go func() {
+		_ = s.ServeListener(listener)
+	}()
+
+	return listener, nil
+}
+
+func newClientTransportIPC(endpoint string) reconnectFunc {
+	return func(ctx context.Context) (ServerCodec, error) {
+		conn, err := newIPCConnection(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewCodec(conn), nil
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !unix
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+var errIPCUnsupported = errors.New("IPC is only supported on Unix platforms")
+
+func ipcListen(string) (net.Listener, error) {
+	return nil, errIPCUnsupported
+}
+
+func newIPCConnection(context.Context, string) (net.Conn, error) {
+	return nil, errIPCUnsupported
+}
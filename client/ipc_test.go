@@ -0,0 +1,65 @@
+//go:build unix
+
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClientIPC tests the calls and subscriptions over a Unix domain socket.
+func TestClientIPC(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	defer server.Stop()
+	endpoint := filepath.Join(t.TempDir(), "nested", "test.ipc")
+	// a leftover socket file is replaced
+	require.NoError(t, os.MkdirAll(filepath.Dir(endpoint), 0o750))
+	require.NoError(t, os.WriteFile(endpoint, nil, 0o600))
+
+	listener, err := server.ServeIPC(endpoint)
+	require.NoError(t, err)
+	defer listener.Close()
+	info, err := os.Stat(endpoint)
+	require.NoError(t, err)
+	assert.Equal(t, os.ModeSocket, info.Mode().Type())
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	client, err := DialOptions(t.Context(), endpoint)
+	require.NoError(t, err)
+	defer client.Close()
+
+	var result echoResult
+	require.NoError(t, client.CallContextWithSliceArgs(t.Context(), &result, "test_echo", "x", 1))
+	assert.Equal(t, echoResult{String: "x", Int: 1}, result)
+
+	var peer PeerInfo
+	require.NoError(t, client.CallContext(t.Context(), &peer, "test_peerInfo", nil))
+	assert.Equal(t, "ipc", peer.Transport)
+
+	values := make(chan int)
+	sub, err := client.SubscribeWithSliceArgs(
+		t.Context(),
+		"nftest",
+		subscribeMethodSuffix,
+		values,
+		"someSubscription",
+		3,
+		0,
+	)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+	for i := range 3 {
+		assert.Equal(t, i, <-values)
+	}
+
+	t.Run("no server", func(t *testing.T) {
+		_, err := DialIPC(t.Context(), filepath.Join(t.TempDir(), "missing.ipc"))
+		require.Error(t, err)
+	})
+}
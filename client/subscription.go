@@ -218,6 +218,7 @@ type ClientSubscription struct {
 	reorgEtype   reflect.Type
 	reorgChannel chan *ReorgEvent
 	namespace    string
+	method       string // the subscribe method, e.g. "starknet_subscribeNewHeads"
 	subid        string
 
 	// The in channel receives notification values from client dispatcher.
@@ -297,6 +298,10 @@ func (sub *ClientSubscription) ID() string {
 func (sub *ClientSubscription) deliver(result json.RawMessage) (ok bool) {
 	select {
 	case sub.in <- result:
+		if hook := sub.client.hooks.OnSubscriptionMessage; hook != nil {
+			hook(sub.method)
+		}
+
 		return true
 	case <-sub.forwardDone:
 		return false
@@ -0,0 +1,1060 @@
+package contracts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+)
+
+var (
+	ErrABIFunctionNotFound = errors.New("function not found in the ABI")
+	ErrABIEventNotFound    = errors.New("event not found in the ABI")
+	ErrABIUnknownType      = errors.New("unknown ABI type")
+	ErrABIDecode           = errors.New("failed to decode ABI value")
+	ErrABIEncode           = errors.New("failed to encode ABI value")
+)
+
+// Cairo types with a dedicated Go representation
+const (
+	abiTypeFelt252    = "core::felt252"
+	abiTypeBool       = "core::bool"
+	abiTypeU256       = "core::integer::u256"
+	abiTypeByteArray  = "core::byte_array::ByteArray"
+	abiTypeArray      = "core::array::Array::<"
+	abiTypeSpan       = "core::array::Span::<"
+	abiTypeNonZero    = "core::zeroable::NonZero::<"
+	abiTypeIntPrefix  = "core::integer::"
+	cairo0TypeFelt    = "felt"
+	cairo0TypeUint256 = "Uint256"
+)
+
+// feltLikeTypes are the Cairo types serialised as a single felt, and decoded as *felt.Felt.
+var feltLikeTypes = map[string]bool{
+	abiTypeFelt252: true,
+	cairo0TypeFelt: true,
+	"core::starknet::contract_address::ContractAddress": true,
+	"core::starknet::class_hash::ClassHash":             true,
+	"core::starknet::eth_address::EthAddress":           true,
+	"core::starknet::storage_access::StorageAddress":    true,
+}
+
+// ABIParam is a named and typed parameter of an ABI function, event or struct.
+type ABIParam struct {
+	Name string
+	Type string
+}
+
+// ABIFunction is a function of a parsed ABI.
+type ABIFunction struct {
+	Name     string
+	Selector *felt.Felt
+	// The function type: function, constructor or l1_handler
+	Type    ABIType
+	Inputs  []ABIParam
+	Outputs []ABIParam
+	// The state mutability of the function, "view" or "external". Empty when unknown.
+	StateMutability string
+}
+
+// ABIEventDef is an event of a parsed ABI.
+type ABIEventDef struct {
+	// The name of the event, as emitted in the first event key
+	Name     string
+	Selector *felt.Felt
+	// The parameters serialised in the event keys, after the selector
+	Keys []ABIParam
+	// The parameters serialised in the event data
+	Data []ABIParam
+}
+
+// NamedValue is a decoded ABI value with the name and type of its parameter.
+type NamedValue struct {
+	Name  string
+	Type  string
+	Value any
+}
+
+// ABIEnum is a decoded Cairo enum value. Option and Result are decoded as
+// ABIEnum too, e.g. {Variant: "Some", Value: ...}.
+type ABIEnum struct {
+	Variant string
+	// The value of the variant, nil for unit variants
+	Value any
+}
+
+// DecodedEvent is an event decoded with an ABI.
+type DecodedEvent struct {
+	Name   string
+	Fields []NamedValue
+}
+
+// ParsedABI is a contract ABI indexed for decoding calldata, results and events.
+// It supports both the Cairo 0 ABI and the Sierra (Cairo 1+) ABI.
+//
+// The values are decoded to the following Go types:
+//   - felt252, ContractAddress, ClassHash, EthAddress: *felt.Felt
+//   - integers (including u256 and Cairo 0 Uint256): *big.Int
+//   - bool: bool
+//   - ByteArray: string
+//   - arrays, spans and tuples: []any
+//   - structs: map[string]any
+//   - enums (including Option and Result): ABIEnum
+type ParsedABI struct {
+	functions       map[felt.Felt]*ABIFunction
+	functionsByName map[string]*ABIFunction
+	events          map[felt.Felt]*ABIEventDef
+	structs         map[string][]ABIParam
+	enums           map[string][]ABIParam
+}
+
+// ParseABI indexes a Cairo 0 contract ABI.
+//
+// Parameters:
+//   - abi: the ABI of a DeprecatedContractClass
+//
+// Returns:
+//   - *ParsedABI: the parsed ABI
+func ParseABI(abi ABI) *ParsedABI {
+	parsed := newParsedABI()
+
+	for _, entry := range abi {
+		switch e := entry.(type) {
+		case *FunctionABIEntry:
+			parsed.addFunction(&ABIFunction{
+				Name:            e.Name,
+				Type:            e.Type,
+				Inputs:          toABIParams(e.Inputs),
+				Outputs:         toABIParams(e.Outputs),
+				StateMutability: string(e.StateMutability),
+			})
+		case *StructABIEntry:
+			members := make([]ABIParam, len(e.Members))
+			for i, member := range e.Members {
+				members[i] = ABIParam{Name: member.Name, Type: member.Type}
+			}
+			parsed.structs[e.Name] = members
+		case *EventABIEntry:
+			parsed.addEvent(&ABIEventDef{
+				Name: e.Name,
+				Keys: toABIParams(e.Keys),
+				Data: toABIParams(e.Data),
+			})
+		}
+	}
+
+	return parsed
+}
+
+// sierraABIEntry is an entry of a Sierra ABI, with the fields of all the entry types.
+type sierraABIEntry struct {
+	Type            string           `json:"type"`
+	Name            string           `json:"name"`
+	Kind            string           `json:"kind"`
+	Inputs          []sierraABIParam `json:"inputs"`
+	Outputs         []sierraABIParam `json:"outputs"`
+	Members         []sierraABIParam `json:"members"`
+	Variants        []sierraABIParam `json:"variants"`
+	Items           []sierraABIEntry `json:"items"`
+	StateMutability string           `json:"state_mutability"`
+}
+
+type sierraABIParam struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Kind string `json:"kind"`
+}
+
+// ParseSierraABI indexes a Sierra (Cairo 1+) contract ABI.
+//
+// Parameters:
+//   - abi: the ABI of a ContractClass
+//
+// Returns:
+//   - *ParsedABI: the parsed ABI
+//   - error: an error if the ABI is not valid JSON
+func ParseSierraABI(abi NestedString) (*ParsedABI, error) {
+	var entries []sierraABIEntry
+	if err := json.Unmarshal([]byte(abi), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse the Sierra ABI: %w", err)
+	}
+
+	parsed := newParsedABI()
+	eventStructs := make(map[string]sierraABIEntry)
+	var eventEnums []sierraABIEntry
+
+	var addEntries func(entries []sierraABIEntry)
+	addEntries = func(entries []sierraABIEntry) {
+		for _, entry := range entries {
+			switch entry.Type {
+			case "function", "constructor", "l1_handler":
+				parsed.addFunction(&ABIFunction{
+					Name:            entry.Name,
+					Type:            ABIType(entry.Type),
+					Inputs:          sierraToABIParams(entry.Inputs),
+					Outputs:         sierraToABIParams(entry.Outputs),
+					StateMutability: entry.StateMutability,
+				})
+			case "interface":
+				addEntries(entry.Items)
+			case "struct":
+				parsed.structs[entry.Name] = sierraToABIParams(entry.Members)
+			case "enum":
+				parsed.enums[entry.Name] = sierraToABIParams(entry.Variants)
+			case "event":
+				switch entry.Kind {
+				case "struct":
+					eventStructs[entry.Name] = entry
+				case "enum":
+					eventEnums = append(eventEnums, entry)
+				default:
+					// events of the first Sierra ABI versions, with all the fields in the data
+					parsed.addEvent(&ABIEventDef{
+						Name: shortTypeName(entry.Name),
+						Data: sierraToABIParams(entry.Inputs),
+					})
+				}
+			}
+		}
+	}
+	addEntries(entries)
+
+	// the selector of an event is the name of its variant in the event enum
+	// of the contract (or of the component)
+	for _, enum := range eventEnums {
+		for _, variant := range enum.Variants {
+			event, ok := eventStructs[variant.Type]
+			if !ok || variant.Kind == "flat" {
+				continue
+			}
+
+			def := &ABIEventDef{Name: variant.Name}
+			for _, member := range event.Members {
+				switch member.Kind {
+				case "key":
+					def.Keys = append(def.Keys, ABIParam{Name: member.Name, Type: member.Type})
+				case "data":
+					def.Data = append(def.Data, ABIParam{Name: member.Name, Type: member.Type})
+				}
+			}
+			parsed.addEvent(def)
+		}
+	}
+
+	return parsed, nil
+}
+
+// Function returns the function with the given name.
+func (abi *ParsedABI) Function(name string) (*ABIFunction, error) {
+	function, ok := abi.functionsByName[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrABIFunctionNotFound, name)
+	}
+
+	return function, nil
+}
+
+// FunctionBySelector returns the function with the given selector.
+func (abi *ParsedABI) FunctionBySelector(selector *felt.Felt) (*ABIFunction, error) {
+	if selector == nil {
+		return nil, fmt.Errorf("%w: nil selector", ErrABIFunctionNotFound)
+	}
+	function, ok := abi.functions[*selector]
+	if !ok {
+		return nil, fmt.Errorf("%w: selector %s", ErrABIFunctionNotFound, selector)
+	}
+
+	return function, nil
+}
+
+// Functions returns all the functions of the ABI.
+func (abi *ParsedABI) Functions() []*ABIFunction {
+	functions := make([]*ABIFunction, 0, len(abi.functionsByName))
+	for _, function := range abi.functionsByName {
+		functions = append(functions, function)
+	}
+
+	return functions
+}
+
+// DecodeValues decodes the serialised values of the given parameters.
+//
+// Parameters:
+//   - params: the parameters to decode, e.g. the inputs or outputs of a function
+//   - data: the serialised values
+//
+// Returns:
+//   - []NamedValue: the decoded values, in the order of the parameters
+//   - error: an error if the data doesn't match the parameters
+func (abi *ParsedABI) DecodeValues(params []ABIParam, data []*felt.Felt) ([]NamedValue, error) {
+	reader := &feltReader{data: data}
+	values, err := abi.decodeParams(params, reader)
+	if err != nil {
+		return nil, err
+	}
+	if reader.pos != len(data) {
+		return nil, fmt.Errorf(
+			"%w: %d unused felts",
+			ErrABIDecode,
+			len(data)-reader.pos,
+		)
+	}
+
+	return values, nil
+}
+
+// DecodeEvent decodes an event emitted by the contract, identified by its
+// first key.
+//
+// Parameters:
+//   - keys: the keys of the event
+//   - data: the data of the event
+//
+// Returns:
+//   - *DecodedEvent: the decoded event
+//   - error: ErrABIEventNotFound if the event is not in the ABI, or a decoding error
+func (abi *ParsedABI) DecodeEvent(keys, data []*felt.Felt) (*DecodedEvent, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: event without keys", ErrABIEventNotFound)
+	}
+	def, ok := abi.events[*keys[0]]
+	if !ok {
+		return nil, fmt.Errorf("%w: selector %s", ErrABIEventNotFound, keys[0])
+	}
+
+	keyValues, err := abi.DecodeValues(def.Keys, keys[1:])
+	if err != nil {
+		return nil, fmt.Errorf("event %s keys: %w", def.Name, err)
+	}
+	dataValues, err := abi.DecodeValues(def.Data, data)
+	if err != nil {
+		return nil, fmt.Errorf("event %s data: %w", def.Name, err)
+	}
+
+	return &DecodedEvent{
+		Name:   def.Name,
+		Fields: append(keyValues, dataValues...),
+	}, nil
+}
+
+// decodeParams decodes a list of parameters, handling the Cairo 0 arrays whose
+// length is the preceding `<name>_len` parameter.
+func (abi *ParsedABI) decodeParams(params []ABIParam, reader *feltReader) ([]NamedValue, error) {
+	values := make([]NamedValue, 0, len(params))
+	lengths := make(map[string]int)
+
+	for _, param := range params {
+		var value any
+		var err error
+
+		if elemType, ok := strings.CutSuffix(param.Type, "*"); ok {
+			length, found := lengths[param.Name+"_len"]
+			if !found {
+				return nil, fmt.Errorf(
+					"%w: no '%s_len' parameter for the array '%s'",
+					ErrABIDecode,
+					param.Name,
+					param.Name,
+				)
+			}
+			value, err = abi.decodeArray(elemType, length, reader)
+		} else {
+			value, err = abi.decodeType(param.Type, reader)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parameter '%s': %w", param.Name, err)
+		}
+
+		if f, ok := value.(*felt.Felt); ok && strings.HasSuffix(param.Name, "_len") {
+			lengths[param.Name] = int(f.Uint64())
+		}
+		values = append(values, NamedValue{Name: param.Name, Type: param.Type, Value: value})
+	}
+
+	return values, nil
+}
+
+// decodeType decodes a single value of the given type.
+//
+//nolint:gocyclo // A switch over all the Cairo types
+func (abi *ParsedABI) decodeType(typ string, reader *feltReader) (any, error) {
+	typ = strings.TrimSpace(typ)
+
+	switch {
+	case feltLikeTypes[typ]:
+		return reader.read()
+	case typ == abiTypeBool:
+		f, err := reader.read()
+		if err != nil {
+			return nil, err
+		}
+
+		return !f.IsZero(), nil
+	case typ == abiTypeU256 || typ == cairo0TypeUint256:
+		low, err := reader.read()
+		if err != nil {
+			return nil, err
+		}
+		high, err := reader.read()
+		if err != nil {
+			return nil, err
+		}
+		value, err := internalUtils.U256FeltsToBigInt(low, high)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrABIDecode, err)
+		}
+
+		return value, nil
+	case strings.HasPrefix(typ, abiTypeIntPrefix):
+		f, err := reader.read()
+		if err != nil {
+			return nil, err
+		}
+		value := internalUtils.FeltToBigInt(f)
+		// negative signed integers are serialised as P - |value|
+		if strings.HasPrefix(typ, abiTypeIntPrefix+"i") && value.Cmp(halfPrime) > 0 {
+			value.Sub(value, primeBig)
+		}
+
+		return value, nil
+	case typ == abiTypeByteArray:
+		return reader.readByteArray()
+	case typ == "()":
+		return nil, nil //nolint:nilnil // unit type
+	case strings.HasPrefix(typ, "("):
+		return abi.decodeTuple(typ, reader)
+	case strings.HasPrefix(typ, abiTypeArray), strings.HasPrefix(typ, abiTypeSpan):
+		elemType := genericArgument(typ)
+		length, err := reader.readLen()
+		if err != nil {
+			return nil, err
+		}
+
+		return abi.decodeArray(elemType, length, reader)
+	case strings.HasPrefix(typ, abiTypeNonZero):
+		return abi.decodeType(genericArgument(typ), reader)
+	}
+
+	if members, ok := abi.structs[typ]; ok {
+		values, err := abi.decodeParams(members, reader)
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[string]any, len(values))
+		for _, value := range values {
+			result[value.Name] = value.Value
+		}
+
+		return result, nil
+	}
+
+	if variants, ok := abi.enums[typ]; ok {
+		index, err := reader.read()
+		if err != nil {
+			return nil, err
+		}
+		if index.Cmp(new(felt.Felt).SetUint64(uint64(len(variants)))) >= 0 {
+			return nil, fmt.Errorf("%w: invalid variant %s of enum %s", ErrABIDecode, index, typ)
+		}
+		variant := variants[index.Uint64()]
+		value, err := abi.decodeType(variant.Type, reader)
+		if err != nil {
+			return nil, err
+		}
+
+		return ABIEnum{Variant: variant.Name, Value: value}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrABIUnknownType, typ)
+}
+
+// decodeArray decodes length values of the given type.
+func (abi *ParsedABI) decodeArray(elemType string, length int, reader *feltReader) ([]any, error) {
+	values := make([]any, length)
+	for i := range values {
+		value, err := abi.decodeType(elemType, reader)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// decodeTuple decodes a tuple such as "(core::felt252, core::bool)". The Cairo 0
+// named tuples, such as "(a: felt, b: felt)", are supported too.
+func (abi *ParsedABI) decodeTuple(typ string, reader *feltReader) ([]any, error) {
+	elemTypes := splitTypeList(typ[1 : len(typ)-1])
+	values := make([]any, len(elemTypes))
+	for i, elemType := range elemTypes {
+		if _, namedType, ok := strings.Cut(elemType, ":"); ok && !strings.Contains(elemType, "::") {
+			elemType = namedType
+		}
+		value, err := abi.decodeType(elemType, reader)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+func newParsedABI() *ParsedABI {
+	return &ParsedABI{
+		functions:       make(map[felt.Felt]*ABIFunction),
+		functionsByName: make(map[string]*ABIFunction),
+		events:          make(map[felt.Felt]*ABIEventDef),
+		structs:         make(map[string][]ABIParam),
+		enums:           make(map[string][]ABIParam),
+	}
+}
+
+func (abi *ParsedABI) addFunction(function *ABIFunction) {
+	function.Selector = internalUtils.GetSelectorFromNameFelt(function.Name)
+	abi.functions[*function.Selector] = function
+	abi.functionsByName[function.Name] = function
+}
+
+func (abi *ParsedABI) addEvent(event *ABIEventDef) {
+	event.Selector = internalUtils.GetSelectorFromNameFelt(event.Name)
+	abi.events[*event.Selector] = event
+}
+
+func toABIParams(params []TypedParameter) []ABIParam {
+	result := make([]ABIParam, len(params))
+	for i, param := range params {
+		result[i] = ABIParam(param)
+	}
+
+	return result
+}
+
+func sierraToABIParams(params []sierraABIParam) []ABIParam {
+	result := make([]ABIParam, len(params))
+	for i, param := range params {
+		result[i] = ABIParam{Name: param.Name, Type: param.Type}
+	}
+
+	return result
+}
+
+// shortTypeName returns the last path segment of a Cairo type name.
+func shortTypeName(name string) string {
+	if i := strings.LastIndex(name, "::"); i >= 0 {
+		return name[i+2:]
+	}
+
+	return name
+}
+
+// genericArgument returns the generic argument of a type such as
+// "core::array::Array::<core::felt252>".
+func genericArgument(typ string) string {
+	start := strings.Index(typ, "<")
+	end := strings.LastIndex(typ, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+
+	return typ[start+1 : end]
+}
+
+// splitTypeList splits a comma separated list of types, ignoring the commas
+// nested in generic arguments and tuples.
+func splitTypeList(list string) []string {
+	var types []string
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '<', '(':
+			depth++
+		case '>', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				types = append(types, strings.TrimSpace(list[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(list[start:]); last != "" {
+		types = append(types, last)
+	}
+
+	return types
+}
+
+var (
+	// the Starknet field prime P
+	primeBig = new(big.Int).Add(
+		internalUtils.FeltToBigInt(new(felt.Felt).Sub(&felt.Zero, &felt.One)),
+		big.NewInt(1),
+	)
+	halfPrime = new(big.Int).Rsh(primeBig, 1)
+)
+
+// feltReader reads serialised values sequentially.
+type feltReader struct {
+	data []*felt.Felt
+	pos  int
+}
+
+func (r *feltReader) read() (*felt.Felt, error) {
+	if r.pos >= len(r.data) {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrABIDecode)
+	}
+	f := r.data[r.pos]
+	r.pos++
+
+	return f, nil
+}
+
+// readLen reads a length, which can't exceed the number of felts left.
+func (r *feltReader) readLen() (int, error) {
+	f, err := r.read()
+	if err != nil {
+		return 0, err
+	}
+	if f.Cmp(new(felt.Felt).SetUint64(uint64(len(r.data)-r.pos))) > 0 {
+		return 0, fmt.Errorf("%w: length %s exceeds the data length", ErrABIDecode, f)
+	}
+
+	return int(f.Uint64()), nil
+}
+
+// readByteArray reads a serialised ByteArray: [words_len, words..., pending_word, pending_len].
+func (r *feltReader) readByteArray() (string, error) {
+	start := r.pos
+	wordsLen, err := r.readLen()
+	if err != nil {
+		return "", err
+	}
+	//nolint:mnd // the words, pending word and pending word length
+	if r.pos+wordsLen+2 > len(r.data) {
+		return "", fmt.Errorf("%w: unexpected end of data in ByteArray", ErrABIDecode)
+	}
+	r.pos += wordsLen + 2 //nolint:mnd // the words, pending word and pending word length
+
+	str, err := internalUtils.ByteArrFeltToString(r.data[start:r.pos])
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrABIDecode, err)
+	}
+
+	return str, nil
+}
+
+// EncodeValues serialises Go values as the given parameters, e.g. to build the
+// calldata of a function. The Go types accepted for each Cairo type are:
+//   - felt252, ContractAddress, ClassHash, EthAddress: *felt.Felt, felt.Felt,
+//     *big.Int, Go integers, or hex/decimal strings
+//   - integers (including u256 and Cairo 0 Uint256): the same types as felt252,
+//     *felt.Felt excepted for the negative values of signed integers
+//   - bool: bool
+//   - ByteArray: string
+//   - arrays and spans: any slice
+//   - tuples: []any
+//   - structs: map[string]any, or a Go struct whose fields are matched by
+//     their `abi` tag, or by their name ignoring the case and underscores
+//   - enums (including Option and Result): ABIEnum
+//
+// The Cairo 0 `<name>_len` parameters preceding an array parameter must be
+// omitted: their value is the length of the array.
+//
+// Parameters:
+//   - params: the parameters to encode, e.g. the inputs of a function
+//   - values: the Go values, one per parameter
+//
+// Returns:
+//   - []*felt.Felt: the serialised values
+//   - error: an error if the values don't match the parameters
+func (abi *ParsedABI) EncodeValues(params []ABIParam, values ...any) ([]*felt.Felt, error) {
+	result := make([]*felt.Felt, 0, len(values))
+	valueIndex := 0
+
+	for i, param := range params {
+		// the length of a Cairo 0 array is encoded with the array
+		if i+1 < len(params) && isCairo0ArrayLen(param, params[i+1]) {
+			continue
+		}
+		if valueIndex >= len(values) {
+			return nil, fmt.Errorf(
+				"%w: expected %d values, got %d",
+				ErrABIEncode,
+				countEncodedParams(params),
+				len(values),
+			)
+		}
+		value := values[valueIndex]
+		valueIndex++
+
+		var encoded []*felt.Felt
+		var err error
+		if elemType, ok := strings.CutSuffix(param.Type, "*"); ok {
+			encoded, err = abi.encodeSlice(elemType, value)
+		} else {
+			encoded, err = abi.encodeType(param.Type, value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parameter '%s': %w", param.Name, err)
+		}
+		result = append(result, encoded...)
+	}
+
+	if valueIndex != len(values) {
+		return nil, fmt.Errorf(
+			"%w: expected %d values, got %d",
+			ErrABIEncode,
+			countEncodedParams(params),
+			len(values),
+		)
+	}
+
+	return result, nil
+}
+
+// isCairo0ArrayLen returns true if param is the length of the Cairo 0 array next.
+func isCairo0ArrayLen(param, next ABIParam) bool {
+	return param.Name == next.Name+"_len" && strings.HasSuffix(next.Type, "*")
+}
+
+// countEncodedParams returns the number of values expected by EncodeValues.
+func countEncodedParams(params []ABIParam) int {
+	count := len(params)
+	for i := 0; i+1 < len(params); i++ {
+		if isCairo0ArrayLen(params[i], params[i+1]) {
+			count--
+		}
+	}
+
+	return count
+}
+
+// encodeType serialises a Go value as the given Cairo type.
+//
+//nolint:gocyclo // A switch over all the Cairo types
+func (abi *ParsedABI) encodeType(typ string, value any) ([]*felt.Felt, error) {
+	typ = strings.TrimSpace(typ)
+
+	switch {
+	case feltLikeTypes[typ]:
+		f, err := toFelt(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*felt.Felt{f}, nil
+	case typ == abiTypeBool:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected a bool, got %T", ErrABIEncode, value)
+		}
+		if b {
+			return []*felt.Felt{new(felt.Felt).SetUint64(1)}, nil
+		}
+
+		return []*felt.Felt{new(felt.Felt)}, nil
+	case typ == abiTypeU256 || typ == cairo0TypeUint256:
+		n, err := toBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+		felts, err := internalUtils.BigIntToU256Felts(n)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrABIEncode, err)
+		}
+
+		return felts, nil
+	case strings.HasPrefix(typ, abiTypeIntPrefix):
+		n, err := toBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkIntRange(typ, n); err != nil {
+			return nil, err
+		}
+		if n.Sign() < 0 {
+			n = new(big.Int).Add(n, primeBig)
+		}
+
+		return []*felt.Felt{internalUtils.BigIntToFelt(n)}, nil
+	case typ == abiTypeByteArray:
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected a string, got %T", ErrABIEncode, value)
+		}
+
+		return internalUtils.StringToByteArrFelt(str)
+	case typ == "()":
+		return nil, nil
+	case strings.HasPrefix(typ, "("):
+		return abi.encodeTuple(typ, value)
+	case strings.HasPrefix(typ, abiTypeArray), strings.HasPrefix(typ, abiTypeSpan):
+		return abi.encodeSlice(genericArgument(typ), value)
+	case strings.HasPrefix(typ, abiTypeNonZero):
+		return abi.encodeType(genericArgument(typ), value)
+	}
+
+	if members, ok := abi.structs[typ]; ok {
+		return abi.encodeStruct(typ, members, value)
+	}
+
+	if variants, ok := abi.enums[typ]; ok {
+		enum, ok := value.(ABIEnum)
+		if !ok {
+			return nil, fmt.Errorf(
+				"%w: expected an ABIEnum for %s, got %T",
+				ErrABIEncode,
+				typ,
+				value,
+			)
+		}
+		for i, variant := range variants {
+			if variant.Name != enum.Variant {
+				continue
+			}
+			encoded, err := abi.encodeType(variant.Type, enum.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			return append([]*felt.Felt{new(felt.Felt).SetUint64(uint64(i))}, encoded...), nil
+		}
+
+		return nil, fmt.Errorf(
+			"%w: unknown variant '%s' of enum %s",
+			ErrABIEncode,
+			enum.Variant,
+			typ,
+		)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrABIUnknownType, typ)
+}
+
+// encodeSlice serialises a Go slice as an array of the given element type,
+// prefixed by its length.
+func (abi *ParsedABI) encodeSlice(elemType string, value any) ([]*felt.Felt, error) {
+	slice := reflect.ValueOf(value)
+	if slice.Kind() != reflect.Slice && slice.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%w: expected a slice, got %T", ErrABIEncode, value)
+	}
+
+	result := []*felt.Felt{new(felt.Felt).SetUint64(uint64(slice.Len()))}
+	for i := range slice.Len() {
+		encoded, err := abi.encodeType(elemType, slice.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, encoded...)
+	}
+
+	return result, nil
+}
+
+// encodeTuple serialises a []any as a tuple such as "(core::felt252, core::bool)".
+func (abi *ParsedABI) encodeTuple(typ string, value any) ([]*felt.Felt, error) {
+	elemTypes := splitTypeList(typ[1 : len(typ)-1])
+	values, ok := value.([]any)
+	if !ok || len(values) != len(elemTypes) {
+		return nil, fmt.Errorf(
+			"%w: expected a []any of length %d for %s, got %T",
+			ErrABIEncode,
+			len(elemTypes),
+			typ,
+			value,
+		)
+	}
+
+	var result []*felt.Felt
+	for i, elemType := range elemTypes {
+		if _, namedType, ok := strings.Cut(elemType, ":"); ok && !strings.Contains(elemType, "::") {
+			elemType = namedType
+		}
+		encoded, err := abi.encodeType(elemType, values[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, encoded...)
+	}
+
+	return result, nil
+}
+
+// encodeStruct serialises a map[string]any or a Go struct as the given struct.
+func (abi *ParsedABI) encodeStruct(
+	typ string,
+	members []ABIParam,
+	value any,
+) ([]*felt.Felt, error) {
+	fields, err := structFields(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*felt.Felt
+	for _, member := range members {
+		field, ok := fields[normaliseFieldName(member.Name)]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing member '%s' of %s", ErrABIEncode, member.Name, typ)
+		}
+		var encoded []*felt.Felt
+		if elemType, ok := strings.CutSuffix(member.Type, "*"); ok {
+			encoded, err = abi.encodeSlice(elemType, field)
+		} else {
+			encoded, err = abi.encodeType(member.Type, field)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("member '%s': %w", member.Name, err)
+		}
+		result = append(result, encoded...)
+	}
+
+	return result, nil
+}
+
+// structFields returns the fields of a map[string]any or a Go struct, keyed by
+// their normalised name.
+func structFields(value any) (map[string]any, error) {
+	if m, ok := value.(map[string]any); ok {
+		fields := make(map[string]any, len(m))
+		for name, field := range m {
+			fields[normaliseFieldName(name)] = field
+		}
+
+		return fields, nil
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf(
+			"%w: expected a struct or a map[string]any, got %T",
+			ErrABIEncode,
+			value,
+		)
+	}
+
+	fields := make(map[string]any, v.NumField())
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("abi"); tag != "" {
+			name = tag
+		}
+		fields[normaliseFieldName(name)] = v.Field(i).Interface()
+	}
+
+	return fields, nil
+}
+
+// normaliseFieldName lowercases a name and removes its underscores, so that
+// `token_id` matches `TokenID`.
+func normaliseFieldName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// checkIntRange checks that a value fits in the Cairo integer type, e.g. u8 or i64.
+func checkIntRange(typ string, n *big.Int) error {
+	name := strings.TrimPrefix(typ, abiTypeIntPrefix)
+	if name == "usize" {
+		name = "u32"
+	}
+	if len(name) < 2 || (name[0] != 'u' && name[0] != 'i') {
+		return nil
+	}
+	bits, err := strconv.Atoi(name[1:])
+	if err != nil {
+		return nil //nolint:nilerr // not a sized integer, e.g. u256 members
+	}
+
+	var minValue, maxValue *big.Int
+	if name[0] == 'u' {
+		minValue = big.NewInt(0)
+		maxValue = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(bits)), big.NewInt(1))
+	} else {
+		maxValue = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(bits-1)), big.NewInt(1))
+		minValue = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), uint(bits-1)))
+	}
+	if n.Cmp(minValue) < 0 || n.Cmp(maxValue) > 0 {
+		return fmt.Errorf("%w: %s overflows %s", ErrABIEncode, n, name)
+	}
+
+	return nil
+}
+
+// toFelt converts a Go value to a felt.
+func toFelt(value any) (*felt.Felt, error) {
+	switch v := value.(type) {
+	case *felt.Felt:
+		if v == nil {
+			return nil, fmt.Errorf("%w: nil felt", ErrABIEncode)
+		}
+
+		return v, nil
+	case felt.Felt:
+		return &v, nil
+	}
+
+	n, err := toBigInt(value)
+	if err != nil {
+		return nil, err
+	}
+	if n.Sign() < 0 || n.Cmp(primeBig) >= 0 {
+		return nil, fmt.Errorf("%w: %s is not a valid felt", ErrABIEncode, n)
+	}
+
+	return internalUtils.BigIntToFelt(n), nil
+}
+
+// toBigInt converts a Go value to a big.Int.
+func toBigInt(value any) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return nil, fmt.Errorf("%w: nil big.Int", ErrABIEncode)
+		}
+
+		return new(big.Int).Set(v), nil
+	case big.Int:
+		return new(big.Int).Set(&v), nil
+	case *felt.Felt:
+		if v == nil {
+			return nil, fmt.Errorf("%w: nil felt", ErrABIEncode)
+		}
+
+		return internalUtils.FeltToBigInt(v), nil
+	case felt.Felt:
+		return internalUtils.FeltToBigInt(&v), nil
+	case string:
+		n, ok := new(big.Int).SetString(v, 0)
+		if !ok {
+			return nil, fmt.Errorf("%w: '%s' is not a hex or decimal number", ErrABIEncode, v)
+		}
+
+		return n, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() { //nolint:exhaustive // only the integer kinds are supported
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uintptr:
+		return new(big.Int).SetUint64(rv.Uint()), nil
+	default:
+		return nil, fmt.Errorf("%w: expected a number, got %T", ErrABIEncode, value)
+	}
+}
@@ -0,0 +1,289 @@
+package contracts
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseSierraABI tests decoding calldata, results and events with a
+// Sierra ABI.
+func TestParseSierraABI(t *testing.T) {
+	content, err := os.ReadFile("./testData/codec_test_abi.json")
+	require.NoError(t, err)
+
+	abi, err := ParseSierraABI(NestedString(content))
+	require.NoError(t, err)
+	assert.Len(t, abi.Functions(), 3)
+
+	feltsOf := func(values ...uint64) []*felt.Felt {
+		felts := make([]*felt.Felt, len(values))
+		for i, value := range values {
+			felts[i] = new(felt.Felt).SetUint64(value)
+		}
+
+		return felts
+	}
+	minusThree := new(felt.Felt).Sub(&felt.Zero, new(felt.Felt).SetUint64(3))
+	amount := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(5))
+	position := map[string]any{
+		"owner":  new(felt.Felt).SetUint64(1),
+		"amount": amount,
+		"delta":  big.NewInt(-3),
+	}
+
+	t.Run("function inputs", func(t *testing.T) {
+		function, err := abi.Function("deposit")
+		require.NoError(t, err)
+		assert.Equal(t, internalUtils.GetSelectorFromNameFelt("deposit"), function.Selector)
+		assert.Equal(t, "external", function.StateMutability)
+
+		bySelector, err := abi.FunctionBySelector(function.Selector)
+		require.NoError(t, err)
+		assert.Same(t, function, bySelector)
+
+		calldata := feltsOf(1, 5, 1)
+		calldata = append(calldata, minusThree)
+		calldata = append(calldata, feltsOf(2, 0xa, 0xb, 0, 0x68656c6c6f, 5, 0, 0x42)...)
+
+		values, err := abi.DecodeValues(function.Inputs, calldata)
+		require.NoError(t, err)
+		assert.Equal(t, []NamedValue{
+			{Name: "position", Type: "vault::Position", Value: position},
+			{
+				Name:  "tags",
+				Type:  "core::array::Span::<core::felt252>",
+				Value: []any{new(felt.Felt).SetUint64(0xa), new(felt.Felt).SetUint64(0xb)},
+			},
+			{Name: "memo", Type: "core::byte_array::ByteArray", Value: "hello"},
+			{
+				Name:  "referrer",
+				Type:  "core::option::Option::<core::felt252>",
+				Value: ABIEnum{Variant: "Some", Value: new(felt.Felt).SetUint64(0x42)},
+			},
+		}, values)
+
+		_, err = abi.DecodeValues(function.Inputs, calldata[:5])
+		require.ErrorIs(t, err, ErrABIDecode)
+		_, err = abi.DecodeValues(function.Inputs, append(calldata, new(felt.Felt)))
+		require.ErrorIs(t, err, ErrABIDecode)
+	})
+
+	t.Run("function outputs", func(t *testing.T) {
+		function, err := abi.Function("deposit")
+		require.NoError(t, err)
+		values, err := abi.DecodeValues(function.Outputs, feltsOf(1, 7, 0))
+		require.NoError(t, err)
+		require.Len(t, values, 1)
+		assert.Equal(t, []any{true, big.NewInt(7)}, values[0].Value)
+
+		function, err = abi.Function("get_positions")
+		require.NoError(t, err)
+		result := feltsOf(2, 1, 5, 1)
+		result = append(result, minusThree)
+		result = append(result, feltsOf(1, 5, 1)...)
+		result = append(result, minusThree)
+		values, err = abi.DecodeValues(function.Outputs, result)
+		require.NoError(t, err)
+		assert.Equal(t, []any{position, position}, values[0].Value)
+	})
+
+	t.Run("events", func(t *testing.T) {
+		keys := []*felt.Felt{
+			internalUtils.GetSelectorFromNameFelt("Deposited"),
+			new(felt.Felt).SetUint64(1),
+		}
+		event, err := abi.DecodeEvent(keys, feltsOf(5, 1))
+		require.NoError(t, err)
+		assert.Equal(t, &DecodedEvent{
+			Name: "Deposited",
+			Fields: []NamedValue{
+				{
+					Name:  "owner",
+					Type:  "core::starknet::contract_address::ContractAddress",
+					Value: new(felt.Felt).SetUint64(1),
+				},
+				{Name: "amount", Type: "core::integer::u256", Value: amount},
+			},
+		}, event)
+
+		_, err = abi.DecodeEvent(feltsOf(1), nil)
+		require.ErrorIs(t, err, ErrABIEventNotFound)
+	})
+
+	t.Run("unknown function", func(t *testing.T) {
+		_, err := abi.Function("withdraw")
+		require.ErrorIs(t, err, ErrABIFunctionNotFound)
+		_, err = abi.FunctionBySelector(new(felt.Felt).SetUint64(1))
+		require.ErrorIs(t, err, ErrABIFunctionNotFound)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := abi.DecodeValues([]ABIParam{{Name: "x", Type: "vault::Unknown"}}, feltsOf(1))
+		require.ErrorIs(t, err, ErrABIUnknownType)
+	})
+}
+
+// TestParseABI tests decoding calldata and events with a Cairo 0 ABI.
+func TestParseABI(t *testing.T) {
+	uint256 := []Member{
+		{TypedParameter: TypedParameter{Name: "low", Type: "felt"}},
+		{TypedParameter: TypedParameter{Name: "high", Type: "felt"}, Offset: 1},
+	}
+	abi := ParseABI(ABI{
+		&StructABIEntry{Type: ABITypeStruct, Name: "Uint256", Size: 2, Members: uint256},
+		&FunctionABIEntry{
+			Type: ABITypeFunction,
+			Name: "transfer",
+			Inputs: []TypedParameter{
+				{Name: "to", Type: "felt"},
+				{Name: "amount", Type: "Uint256"},
+				{Name: "data_len", Type: "felt"},
+				{Name: "data", Type: "felt*"},
+			},
+			Outputs: []TypedParameter{{Name: "success", Type: "felt"}},
+		},
+		&EventABIEntry{
+			Type: ABITypeEvent,
+			Name: "Transfer",
+			Data: []TypedParameter{
+				{Name: "to", Type: "felt"},
+				{Name: "amount", Type: "Uint256"},
+			},
+		},
+	})
+
+	function, err := abi.Function("transfer")
+	require.NoError(t, err)
+	values, err := abi.DecodeValues(function.Inputs, []*felt.Felt{
+		internalUtils.DeadBeef,
+		new(felt.Felt).SetUint64(10),
+		new(felt.Felt),
+		new(felt.Felt).SetUint64(2),
+		new(felt.Felt).SetUint64(3),
+		new(felt.Felt).SetUint64(4),
+	})
+	require.NoError(t, err)
+	require.Len(t, values, 4)
+	assert.Equal(t, internalUtils.DeadBeef, values[0].Value)
+	assert.Equal(t, big.NewInt(10), values[1].Value)
+	assert.Equal(t, new(felt.Felt).SetUint64(2), values[2].Value)
+	assert.Equal(t, []any{new(felt.Felt).SetUint64(3), new(felt.Felt).SetUint64(4)}, values[3].Value)
+
+	event, err := abi.DecodeEvent(
+		[]*felt.Felt{internalUtils.GetSelectorFromNameFelt("Transfer")},
+		[]*felt.Felt{internalUtils.DeadBeef, new(felt.Felt).SetUint64(1), new(felt.Felt)},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "Transfer", event.Name)
+	assert.Equal(t, big.NewInt(1), event.Fields[1].Value)
+}
+
+// TestEncodeValues tests that EncodeValues is the inverse of DecodeValues, and
+// the accepted Go types.
+func TestEncodeValues(t *testing.T) {
+	content, err := os.ReadFile("./testData/codec_test_abi.json")
+	require.NoError(t, err)
+	abi, err := ParseSierraABI(NestedString(content))
+	require.NoError(t, err)
+
+	function, err := abi.Function("deposit")
+	require.NoError(t, err)
+
+	type position struct {
+		Owner  *felt.Felt
+		Amount *big.Int
+		Delta  int64 `abi:"delta"`
+	}
+	amount := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(5))
+
+	calldata, err := abi.EncodeValues(
+		function.Inputs,
+		position{Owner: new(felt.Felt).SetUint64(1), Amount: amount, Delta: -3},
+		[]uint64{0xa, 0xb},
+		"hello",
+		ABIEnum{Variant: "Some", Value: "0x42"},
+	)
+	require.NoError(t, err)
+
+	values, err := abi.DecodeValues(function.Inputs, calldata)
+	require.NoError(t, err)
+	mapCalldata, err := abi.EncodeValues(
+		function.Inputs,
+		values[0].Value,
+		values[1].Value,
+		values[2].Value,
+		values[3].Value,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, calldata, mapCalldata)
+	assert.Equal(t, "hello", values[2].Value)
+	assert.Equal(t, big.NewInt(-3), values[0].Value.(map[string]any)["delta"])
+
+	t.Run("invalid values", func(t *testing.T) {
+		owner := new(felt.Felt).SetUint64(1)
+		testSet := map[string][]any{
+			"missing value": {position{Owner: owner, Amount: amount}},
+			"u256 overflow": {
+				position{Owner: owner, Amount: new(big.Int).Lsh(big.NewInt(1), 256)},
+				[]int{}, "", ABIEnum{Variant: "None"},
+			},
+			"i64 overflow": {
+				map[string]any{"owner": owner, "amount": 1, "delta": uint64(1) << 63},
+				[]int{}, "", ABIEnum{Variant: "None"},
+			},
+			"unknown variant": {
+				position{Owner: owner, Amount: amount}, []int{}, "", ABIEnum{Variant: "Maybe"},
+			},
+			"missing member": {
+				map[string]any{"owner": owner}, []int{}, "", ABIEnum{Variant: "None"},
+			},
+			"not a slice": {
+				position{Owner: owner, Amount: amount}, 1, "", ABIEnum{Variant: "None"},
+			},
+			"invalid number": {
+				position{Owner: owner, Amount: amount}, []string{"abc"}, "", ABIEnum{Variant: "None"},
+			},
+		}
+
+		for name, values := range testSet {
+			t.Run(name, func(t *testing.T) {
+				_, err := abi.EncodeValues(function.Inputs, values...)
+				require.ErrorIs(t, err, ErrABIEncode)
+			})
+		}
+	})
+
+	t.Run("Cairo 0 arrays", func(t *testing.T) {
+		cairo0ABI := ParseABI(ABI{
+			&FunctionABIEntry{
+				Type: ABITypeFunction,
+				Name: "set",
+				Inputs: []TypedParameter{
+					{Name: "data_len", Type: "felt"},
+					{Name: "data", Type: "felt*"},
+					{Name: "flag", Type: "felt"},
+				},
+			},
+		})
+		function, err := cairo0ABI.Function("set")
+		require.NoError(t, err)
+
+		calldata, err := cairo0ABI.EncodeValues(function.Inputs, []int{7, 8}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []*felt.Felt{
+			new(felt.Felt).SetUint64(2),
+			new(felt.Felt).SetUint64(7),
+			new(felt.Felt).SetUint64(8),
+			new(felt.Felt).SetUint64(1),
+		}, calldata)
+
+		_, err = cairo0ABI.EncodeValues(function.Inputs, 2, []int{7, 8}, 1)
+		require.ErrorIs(t, err, ErrABIEncode)
+	})
+}
@@ -0,0 +1,679 @@
+package contracts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var (
+	ErrInvalidInstruction = errors.New("invalid CASM instruction")
+	ErrInvalidHintPC      = errors.New("hint not at the start of an instruction")
+	ErrPCNotFound         = errors.New("pc not found in the bytecode")
+)
+
+// The layout of an encoded Cairo instruction: the biased 16-bit offsets of
+// dst, op0 and op1, followed by 15 flags.
+const (
+	offsetBits      = 16
+	offsetBias      = 1 << (offsetBits - 1)
+	offsetMask      = 1<<offsetBits - 1
+	flagsShift      = 3 * offsetBits
+	instructionBits = flagsShift + 15
+)
+
+// The flags of an encoded Cairo instruction.
+const (
+	flagDstFP uint64 = 1 << iota
+	flagOp0FP
+	flagOp1Imm
+	flagOp1FP
+	flagOp1AP
+	flagResAdd
+	flagResMul
+	flagPCJumpAbs
+	flagPCJumpRel
+	flagPCJnz
+	flagAPAdd
+	flagAPAdd1
+	flagOpcodeCall
+	flagOpcodeRet
+	flagOpcodeAssertEq
+)
+
+// The groups of flags, of which at most one flag can be set.
+const (
+	flagsOp1Src = flagOp1Imm | flagOp1FP | flagOp1AP
+	flagsRes    = flagResAdd | flagResMul
+	flagsPC     = flagPCJumpAbs | flagPCJumpRel | flagPCJnz
+	flagsAP     = flagAPAdd | flagAPAdd1
+	flagsOpcode = flagOpcodeCall | flagOpcodeRet | flagOpcodeAssertEq
+)
+
+// CasmOpcode is the kind of a CASM instruction.
+type CasmOpcode string
+
+const (
+	// `dst = res`
+	CasmAssertEq CasmOpcode = "assert_eq"
+	// `jmp abs res` or `jmp rel res`
+	CasmJump CasmOpcode = "jmp"
+	// `jmp rel res if dst != 0`
+	CasmJumpNz CasmOpcode = "jnz"
+	// `call abs res` or `call rel res`
+	CasmCall CasmOpcode = "call"
+	// `ret`
+	CasmRet CasmOpcode = "ret"
+	// `ap += res`
+	CasmAddAP CasmOpcode = "add_ap"
+	// A bytecode word that isn't a valid instruction, e.g. a constant
+	CasmData CasmOpcode = "data"
+)
+
+// CasmInstruction is a decoded CASM instruction.
+type CasmInstruction struct {
+	// The offset of the instruction in the bytecode
+	PC uint64
+	// The number of bytecode words of the instruction: 2 with an immediate,
+	// 1 otherwise
+	Size   uint64
+	Opcode CasmOpcode
+	// The destination of CasmAssertEq, or the condition of CasmJumpNz
+	Dst CellRef
+	// The right-hand side of CasmAssertEq, the target of CasmJump, CasmJumpNz
+	// and CasmCall, or the increment of CasmAddAP
+	Res ResOperand
+	// Whether the target of CasmJump, CasmJumpNz or CasmCall is relative to
+	// the pc
+	Relative bool
+	// Whether ap is incremented by 1 after the instruction (`, ap++`)
+	IncAP bool
+	// The hints executed before the instruction
+	Hints []Hint
+	// The first bytecode word of the instruction
+	Word *felt.Felt
+}
+
+// Target returns the pc jumped to or called by a CasmJump, CasmJumpNz or
+// CasmCall instruction whose target is an immediate.
+//
+// Returns:
+//   - uint64: the target pc
+//   - bool: false if the instruction has no immediate target
+func (inst *CasmInstruction) Target() (uint64, bool) {
+	switch inst.Opcode {
+	case CasmJump, CasmJumpNz, CasmCall:
+	default:
+		return 0, false
+	}
+	immediate, ok := inst.Res.Data.(Immediate)
+	if !ok {
+		return 0, false
+	}
+	value, ok := immediate.bigInt()
+	if !ok {
+		return 0, false
+	}
+	if inst.Relative {
+		value.Add(value, new(big.Int).SetUint64(inst.PC))
+	}
+	if !value.IsUint64() {
+		return 0, false
+	}
+
+	return value.Uint64(), true
+}
+
+// String returns the instruction in the CASM syntax of the Cairo compiler,
+// e.g. `[ap + 0] = [fp + -3] + 1, ap++`.
+func (inst *CasmInstruction) String() string {
+	var text string
+	switch inst.Opcode {
+	case CasmAssertEq:
+		text = fmt.Sprintf("%s = %s", inst.Dst, inst.Res)
+	case CasmJump:
+		text = fmt.Sprintf("jmp %s %s", jumpMode(inst.Relative), inst.Res)
+	case CasmJumpNz:
+		text = fmt.Sprintf("jmp rel %s if %s != 0", inst.Res, inst.Dst)
+	case CasmCall:
+		text = fmt.Sprintf("call %s %s", jumpMode(inst.Relative), inst.Res)
+	case CasmRet:
+		text = "ret"
+	case CasmAddAP:
+		text = "ap += " + inst.Res.String()
+	default:
+		return "dw " + inst.Word.String()
+	}
+	if inst.IncAP {
+		text += ", ap++"
+	}
+
+	return text
+}
+
+// CasmFunction is a function of the bytecode: an entry point, or a function
+// called by an instruction.
+type CasmFunction struct {
+	// The pc of the first instruction of the function
+	PC uint64
+	// The pc following the last instruction of the function
+	End uint64
+	// The type of the entry point, e.g. "EXTERNAL". Empty for the functions
+	// that aren't entry points.
+	EntryPointType string
+	// The entry point, nil for the functions that aren't entry points
+	EntryPoint *CasmEntryPoint
+}
+
+// Name returns the name of the function: the type and selector of the entry
+// point, e.g. "EXTERNAL 0x362...", or "func_<pc>".
+func (f *CasmFunction) Name() string {
+	if f.EntryPoint != nil {
+		return f.EntryPointType + " " + f.EntryPoint.Selector.String()
+	}
+
+	return fmt.Sprintf("func_%d", f.PC)
+}
+
+// CasmDisassembly is the disassembled bytecode of a CASM class.
+type CasmDisassembly struct {
+	// The instructions, ordered by pc
+	Instructions []CasmInstruction
+	// The functions, ordered by pc
+	Functions []CasmFunction
+}
+
+// CasmLocation is the location of a pc in the disassembled bytecode.
+type CasmLocation struct {
+	// The instruction containing the pc
+	Instruction *CasmInstruction
+	// The function containing the pc, nil if the pc is before the first
+	// function
+	Function *CasmFunction
+}
+
+// Disassemble decodes the bytecode of the class into CASM instructions,
+// attaches the hints to the instructions at their pc, and splits the
+// bytecode into functions, starting at the entry points and at the targets of
+// the call instructions. The bytecode words that aren't valid instructions,
+// e.g. the constants appended to the code, are decoded as CasmData.
+//
+// Returns:
+//   - *CasmDisassembly: the disassembled bytecode
+//   - error: ErrInvalidHintPC if a hint isn't at the start of an instruction
+func (c *CasmClass) Disassemble() (*CasmDisassembly, error) {
+	disassembly := &CasmDisassembly{
+		Instructions: make([]CasmInstruction, 0, len(c.ByteCode)),
+		Functions:    nil,
+	}
+	for pc := uint64(0); pc < uint64(len(c.ByteCode)); {
+		inst, err := decodeInstruction(c.ByteCode, pc)
+		if err != nil {
+			inst = dataWord(c.ByteCode, pc)
+		}
+		disassembly.Instructions = append(disassembly.Instructions, inst)
+		pc += inst.Size
+	}
+
+	for _, hints := range c.Hints {
+		if hints.Int < 0 {
+			return nil, fmt.Errorf("%w: pc %d", ErrInvalidHintPC, hints.Int)
+		}
+		inst := disassembly.instructionAt(uint64(hints.Int))
+		if inst == nil || inst.PC != uint64(hints.Int) {
+			return nil, fmt.Errorf("%w: pc %d", ErrInvalidHintPC, hints.Int)
+		}
+		inst.Hints = append(inst.Hints, hints.HintArr...)
+	}
+
+	disassembly.Functions = c.functions(disassembly.Instructions)
+
+	return disassembly, nil
+}
+
+// Resolve returns the instruction and the function containing a pc, e.g. a
+// pc reported in a revert reason.
+//
+// Parameters:
+//   - pc: the pc
+//
+// Returns:
+//   - *CasmLocation: the location of the pc
+//   - error: ErrPCNotFound if the pc is beyond the bytecode
+func (d *CasmDisassembly) Resolve(pc uint64) (*CasmLocation, error) {
+	inst := d.instructionAt(pc)
+	if inst == nil {
+		return nil, fmt.Errorf("%w: %d", ErrPCNotFound, pc)
+	}
+
+	location := &CasmLocation{Instruction: inst, Function: nil}
+	i := sort.Search(len(d.Functions), func(i int) bool { return d.Functions[i].PC > pc })
+	if i > 0 {
+		location.Function = &d.Functions[i-1]
+	}
+
+	return location, nil
+}
+
+// String returns the listing of the bytecode: the functions with their
+// instructions and hints, and the targets of the jumps and calls.
+func (d *CasmDisassembly) String() string {
+	var listing strings.Builder
+	nextFunction := 0
+	for i := range d.Instructions {
+		inst := &d.Instructions[i]
+		for nextFunction < len(d.Functions) && d.Functions[nextFunction].PC <= inst.PC {
+			if i > 0 {
+				listing.WriteString("\n")
+			}
+			listing.WriteString(d.Functions[nextFunction].header())
+			nextFunction++
+		}
+
+		for _, hint := range inst.Hints {
+			fmt.Fprintf(&listing, "%8s%%{ %s %%}\n", "", hint)
+		}
+		fmt.Fprintf(&listing, "%6d  %s", inst.PC, inst)
+		if target, ok := inst.Target(); ok {
+			fmt.Fprintf(&listing, " // -> %s", d.targetName(target))
+		}
+		listing.WriteString("\n")
+	}
+
+	return listing.String()
+}
+
+// header returns the line starting the function in the listing, with the
+// builtins of the entry points.
+func (f *CasmFunction) header() string {
+	if f.EntryPoint == nil || len(f.EntryPoint.Builtins) == 0 {
+		return f.Name() + ":\n"
+	}
+
+	return f.Name() + ": // builtins: " + strings.Join(f.EntryPoint.Builtins, ", ") + "\n"
+}
+
+// targetName returns the name of the function starting at the target pc, or
+// the pc itself.
+func (d *CasmDisassembly) targetName(target uint64) string {
+	i := sort.Search(len(d.Functions), func(i int) bool { return d.Functions[i].PC >= target })
+	if i < len(d.Functions) && d.Functions[i].PC == target {
+		return d.Functions[i].Name()
+	}
+
+	return fmt.Sprintf("pc %d", target)
+}
+
+// instructionAt returns the instruction containing a pc, or nil if the pc is
+// beyond the bytecode.
+func (d *CasmDisassembly) instructionAt(pc uint64) *CasmInstruction {
+	i := sort.Search(len(d.Instructions), func(i int) bool {
+		return d.Instructions[i].PC+d.Instructions[i].Size > pc
+	})
+	if i == len(d.Instructions) {
+		return nil
+	}
+
+	return &d.Instructions[i]
+}
+
+// functions returns the functions of the bytecode, starting at the entry
+// points and at the targets of the call instructions.
+func (c *CasmClass) functions(instructions []CasmInstruction) []CasmFunction {
+	byPC := make(map[uint64]CasmFunction)
+	for _, inst := range instructions {
+		if target, ok := inst.Target(); ok && inst.Opcode == CasmCall &&
+			target < uint64(len(c.ByteCode)) {
+			byPC[target] = CasmFunction{PC: target, End: 0, EntryPointType: "", EntryPoint: nil}
+		}
+	}
+	entryPoints := []struct {
+		entryPointType string
+		list           []CasmEntryPoint
+	}{
+		{"CONSTRUCTOR", c.EntryPointsByType.Constructor},
+		{"EXTERNAL", c.EntryPointsByType.External},
+		{"L1_HANDLER", c.EntryPointsByType.L1Handler},
+	}
+	for _, entryPoints := range entryPoints {
+		list := entryPoints.list
+		for i := range list {
+			pc := uint64(list[i].Offset)
+			byPC[pc] = CasmFunction{
+				PC:             pc,
+				End:            0,
+				EntryPointType: entryPoints.entryPointType,
+				EntryPoint:     &list[i],
+			}
+		}
+	}
+
+	functions := make([]CasmFunction, 0, len(byPC))
+	for _, function := range byPC {
+		functions = append(functions, function)
+	}
+	sort.Slice(functions, func(i, j int) bool { return functions[i].PC < functions[j].PC })
+	for i := range functions {
+		functions[i].End = uint64(len(c.ByteCode))
+		if i+1 < len(functions) {
+			functions[i].End = functions[i+1].PC
+		}
+	}
+
+	return functions
+}
+
+// encodedInstruction is a Cairo instruction split into its offsets and flags.
+type encodedInstruction struct {
+	offDst int
+	offOp0 int
+	offOp1 int
+	flags  uint64
+}
+
+// decodeInstruction decodes the instruction at a pc of the bytecode.
+func decodeInstruction(bytecode []*felt.Felt, pc uint64) (CasmInstruction, error) {
+	enc, err := decodeWord(bytecode[pc])
+	if err != nil {
+		return CasmInstruction{}, err
+	}
+
+	inst := CasmInstruction{
+		PC:       pc,
+		Size:     1,
+		Opcode:   "",
+		Dst:      cellRef(enc.flags&flagDstFP != 0, enc.offDst),
+		Res:      ResOperand{Type: "", Data: nil},
+		Relative: false,
+		IncAP:    enc.flags&flagAPAdd1 != 0,
+		Hints:    nil,
+		Word:     bytecode[pc],
+	}
+	op1, err := decodeOp1(bytecode, pc, enc)
+	if err != nil {
+		return CasmInstruction{}, err
+	}
+	if op1.Type == operandImmediate {
+		inst.Size = 2
+	}
+	if inst.Res, err = decodeRes(enc, op1); err != nil {
+		return CasmInstruction{}, err
+	}
+	if err := decodeOpcode(&inst, enc); err != nil {
+		return CasmInstruction{}, err
+	}
+
+	return inst, nil
+}
+
+// decodeWord splits a bytecode word into the offsets and flags of an
+// instruction.
+func decodeWord(word *felt.Felt) (encodedInstruction, error) {
+	value := word.BigInt(new(big.Int))
+	if value.BitLen() > instructionBits {
+		return encodedInstruction{}, fmt.Errorf("%w: %s", ErrInvalidInstruction, word)
+	}
+	encoded := value.Uint64()
+	flags := encoded >> flagsShift
+	for _, group := range []uint64{flagsOp1Src, flagsRes, flagsPC, flagsAP, flagsOpcode} {
+		if bits.OnesCount64(flags&group) > 1 {
+			return encodedInstruction{}, fmt.Errorf("%w: %s", ErrInvalidInstruction, word)
+		}
+	}
+	offset := func(i int) int {
+		return int(encoded>>(i*offsetBits)&offsetMask) - offsetBias
+	}
+
+	return encodedInstruction{
+		offDst: offset(0),
+		offOp0: offset(1),
+		offOp1: offset(2),
+		flags:  flags,
+	}, nil
+}
+
+// decodeOp1 returns the op1 operand of an instruction: an immediate, a cell,
+// or a cell addressed by op0.
+func decodeOp1(bytecode []*felt.Felt, pc uint64, enc encodedInstruction) (ResOperand, error) {
+	switch enc.flags & flagsOp1Src {
+	case flagOp1Imm:
+		if enc.offOp1 != 1 || pc+1 >= uint64(len(bytecode)) {
+			return ResOperand{}, fmt.Errorf(
+				"%w: missing immediate at pc %d", ErrInvalidInstruction, pc,
+			)
+		}
+
+		return ResOperand{Type: operandImmediate, Data: signedImmediate(bytecode[pc+1])}, nil
+	case flagOp1FP, flagOp1AP:
+		return ResOperand{
+			Type: operandDeref,
+			Data: Deref(cellRef(enc.flags&flagOp1FP != 0, enc.offOp1)),
+		}, nil
+	default:
+		return ResOperand{
+			Type: operandDoubleDeref,
+			Data: DoubleDeref{
+				CellRef: cellRef(enc.flags&flagOp0FP != 0, enc.offOp0),
+				Offset:  enc.offOp1,
+			},
+		}, nil
+	}
+}
+
+// decodeRes returns the result of an instruction: op1, or op0 added to or
+// multiplied by op1.
+func decodeRes(enc encodedInstruction, op1 ResOperand) (ResOperand, error) {
+	var operation Operation
+	switch enc.flags & flagsRes {
+	case flagResAdd:
+		operation = Add
+	case flagResMul:
+		operation = Mul
+	default:
+		return op1, nil
+	}
+	if op1.Type == operandDoubleDeref {
+		return ResOperand{}, fmt.Errorf("%w: binary operation on a double dereference",
+			ErrInvalidInstruction)
+	}
+
+	return ResOperand{
+		Type: operandBinOp,
+		Data: BinOp{
+			Operation: operation,
+			A:         cellRef(enc.flags&flagOp0FP != 0, enc.offOp0),
+			B:         B{Type: op1.Type, Data: op1.Data},
+		},
+	}, nil
+}
+
+// decodeOpcode sets the opcode of an instruction from its opcode, pc update
+// and ap update flags.
+func decodeOpcode(inst *CasmInstruction, enc encodedInstruction) error {
+	pcUpdate := enc.flags & flagsPC
+	apUpdate := enc.flags & flagsAP
+	isJump := pcUpdate == flagPCJumpAbs || pcUpdate == flagPCJumpRel
+
+	switch enc.flags & flagsOpcode {
+	case flagOpcodeAssertEq:
+		if pcUpdate != 0 || apUpdate == flagAPAdd {
+			return fmt.Errorf("%w: assert_eq updating pc or ap", ErrInvalidInstruction)
+		}
+		inst.Opcode = CasmAssertEq
+	case flagOpcodeCall:
+		if !isJump || apUpdate != 0 {
+			return fmt.Errorf("%w: call without a jump", ErrInvalidInstruction)
+		}
+		inst.Opcode, inst.Relative = CasmCall, pcUpdate == flagPCJumpRel
+	case flagOpcodeRet:
+		if pcUpdate != flagPCJumpAbs || apUpdate != 0 {
+			return fmt.Errorf("%w: ret without a jump", ErrInvalidInstruction)
+		}
+		inst.Opcode = CasmRet
+	default:
+		return decodeNop(inst, enc)
+	}
+
+	return nil
+}
+
+// decodeNop sets the opcode of an instruction without opcode flag: a jump, or
+// an ap increment.
+func decodeNop(inst *CasmInstruction, enc encodedInstruction) error {
+	apUpdate := enc.flags & flagsAP
+	switch pcUpdate := enc.flags & flagsPC; pcUpdate {
+	case flagPCJnz:
+		if enc.flags&flagsRes != 0 || apUpdate == flagAPAdd {
+			return fmt.Errorf("%w: jnz with a binary operation", ErrInvalidInstruction)
+		}
+		inst.Opcode, inst.Relative = CasmJumpNz, true
+	case flagPCJumpAbs, flagPCJumpRel:
+		if apUpdate == flagAPAdd {
+			return fmt.Errorf("%w: jmp with ap +=", ErrInvalidInstruction)
+		}
+		inst.Opcode, inst.Relative = CasmJump, pcUpdate == flagPCJumpRel
+	default:
+		if apUpdate != flagAPAdd {
+			return fmt.Errorf("%w: no operation", ErrInvalidInstruction)
+		}
+		inst.Opcode = CasmAddAP
+	}
+
+	return nil
+}
+
+// dataWord returns the bytecode word at a pc as a CasmData instruction.
+func dataWord(bytecode []*felt.Felt, pc uint64) CasmInstruction {
+	return CasmInstruction{
+		PC:       pc,
+		Size:     1,
+		Opcode:   CasmData,
+		Dst:      CellRef{Register: "", Offset: 0},
+		Res:      ResOperand{Type: "", Data: nil},
+		Relative: false,
+		IncAP:    false,
+		Hints:    nil,
+		Word:     bytecode[pc],
+	}
+}
+
+// cellRef returns the cell at an offset of ap, or of fp.
+func cellRef(isFP bool, offset int) CellRef {
+	if isFP {
+		return CellRef{Register: FP, Offset: offset}
+	}
+
+	return CellRef{Register: AP, Offset: offset}
+}
+
+// signedImmediate returns an immediate word as a signed value, the felts
+// above half the prime being negative values.
+func signedImmediate(word *felt.Felt) Immediate {
+	negated := new(felt.Felt).Neg(word)
+	if negated.Cmp(word) < 0 {
+		return Immediate("-" + negated.String())
+	}
+
+	return Immediate(word.String())
+}
+
+// jumpMode returns the mode of a jump or call target.
+func jumpMode(relative bool) string {
+	if relative {
+		return "rel"
+	}
+
+	return "abs"
+}
+
+// String returns the cell in the CASM syntax, e.g. `[ap + -1]`.
+func (c CellRef) String() string {
+	return fmt.Sprintf("[%s + %d]", strings.ToLower(string(c.Register)), c.Offset)
+}
+
+// String returns the dereferenced cell in the CASM syntax, e.g. `[fp + -3]`.
+func (d Deref) String() string {
+	return CellRef(d).String()
+}
+
+// String returns the double dereference in the CASM syntax, e.g.
+// `[[fp + -3] + 1]`.
+func (dd DoubleDeref) String() string {
+	return fmt.Sprintf("[%s + %d]", dd.CellRef, dd.Offset)
+}
+
+// String returns the immediate as a decimal number.
+func (i Immediate) String() string {
+	value, ok := i.bigInt()
+	if !ok {
+		return string(i)
+	}
+
+	return value.String()
+}
+
+// bigInt returns the value of the immediate, or false if it isn't a number.
+func (i Immediate) bigInt() (*big.Int, bool) {
+	return new(big.Int).SetString(string(i), 0)
+}
+
+// String returns the binary operation in the CASM syntax, e.g.
+// `[ap + 0] + 1`.
+func (b BinOp) String() string {
+	operator := "+"
+	if b.Operation == Mul {
+		operator = "*"
+	}
+
+	return fmt.Sprintf("%s %s %s", b.A, operator, b.B)
+}
+
+// String returns the operand in the CASM syntax.
+func (b B) String() string {
+	return fmt.Sprint(b.Data)
+}
+
+// String returns the operand in the CASM syntax.
+func (r ResOperand) String() string {
+	return fmt.Sprint(r.Data)
+}
+
+// String returns the hint in a readable form, e.g.
+// `TestLessThan(lhs=[ap + 0], rhs=5, dst=[ap + 1])`.
+func (h Hint) String() string {
+	if h.Type == "enum" {
+		return fmt.Sprint(h.Data)
+	}
+
+	var fields []string
+	hintFields(reflect.ValueOf(h.Data), &fields)
+
+	return h.Type + "(" + strings.Join(fields, ", ") + ")"
+}
+
+// hintFields appends the `name=value` representation of the fields of a hint
+// struct, including the fields of its embedded structs.
+func hintFields(value reflect.Value, fields *[]string) {
+	if value.Kind() != reflect.Struct {
+		return
+	}
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+		if field.Anonymous {
+			hintFields(value.Field(i), fields)
+
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		*fields = append(*fields, fmt.Sprintf("%s=%v", name, value.Field(i).Interface()))
+	}
+}
@@ -0,0 +1,225 @@
+package contracts
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDisassemble tests the disassembly of the bytecode of a compiled class:
+// the instructions, their hints, the functions and the listing.
+func TestDisassemble(t *testing.T) {
+	casmClass, err := UnmarshalCasmClass("./testData/hello_starknet_compiled.casm.json")
+	require.NoError(t, err)
+
+	disassembly, err := casmClass.Disassemble()
+	require.NoError(t, err)
+
+	// the instructions cover the whole bytecode
+	var size uint64
+	for _, inst := range disassembly.Instructions {
+		assert.Equal(t, size, inst.PC)
+		size += inst.Size
+	}
+	assert.Equal(t, uint64(len(casmClass.ByteCode)), size)
+
+	expectedInstructions := map[uint64]string{
+		0:  "jmp rel 7 if [ap + 0] != 0, ap++",
+		2:  "[ap + 0] = [fp + -6] + 340282366920938463463374607431768189416, ap++",
+		4:  "[ap + -1] = [[fp + -7] + 0]",
+		5:  "jmp rel 110",
+		7:  "[fp + -6] = [ap + 0] + 22040, ap++",
+		12: "call rel 232",
+		28: "ap += 1",
+		41: "ret",
+	}
+	for pc, expected := range expectedInstructions {
+		location, err := disassembly.Resolve(pc)
+		require.NoError(t, err)
+		assert.Equal(t, pc, location.Instruction.PC)
+		assert.Equal(t, expected, location.Instruction.String())
+	}
+
+	var hints int
+	for _, inst := range disassembly.Instructions {
+		hints += len(inst.Hints)
+	}
+	assert.Equal(t, len(casmClass.Hints), hints)
+	location, err := disassembly.Resolve(28)
+	require.NoError(t, err)
+	require.Len(t, location.Instruction.Hints, 1)
+	assert.Equal(t, "AllocSegment(dst=[ap + 0])", location.Instruction.Hints[0].String())
+
+	require.GreaterOrEqual(t, len(disassembly.Functions), 3)
+	assert.Equal(
+		t,
+		"EXTERNAL 0x362398bec32bc0ebb411203221a35a0301193a96f317ebe5e40be9f60d15320",
+		disassembly.Functions[0].Name(),
+	)
+	assert.Equal(t, uint64(130), disassembly.Functions[0].End)
+	assert.Equal(t, uint64(130), disassembly.Functions[1].PC)
+	assert.Equal(t, "func_244", disassembly.Functions[2].Name())
+	assert.Nil(t, disassembly.Functions[2].EntryPoint)
+
+	target, ok := disassembly.Instructions[8].Target()
+	require.True(t, ok)
+	assert.Equal(t, uint64(244), target)
+
+	listing := disassembly.String()
+	assert.Contains(t, listing,
+		"EXTERNAL 0x362398bec32bc0ebb411203221a35a0301193a96f317ebe5e40be9f60d15320:"+
+			" // builtins: range_check\n"+
+			"        %{ TestLessThanOrEqual(lhs=22040, rhs=[fp + -6], dst=[ap + 0]) %}\n"+
+			"     0  jmp rel 7 if [ap + 0] != 0, ap++ // -> pc 7\n",
+	)
+	assert.Contains(t, listing, "    12  call rel 232 // -> func_244\n")
+	assert.Contains(t, listing, "\nfunc_244:\n")
+}
+
+// TestDecodeInstruction tests the decoding of the bytecode words into
+// instructions, and of the invalid words into data words.
+func TestDecodeInstruction(t *testing.T) {
+	testSet := []struct {
+		Description string
+		ByteCode    []string
+		Expected    []string
+	}{
+		{
+			Description: "ret",
+			ByteCode:    []string{"0x208b7fff7fff7ffe"},
+			Expected:    []string{"ret"},
+		},
+		{
+			Description: "absolute jump",
+			ByteCode:    []string{"0x8780017fff7fff", "0x5"},
+			Expected:    []string{"jmp abs 5"},
+		},
+		{
+			Description: "multiplication",
+			ByteCode:    []string{"0x40527fff7ffd8000"},
+			Expected:    []string{"[ap + 0] = [fp + -3] * [ap + -1]"},
+		},
+		{
+			Description: "ap increment by a cell",
+			ByteCode:    []string{"0x40b7ffd7fff7fff"},
+			Expected:    []string{"ap += [fp + -3]"},
+		},
+		{
+			Description: "negative immediate",
+			ByteCode: []string{
+				"0x480680017fff8000",
+				"0x800000000000011000000000000000000000000000000000000000000000000",
+			},
+			Expected: []string{"[ap + 0] = -1, ap++"},
+		},
+		{
+			Description: "missing immediate",
+			ByteCode:    []string{"0x208b7fff7fff7ffe", "0x480680017fff8000"},
+			Expected:    []string{"ret", "dw 0x480680017fff8000"},
+		},
+		{
+			Description: "binary operation on a double dereference",
+			ByteCode:    []string{"0x4020800080008000"},
+			Expected:    []string{"dw 0x4020800080008000"},
+		},
+		{
+			Description: "two opcodes",
+			ByteCode:    []string{"0x6000800080008000"},
+			Expected:    []string{"dw 0x6000800080008000"},
+		},
+		{
+			Description: "word larger than an instruction",
+			ByteCode:    []string{"0x8000000000000000"},
+			Expected:    []string{"dw 0x8000000000000000"},
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Description, func(t *testing.T) {
+			casmClass := CasmClass{ByteCode: internalUtils.TestHexArrToFelt(t, test.ByteCode)}
+			disassembly, err := casmClass.Disassemble()
+			require.NoError(t, err)
+
+			require.Len(t, disassembly.Instructions, len(test.Expected))
+			for i, inst := range disassembly.Instructions {
+				assert.Equal(t, test.Expected[i], inst.String())
+			}
+		})
+	}
+}
+
+// TestResolve tests the resolution of pcs to their instruction and function,
+// and the errors of the disassembly.
+func TestResolve(t *testing.T) {
+	casmClass, err := UnmarshalCasmClass("./testData/hello_starknet_compiled.casm.json")
+	require.NoError(t, err)
+	disassembly, err := casmClass.Disassemble()
+	require.NoError(t, err)
+
+	// the immediate of the instruction at pc 2
+	location, err := disassembly.Resolve(3)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), location.Instruction.PC)
+	assert.Equal(t, uint64(0), location.Function.PC)
+
+	location, err = disassembly.Resolve(250)
+	require.NoError(t, err)
+	assert.Equal(t, "func_244", location.Function.Name())
+
+	_, err = disassembly.Resolve(uint64(len(casmClass.ByteCode)))
+	require.ErrorIs(t, err, ErrPCNotFound)
+
+	casmClass.Hints = append(casmClass.Hints, Hints{Int: 3, HintArr: casmClass.Hints[0].HintArr})
+	_, err = casmClass.Disassemble()
+	require.ErrorIs(t, err, ErrInvalidHintPC)
+}
+
+// TestHint_String tests the readable form of the hints.
+func TestHint_String(t *testing.T) {
+	testSet := []struct {
+		Hint     string
+		Expected string
+	}{
+		{
+			Hint:     `"AssertAllKeysUsed"`,
+			Expected: "AssertAllKeysUsed",
+		},
+		{
+			Hint: `{"Felt252DictEntryInit":{"dict_ptr":{"Deref":{"register":"FP","offset":-4}},` +
+				`"key":{"Immediate":"0x2a"}}}`,
+			Expected: "Felt252DictEntryInit(dict_ptr=[fp + -4], key=42)",
+		},
+		{
+			Hint: `{"WideMul128":{"lhs":{"DoubleDeref":[{"register":"AP","offset":-1},2]},` +
+				`"rhs":{"BinOp":{"op":"Mul","a":{"register":"FP","offset":0},` +
+				`"b":{"Immediate":"-0x1"}}},"high":{"register":"AP","offset":0},` +
+				`"low":{"register":"AP","offset":1}}}`,
+			Expected: "WideMul128(lhs=[[ap + -1] + 2], rhs=[fp + 0] * -1, " +
+				"high=[ap + 0], low=[ap + 1])",
+		},
+		{
+			Hint: `{"Cheatcode":{"selector":"0x1","input_start":{"Deref":{"register":"AP","offset":0}},` +
+				`"input_end":{"Deref":{"register":"AP","offset":1}},` +
+				`"output_start":{"register":"AP","offset":2},"output_end":{"register":"AP","offset":3}}}`,
+			Expected: "Cheatcode(selector=0x1, input_start=[ap + 0], input_end=[ap + 1], " +
+				"output_start=[ap + 2], output_end=[ap + 3])",
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Expected, func(t *testing.T) {
+			var hint Hint
+			require.NoError(t, json.Unmarshal([]byte(test.Hint), &hint))
+			assert.Equal(t, test.Expected, hint.String())
+		})
+	}
+
+	// the instruction operands use the same syntax
+	assert.Equal(t, "-5", Immediate("-0x5").String())
+	assert.Equal(t, "[fp + 3]", Deref{Register: FP, Offset: 3}.String())
+	assert.Equal(t, "dw 0x1", (&CasmInstruction{Opcode: CasmData, Word: new(felt.Felt).SetUint64(1)}).String())
+}
@@ -0,0 +1,290 @@
+// Package contract provides a runtime handle to call and invoke the functions
+// of a deployed contract by name, with Go values encoded and decoded from the
+// contract ABI. It is a separate package from `contracts` since it builds on
+// the `rpc` and `account` packages, which import `contracts`.
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+var (
+	ErrNoAccount        = errors.New("the contract handle has no account to send transactions")
+	ErrUnsupportedClass = errors.New("unsupported contract class type")
+)
+
+// Contract is a runtime handle to a deployed contract. Its functions are called
+// by name, with Go values as arguments and results, encoded and decoded with
+// the contract ABI (Cairo 0 or Sierra). See contracts.ParsedABI.EncodeValues for
+// the Go types accepted for each Cairo type.
+type Contract struct {
+	Address  *felt.Felt
+	Provider rpc.RPCProvider
+	// The account used to invoke the contract functions. Nil for a read-only handle.
+	Account account.AccountInterface
+	// The block used by Call. Defaults to the latest block.
+	BlockID rpc.BlockID
+
+	mu  sync.Mutex
+	abi *contracts.ParsedABI
+}
+
+// NewContract creates a new Contract handle.
+//
+// Parameters:
+//   - address: the address of the contract
+//   - abi: the parsed ABI of the contract (see contracts.ParseABI and
+//     contracts.ParseSierraABI). If nil, it is fetched with `ClassAt` on first use.
+//   - provider: the provider used to call the contract and fetch its ABI
+//   - acc: the account used to invoke the contract. Can be nil for a read-only handle.
+//
+// Returns:
+//   - *Contract: the contract handle
+func NewContract(
+	address *felt.Felt,
+	abi *contracts.ParsedABI,
+	provider rpc.RPCProvider,
+	acc account.AccountInterface,
+) *Contract {
+	return &Contract{
+		Address:  address,
+		Provider: provider,
+		Account:  acc,
+		BlockID:  rpc.WithBlockTag(rpc.BlockTagLatest),
+		abi:      abi,
+	}
+}
+
+// ABI returns the parsed ABI of the contract, fetching the contract class with
+// `ClassAt` if it was not supplied.
+//
+// Parameters:
+//   - ctx: the context.Context for the request
+//
+// Returns:
+//   - *contracts.ParsedABI: the parsed ABI
+//   - error: an error if the class can't be fetched or its ABI parsed
+func (contract *Contract) ABI(ctx context.Context) (*contracts.ParsedABI, error) {
+	contract.mu.Lock()
+	defer contract.mu.Unlock()
+
+	if contract.abi != nil {
+		return contract.abi, nil
+	}
+
+	class, err := contract.Provider.ClassAt(ctx, contract.BlockID, contract.Address)
+	if err != nil {
+		return nil, err
+	}
+	abi, err := ParseClassABI(class)
+	if err != nil {
+		return nil, err
+	}
+	contract.abi = abi
+
+	return abi, nil
+}
+
+// ParseClassABI parses the ABI of a contract class, as returned by the
+// `ClassAt` and `Class` methods of the provider.
+//
+// Parameters:
+//   - class: a *contracts.ContractClass or *contracts.DeprecatedContractClass
+//
+// Returns:
+//   - *contracts.ParsedABI: the parsed ABI
+//   - error: ErrUnsupportedClass if the class type is unknown, or a parsing error
+func ParseClassABI(class rpc.ClassOutput) (*contracts.ParsedABI, error) {
+	switch class := class.(type) {
+	case *contracts.ContractClass:
+		return contracts.ParseSierraABI(class.ABI)
+	case *contracts.DeprecatedContractClass:
+		if class.ABI == nil {
+			return contracts.ParseABI(nil), nil
+		}
+
+		return contracts.ParseABI(*class.ABI), nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedClass, class)
+	}
+}
+
+// EncodeCalldata encodes the arguments of a contract function.
+//
+// Parameters:
+//   - ctx: the context.Context for the request, used if the ABI must be fetched
+//   - function: the name of the function
+//   - args: the Go values of the function inputs
+//
+// Returns:
+//   - []*felt.Felt: the calldata
+//   - error: an error if the function is unknown or the arguments don't match its inputs
+func (contract *Contract) EncodeCalldata(
+	ctx context.Context,
+	function string,
+	args ...any,
+) ([]*felt.Felt, error) {
+	abi, err := contract.ABI(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fn, err := abi.Function(function)
+	if err != nil {
+		return nil, err
+	}
+
+	return abi.EncodeValues(fn.Inputs, args...)
+}
+
+// Call calls a contract function, without creating a transaction, at the
+// contract BlockID, and decodes its result.
+//
+// Parameters:
+//   - ctx: the context.Context for the request
+//   - function: the name of the function, e.g. "balance_of"
+//   - args: the Go values of the function inputs
+//
+// Returns:
+//   - []any: the decoded values of the function outputs
+//   - error: an error if the encoding, the call or the decoding fails
+func (contract *Contract) Call(ctx context.Context, function string, args ...any) ([]any, error) {
+	abi, err := contract.ABI(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fn, err := abi.Function(function)
+	if err != nil {
+		return nil, err
+	}
+	calldata, err := abi.EncodeValues(fn.Inputs, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := contract.Provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    contract.Address,
+		EntryPointSelector: fn.Selector,
+		Calldata:           calldata,
+	}, contract.BlockID)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := abi.DecodeValues(fn.Outputs, result)
+	if err != nil {
+		return nil, err
+	}
+	decoded := make([]any, len(values))
+	for i, value := range values {
+		decoded[i] = value.Value
+	}
+
+	return decoded, nil
+}
+
+// FunctionCall builds the call of a contract function, to be sent with other
+// calls in a single transaction with account.BuildAndSendInvokeTxn.
+//
+// Parameters:
+//   - ctx: the context.Context for the request, used if the ABI must be fetched
+//   - function: the name of the function, e.g. "transfer"
+//   - args: the Go values of the function inputs
+//
+// Returns:
+//   - rpc.InvokeFunctionCall: the function call
+//   - error: an error if the function is unknown or the arguments don't match its inputs
+func (contract *Contract) FunctionCall(
+	ctx context.Context,
+	function string,
+	args ...any,
+) (rpc.InvokeFunctionCall, error) {
+	calldata, err := contract.EncodeCalldata(ctx, function, args...)
+	if err != nil {
+		return rpc.InvokeFunctionCall{}, err
+	}
+
+	return rpc.InvokeFunctionCall{
+		ContractAddress: contract.Address,
+		FunctionName:    function,
+		CallData:        calldata,
+	}, nil
+}
+
+// Invoke sends a v3 invoke transaction calling a contract function, with the
+// contract account and the default transaction options.
+//
+// Parameters:
+//   - ctx: the context.Context for the request
+//   - function: the name of the function, e.g. "transfer"
+//   - args: the Go values of the function inputs
+//
+// Returns:
+//   - rpc.AddInvokeTransactionResponse: the response of the submitted transaction
+//   - error: ErrNoAccount if the handle has no account, or an error if the
+//     encoding or the transaction fails
+func (contract *Contract) Invoke(
+	ctx context.Context,
+	function string,
+	args ...any,
+) (rpc.AddInvokeTransactionResponse, error) {
+	return contract.InvokeWithOptions(ctx, nil, function, args...)
+}
+
+// InvokeWithOptions is like Invoke, with custom transaction options.
+//
+// Parameters:
+//   - ctx: the context.Context for the request
+//   - opts: the options for building the transaction. Pass `nil` to use default values.
+//   - function: the name of the function, e.g. "transfer"
+//   - args: the Go values of the function inputs
+//
+// Returns:
+//   - rpc.AddInvokeTransactionResponse: the response of the submitted transaction
+//   - error: ErrNoAccount if the handle has no account, or an error if the
+//     encoding or the transaction fails
+func (contract *Contract) InvokeWithOptions(
+	ctx context.Context,
+	opts *account.TxnOptions,
+	function string,
+	args ...any,
+) (rpc.AddInvokeTransactionResponse, error) {
+	if contract.Account == nil {
+		return rpc.AddInvokeTransactionResponse{}, ErrNoAccount
+	}
+
+	call, err := contract.FunctionCall(ctx, function, args...)
+	if err != nil {
+		return rpc.AddInvokeTransactionResponse{}, err
+	}
+
+	return contract.Account.BuildAndSendInvokeTxn(ctx, []rpc.InvokeFunctionCall{call}, opts)
+}
+
+// DecodeEvent decodes an event emitted by the contract.
+//
+// Parameters:
+//   - ctx: the context.Context for the request, used if the ABI must be fetched
+//   - event: the emitted event
+//
+// Returns:
+//   - *contracts.DecodedEvent: the decoded event
+//   - error: an error if the event is not in the ABI or can't be decoded
+func (contract *Contract) DecodeEvent(
+	ctx context.Context,
+	event *rpc.EventContent,
+) (*contracts.DecodedEvent, error) {
+	abi, err := contract.ABI(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return abi.DecodeEvent(event.Keys, event.Data)
+}
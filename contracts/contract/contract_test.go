@@ -0,0 +1,183 @@
+package contract_test
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/contracts/contract"
+	"github.com/NethermindEth/starknet.go/internal/tests"
+	"github.com/NethermindEth/starknet.go/internal/tests/mocks/rpcv10mock"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMain(m *testing.M) {
+	tests.LoadEnv()
+	os.Exit(m.Run())
+}
+
+// invokerAccount is an account that records the function calls it is asked to send.
+type invokerAccount struct {
+	account.AccountInterface
+	calls []rpc.InvokeFunctionCall
+}
+
+func (acc *invokerAccount) BuildAndSendInvokeTxn(
+	_ context.Context,
+	functionCalls []rpc.InvokeFunctionCall,
+	_ *account.TxnOptions,
+) (rpc.AddInvokeTransactionResponse, error) {
+	acc.calls = append(acc.calls, functionCalls...)
+
+	return rpc.AddInvokeTransactionResponse{Hash: internalUtils.DeadBeef}, nil
+}
+
+// TestContractSierra tests calling and invoking the functions of a Cairo 1
+// contract, with its ABI fetched lazily.
+func TestContractSierra(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	content, err := os.ReadFile("../testData/codec_test_abi.json")
+	require.NoError(t, err)
+	address := internalUtils.TestHexToFelt(t, "0x1234")
+	blockID := rpc.WithBlockTag(rpc.BlockTagLatest)
+
+	mockCtrl := gomock.NewController(t)
+	mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+	mockRPCProvider.EXPECT().
+		ClassAt(gomock.Any(), blockID, address).
+		Return(&contracts.ContractClass{ABI: contracts.NestedString(content)}, nil).
+		Times(1)
+
+	acc := new(invokerAccount)
+	handle := contract.NewContract(address, nil, mockRPCProvider, acc)
+
+	t.Run("call", func(t *testing.T) {
+		mockRPCProvider.EXPECT().
+			Call(gomock.Any(), rpc.FunctionCall{
+				ContractAddress:    address,
+				EntryPointSelector: internalUtils.GetSelectorFromNameFelt("get_positions"),
+				Calldata:           []*felt.Felt{},
+			}, blockID).
+			Return([]*felt.Felt{
+				new(felt.Felt).SetUint64(1),
+				new(felt.Felt).SetUint64(0xa),
+				new(felt.Felt).SetUint64(7),
+				new(felt.Felt),
+				new(felt.Felt),
+			}, nil)
+
+		result, err := handle.Call(t.Context(), "get_positions")
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		positions, ok := result[0].([]any)
+		require.True(t, ok)
+		require.Len(t, positions, 1)
+		position, ok := positions[0].(map[string]any)
+		require.True(t, ok)
+		require.Len(t, position, 3)
+		assert.Equal(t, new(felt.Felt).SetUint64(0xa), position["owner"])
+		assertBigInt(t, big.NewInt(7), position["amount"])
+		assertBigInt(t, big.NewInt(0), position["delta"])
+	})
+
+	t.Run("invoke", func(t *testing.T) {
+		position := map[string]any{"owner": "0xa", "amount": 7, "delta": -1}
+		resp, err := handle.Invoke(
+			t.Context(),
+			"deposit",
+			position,
+			[]*felt.Felt{},
+			"",
+			contracts.ABIEnum{Variant: "None"},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, internalUtils.DeadBeef, resp.Hash)
+
+		expectedCalldata, err := handle.EncodeCalldata(
+			t.Context(),
+			"deposit",
+			position,
+			[]*felt.Felt{},
+			"",
+			contracts.ABIEnum{Variant: "None"},
+		)
+		require.NoError(t, err)
+		require.Len(t, acc.calls, 1)
+		assert.Equal(t, rpc.InvokeFunctionCall{
+			ContractAddress: address,
+			FunctionName:    "deposit",
+			CallData:        expectedCalldata,
+		}, acc.calls[0])
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := handle.Call(t.Context(), "withdraw")
+		require.ErrorIs(t, err, contracts.ErrABIFunctionNotFound)
+
+		_, err = handle.Invoke(t.Context(), "deposit", 1)
+		require.ErrorIs(t, err, contracts.ErrABIEncode)
+
+		readOnly := contract.NewContract(address, nil, mockRPCProvider, nil)
+		_, err = readOnly.Invoke(t.Context(), "deposit")
+		require.ErrorIs(t, err, contract.ErrNoAccount)
+	})
+}
+
+// TestContractCairo0 tests calling a Cairo 0 contract with a supplied ABI.
+func TestContractCairo0(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	class := contracts.DeprecatedContractClass{
+		ABI: &contracts.ABI{
+			&contracts.FunctionABIEntry{
+				Type:            contracts.ABITypeFunction,
+				Name:            "balanceOf",
+				StateMutability: contracts.FuncStateMutVIEW,
+				Inputs:          []contracts.TypedParameter{{Name: "account", Type: "felt"}},
+				Outputs:         []contracts.TypedParameter{{Name: "balance", Type: "Uint256"}},
+			},
+		},
+	}
+	abi, err := contract.ParseClassABI(&class)
+	require.NoError(t, err)
+
+	address := internalUtils.TestHexToFelt(t, "0x1234")
+	mockCtrl := gomock.NewController(t)
+	mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+	mockRPCProvider.EXPECT().
+		Call(gomock.Any(), rpc.FunctionCall{
+			ContractAddress:    address,
+			EntryPointSelector: internalUtils.GetSelectorFromNameFelt("balanceOf"),
+			Calldata:           []*felt.Felt{internalUtils.DeadBeef},
+		}, rpc.WithBlockTag(rpc.BlockTagLatest)).
+		Return([]*felt.Felt{new(felt.Felt).SetUint64(3), new(felt.Felt)}, nil)
+
+	handle := contract.NewContract(address, abi, mockRPCProvider, nil)
+	result, err := handle.Call(t.Context(), "balanceOf", internalUtils.DeadBeef)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assertBigInt(t, big.NewInt(3), result[0])
+
+	_, err = contract.ParseClassABI("class")
+	require.ErrorIs(t, err, contract.ErrUnsupportedClass)
+}
+
+// assertBigInt asserts that actual is a *big.Int with the expected value.
+// The values are compared with Cmp, since a decoded zero and big.NewInt(0)
+// differ in their internal representation.
+func assertBigInt(t *testing.T, expected *big.Int, actual any) {
+	t.Helper()
+
+	value, ok := actual.(*big.Int)
+	require.True(t, ok, "expected a *big.Int, got %T", actual)
+	assert.Zero(t, expected.Cmp(value), "expected %s, got %s", expected, value)
+}
@@ -0,0 +1,203 @@
+package contracts
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// SierraProgramDiff is the semantic difference between two Sierra programs,
+// e.g. two versions of a class. The declarations are compared by their long
+// IDs, e.g. `Array<felt252>`, regardless of their IDs and debug names, and the
+// entry points by the code they execute.
+type SierraProgramDiff struct {
+	// The long IDs of the types declared by one program only, sorted
+	AddedTypes   []string
+	RemovedTypes []string
+	// The long IDs of the libfuncs declared by one program only, sorted. The
+	// libfuncs calling the functions of the program, e.g. `function_call`,
+	// aren't compared, as the changes of the functions are part of the
+	// changes of the entry points.
+	AddedLibfuncs   []string
+	RemovedLibfuncs []string
+	// The selectors of the entry points of one program only
+	AddedEntryPoints   []*felt.Felt
+	RemovedEntryPoints []*felt.Felt
+	// The selectors of the entry points of both programs whose code differs
+	ChangedEntryPoints []*felt.Felt
+}
+
+// DiffSierraPrograms returns the semantic difference between two Sierra
+// programs.
+//
+// Parameters:
+//   - from: the original program
+//   - to: the updated program
+//
+// Returns:
+//   - *SierraProgramDiff: the declarations and entry points added, removed or
+//     changed by the updated program
+func DiffSierraPrograms(from, to *SierraProgram) *SierraProgramDiff {
+	diff := &SierraProgramDiff{}
+	diff.AddedTypes, diff.RemovedTypes = diffLongIDs(from.typeLongIDs(), to.typeLongIDs())
+	diff.AddedLibfuncs, diff.RemovedLibfuncs = diffLongIDs(
+		from.libfuncLongIDs(),
+		to.libfuncLongIDs(),
+	)
+
+	fromCode := from.entryPointsCode()
+	toCode := to.entryPointsCode()
+	for i := range to.EntryPoints {
+		code, ok := fromCode[entryPointKey(&to.EntryPoints[i])]
+		switch {
+		case !ok:
+			diff.AddedEntryPoints = append(diff.AddedEntryPoints, to.EntryPoints[i].Selector)
+		case code != toCode[entryPointKey(&to.EntryPoints[i])]:
+			diff.ChangedEntryPoints = append(diff.ChangedEntryPoints, to.EntryPoints[i].Selector)
+		}
+	}
+	for i := range from.EntryPoints {
+		if _, ok := toCode[entryPointKey(&from.EntryPoints[i])]; !ok {
+			diff.RemovedEntryPoints = append(diff.RemovedEntryPoints, from.EntryPoints[i].Selector)
+		}
+	}
+
+	return diff
+}
+
+// IsEmpty returns whether the programs are semantically equal.
+//
+// Returns:
+//   - bool: true if nothing was added, removed or changed
+func (d *SierraProgramDiff) IsEmpty() bool {
+	return len(d.AddedTypes) == 0 && len(d.RemovedTypes) == 0 &&
+		len(d.AddedLibfuncs) == 0 && len(d.RemovedLibfuncs) == 0 &&
+		len(d.AddedEntryPoints) == 0 && len(d.RemovedEntryPoints) == 0 &&
+		len(d.ChangedEntryPoints) == 0
+}
+
+// diffLongIDs returns the sorted long IDs of one set only.
+func diffLongIDs(from, to map[string]struct{}) (added, removed []string) {
+	for longID := range to {
+		if _, ok := from[longID]; !ok {
+			added = append(added, longID)
+		}
+	}
+	for longID := range from {
+		if _, ok := to[longID]; !ok {
+			removed = append(removed, longID)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+
+	return added, removed
+}
+
+// longIDs returns the naming of the declarations by their long IDs, naming the
+// functions with a function.
+func (p *SierraProgram) longIDs(function func(id uint64) string) *sierraNames {
+	return &sierraNames{program: p, debug: false, function: function}
+}
+
+// typeLongIDs returns the set of the long IDs of the types.
+func (p *SierraProgram) typeLongIDs() map[string]struct{} {
+	names := p.longIDs(p.FunctionName)
+	longIDs := make(map[string]struct{}, len(p.Types))
+	for i := range p.Types {
+		longIDs[names.typeName(p.Types[i].ID, 0)] = struct{}{}
+	}
+
+	return longIDs
+}
+
+// libfuncLongIDs returns the set of the long IDs of the libfuncs which don't
+// call the functions of the program.
+func (p *SierraProgram) libfuncLongIDs() map[string]struct{} {
+	names := p.longIDs(p.FunctionName)
+	longIDs := make(map[string]struct{}, len(p.Libfuncs))
+	for i := range p.Libfuncs {
+		if !hasUserFuncArg(p.Libfuncs[i].Args) {
+			longIDs[names.libfuncName(p.Libfuncs[i].ID, 0)] = struct{}{}
+		}
+	}
+
+	return longIDs
+}
+
+// hasUserFuncArg returns whether generic args refer to a function of the
+// program.
+func hasUserFuncArg(args []SierraGenericArg) bool {
+	for _, arg := range args {
+		if arg.Kind == SierraArgUserFunc {
+			return true
+		}
+	}
+
+	return false
+}
+
+// entryPointKey returns the key of an entry point: its type and selector.
+func entryPointKey(entryPoint *SierraEntryPointUsage) string {
+	return entryPoint.EntryPointType + " " + entryPoint.Selector.String()
+}
+
+// entryPointsCode returns the code of the entry points, by key.
+func (p *SierraProgram) entryPointsCode() map[string]string {
+	code := make(map[string]string, len(p.EntryPoints))
+	for i := range p.EntryPoints {
+		code[entryPointKey(&p.EntryPoints[i])] = p.code(p.EntryPoints[i].Function)
+	}
+
+	return code
+}
+
+// code returns a canonical form of the code executable from a function,
+// which doesn't depend on the IDs of the declarations: the functions are
+// numbered in the order they're called, the declarations are named by their
+// long IDs, and the statements are numbered from the start of their function.
+func (p *SierraProgram) code(function uint64) string {
+	functions := p.functionCode(function)
+	order := make(map[uint64]int, len(functions))
+	for i, code := range functions {
+		order[code.function] = i
+	}
+	names := p.longIDs(func(id uint64) string { return fmt.Sprintf("#%d", order[id]) })
+
+	var builder strings.Builder
+	for i, code := range functions {
+		fn := &p.Functions[code.function]
+		fmt.Fprintf(&builder, "#%d(", i)
+		for _, typ := range fn.ParamTypes {
+			builder.WriteString(names.typeName(typ, 0) + ", ")
+		}
+		builder.WriteString(") -> (")
+		for _, typ := range fn.RetTypes {
+			builder.WriteString(names.typeName(typ, 0) + ", ")
+		}
+		fmt.Fprintf(&builder, ") %v\n", fn.Params)
+
+		for _, idx := range code.statements {
+			statement := &p.Statements[idx]
+			fmt.Fprintf(&builder, "%d: ", idx-fn.EntryPoint)
+			if statement.Return {
+				fmt.Fprintf(&builder, "return %v\n", statement.Args)
+
+				continue
+			}
+			fmt.Fprintf(&builder, "%s %v", names.libfuncName(statement.Libfunc, 0), statement.Args)
+			for _, branch := range statement.Branches {
+				if branch.Target == nil {
+					fmt.Fprintf(&builder, " next %v", branch.Results)
+				} else {
+					fmt.Fprintf(&builder, " %d %v", *branch.Target-fn.EntryPoint, branch.Results)
+				}
+			}
+			builder.WriteString("\n")
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,1116 @@
+package contracts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/curve"
+)
+
+var (
+	ErrInvalidSierraProgram     = errors.New("invalid Sierra program")
+	ErrUnsupportedSierraVersion = errors.New("unsupported Sierra version")
+)
+
+// The layout of a Sierra program: the Sierra and compiler versions, followed
+// by the compressed program.
+const (
+	sierraVersionLength = 3
+	sierraHeaderLength  = 2 * sierraVersionLength
+	// the maximum number of bits of the words packed in a felt
+	sierraPackedBits = 251
+	// the declared info of a type is above its number of generic args
+	sierraTypeInfoShift = 128
+	// the target of a branch falling through to the next statement
+	sierraFallthrough = math.MaxUint64
+	// the bound of the nesting of the generic args in a name
+	sierraMaxNameDepth = 32
+	// the size of a felt in the code size charged by the declare fees
+	sierraWordBytes = 32
+)
+
+// The flags of the declared info of a type.
+const (
+	typeInfoStorable uint64 = 1 << iota
+	typeInfoDroppable
+	typeInfoDuplicatable
+	typeInfoZeroSized
+	typeInfoDeclared uint64 = 1 << 63
+)
+
+// The kinds of the statements of a Sierra program.
+const (
+	sierraInvocation = iota
+	sierraReturn
+)
+
+// SierraGenericArgKind is the kind of a generic argument of a Sierra type or
+// libfunc.
+type SierraGenericArgKind string
+
+const (
+	// A user type, identified by the hash of its name, e.g. the `ut@...` of
+	// `Struct<ut@..., felt252>`
+	SierraArgUserType SierraGenericArgKind = "user_type"
+	// A type declared by the program
+	SierraArgType SierraGenericArgKind = "type"
+	// A value, e.g. the value of a `Const`
+	SierraArgValue SierraGenericArgKind = "value"
+	// A function of the program, e.g. the callee of `function_call`
+	SierraArgUserFunc SierraGenericArgKind = "user_func"
+	// A libfunc declared by the program
+	SierraArgLibfunc SierraGenericArgKind = "libfunc"
+)
+
+// The generic argument kinds, by their serialised value. The negative values
+// are serialised as their absolute value, after sierraNegativeValue.
+var sierraArgKinds = []SierraGenericArgKind{
+	SierraArgUserType,
+	SierraArgType,
+	SierraArgValue,
+	SierraArgUserFunc,
+	SierraArgLibfunc,
+}
+
+const sierraNegativeValue = 5
+
+// The Sierra version 0.1.0, serialised as a short string by the first Cairo 1
+// compilers.
+var sierraVersion010 = new(felt.Felt).SetBytes([]byte("0.1.0"))
+
+// The generic IDs that don't fit in a short string, which are serialised as
+// their Starknet Keccak.
+var sierraLongGenericIDs = func() map[felt.Felt]string {
+	names := []string{
+		"storage_address_from_base_and_offset",
+		"contract_address_try_from_felt252",
+		"storage_base_address_from_felt252",
+		"storage_address_try_from_felt252",
+		"secp256k1_get_point_from_x_syscall",
+		"secp256r1_get_point_from_x_syscall",
+		"circuit_failure_guarantee_verify",
+		"u96_limbs_less_than_guarantee_verify",
+		"u96_single_limb_less_than_guarantee_verify",
+	}
+	ids := make(map[felt.Felt]string, len(names))
+	for _, name := range names {
+		ids[*curve.StarknetKeccak([]byte(name))] = name
+	}
+
+	return ids
+}()
+
+// SierraVersion is a version of the Sierra language or of the Cairo compiler.
+type SierraVersion struct {
+	Major uint64
+	Minor uint64
+	Patch uint64
+}
+
+// String returns the version as `major.minor.patch`.
+func (v SierraVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// SierraDebugInfo is the debug info of a Sierra program: the names of its
+// types, libfuncs and functions. It's the `sierra_program_debug_info` field
+// of the compiled contract class file, which isn't part of the declared class.
+type SierraDebugInfo struct {
+	TypeNames     []SierraDebugName `json:"type_names"`
+	LibfuncNames  []SierraDebugName `json:"libfunc_names"`
+	UserFuncNames []SierraDebugName `json:"user_func_names"`
+}
+
+// SierraDebugName is the name of a type, libfunc or function of a Sierra
+// program, serialised as an `[id, name]` pair.
+type SierraDebugName struct {
+	ID   uint64
+	Name string
+}
+
+// UnmarshalJSON unmarshals an `[id, name]` pair into a SierraDebugName.
+//
+// Parameters:
+//   - data: the JSON data to unmarshal
+//
+// Returns:
+//   - error: an error if the data isn't an `[id, name]` pair
+func (n *SierraDebugName) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("invalid debug name %s: expected an [id, name] pair", data)
+	}
+	if err := json.Unmarshal(pair[0], &n.ID); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(pair[1], &n.Name)
+}
+
+// MarshalJSON marshals a SierraDebugName into an `[id, name]` pair.
+//
+// Returns:
+//   - []byte: the JSON pair
+//   - error: an error if the marshalling fails
+func (n SierraDebugName) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{n.ID, n.Name})
+}
+
+// UnmarshalSierraDebugInfo reads the debug info of the Sierra program of a
+// compiled contract class file, e.g. `<contract>.contract_class.json`.
+//
+// Parameters:
+//   - filePath: the path of the compiled contract class file
+//
+// Returns:
+//   - *SierraDebugInfo: the debug info, empty if the file has none
+//   - error: an error if the file can't be read or unmarshalled
+func UnmarshalSierraDebugInfo(filePath string) (*SierraDebugInfo, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var class struct {
+		DebugInfo *SierraDebugInfo `json:"sierra_program_debug_info"`
+	}
+	if err := json.Unmarshal(content, &class); err != nil {
+		return nil, err
+	}
+	if class.DebugInfo == nil {
+		return &SierraDebugInfo{}, nil
+	}
+
+	return class.DebugInfo, nil
+}
+
+// SierraGenericArg is a generic argument of a Sierra type or libfunc.
+type SierraGenericArg struct {
+	Kind SierraGenericArgKind
+	// The ID of the SierraArgType, SierraArgUserFunc or SierraArgLibfunc
+	ID uint64
+	// The value of the SierraArgValue, or the hash of the name of the
+	// SierraArgUserType
+	Value *big.Int
+}
+
+// SierraTypeInfo is the declared info of a Sierra type.
+type SierraTypeInfo struct {
+	Storable     bool
+	Droppable    bool
+	Duplicatable bool
+	ZeroSized    bool
+}
+
+// SierraTypeDeclaration is a concrete type declared by a Sierra program,
+// e.g. `Array<felt252>`.
+type SierraTypeDeclaration struct {
+	ID uint64
+	// The generic type, e.g. `Array`
+	GenericID string
+	Args      []SierraGenericArg
+	// The declared info of the type, nil for the programs compiled before it
+	// was part of the program
+	Info *SierraTypeInfo
+	// The name of the type, empty without debug info
+	DebugName string
+}
+
+// SierraLibfuncDeclaration is a concrete libfunc declared by a Sierra
+// program, e.g. `array_append<felt252>`.
+type SierraLibfuncDeclaration struct {
+	ID uint64
+	// The generic libfunc, e.g. `array_append`
+	GenericID string
+	Args      []SierraGenericArg
+	// The name of the libfunc, empty without debug info
+	DebugName string
+}
+
+// SierraStatement is a statement of a Sierra program: the invocation of a
+// libfunc, or a return.
+type SierraStatement struct {
+	// Whether the statement returns from the function
+	Return bool
+	// The ID of the invoked libfunc
+	Libfunc uint64
+	// The variables passed to the libfunc, or returned
+	Args []uint64
+	// The branches of the invocation, one per possible outcome of the libfunc
+	Branches []SierraBranch
+}
+
+// SierraBranch is a branch of the invocation of a libfunc.
+type SierraBranch struct {
+	// The index of the statement jumped to, nil to fall through to the next
+	// statement
+	Target *uint64
+	// The variables assigned by the libfunc in the branch
+	Results []uint64
+}
+
+// SierraFunction is a function of a Sierra program.
+type SierraFunction struct {
+	ID         uint64
+	ParamTypes []uint64
+	RetTypes   []uint64
+	// The variables of the parameters
+	Params []uint64
+	// The index of the first statement of the function
+	EntryPoint uint64
+	// The name of the function, empty without debug info
+	DebugName string
+}
+
+// SierraEntryPointUsage is the code used by an entry point of a class.
+type SierraEntryPointUsage struct {
+	// The type of the entry point, e.g. "EXTERNAL"
+	EntryPointType string
+	Selector       *felt.Felt
+	// The ID of the function of the entry point
+	Function uint64
+	// The IDs of the functions executable from the entry point, including
+	// Function, sorted
+	Functions []uint64
+	// The IDs of the libfuncs invoked by these functions, sorted
+	Libfuncs []uint64
+}
+
+// SierraProgramSize is the size breakdown of a Sierra program. The length
+// of the program is charged by the declare fees, see DeclareCodeSize.
+type SierraProgramSize struct {
+	// The number of felts of the program
+	Program int
+	// The felts of the Sierra and compiler versions
+	Header int
+	// The felts of the table of the distinct words of the program, with its
+	// length and padding
+	CodeTable int
+	// The felts of the packed words, with their number
+	Packed int
+	// The number of words of the decompressed program, and of each of its
+	// sections
+	Words      int
+	Types      int
+	Libfuncs   int
+	Statements int
+	Functions  int
+	// The length in bytes of the ABI of the class
+	ABI int
+}
+
+// DeclareCodeSize returns the code size charged by the declare fees of a
+// class: the felts of its Sierra program and CASM bytecode as 32-byte words,
+// plus the length of its ABI.
+//
+// Parameters:
+//   - casmClass: the compiled class of the Sierra program
+//
+// Returns:
+//   - int: the code size in bytes
+func (s *SierraProgramSize) DeclareCodeSize(casmClass *CasmClass) int {
+	return (s.Program+len(casmClass.ByteCode))*sierraWordBytes + s.ABI
+}
+
+// SierraProgram is a decoded Sierra program.
+type SierraProgram struct {
+	SierraVersion   SierraVersion
+	CompilerVersion SierraVersion
+	// The declarations of the program, indexed by ID
+	Types      []SierraTypeDeclaration
+	Libfuncs   []SierraLibfuncDeclaration
+	Statements []SierraStatement
+	Functions  []SierraFunction
+	// The code used by each entry point of the class
+	EntryPoints []SierraEntryPointUsage
+	Size        SierraProgramSize
+}
+
+// DecodeSierraProgram decodes the Sierra program of the class: its versions,
+// its declarations and statements, and the code used by each entry point.
+// The programs of Sierra 0.x, compiled before Cairo 1.0, aren't supported.
+//
+// Parameters:
+//   - debugInfo: the debug info of the program, naming its declarations. It
+//     can be nil
+//
+// Returns:
+//   - *SierraProgram: the decoded program
+//   - error: an error if the program can't be decoded
+func (c *ContractClass) DecodeSierraProgram(debugInfo *SierraDebugInfo) (*SierraProgram, error) {
+	if len(c.SierraProgram) < sierraHeaderLength {
+		return nil, fmt.Errorf(
+			"%w: %d felts, shorter than the version header",
+			ErrInvalidSierraProgram, len(c.SierraProgram),
+		)
+	}
+	if c.SierraProgram[0].Equal(sierraVersion010) {
+		return nil, fmt.Errorf("%w: 0.1.0", ErrUnsupportedSierraVersion)
+	}
+	header := &sierraReader{words: c.SierraProgram[:sierraHeaderLength], pos: 0}
+	sierraVersion, err := header.readVersion()
+	if err != nil {
+		return nil, err
+	}
+	if sierraVersion.Major == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSierraVersion, sierraVersion)
+	}
+	compilerVersion, err := header.readVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	words, codeTableLen, err := decompressSierraProgram(c.SierraProgram[sierraHeaderLength:])
+	if err != nil {
+		return nil, err
+	}
+	program, err := decodeSierraProgram(words)
+	if err != nil {
+		return nil, err
+	}
+	program.SierraVersion = sierraVersion
+	program.CompilerVersion = compilerVersion
+	program.Size.Program = len(c.SierraProgram)
+	program.Size.Header = sierraHeaderLength
+	program.Size.CodeTable = codeTableLen
+	program.Size.Packed = len(c.SierraProgram) - sierraHeaderLength - codeTableLen
+	program.Size.ABI = len(c.ABI)
+
+	program.setDebugNames(debugInfo)
+	if program.EntryPoints, err = program.entryPoints(&c.EntryPointsByType); err != nil {
+		return nil, err
+	}
+
+	return program, nil
+}
+
+// TypeName returns the name of a type: its debug name, or its generic ID and
+// generic args, e.g. `Array<[3]>`, where `[3]` is an unnamed type.
+//
+// Parameters:
+//   - id: the ID of the type
+//
+// Returns:
+//   - string: the name of the type
+func (p *SierraProgram) TypeName(id uint64) string {
+	return p.debugNames().typeName(id, 0)
+}
+
+// LibfuncName returns the name of a libfunc: its debug name, or its generic ID
+// and generic args, e.g. `array_append<[3]>`.
+//
+// Parameters:
+//   - id: the ID of the libfunc
+//
+// Returns:
+//   - string: the name of the libfunc
+func (p *SierraProgram) LibfuncName(id uint64) string {
+	return p.debugNames().libfuncName(id, 0)
+}
+
+// FunctionName returns the name of a function: its debug name, or its ID
+// between brackets, e.g. `[3]`.
+//
+// Parameters:
+//   - id: the ID of the function
+//
+// Returns:
+//   - string: the name of the function
+func (p *SierraProgram) FunctionName(id uint64) string {
+	if id < uint64(len(p.Functions)) && p.Functions[id].DebugName != "" {
+		return p.Functions[id].DebugName
+	}
+
+	return fmt.Sprintf("[%d]", id)
+}
+
+// SierraAllowedLibfuncs is a list of the generic libfuncs allowed in the
+// declared classes, in the format of the lists of the Cairo compiler, e.g.
+// `audited.json` or `experimental.json`.
+type SierraAllowedLibfuncs struct {
+	AllowedLibfuncs []string `json:"allowed_libfuncs"`
+}
+
+// DisallowedLibfuncs returns the libfuncs of the program whose generic libfunc
+// isn't allowed, e.g. the experimental libfuncs when checked against the
+// audited list. The entry points using them can be found with their
+// SierraEntryPointUsage.
+//
+// Parameters:
+//   - allowed: the allowed generic libfuncs, e.g. `array_append`
+//
+// Returns:
+//   - []SierraLibfuncDeclaration: the disallowed libfuncs, by ID
+func (p *SierraProgram) DisallowedLibfuncs(allowed []string) []SierraLibfuncDeclaration {
+	allowedSet := make(map[string]struct{}, len(allowed))
+	for _, genericID := range allowed {
+		allowedSet[genericID] = struct{}{}
+	}
+
+	var disallowed []SierraLibfuncDeclaration
+	for i := range p.Libfuncs {
+		if _, ok := allowedSet[p.Libfuncs[i].GenericID]; !ok {
+			disallowed = append(disallowed, p.Libfuncs[i])
+		}
+	}
+
+	return disallowed
+}
+
+// debugNames returns the naming of the declarations by their debug names,
+// falling back to their long IDs.
+func (p *SierraProgram) debugNames() *sierraNames {
+	return &sierraNames{program: p, debug: true, function: p.FunctionName}
+}
+
+// sierraNames names the declarations of a program.
+type sierraNames struct {
+	program *SierraProgram
+	// whether the debug names are used
+	debug bool
+	// names the functions of the program
+	function func(id uint64) string
+}
+
+// typeName returns the name of a type. depth is the nesting of the type in
+// the name being built.
+func (n *sierraNames) typeName(id uint64, depth int) string {
+	if id >= uint64(len(n.program.Types)) || depth > sierraMaxNameDepth {
+		return fmt.Sprintf("[%d]", id)
+	}
+	decl := &n.program.Types[id]
+	if n.debug && decl.DebugName != "" {
+		return decl.DebugName
+	}
+
+	return n.longID(decl.GenericID, decl.Args, depth)
+}
+
+// libfuncName returns the name of a libfunc. depth is the nesting of the
+// libfunc in the name being built.
+func (n *sierraNames) libfuncName(id uint64, depth int) string {
+	if id >= uint64(len(n.program.Libfuncs)) || depth > sierraMaxNameDepth {
+		return fmt.Sprintf("[%d]", id)
+	}
+	decl := &n.program.Libfuncs[id]
+	if n.debug && decl.DebugName != "" {
+		return decl.DebugName
+	}
+
+	return n.longID(decl.GenericID, decl.Args, depth)
+}
+
+// longID returns the name of a generic type or libfunc with its generic args.
+func (n *sierraNames) longID(genericID string, args []SierraGenericArg, depth int) string {
+	if len(args) == 0 {
+		return genericID
+	}
+	names := make([]string, len(args))
+	for i, arg := range args {
+		switch arg.Kind {
+		case SierraArgUserType:
+			names[i] = "ut@0x" + arg.Value.Text(16) //nolint:mnd // hexadecimal
+		case SierraArgType:
+			names[i] = n.typeName(arg.ID, depth+1)
+		case SierraArgUserFunc:
+			names[i] = "user@" + n.function(arg.ID)
+		case SierraArgLibfunc:
+			names[i] = "lib@" + n.libfuncName(arg.ID, depth+1)
+		case SierraArgValue:
+			names[i] = arg.Value.String()
+		}
+	}
+
+	return genericID + "<" + strings.Join(names, ", ") + ">"
+}
+
+// setDebugNames sets the debug names of the declarations of the program.
+func (p *SierraProgram) setDebugNames(debugInfo *SierraDebugInfo) {
+	if debugInfo == nil {
+		return
+	}
+	for _, name := range debugInfo.TypeNames {
+		if name.ID < uint64(len(p.Types)) {
+			p.Types[name.ID].DebugName = name.Name
+		}
+	}
+	for _, name := range debugInfo.LibfuncNames {
+		if name.ID < uint64(len(p.Libfuncs)) {
+			p.Libfuncs[name.ID].DebugName = name.Name
+		}
+	}
+	for _, name := range debugInfo.UserFuncNames {
+		if name.ID < uint64(len(p.Functions)) {
+			p.Functions[name.ID].DebugName = name.Name
+		}
+	}
+}
+
+// entryPoints returns the code used by the entry points of the class.
+func (p *SierraProgram) entryPoints(
+	entryPointsByType *SierraEntryPointsByType,
+) ([]SierraEntryPointUsage, error) {
+	entryPoints := []struct {
+		entryPointType string
+		list           []SierraEntryPoint
+	}{
+		{"CONSTRUCTOR", entryPointsByType.Constructor},
+		{"EXTERNAL", entryPointsByType.External},
+		{"L1_HANDLER", entryPointsByType.L1Handler},
+	}
+
+	usages := make([]SierraEntryPointUsage, 0, len(entryPointsByType.Constructor)+
+		len(entryPointsByType.External)+len(entryPointsByType.L1Handler))
+	for _, entryPoints := range entryPoints {
+		for _, entryPoint := range entryPoints.list {
+			function := uint64(entryPoint.FunctionIdx)
+			if function >= uint64(len(p.Functions)) {
+				return nil, fmt.Errorf(
+					"%w: entry point %s calls the unknown function %d",
+					ErrInvalidSierraProgram, entryPoint.Selector, function,
+				)
+			}
+			usage := SierraEntryPointUsage{
+				EntryPointType: entryPoints.entryPointType,
+				Selector:       entryPoint.Selector,
+				Function:       function,
+				Functions:      nil,
+				Libfuncs:       nil,
+			}
+			libfuncs := make(map[uint64]struct{})
+			for _, code := range p.functionCode(function) {
+				usage.Functions = append(usage.Functions, code.function)
+				for _, idx := range code.statements {
+					if !p.Statements[idx].Return {
+						libfuncs[p.Statements[idx].Libfunc] = struct{}{}
+					}
+				}
+			}
+			for libfunc := range libfuncs {
+				usage.Libfuncs = append(usage.Libfuncs, libfunc)
+			}
+			slices.Sort(usage.Functions)
+			slices.Sort(usage.Libfuncs)
+			usages = append(usages, usage)
+		}
+	}
+
+	return usages, nil
+}
+
+// sierraFunctionCode is the reachable statements of a function.
+type sierraFunctionCode struct {
+	function   uint64
+	statements []uint64
+}
+
+// functionCode returns the code of a function and of the functions it calls,
+// transitively, in the order they're called.
+func (p *SierraProgram) functionCode(function uint64) []sierraFunctionCode {
+	var code []sierraFunctionCode
+	seen := map[uint64]bool{function: true}
+	for queue := []uint64{function}; len(queue) > 0; queue = queue[1:] {
+		statements := p.reachableStatements(p.Functions[queue[0]].EntryPoint)
+		code = append(code, sierraFunctionCode{function: queue[0], statements: statements})
+		for _, idx := range statements {
+			for _, callee := range p.callees(&p.Statements[idx]) {
+				if !seen[callee] {
+					seen[callee] = true
+					queue = append(queue, callee)
+				}
+			}
+		}
+	}
+
+	return code
+}
+
+// reachableStatements returns the sorted indices of the statements reachable
+// from a statement, without entering the called functions.
+func (p *SierraProgram) reachableStatements(entryPoint uint64) []uint64 {
+	seen := map[uint64]bool{entryPoint: true}
+	statements := []uint64{entryPoint}
+	for stack := []uint64{entryPoint}; len(stack) > 0; {
+		idx := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, branch := range p.Statements[idx].Branches {
+			next := idx + 1
+			if branch.Target != nil {
+				next = *branch.Target
+			}
+			if next < uint64(len(p.Statements)) && !seen[next] {
+				seen[next] = true
+				statements = append(statements, next)
+				stack = append(stack, next)
+			}
+		}
+	}
+	slices.Sort(statements)
+
+	return statements
+}
+
+// callees returns the functions called by a statement.
+func (p *SierraProgram) callees(statement *SierraStatement) []uint64 {
+	if statement.Return {
+		return nil
+	}
+	var callees []uint64
+	for _, arg := range p.Libfuncs[statement.Libfunc].Args {
+		if arg.Kind == SierraArgUserFunc && arg.ID < uint64(len(p.Functions)) {
+			callees = append(callees, arg.ID)
+		}
+	}
+
+	return callees
+}
+
+// decompressSierraProgram decompresses the words of a Sierra program. The
+// compressed program is made of the number of distinct words, the padding of
+// their number to a power of 2, the distinct words, the number of words, and
+// the indices of the words among the distinct words, packed in felts.
+//
+// It returns the words, and the number of felts of the table of the distinct
+// words.
+func decompressSierraProgram(compressed []*felt.Felt) ([]*felt.Felt, int, error) {
+	reader := &sierraReader{words: compressed, pos: 0}
+	codeLen, err := reader.readLen()
+	if err != nil {
+		return nil, 0, err
+	}
+	padding, err := reader.readUint()
+	if err != nil {
+		return nil, 0, err
+	}
+	if codeLen == 0 || padding > math.MaxUint32 || reader.pos+codeLen > len(compressed) {
+		return nil, 0, fmt.Errorf("%w: invalid code table", ErrInvalidSierraProgram)
+	}
+	code := compressed[reader.pos : reader.pos+codeLen]
+	reader.pos += codeLen
+	codeTableLen := reader.pos
+
+	wordsLen, err := reader.readUint()
+	if err != nil {
+		return nil, 0, err
+	}
+	paddedLen := uint64(codeLen) + padding
+	wordsPerFelt := uint64(sierraPackedBits / max(bits.Len64(paddedLen)-1, 1))
+	packed := compressed[reader.pos:]
+	if wordsLen > uint64(len(packed))*wordsPerFelt ||
+		(wordsLen+wordsPerFelt-1)/wordsPerFelt != uint64(len(packed)) {
+		return nil, 0, fmt.Errorf(
+			"%w: %d packed felts for %d words",
+			ErrInvalidSierraProgram, len(packed), wordsLen,
+		)
+	}
+
+	words := make([]*felt.Felt, 0, wordsLen)
+	base := new(big.Int).SetUint64(paddedLen)
+	index := new(big.Int)
+	for _, word := range packed {
+		value := word.BigInt(new(big.Int))
+		for i := uint64(0); i < wordsPerFelt && uint64(len(words)) < wordsLen; i++ {
+			value.DivMod(value, base, index)
+			if index.Uint64() >= uint64(codeLen) {
+				return nil, 0, fmt.Errorf(
+					"%w: word index %s out of the code table",
+					ErrInvalidSierraProgram, index,
+				)
+			}
+			words = append(words, code[index.Uint64()])
+		}
+	}
+
+	return words, codeTableLen, nil
+}
+
+// decodeSierraProgram decodes the sections of a decompressed Sierra program:
+// the types, the libfuncs, the statements and the functions.
+func decodeSierraProgram(words []*felt.Felt) (*SierraProgram, error) {
+	reader := &sierraReader{words: words, pos: 0}
+	program := &SierraProgram{}
+	var err error
+
+	start := reader.pos
+	if program.Types, err = reader.readTypes(); err != nil {
+		return nil, err
+	}
+	program.Size.Types, start = reader.pos-start, reader.pos
+	if program.Libfuncs, err = reader.readLibfuncs(); err != nil {
+		return nil, err
+	}
+	program.Size.Libfuncs, start = reader.pos-start, reader.pos
+	if program.Statements, err = reader.readStatements(); err != nil {
+		return nil, err
+	}
+	program.Size.Statements, start = reader.pos-start, reader.pos
+	if program.Functions, err = reader.readFunctions(); err != nil {
+		return nil, err
+	}
+	program.Size.Functions = reader.pos - start
+	program.Size.Words = len(words)
+	if reader.pos != len(words) {
+		return nil, fmt.Errorf(
+			"%w: %d words after the functions",
+			ErrInvalidSierraProgram, len(words)-reader.pos,
+		)
+	}
+
+	if err := program.validate(); err != nil {
+		return nil, err
+	}
+
+	return program, nil
+}
+
+// validate checks that the statements and functions of the program refer to
+// its libfuncs and statements.
+func (p *SierraProgram) validate() error {
+	statementsLen := uint64(len(p.Statements))
+	for i := range p.Statements {
+		statement := &p.Statements[i]
+		if !statement.Return && statement.Libfunc >= uint64(len(p.Libfuncs)) {
+			return fmt.Errorf(
+				"%w: statement %d invokes the unknown libfunc %d",
+				ErrInvalidSierraProgram, i, statement.Libfunc,
+			)
+		}
+		for _, branch := range statement.Branches {
+			if branch.Target != nil && *branch.Target >= statementsLen {
+				return fmt.Errorf(
+					"%w: statement %d jumps to the unknown statement %d",
+					ErrInvalidSierraProgram, i, *branch.Target,
+				)
+			}
+		}
+	}
+	for i := range p.Functions {
+		if p.Functions[i].EntryPoint >= statementsLen {
+			return fmt.Errorf(
+				"%w: function %d starts at the unknown statement %d",
+				ErrInvalidSierraProgram, i, p.Functions[i].EntryPoint,
+			)
+		}
+	}
+
+	return nil
+}
+
+// sierraReader reads the words of a Sierra program sequentially.
+type sierraReader struct {
+	words []*felt.Felt
+	pos   int
+}
+
+func (r *sierraReader) read() (*felt.Felt, error) {
+	if r.pos >= len(r.words) {
+		return nil, fmt.Errorf("%w: unexpected end of the program", ErrInvalidSierraProgram)
+	}
+	word := r.words[r.pos]
+	r.pos++
+
+	return word, nil
+}
+
+// readUint reads a word fitting in a uint64.
+func (r *sierraReader) readUint() (uint64, error) {
+	word, err := r.read()
+	if err != nil {
+		return 0, err
+	}
+
+	return feltToUint(word)
+}
+
+// readLen reads a length, which can't exceed the number of words left.
+func (r *sierraReader) readLen() (int, error) {
+	length, err := r.readUint()
+	if err != nil {
+		return 0, err
+	}
+	if length > uint64(len(r.words)-r.pos) {
+		return 0, fmt.Errorf(
+			"%w: length %d exceeds the program length",
+			ErrInvalidSierraProgram, length,
+		)
+	}
+
+	return int(length), nil
+}
+
+// readUints reads a length-prefixed list of uint64.
+func (r *sierraReader) readUints() ([]uint64, error) {
+	length, err := r.readLen()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.readUintsN(length)
+}
+
+// readUintsN reads n uint64.
+func (r *sierraReader) readUintsN(n int) ([]uint64, error) {
+	values := make([]uint64, n)
+	for i := range values {
+		var err error
+		if values[i], err = r.readUint(); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// readVersion reads a version, as its major, minor and patch numbers.
+func (r *sierraReader) readVersion() (SierraVersion, error) {
+	values, err := r.readUintsN(sierraVersionLength)
+	if err != nil {
+		return SierraVersion{}, err
+	}
+
+	return SierraVersion{Major: values[0], Minor: values[1], Patch: values[2]}, nil
+}
+
+// readGenericID reads the generic ID of a type or libfunc: a short string, or
+// the Starknet Keccak of the longer IDs.
+func (r *sierraReader) readGenericID() (string, error) {
+	word, err := r.read()
+	if err != nil {
+		return "", err
+	}
+	if name, ok := sierraLongGenericIDs[*word]; ok {
+		return name, nil
+	}
+	bytes := word.Bytes()
+	name := strings.TrimLeft(string(bytes[:]), "\x00")
+	for _, char := range name {
+		if char < ' ' || char > '~' {
+			// an unknown long ID
+			return word.String(), nil
+		}
+	}
+
+	return name, nil
+}
+
+// readGenericArgs reads n generic args.
+func (r *sierraReader) readGenericArgs(n uint64) ([]SierraGenericArg, error) {
+	if n > uint64(len(r.words)-r.pos) {
+		return nil, fmt.Errorf("%w: %d generic args", ErrInvalidSierraProgram, n)
+	}
+	args := make([]SierraGenericArg, n)
+	for i := range args {
+		kind, err := r.readUint()
+		if err != nil {
+			return nil, err
+		}
+		word, err := r.read()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case kind == sierraNegativeValue:
+			args[i].Kind = SierraArgValue
+			args[i].Value = new(big.Int).Neg(word.BigInt(new(big.Int)))
+		case kind >= uint64(len(sierraArgKinds)):
+			return nil, fmt.Errorf("%w: generic arg kind %d", ErrInvalidSierraProgram, kind)
+		case sierraArgKinds[kind] == SierraArgUserType || sierraArgKinds[kind] == SierraArgValue:
+			args[i].Kind = sierraArgKinds[kind]
+			args[i].Value = word.BigInt(new(big.Int))
+		default:
+			args[i].Kind = sierraArgKinds[kind]
+			if args[i].ID, err = feltToUint(word); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return args, nil
+}
+
+// readTypes reads the type declarations. The number of generic args of a
+// type is followed by its declared info, if any, in the same word.
+func (r *sierraReader) readTypes() ([]SierraTypeDeclaration, error) {
+	length, err := r.readLen()
+	if err != nil {
+		return nil, err
+	}
+	types := make([]SierraTypeDeclaration, length)
+	for i := range types {
+		types[i].ID = uint64(i)
+		if types[i].GenericID, err = r.readGenericID(); err != nil {
+			return nil, err
+		}
+		word, err := r.read()
+		if err != nil {
+			return nil, err
+		}
+		value := word.BigInt(new(big.Int))
+		infoValue := new(big.Int).Rsh(value, sierraTypeInfoShift)
+		argsLen := value.Sub(value, new(big.Int).Lsh(infoValue, sierraTypeInfoShift))
+		if !infoValue.IsUint64() || !argsLen.IsUint64() {
+			return nil, fmt.Errorf("%w: type %d: invalid info %s", ErrInvalidSierraProgram, i, word)
+		}
+		if types[i].Info, err = typeInfo(infoValue.Uint64()); err != nil {
+			return nil, err
+		}
+		if types[i].Args, err = r.readGenericArgs(argsLen.Uint64()); err != nil {
+			return nil, err
+		}
+	}
+
+	return types, nil
+}
+
+// typeInfo returns the declared info of a type from its flags.
+func typeInfo(flags uint64) (*SierraTypeInfo, error) {
+	if flags == 0 {
+		return nil, nil
+	}
+	if flags&typeInfoDeclared == 0 ||
+		flags&^(typeInfoDeclared|typeInfoStorable|typeInfoDroppable|
+			typeInfoDuplicatable|typeInfoZeroSized) != 0 {
+		return nil, fmt.Errorf("%w: type info %#x", ErrInvalidSierraProgram, flags)
+	}
+
+	return &SierraTypeInfo{
+		Storable:     flags&typeInfoStorable != 0,
+		Droppable:    flags&typeInfoDroppable != 0,
+		Duplicatable: flags&typeInfoDuplicatable != 0,
+		ZeroSized:    flags&typeInfoZeroSized != 0,
+	}, nil
+}
+
+// readLibfuncs reads the libfunc declarations.
+func (r *sierraReader) readLibfuncs() ([]SierraLibfuncDeclaration, error) {
+	length, err := r.readLen()
+	if err != nil {
+		return nil, err
+	}
+	libfuncs := make([]SierraLibfuncDeclaration, length)
+	for i := range libfuncs {
+		libfuncs[i].ID = uint64(i)
+		if libfuncs[i].GenericID, err = r.readGenericID(); err != nil {
+			return nil, err
+		}
+		argsLen, err := r.readUint()
+		if err != nil {
+			return nil, err
+		}
+		if libfuncs[i].Args, err = r.readGenericArgs(argsLen); err != nil {
+			return nil, err
+		}
+	}
+
+	return libfuncs, nil
+}
+
+// readStatements reads the statements.
+func (r *sierraReader) readStatements() ([]SierraStatement, error) {
+	length, err := r.readLen()
+	if err != nil {
+		return nil, err
+	}
+	statements := make([]SierraStatement, length)
+	for i := range statements {
+		kind, err := r.readUint()
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case sierraInvocation:
+			err = r.readInvocation(&statements[i])
+		case sierraReturn:
+			statements[i].Return = true
+			statements[i].Args, err = r.readUints()
+		default:
+			err = fmt.Errorf("%w: statement %d of kind %d", ErrInvalidSierraProgram, i, kind)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return statements, nil
+}
+
+// readInvocation reads the invocation of a libfunc: the libfunc, its args and
+// its branches.
+func (r *sierraReader) readInvocation(statement *SierraStatement) error {
+	var err error
+	if statement.Libfunc, err = r.readUint(); err != nil {
+		return err
+	}
+	if statement.Args, err = r.readUints(); err != nil {
+		return err
+	}
+	length, err := r.readLen()
+	if err != nil {
+		return err
+	}
+	statement.Branches = make([]SierraBranch, length)
+	for i := range statement.Branches {
+		target, err := r.readUint()
+		if err != nil {
+			return err
+		}
+		if target != sierraFallthrough {
+			statement.Branches[i].Target = &target
+		}
+		if statement.Branches[i].Results, err = r.readUints(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readFunctions reads the functions. The variables of the parameters aren't
+// prefixed by their number, which is the number of parameter types.
+func (r *sierraReader) readFunctions() ([]SierraFunction, error) {
+	length, err := r.readLen()
+	if err != nil {
+		return nil, err
+	}
+	functions := make([]SierraFunction, length)
+	for i := range functions {
+		function := &functions[i]
+		function.ID = uint64(i)
+		if function.ParamTypes, err = r.readUints(); err != nil {
+			return nil, err
+		}
+		if function.RetTypes, err = r.readUints(); err != nil {
+			return nil, err
+		}
+		if function.Params, err = r.readUintsN(len(function.ParamTypes)); err != nil {
+			return nil, err
+		}
+		if function.EntryPoint, err = r.readUint(); err != nil {
+			return nil, err
+		}
+	}
+
+	return functions, nil
+}
+
+// feltToUint converts a felt to a uint64, failing if it doesn't fit.
+func feltToUint(word *felt.Felt) (uint64, error) {
+	value := word.BigInt(new(big.Int))
+	if !value.IsUint64() {
+		return 0, fmt.Errorf("%w: %s isn't a uint64", ErrInvalidSierraProgram, word)
+	}
+
+	return value.Uint64(), nil
+}
@@ -0,0 +1,288 @@
+package contracts
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeTestProgram decodes the Sierra program of a class file, with its
+// debug info if debug is set.
+func decodeTestProgram(t *testing.T, filePath string, debug bool) *SierraProgram {
+	t.Helper()
+
+	class := internalUtils.TestUnmarshalJSONFileToType[ContractClass](t, filePath)
+	var debugInfo *SierraDebugInfo
+	if debug {
+		var err error
+		debugInfo, err = UnmarshalSierraDebugInfo(filePath)
+		require.NoError(t, err)
+	}
+	program, err := class.DecodeSierraProgram(debugInfo)
+	require.NoError(t, err)
+
+	return program
+}
+
+// TestDecodeSierraProgram tests the decoding of the Sierra programs of
+// classes compiled by different compiler versions.
+func TestDecodeSierraProgram(t *testing.T) {
+	testSet := []struct {
+		FilePath           string
+		SierraVersion      string
+		CompilerVersion    string
+		Types              int
+		Libfuncs           int
+		Statements         int
+		Functions          int
+		ExpectedTypeNames  map[uint64]string
+		ExpectedEntryPoint string
+	}{
+		{
+			FilePath:        "./testData/hello_starknet_compiled.sierra.json",
+			SierraVersion:   "1.3.0",
+			CompilerVersion: "2.1.0",
+			Types:           31,
+			Libfuncs:        111,
+			Statements:      409,
+			Functions:       11,
+			// no debug names
+			ExpectedTypeNames: map[uint64]string{
+				3: "Array<felt252>",
+				4: "Snapshot<Array<felt252>>",
+			},
+			ExpectedEntryPoint: "[0]",
+		},
+		{
+			FilePath:        "./testData/test_contract.sierra.json",
+			SierraVersion:   "1.6.0",
+			CompilerVersion: "2.7.0",
+			Types:           25,
+			Libfuncs:        58,
+			Statements:      236,
+			Functions:       2,
+			ExpectedTypeNames: map[uint64]string{
+				2: "Unit",
+				4: "core::option::Option::<core::box::Box::<@core::felt252>>",
+			},
+			ExpectedEntryPoint: "test_contracts::contract::Contract::__wrapper__ContractImpl__get_value",
+		},
+		{
+			FilePath:        "../hash/testData/contracts_v2_ERC20.contract_class.json",
+			SierraVersion:   "1.5.0",
+			CompilerVersion: "2.6.3",
+			Types:           76,
+			Libfuncs:        188,
+			Statements:      4033,
+			Functions:       20,
+			ExpectedTypeNames: map[uint64]string{
+				5: "u128",
+				6: "core::integer::u256",
+			},
+			ExpectedEntryPoint: "contracts_v2::erc20::ERC20::__wrapper__constructor",
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.FilePath, func(t *testing.T) {
+			class := internalUtils.TestUnmarshalJSONFileToType[ContractClass](t, test.FilePath)
+			program := decodeTestProgram(t, test.FilePath, true)
+
+			assert.Equal(t, test.SierraVersion, program.SierraVersion.String())
+			assert.Equal(t, test.CompilerVersion, program.CompilerVersion.String())
+			assert.Len(t, program.Types, test.Types)
+			assert.Len(t, program.Libfuncs, test.Libfuncs)
+			assert.Len(t, program.Statements, test.Statements)
+			assert.Len(t, program.Functions, test.Functions)
+			for id, name := range test.ExpectedTypeNames {
+				assert.Equal(t, name, program.TypeName(id))
+			}
+
+			size := program.Size
+			assert.Equal(t, len(class.SierraProgram), size.Program)
+			assert.Equal(t, size.Program, size.Header+size.CodeTable+size.Packed)
+			assert.Equal(t, size.Words, size.Types+size.Libfuncs+size.Statements+size.Functions)
+			assert.Equal(t, len(class.ABI), size.ABI)
+
+			entryPoints := len(class.EntryPointsByType.Constructor) +
+				len(class.EntryPointsByType.External) + len(class.EntryPointsByType.L1Handler)
+			require.Len(t, program.EntryPoints, entryPoints)
+			entryPoint := program.EntryPoints[0]
+			assert.Equal(t, test.ExpectedEntryPoint, program.FunctionName(entryPoint.Function))
+			assert.Contains(t, entryPoint.Functions, entryPoint.Function)
+			assert.NotEmpty(t, entryPoint.Libfuncs)
+			assert.IsIncreasing(t, entryPoint.Libfuncs)
+		})
+	}
+
+	t.Run("without debug info", func(t *testing.T) {
+		program := decodeTestProgram(t, "./testData/test_contract.sierra.json", false)
+		assert.Equal(t, "Struct<ut@0x2ee1e2b1b89f8c495f200e4956278a4d47395fe262f27b52e5865c9524c08c3>",
+			program.TypeName(2))
+		assert.Equal(t, "store_temp<RangeCheck>", program.LibfuncName(4))
+		assert.Equal(t, "[1]", program.FunctionName(1))
+	})
+
+	t.Run("long generic IDs", func(t *testing.T) {
+		program := decodeTestProgram(t, "../hash/testData/contracts_v2_ERC20.contract_class.json", false)
+		var genericIDs []string
+		for _, libfunc := range program.Libfuncs {
+			genericIDs = append(genericIDs, libfunc.GenericID)
+		}
+		assert.Contains(t, genericIDs, "storage_address_from_base_and_offset")
+		assert.Contains(t, genericIDs, "contract_address_try_from_felt252")
+	})
+}
+
+// TestDecodeSierraProgram_Errors tests the decoding of invalid Sierra
+// programs.
+func TestDecodeSierraProgram_Errors(t *testing.T) {
+	class := internalUtils.TestUnmarshalJSONFileToType[ContractClass](
+		t, "./testData/hello_starknet_compiled.sierra.json",
+	)
+	program := class.SierraProgram
+
+	testSet := []struct {
+		Description   string
+		SierraProgram []*felt.Felt
+		EntryPoints   SierraEntryPointsByType
+		ExpectedError error
+	}{
+		{
+			Description:   "shorter than the header",
+			SierraProgram: program[:5],
+			EntryPoints:   class.EntryPointsByType,
+			ExpectedError: ErrInvalidSierraProgram,
+		},
+		{
+			Description: "Sierra 0.1.0",
+			SierraProgram: append(
+				[]*felt.Felt{new(felt.Felt).SetBytes([]byte("0.1.0"))},
+				program[1:]...,
+			),
+			EntryPoints:   class.EntryPointsByType,
+			ExpectedError: ErrUnsupportedSierraVersion,
+		},
+		{
+			Description:   "truncated",
+			SierraProgram: program[:len(program)-1],
+			EntryPoints:   class.EntryPointsByType,
+			ExpectedError: ErrInvalidSierraProgram,
+		},
+		{
+			Description:   "entry point of an unknown function",
+			SierraProgram: program,
+			EntryPoints: SierraEntryPointsByType{
+				External: []SierraEntryPoint{{FunctionIdx: 11, Selector: internalUtils.DeadBeef}},
+			},
+			ExpectedError: ErrInvalidSierraProgram,
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Description, func(t *testing.T) {
+			invalidClass := ContractClass{
+				SierraProgram:     test.SierraProgram,
+				EntryPointsByType: test.EntryPoints,
+			}
+			_, err := invalidClass.DecodeSierraProgram(nil)
+			require.ErrorIs(t, err, test.ExpectedError)
+		})
+	}
+}
+
+// TestDiffSierraPrograms tests the semantic difference between Sierra
+// programs.
+func TestDiffSierraPrograms(t *testing.T) {
+	helloStarknet := "../hash/testData/contracts_v2_HelloStarknet.contract_class.json"
+
+	t.Run("debug info", func(t *testing.T) {
+		diff := DiffSierraPrograms(
+			decodeTestProgram(t, helloStarknet, true),
+			decodeTestProgram(t, helloStarknet, false),
+		)
+		assert.True(t, diff.IsEmpty())
+	})
+
+	t.Run("changed entry point", func(t *testing.T) {
+		from := decodeTestProgram(t, helloStarknet, true)
+		to := decodeTestProgram(t, helloStarknet, true)
+		// replaces a libfunc invocation of the first entry point
+		entryPoint := to.EntryPoints[0]
+		statement := &to.Statements[to.Functions[entryPoint.Function].EntryPoint]
+		require.False(t, statement.Return)
+		statement.Libfunc = entryPoint.Libfuncs[len(entryPoint.Libfuncs)-1]
+
+		diff := DiffSierraPrograms(from, to)
+		assert.Equal(t, []*felt.Felt{entryPoint.Selector}, diff.ChangedEntryPoints)
+		assert.Empty(t, diff.AddedEntryPoints)
+		assert.Empty(t, diff.RemovedEntryPoints)
+		assert.Empty(t, diff.AddedLibfuncs)
+	})
+
+	t.Run("different classes", func(t *testing.T) {
+		from := decodeTestProgram(t, "./testData/hello_starknet_compiled.sierra.json", false)
+		to := decodeTestProgram(t, "./testData/test_contract.sierra.json", true)
+
+		diff := DiffSierraPrograms(from, to)
+		assert.False(t, diff.IsEmpty())
+		assert.Len(t, diff.AddedEntryPoints, 2)
+		assert.Len(t, diff.RemovedEntryPoints, 2)
+		assert.Contains(t, diff.AddedTypes, "i32")
+		assert.Contains(t, diff.AddedLibfuncs, "i32_try_from_felt252")
+		assert.Contains(t, diff.RemovedLibfuncs, "felt252_add")
+		assert.IsIncreasing(t, diff.AddedLibfuncs)
+	})
+}
+
+// TestDisallowedLibfuncs tests the check of the libfuncs of a program
+// against an allowed list.
+func TestDisallowedLibfuncs(t *testing.T) {
+	program := decodeTestProgram(t, "./testData/test_contract.sierra.json", true)
+
+	var allowed SierraAllowedLibfuncs
+	require.NoError(t, json.Unmarshal([]byte(`{"allowed_libfuncs": []}`), &allowed))
+	for _, libfunc := range program.Libfuncs {
+		if libfunc.GenericID != "i32_try_from_felt252" {
+			allowed.AllowedLibfuncs = append(allowed.AllowedLibfuncs, libfunc.GenericID)
+		}
+	}
+
+	disallowed := program.DisallowedLibfuncs(allowed.AllowedLibfuncs)
+	require.Len(t, disallowed, 1)
+	assert.Equal(t, "i32_try_from_felt252", disallowed[0].DebugName)
+
+	// the entry points using it
+	for _, entryPoint := range program.EntryPoints {
+		assert.Contains(t, entryPoint.Libfuncs, disallowed[0].ID)
+	}
+
+	assert.Empty(t, program.DisallowedLibfuncs(append(allowed.AllowedLibfuncs, "i32_try_from_felt252")))
+}
+
+// TestDeclareCodeSize tests the code size charged by the declare fees.
+func TestDeclareCodeSize(t *testing.T) {
+	program := decodeTestProgram(t, "./testData/hello_starknet_compiled.sierra.json", false)
+	casmClass, err := UnmarshalCasmClass("./testData/hello_starknet_compiled.casm.json")
+	require.NoError(t, err)
+
+	assert.Equal(t, (337+len(casmClass.ByteCode))*32+program.Size.ABI,
+		program.Size.DeclareCodeSize(casmClass))
+}
+
+// TestSierraDebugName_JSON tests the [id, name] form of the debug names.
+func TestSierraDebugName_JSON(t *testing.T) {
+	var name SierraDebugName
+	require.NoError(t, json.Unmarshal([]byte(`[3, "felt252"]`), &name))
+	assert.Equal(t, SierraDebugName{ID: 3, Name: "felt252"}, name)
+
+	data, err := json.Marshal(name)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[3, "felt252"]`, string(data))
+
+	require.Error(t, json.Unmarshal([]byte(`[3]`), &name))
+}
@@ -494,6 +494,14 @@ type Felt252DictEntryUpdate struct {
 	Value   ResOperand `json:"value"`
 }
 
+// The types of the ResOperand and B operands
+const (
+	operandBinOp       = "BinOp"
+	operandDeref       = "Deref"
+	operandDoubleDeref = "DoubleDeref"
+	operandImmediate   = "Immediate"
+)
+
 // Can be one of the following values:
 //   - BinOp
 //   - Deref
@@ -526,19 +534,19 @@ func (r *ResOperand) UnmarshalJSON(data []byte) error {
 	var err error
 
 	switch hintType {
-	case "BinOp":
+	case operandBinOp:
 		var binOp BinOp
 		err = json.Unmarshal(hintData, &binOp)
 		r.Data = binOp
-	case "Deref":
+	case operandDeref:
 		var deref Deref
 		err = json.Unmarshal(hintData, &deref)
 		r.Data = deref
-	case "DoubleDeref":
+	case operandDoubleDeref:
 		var doubleDeref DoubleDeref
 		err = json.Unmarshal(hintData, &doubleDeref)
 		r.Data = doubleDeref
-	case "Immediate":
+	case operandImmediate:
 		var immediate Immediate
 		err = json.Unmarshal(hintData, &immediate)
 		r.Data = immediate
@@ -656,13 +664,13 @@ func (b *B) UnmarshalJSON(data []byte) error {
 	}
 
 	switch hintType {
-	case "Deref":
+	case operandDeref:
 		var deref Deref
 		if err := json.Unmarshal(hintData, &deref); err != nil {
 			return err
 		}
 		b.Data = deref
-	case "Immediate":
+	case operandImmediate:
 		var immediate Immediate
 		if err := json.Unmarshal(hintData, &immediate); err != nil {
 			return err
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 
 	"github.com/NethermindEth/juno/core/felt"
@@ -131,6 +132,30 @@ func encodeProgram(content []byte) (string, error) {
 	return program, nil
 }
 
+// DecodeProgram decodes a base64 encoded and gzip compressed program, as found in
+// the `program` field of a DeprecatedContractClass, into its raw JSON representation.
+//
+// Parameters:
+//   - program: the encoded program
+//
+// Returns:
+//   - []byte: the JSON representation of the program
+//   - error: the error if any
+func DecodeProgram(program string) ([]byte, error) {
+	compressed, err := base64.StdEncoding.DecodeString(program)
+	if err != nil {
+		return nil, err
+	}
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+
+	return io.ReadAll(gzipReader)
+}
+
 type DeprecatedEntryPointsByType struct {
 	Constructor []DeprecatedCairoEntryPoint `json:"CONSTRUCTOR"`
 	External    []DeprecatedCairoEntryPoint `json:"EXTERNAL"`
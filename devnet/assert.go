@@ -0,0 +1,269 @@
+package devnet
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// AssertSucceeded checks that a transaction succeeded, reporting its revert
+// reason otherwise.
+//
+// Parameters:
+//   - t: the test
+//   - receipt: the receipt of the transaction
+//
+// Returns:
+//   - bool: whether the transaction succeeded
+func AssertSucceeded(t testing.TB, receipt *rpc.TransactionReceiptWithBlockInfo) bool {
+	t.Helper()
+
+	if receipt.ExecutionStatus != rpc.TxnExecutionStatusSUCCEEDED {
+		t.Errorf(
+			"transaction %s: expected %s, got %s: %s",
+			receipt.Hash,
+			rpc.TxnExecutionStatusSUCCEEDED,
+			receipt.ExecutionStatus,
+			receipt.RevertReason,
+		)
+
+		return false
+	}
+
+	return true
+}
+
+// AssertReverted checks that a transaction was reverted with a reason
+// matching a regular expression.
+//
+// Parameters:
+//   - t: the test
+//   - receipt: the receipt of the transaction
+//   - reasonPattern: the regular expression the revert reason must match, e.g.
+//     "Insufficient balance"
+//
+// Returns:
+//   - bool: whether the transaction was reverted with a matching reason
+func AssertReverted(
+	t testing.TB,
+	receipt *rpc.TransactionReceiptWithBlockInfo,
+	reasonPattern string,
+) bool {
+	t.Helper()
+
+	if receipt.ExecutionStatus != rpc.TxnExecutionStatusREVERTED {
+		t.Errorf(
+			"transaction %s: expected %s, got %s",
+			receipt.Hash,
+			rpc.TxnExecutionStatusREVERTED,
+			receipt.ExecutionStatus,
+		)
+
+		return false
+	}
+	reason, err := regexp.Compile(reasonPattern)
+	if err != nil {
+		t.Errorf("invalid revert reason pattern: %v", err)
+
+		return false
+	}
+	if !reason.MatchString(receipt.RevertReason) {
+		t.Errorf(
+			"transaction %s: the revert reason doesn't match %q:\n%s",
+			receipt.Hash,
+			reasonPattern,
+			receipt.RevertReason,
+		)
+
+		return false
+	}
+
+	return true
+}
+
+// AssertEvent checks that a transaction emitted an event with the given
+// fields, decoded with the ABI of the emitting contract. The event can have
+// other fields than the expected ones. The values must have the types of the
+// contracts.ParsedABI decoding, e.g. *big.Int for the integers. The *big.Int
+// are compared with Cmp and the *felt.Felt with Equal, recursing into the
+// slices, maps and structs, and the other values with reflect.DeepEqual.
+//
+// Parameters:
+//   - t: the test
+//   - receipt: the receipt of the transaction
+//   - abi: the ABI of the emitting contract
+//   - from: the address of the emitting contract
+//   - name: the name of the event, e.g. "Transfer"
+//   - fields: the expected values of the event fields, by name
+//
+// Returns:
+//   - *contracts.DecodedEvent: the first matching event, or nil if none matches
+func AssertEvent(
+	t testing.TB,
+	receipt *rpc.TransactionReceiptWithBlockInfo,
+	abi *contracts.ParsedABI,
+	from *felt.Felt,
+	name string,
+	fields map[string]any,
+) *contracts.DecodedEvent {
+	t.Helper()
+
+	var emitted []string
+	for _, event := range receipt.Events {
+		if !event.FromAddress.Equal(from) {
+			continue
+		}
+		decoded, err := abi.DecodeEvent(event.Keys, event.Data)
+		if err != nil {
+			continue
+		}
+		if decoded.Name != name && !strings.HasSuffix(decoded.Name, "::"+name) {
+			emitted = append(emitted, decoded.Name)
+
+			continue
+		}
+		if eventHasFields(decoded, fields) {
+			return decoded
+		}
+		emitted = append(emitted, formatEvent(decoded))
+	}
+
+	t.Errorf(
+		"transaction %s: no %s event with the fields %v emitted by %s, got: %v",
+		receipt.Hash,
+		name,
+		fields,
+		from,
+		emitted,
+	)
+
+	return nil
+}
+
+// eventHasFields returns whether the event has the given field values.
+func eventHasFields(event *contracts.DecodedEvent, fields map[string]any) bool {
+	for name, expected := range fields {
+		found := false
+		for _, field := range event.Fields {
+			if field.Name == name {
+				found = valuesEqual(expected, field.Value)
+
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// valuesEqual returns whether a decoded value equals the expected one. The
+// *big.Int and *felt.Felt are compared by value, since equal values can have
+// different internal representations, e.g. a decoded zero *big.Int.
+func valuesEqual(expected, actual any) bool {
+	switch expected := expected.(type) {
+	case *big.Int:
+		actual, ok := actual.(*big.Int)
+		if !ok || expected == nil || actual == nil {
+			return ok && expected == actual
+		}
+
+		return expected.Cmp(actual) == 0
+	case *felt.Felt:
+		actual, ok := actual.(*felt.Felt)
+		if !ok || expected == nil || actual == nil {
+			return ok && expected == actual
+		}
+
+		return expected.Equal(actual)
+	}
+
+	expectedValue := reflect.ValueOf(expected)
+	actualValue := reflect.ValueOf(actual)
+	if !expectedValue.IsValid() || !actualValue.IsValid() {
+		return reflect.DeepEqual(expected, actual)
+	}
+
+	switch expectedValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		return sequencesEqual(expectedValue, actualValue)
+	case reflect.Map:
+		return mapsEqual(expectedValue, actualValue)
+	case reflect.Struct:
+		return structsEqual(expectedValue, actualValue)
+	default:
+		return reflect.DeepEqual(expected, actual)
+	}
+}
+
+// sequencesEqual returns whether two slices or arrays have equal elements,
+// whatever their element types.
+func sequencesEqual(expected, actual reflect.Value) bool {
+	kind := actual.Kind()
+	if (kind != reflect.Slice && kind != reflect.Array) || expected.Len() != actual.Len() {
+		return false
+	}
+	for i := range expected.Len() {
+		if !valuesEqual(expected.Index(i).Interface(), actual.Index(i).Interface()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mapsEqual returns whether two maps with the same key type have equal values.
+func mapsEqual(expected, actual reflect.Value) bool {
+	if actual.Kind() != reflect.Map || expected.Type().Key() != actual.Type().Key() ||
+		expected.Len() != actual.Len() {
+		return false
+	}
+	iter := expected.MapRange()
+	for iter.Next() {
+		value := actual.MapIndex(iter.Key())
+		if !value.IsValid() || !valuesEqual(iter.Value().Interface(), value.Interface()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// structsEqual returns whether two structs of the same type have equal fields.
+// The structs with unexported fields are compared with reflect.DeepEqual.
+func structsEqual(expected, actual reflect.Value) bool {
+	if expected.Type() != actual.Type() {
+		return false
+	}
+	for i := range expected.NumField() {
+		if !expected.Type().Field(i).IsExported() {
+			return reflect.DeepEqual(expected.Interface(), actual.Interface())
+		}
+	}
+	for i := range expected.NumField() {
+		if !valuesEqual(expected.Field(i).Interface(), actual.Field(i).Interface()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// formatEvent formats a decoded event for the error messages.
+func formatEvent(event *contracts.DecodedEvent) string {
+	fields := make([]string, len(event.Fields))
+	for i, field := range event.Fields {
+		fields[i] = fmt.Sprintf("%s: %v", field.Name, field.Value)
+	}
+
+	return event.Name + "{" + strings.Join(fields, ", ") + "}"
+}
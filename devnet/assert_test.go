@@ -0,0 +1,207 @@
+package devnet
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/internal/tests"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errorRecorder is a testing.TB recording the reported errors.
+type errorRecorder struct {
+	testing.TB
+	errors []string
+}
+
+func (recorder *errorRecorder) Helper() {}
+
+func (recorder *errorRecorder) Errorf(format string, args ...any) {
+	recorder.errors = append(recorder.errors, fmt.Sprintf(format, args...))
+}
+
+// TestAssertions tests the receipt assertion helpers.
+func TestAssertions(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	token := internalUtils.TestHexToFelt(t, "0x1234")
+	abi := contracts.ParseABI(contracts.ABI{
+		&contracts.StructABIEntry{
+			Type: contracts.ABITypeStruct,
+			Name: "Uint256",
+			Size: 2,
+			Members: []contracts.Member{
+				{TypedParameter: contracts.TypedParameter{Name: "low", Type: "felt"}},
+				{TypedParameter: contracts.TypedParameter{Name: "high", Type: "felt"}, Offset: 1},
+			},
+		},
+		&contracts.EventABIEntry{
+			Type: contracts.ABITypeEvent,
+			Name: "Transfer",
+			Data: []contracts.TypedParameter{
+				{Name: "to", Type: "felt"},
+				{Name: "amount", Type: "Uint256"},
+			},
+		},
+	})
+	transfer := func(from *felt.Felt, amount uint64) rpc.Event {
+		return rpc.Event{
+			FromAddress: from,
+			EventContent: rpc.EventContent{
+				Keys: []*felt.Felt{internalUtils.GetSelectorFromNameFelt("Transfer")},
+				Data: []*felt.Felt{
+					internalUtils.DeadBeef,
+					new(felt.Felt).SetUint64(amount),
+					new(felt.Felt),
+				},
+			},
+		}
+	}
+
+	succeeded := &rpc.TransactionReceiptWithBlockInfo{
+		TransactionReceipt: rpc.TransactionReceipt{
+			Hash:            new(felt.Felt).SetUint64(1),
+			ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+			Events: []rpc.Event{
+				// another contract
+				transfer(internalUtils.DeadBeef, 10),
+				// not in the ABI
+				{
+					FromAddress:  token,
+					EventContent: rpc.EventContent{Keys: []*felt.Felt{new(felt.Felt)}},
+				},
+				transfer(token, 5),
+				transfer(token, 10),
+				transfer(token, 0),
+			},
+		},
+	}
+	reverted := &rpc.TransactionReceiptWithBlockInfo{
+		TransactionReceipt: rpc.TransactionReceipt{
+			Hash:            new(felt.Felt).SetUint64(2),
+			ExecutionStatus: rpc.TxnExecutionStatusREVERTED,
+			RevertReason:    "Error in the called contract: 'u256_sub Overflow'",
+		},
+	}
+
+	t.Run("succeeded", func(t *testing.T) {
+		recorder := &errorRecorder{TB: t}
+		assert.True(t, AssertSucceeded(recorder, succeeded))
+		assert.False(t, AssertSucceeded(recorder, reverted))
+		require.Len(t, recorder.errors, 1)
+		assert.Contains(t, recorder.errors[0], "u256_sub Overflow")
+	})
+
+	t.Run("reverted", func(t *testing.T) {
+		recorder := &errorRecorder{TB: t}
+		assert.True(t, AssertReverted(recorder, reverted, `u256_sub Overflow`))
+		assert.True(t, AssertReverted(recorder, reverted, `^Error in .*Overflow'$`))
+		assert.Empty(t, recorder.errors)
+
+		assert.False(t, AssertReverted(recorder, reverted, `Insufficient balance`))
+		assert.False(t, AssertReverted(recorder, reverted, `(`))
+		assert.False(t, AssertReverted(recorder, succeeded, `.*`))
+		assert.Len(t, recorder.errors, 3)
+	})
+
+	t.Run("event", func(t *testing.T) {
+		recorder := &errorRecorder{TB: t}
+		event := AssertEvent(recorder, succeeded, abi, token, "Transfer", map[string]any{
+			"to":     internalUtils.DeadBeef,
+			"amount": big.NewInt(10),
+		})
+		require.NotNil(t, event)
+		assert.Equal(t, big.NewInt(10), event.Fields[1].Value)
+
+		// any field
+		event = AssertEvent(recorder, succeeded, abi, token, "Transfer", nil)
+		require.NotNil(t, event)
+		assert.Equal(t, big.NewInt(5), event.Fields[1].Value)
+		assert.Empty(t, recorder.errors)
+
+		assert.Nil(t, AssertEvent(recorder, succeeded, abi, token, "Transfer", map[string]any{
+			"amount": big.NewInt(7),
+		}))
+		assert.Nil(t, AssertEvent(recorder, succeeded, abi, token, "Approval", nil))
+		assert.Nil(t, AssertEvent(recorder, succeeded, abi, token, "Transfer", map[string]any{
+			"spender": internalUtils.DeadBeef,
+		}))
+		require.Len(t, recorder.errors, 3)
+		assert.Contains(t, recorder.errors[0], "Transfer{to: 0xdeadbeef, amount: 5}")
+	})
+
+	t.Run("event with a zero field", func(t *testing.T) {
+		recorder := &errorRecorder{TB: t}
+		event := AssertEvent(recorder, succeeded, abi, token, "Transfer", map[string]any{
+			"to":     new(felt.Felt).SetBytes(internalUtils.DeadBeef.Marshal()),
+			"amount": big.NewInt(0),
+		})
+		require.NotNil(t, event)
+		assert.Empty(t, recorder.errors)
+	})
+}
+
+// TestValuesEqual tests the comparison of the decoded event values.
+func TestValuesEqual(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	// a zero decoded from a felt, with a non-nil empty abs
+	decodedZero := new(big.Int).SetBytes(new(felt.Felt).Marshal())
+
+	testSet := []struct {
+		Name     string
+		Expected any
+		Actual   any
+		Equal    bool
+	}{
+		{Name: "zero", Expected: big.NewInt(0), Actual: decodedZero, Equal: true},
+		{Name: "integers", Expected: big.NewInt(1), Actual: big.NewInt(2), Equal: false},
+		{Name: "nil integer", Expected: big.NewInt(0), Actual: (*big.Int)(nil), Equal: false},
+		{Name: "felts", Expected: new(felt.Felt), Actual: new(felt.Felt), Equal: true},
+		{Name: "felt and integer", Expected: new(felt.Felt), Actual: decodedZero, Equal: false},
+		{
+			Name:     "nested",
+			Expected: []any{map[string]any{"amount": big.NewInt(0)}},
+			Actual:   []any{map[string]any{"amount": decodedZero}},
+			Equal:    true,
+		},
+		{
+			Name:     "typed slice",
+			Expected: []*big.Int{big.NewInt(0), big.NewInt(1)},
+			Actual:   []any{decodedZero, big.NewInt(1)},
+			Equal:    true,
+		},
+		{
+			Name:     "missing key",
+			Expected: map[string]any{"amount": big.NewInt(0)},
+			Actual:   map[string]any{"value": decodedZero},
+			Equal:    false,
+		},
+		{
+			Name:     "enum",
+			Expected: contracts.ABIEnum{Variant: "Some", Value: big.NewInt(0)},
+			Actual:   contracts.ABIEnum{Variant: "Some", Value: decodedZero},
+			Equal:    true,
+		},
+		{
+			Name:     "enum variant",
+			Expected: contracts.ABIEnum{Variant: "Some", Value: big.NewInt(0)},
+			Actual:   contracts.ABIEnum{Variant: "None"},
+			Equal:    false,
+		},
+		{Name: "strings", Expected: "abc", Actual: "abc", Equal: true},
+		{Name: "lengths", Expected: []any{true}, Actual: []any{true, false}, Equal: false},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Equal, valuesEqual(test.Expected, test.Actual))
+		})
+	}
+}
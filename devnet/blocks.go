@@ -0,0 +1,131 @@
+package devnet
+
+import (
+	"context"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// SetTimeResponse is the response of the `devnet_setTime` JSON-RPC method.
+type SetTimeResponse struct {
+	BlockTimestamp uint64 `json:"block_timestamp"`
+	// The hash of the generated block. Nil if no block was generated.
+	BlockHash *felt.Felt `json:"block_hash,omitempty"`
+}
+
+// SetTime sets the timestamp of the next blocks, with the `devnet_setTime`
+// JSON-RPC method.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//   - timestamp: the timestamp, in seconds since the Unix epoch
+//   - generateBlock: whether to generate a block with the new timestamp right away
+//
+// Returns:
+//   - *SetTimeResponse: the new timestamp and the hash of the generated block, if any
+//   - error: an error if any
+func (devnet *DevNet) SetTime(
+	ctx context.Context,
+	timestamp uint64,
+	generateBlock bool,
+) (*SetTimeResponse, error) {
+	params := struct {
+		Time          uint64 `json:"time"`
+		GenerateBlock bool   `json:"generate_block"`
+	}{
+		Time:          timestamp,
+		GenerateBlock: generateBlock,
+	}
+
+	var resp SetTimeResponse
+	if err := devnet.call(ctx, &resp, "setTime", params); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// IncreaseTimeResponse is the response of the `devnet_increaseTime` JSON-RPC
+// method.
+type IncreaseTimeResponse struct {
+	TimestampIncreasedBy uint64 `json:"timestamp_increased_by"`
+	// The hash of the block generated with the new timestamp
+	BlockHash *felt.Felt `json:"block_hash"`
+}
+
+// IncreaseTime moves the time of the devnet forward and generates a block,
+// with the `devnet_increaseTime` JSON-RPC method.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//   - seconds: the number of seconds to move the time forward by
+//
+// Returns:
+//   - *IncreaseTimeResponse: the increase and the hash of the generated block
+//   - error: an error if any
+func (devnet *DevNet) IncreaseTime(
+	ctx context.Context,
+	seconds uint64,
+) (*IncreaseTimeResponse, error) {
+	params := struct {
+		Time uint64 `json:"time"`
+	}{Time: seconds}
+
+	var resp IncreaseTimeResponse
+	if err := devnet.call(ctx, &resp, "increaseTime", params); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// CreateBlock creates a block with the pending transactions, with the
+// `devnet_createBlock` JSON-RPC method. It is useful when the devnet is
+// started with a block generation interval or on demand.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//
+// Returns:
+//   - *felt.Felt: the hash of the created block
+//   - error: an error if any
+func (devnet *DevNet) CreateBlock(ctx context.Context) (*felt.Felt, error) {
+	var resp struct {
+		BlockHash *felt.Felt `json:"block_hash"`
+	}
+	if err := devnet.call(ctx, &resp, "createBlock", nil); err != nil {
+		return nil, err
+	}
+
+	return resp.BlockHash, nil
+}
+
+// AbortBlocks aborts the blocks from the given block to the latest one, with
+// the `devnet_abortBlocks` JSON-RPC method. The transactions of the aborted
+// blocks are reverted, e.g. to simulate a reorg.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//   - startingBlockID: the oldest block to abort
+//
+// Returns:
+//   - []*felt.Felt: the hashes of the aborted blocks
+//   - error: an error if any
+func (devnet *DevNet) AbortBlocks(
+	ctx context.Context,
+	startingBlockID rpc.BlockID,
+) ([]*felt.Felt, error) {
+	params := struct {
+		StartingBlockID rpc.BlockID `json:"starting_block_id"`
+	}{StartingBlockID: startingBlockID}
+
+	var resp struct {
+		Aborted []*felt.Felt `json:"aborted"`
+	}
+	if err := devnet.call(ctx, &resp, "abortBlocks", params); err != nil {
+		return nil, err
+	}
+
+	return resp.Aborted, nil
+}
@@ -0,0 +1,64 @@
+package devnet
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Config is the configuration the devnet was started with, as returned by
+// the `devnet_getConfig` JSON-RPC method.
+type Config struct {
+	Seed                              uint64          `json:"seed"`
+	TotalAccounts                     uint            `json:"total_accounts"`
+	AccountContractClassHash          string          `json:"account_contract_class_hash"`
+	PredeployedAccountsInitialBalance string          `json:"predeployed_accounts_initial_balance"`
+	StartTime                         *uint64         `json:"start_time"`
+	ChainID                           string          `json:"chain_id"`
+	DumpOn                            *string         `json:"dump_on"`
+	DumpPath                          *string         `json:"dump_path"`
+	BlockGenerationOn                 json.RawMessage `json:"block_generation_on"`
+	LiteMode                          bool            `json:"lite_mode"`
+	StateArchive                      string          `json:"state_archive"`
+	ForkConfig                        ForkConfig      `json:"fork_config"`
+	ServerConfig                      ServerConfig    `json:"server_config"`
+	// The gas prices of the blocks, in WEI and FRI
+	L1GasPriceWei     uint64 `json:"l1_gas_price_wei"`
+	L1GasPriceFri     uint64 `json:"l1_gas_price_fri"`
+	L1DataGasPriceWei uint64 `json:"l1_data_gas_price_wei"`
+	L1DataGasPriceFri uint64 `json:"l1_data_gas_price_fri"`
+	L2GasPriceWei     uint64 `json:"l2_gas_price_wei"`
+	L2GasPriceFri     uint64 `json:"l2_gas_price_fri"`
+}
+
+// ForkConfig is the configuration of the forked network, if any.
+type ForkConfig struct {
+	URL         *string `json:"url"`
+	BlockNumber *uint64 `json:"block_number"`
+}
+
+// ServerConfig is the configuration of the devnet server.
+type ServerConfig struct {
+	Host    string `json:"host"`
+	Port    uint16 `json:"port"`
+	Timeout uint16 `json:"timeout"`
+	// The JSON-RPC methods that are disabled, e.g. with the `--restrictive-mode` flag
+	RestrictedMethods []string `json:"restricted_methods"`
+}
+
+// Config returns the configuration of the devnet, with the `devnet_getConfig`
+// JSON-RPC method.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//
+// Returns:
+//   - *Config: the configuration of the devnet
+//   - error: an error if any
+func (devnet *DevNet) Config(ctx context.Context) (*Config, error) {
+	var config Config
+	if err := devnet.call(ctx, &config, "getConfig", nil); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
@@ -15,6 +16,10 @@ import (
 
 type DevNet struct {
 	baseURL string
+
+	// the JSON-RPC client of the `devnet_*` methods, dialed on first use
+	mu sync.Mutex
+	c  callCloser
 }
 
 type TestAccount struct {
@@ -0,0 +1,69 @@
+package devnet
+
+import (
+	"context"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// ImpersonateAccount allows sending transactions from an account of the
+// forked network without its private key, with the
+// `devnet_impersonateAccount` JSON-RPC method. The signatures of the
+// account transactions aren't validated until StopImpersonateAccount is
+// called. Only available when the devnet forks another network.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//   - address: the address of the account
+//
+// Returns:
+//   - error: an error if any
+func (devnet *DevNet) ImpersonateAccount(ctx context.Context, address *felt.Felt) error {
+	params := struct {
+		AccountAddress *felt.Felt `json:"account_address"`
+	}{AccountAddress: address}
+
+	return devnet.call(ctx, nil, "impersonateAccount", params)
+}
+
+// StopImpersonateAccount stops impersonating an account, with the
+// `devnet_stopImpersonateAccount` JSON-RPC method.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//   - address: the address of the account
+//
+// Returns:
+//   - error: an error if any
+func (devnet *DevNet) StopImpersonateAccount(ctx context.Context, address *felt.Felt) error {
+	params := struct {
+		AccountAddress *felt.Felt `json:"account_address"`
+	}{AccountAddress: address}
+
+	return devnet.call(ctx, nil, "stopImpersonateAccount", params)
+}
+
+// AutoImpersonate impersonates every account of the forked network, with the
+// `devnet_autoImpersonate` JSON-RPC method.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//
+// Returns:
+//   - error: an error if any
+func (devnet *DevNet) AutoImpersonate(ctx context.Context) error {
+	return devnet.call(ctx, nil, "autoImpersonate", nil)
+}
+
+// StopAutoImpersonate stops impersonating every account, with the
+// `devnet_stopAutoImpersonate` JSON-RPC method. The accounts impersonated
+// with ImpersonateAccount are still impersonated.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//
+// Returns:
+//   - error: an error if any
+func (devnet *DevNet) StopAutoImpersonate(ctx context.Context) error {
+	return devnet.call(ctx, nil, "stopAutoImpersonate", nil)
+}
@@ -0,0 +1,67 @@
+package devnet
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Isolate snapshots the devnet state and restores it when the test and its
+// subtests complete, so the transactions of the test, and the account nonces
+// they increment, don't leak into the other tests. The devnet must be
+// launched with Options.DumpOn set to DumpOnRequest. The tests isolated on the
+// same devnet must not run in parallel.
+//
+// The accounts don't cache their nonce, it is fetched from the provider for
+// each transaction, so they are usable as is once the state is restored.
+//
+// Parameters:
+//   - t: the test to isolate
+func (instance *Instance) Isolate(t testing.TB) {
+	t.Helper()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := instance.DumpToFile(ctx, path); err != nil {
+		t.Fatalf(
+			"failed to dump the devnet state, is the devnet launched with DumpOn: %q? %v",
+			DumpOnRequest,
+			err,
+		)
+	}
+
+	t.Cleanup(func() {
+		// no snapshot file is written for the initial state
+		var err error
+		if _, statErr := os.Stat(path); errors.Is(statErr, fs.ErrNotExist) {
+			err = instance.Restart(ctx)
+		} else {
+			err = instance.Load(ctx, path)
+		}
+		if err != nil {
+			t.Errorf("failed to restore the devnet state: %v", err)
+		}
+	})
+}
+
+// Run runs f as a subtest of t, isolated with the Isolate method.
+//
+// Parameters:
+//   - t: the parent test
+//   - name: the name of the subtest
+//   - f: the subtest
+//
+// Returns:
+//   - bool: whether the subtest succeeded
+func (instance *Instance) Run(t *testing.T, name string, f func(t *testing.T)) bool {
+	t.Helper()
+
+	return t.Run(name, func(t *testing.T) {
+		t.Helper()
+		instance.Isolate(t)
+		f(t)
+	})
+}
@@ -0,0 +1,60 @@
+package devnet
+
+import (
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInstance_Run tests that the state changes of the isolated subtests are
+// reverted, with the fake starknet-devnet process.
+func TestInstance_Run(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	instance := Start(t, &Options{
+		BinaryPath: os.Args[0],
+		Args:       fakeDevnetArgs,
+		DumpOn:     DumpOnRequest,
+	})
+	blockNumber := func(t *testing.T) uint64 {
+		t.Helper()
+		number, err := instance.Provider.BlockNumber(t.Context())
+		require.NoError(t, err)
+
+		return number
+	}
+
+	// from the initial state
+	instance.Run(t, "initial state", func(t *testing.T) {
+		_, err := instance.CreateBlock(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), blockNumber(t))
+	})
+	assert.Equal(t, uint64(0), blockNumber(t))
+
+	_, err := instance.CreateBlock(t.Context())
+	require.NoError(t, err)
+
+	for _, name := range []string{"first", "second"} {
+		instance.Run(t, name, func(t *testing.T) {
+			assert.Equal(t, uint64(1), blockNumber(t))
+			for range 2 {
+				_, err := instance.CreateBlock(t.Context())
+				require.NoError(t, err)
+			}
+			assert.Equal(t, uint64(3), blockNumber(t))
+
+			// nested isolation
+			instance.Run(t, "nested", func(t *testing.T) {
+				_, err := instance.CreateBlock(t.Context())
+				require.NoError(t, err)
+				assert.Equal(t, uint64(4), blockNumber(t))
+			})
+			assert.Equal(t, uint64(3), blockNumber(t))
+		})
+	}
+	assert.Equal(t, uint64(1), blockNumber(t))
+}
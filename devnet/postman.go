@@ -0,0 +1,143 @@
+package devnet
+
+import (
+	"context"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// PostmanLoad connects the devnet to an L1 node, e.g. anvil, with the
+// `devnet_postmanLoad` JSON-RPC method. The L1<>L2 messaging contract is
+// deployed on the L1 node, unless the address of an existing one is given.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//   - networkURL: the URL of the L1 node
+//   - messagingContract: the address of an existing L1 messaging contract, or an
+//     empty string to deploy a new one
+//
+// Returns:
+//   - string: the address of the L1 messaging contract
+//   - error: an error if any
+func (devnet *DevNet) PostmanLoad(
+	ctx context.Context,
+	networkURL string,
+	messagingContract string,
+) (string, error) {
+	params := struct {
+		NetworkURL        string `json:"network_url"`
+		MessagingContract string `json:"messaging_contract_address,omitempty"`
+	}{
+		NetworkURL:        networkURL,
+		MessagingContract: messagingContract,
+	}
+
+	var resp struct {
+		MessagingContract string `json:"messaging_contract_address"`
+	}
+	if err := devnet.call(ctx, &resp, "postmanLoad", params); err != nil {
+		return "", err
+	}
+
+	return resp.MessagingContract, nil
+}
+
+// MessageToL2 is an L1->L2 message, as handled by the devnet postman.
+type MessageToL2 struct {
+	// The address of the L1 contract sending the message
+	L1ContractAddress string `json:"l1_contract_address"`
+	// The target L2 contract
+	L2ContractAddress *felt.Felt `json:"l2_contract_address"`
+	// The selector of the l1_handler in the target contract
+	EntryPointSelector *felt.Felt   `json:"entry_point_selector"`
+	Payload            []*felt.Felt `json:"payload"`
+	// The fee paid on L1 for the message, in WEI
+	PaidFeeOnL1 *felt.Felt `json:"paid_fee_on_l1"`
+	Nonce       *felt.Felt `json:"nonce"`
+}
+
+// FlushResponse is the response of the `devnet_postmanFlush` JSON-RPC method.
+type FlushResponse struct {
+	// The L2->L1 messages sent to the L1 node
+	MessagesToL1 []rpc.MsgToL1 `json:"messages_to_l1"`
+	// The L1->L2 messages fetched from the L1 node
+	MessagesToL2 []MessageToL2 `json:"messages_to_l2"`
+	// The hashes of the L1 handler transactions of the L1->L2 messages
+	GeneratedL2Transactions []*felt.Felt `json:"generated_l2_transactions"`
+	// The L1 node URL, or "dry run" if the messages weren't sent
+	L1Provider string `json:"l1_provider"`
+}
+
+// PostmanFlush exchanges the pending messages between the devnet and the L1
+// node loaded with PostmanLoad, with the `devnet_postmanFlush` JSON-RPC
+// method. The L1->L2 messages are executed as L1 handler transactions.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//   - dryRun: whether to only return the pending messages, without sending
+//     them, to check them without an L1 node
+//
+// Returns:
+//   - *FlushResponse: the flushed messages
+//   - error: an error if any
+func (devnet *DevNet) PostmanFlush(ctx context.Context, dryRun bool) (*FlushResponse, error) {
+	params := struct {
+		DryRun bool `json:"dry_run"`
+	}{DryRun: dryRun}
+
+	var resp FlushResponse
+	if err := devnet.call(ctx, &resp, "postmanFlush", params); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// PostmanSendMessageToL2 executes an L1->L2 message without an L1 node, with
+// the `devnet_postmanSendMessageToL2` JSON-RPC method.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//   - message: the message to execute
+//
+// Returns:
+//   - *felt.Felt: the hash of the L1 handler transaction
+//   - error: an error if any
+func (devnet *DevNet) PostmanSendMessageToL2(
+	ctx context.Context,
+	message *MessageToL2,
+) (*felt.Felt, error) {
+	var resp struct {
+		TransactionHash *felt.Felt `json:"transaction_hash"`
+	}
+	if err := devnet.call(ctx, &resp, "postmanSendMessageToL2", message); err != nil {
+		return nil, err
+	}
+
+	return resp.TransactionHash, nil
+}
+
+// PostmanConsumeMessageFromL2 consumes an L2->L1 message without an L1 node,
+// with the `devnet_postmanConsumeMessageFromL2` JSON-RPC method.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//   - message: the message to consume
+//
+// Returns:
+//   - *felt.Felt: the hash of the consumed message
+//   - error: an error if any
+func (devnet *DevNet) PostmanConsumeMessageFromL2(
+	ctx context.Context,
+	message *rpc.MsgToL1,
+) (*felt.Felt, error) {
+	var resp struct {
+		MessageHash *felt.Felt `json:"message_hash"`
+	}
+	if err := devnet.call(ctx, &resp, "postmanConsumeMessageFromL2", message); err != nil {
+		return nil, err
+	}
+
+	return resp.MessageHash, nil
+}
@@ -0,0 +1,327 @@
+package devnet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultStartTimeout is the default time to wait for a launched devnet to be alive.
+const DefaultStartTimeout = 30 * time.Second
+
+// maxLaunchAttempts is the number of ports Launch tries, when the free port it
+// picks is taken by another process before the devnet binds it.
+const maxLaunchAttempts = 5
+
+// BinaryEnv is the environment variable that can hold the path of the
+// starknet-devnet binary.
+const BinaryEnv = "STARKNET_DEVNET"
+
+var (
+	// ErrBinaryNotFound is returned when the starknet-devnet binary can't be located.
+	ErrBinaryNotFound = errors.New("starknet-devnet binary not found")
+	// ErrDevnetExited is returned when the devnet process exits before being alive.
+	ErrDevnetExited = errors.New("devnet process exited")
+	// ErrStartTimeout is returned when the devnet isn't alive within the start timeout.
+	ErrStartTimeout = errors.New("timed out waiting for the devnet to be alive")
+)
+
+// DumpMode is the mode of the `--dump-on` starknet-devnet flag.
+type DumpMode string
+
+// The dump modes. DumpOnExit and DumpOnBlock also require the `--dump-path`
+// flag, that can be passed with Options.Args.
+const (
+	// DumpOnRequest allows dumping the state with the `devnet_dump` JSON-RPC method
+	DumpOnRequest DumpMode = "request"
+	// DumpOnExit dumps the state to the `--dump-path` file when the devnet exits
+	DumpOnExit DumpMode = "exit"
+	// DumpOnBlock dumps the state to the `--dump-path` file on each new block
+	DumpOnBlock DumpMode = "block"
+)
+
+// Options are the options of a launched devnet. The zero value launches a
+// devnet with the starknet-devnet defaults.
+type Options struct {
+	// The path of the starknet-devnet binary. If empty, the binary is looked up in
+	// the BinaryEnv environment variable, then in the PATH, then in `~/.cargo/bin`.
+	BinaryPath string
+	// The seed of the predeployed accounts. If nil, a random seed is used.
+	Seed *uint32
+	// The number of predeployed accounts. If zero, the starknet-devnet default is used.
+	Accounts int
+	// The initial balance of the predeployed accounts, in WEI and FRI
+	InitialBalance *big.Int
+	// The URL of the network to fork, if any
+	ForkNetwork string
+	// The block of the forked network to fork from. If zero, the latest block is used.
+	ForkBlock uint64
+	// When the devnet state can be dumped, see DumpOnRequest. Required by the
+	// Instance.Isolate and Instance.Run methods.
+	DumpOn DumpMode
+	// Additional arguments, passed before the arguments set from the options
+	Args []string
+	// The time to wait for the devnet to be alive. Defaults to DefaultStartTimeout.
+	StartTimeout time.Duration
+}
+
+// Process is a starknet-devnet process launched by Launch.
+type Process struct {
+	*DevNet
+	// The base URL of the devnet, e.g. "http://127.0.0.1:5050"
+	URL string
+
+	cmd    *exec.Cmd
+	output *syncBuffer
+	// closed when the process exits
+	exited  chan struct{}
+	waitErr error
+	stop    sync.Once
+}
+
+// Launch starts a starknet-devnet process listening on a free local port,
+// and waits until it is alive. Several processes can run in parallel, each
+// one with its own state. If the port is taken by another process before the
+// devnet binds it, the devnet is launched again on another port.
+//
+// Parameters:
+//   - ctx: the context.Context used to wait for the devnet
+//   - opts: the devnet options, or nil for the defaults
+//
+// Returns:
+//   - *Process: the alive devnet process, to be stopped with its Stop method
+//   - error: ErrBinaryNotFound, ErrDevnetExited, ErrStartTimeout or an error
+//     starting the process
+func Launch(ctx context.Context, opts *Options) (*Process, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	binary, err := findBinary(opts.BinaryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		process, err := launch(ctx, binary, opts)
+		if err == nil || attempt == maxLaunchAttempts || !isBindError(err) {
+			return process, err
+		}
+	}
+}
+
+// launch starts a devnet process on a free local port, and waits until it is
+// alive.
+func launch(ctx context.Context, binary string, opts *Options) (*Process, error) {
+	port, err := freePort()
+	if err != nil {
+		return nil, err
+	}
+
+	output := &syncBuffer{}
+	//nolint:gosec // the binary is chosen by the caller
+	cmd := exec.Command(binary, launchArgs(opts, port)...)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", binary, err)
+	}
+
+	url := fmt.Sprintf("http://127.0.0.1:%d", port)
+	process := &Process{
+		DevNet: NewDevNet(url),
+		URL:    url,
+		cmd:    cmd,
+		output: output,
+		exited: make(chan struct{}),
+	}
+	go func() {
+		process.waitErr = cmd.Wait()
+		close(process.exited)
+	}()
+
+	timeout := opts.StartTimeout
+	if timeout == 0 {
+		timeout = DefaultStartTimeout
+	}
+	if err := process.waitAlive(ctx, timeout); err != nil {
+		_ = process.Stop()
+
+		return nil, err
+	}
+
+	return process, nil
+}
+
+// launchArgs returns the starknet-devnet arguments of the options.
+func launchArgs(opts *Options, port int) []string {
+	args := append([]string{}, opts.Args...)
+	args = append(args, "--host", "127.0.0.1", "--port", strconv.Itoa(port))
+	if opts.Seed != nil {
+		args = append(args, "--seed", strconv.FormatUint(uint64(*opts.Seed), 10))
+	}
+	if opts.Accounts > 0 {
+		args = append(args, "--accounts", strconv.Itoa(opts.Accounts))
+	}
+	if opts.InitialBalance != nil {
+		args = append(args, "--initial-balance", opts.InitialBalance.String())
+	}
+	if opts.ForkNetwork != "" {
+		args = append(args, "--fork-network", opts.ForkNetwork)
+		if opts.ForkBlock != 0 {
+			args = append(args, "--fork-block", strconv.FormatUint(opts.ForkBlock, 10))
+		}
+	}
+
+	if opts.DumpOn != "" {
+		args = append(args, "--dump-on", string(opts.DumpOn))
+	}
+
+	return args
+}
+
+// isBindError returns true if the devnet exited because its port was already
+// in use.
+func isBindError(err error) bool {
+	return errors.Is(err, ErrDevnetExited) &&
+		strings.Contains(strings.ToLower(err.Error()), "address already in use")
+}
+
+// waitAlive polls the devnet until it is alive, the process exits or the
+// timeout expires.
+func (process *Process) waitAlive(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	const pollInterval = 100 * time.Millisecond
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if process.IsAlive() {
+			return nil
+		}
+
+		select {
+		case <-process.exited:
+			return fmt.Errorf(
+				"%w: %w, output:\n%s",
+				ErrDevnetExited,
+				process.waitErr,
+				process.output.String(),
+			)
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf(
+					"%w after %s, output:\n%s",
+					ErrStartTimeout,
+					timeout,
+					process.output.String(),
+				)
+			}
+
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Output returns the output of the devnet process so far.
+func (process *Process) Output() string {
+	return process.output.String()
+}
+
+// Stop kills the devnet process and waits for it to exit. It can be called
+// several times.
+//
+// Returns:
+//   - error: an error if the process can't be killed
+func (process *Process) Stop() error {
+	var err error
+	process.stop.Do(func() {
+		select {
+		case <-process.exited:
+			return
+		default:
+		}
+		if killErr := process.cmd.Process.Kill(); killErr != nil {
+			err = killErr
+
+			return
+		}
+		<-process.exited
+	})
+
+	return err
+}
+
+// findBinary returns the path of the starknet-devnet binary.
+func findBinary(path string) (string, error) {
+	if path == "" {
+		path = os.Getenv(BinaryEnv)
+	}
+	if path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("%w: %w", ErrBinaryNotFound, err)
+		}
+
+		return path, nil
+	}
+
+	if path, err := exec.LookPath("starknet-devnet"); err == nil {
+		return path, nil
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		path := filepath.Join(home, ".cargo", "bin", "starknet-devnet")
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf(
+		"%w: install it with 'cargo install starknet-devnet' or set the %s environment variable",
+		ErrBinaryNotFound,
+		BinaryEnv,
+	)
+}
+
+// freePort returns a free local TCP port.
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use, holding the output of
+// the devnet process.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
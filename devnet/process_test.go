@@ -0,0 +1,223 @@
+package devnet
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/client"
+	"github.com/NethermindEth/starknet.go/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDevnetArgs make the test binary act as a fake starknet-devnet process,
+// served by TestFakeDevnetProcess.
+var fakeDevnetArgs = []string{"-test.run=^TestFakeDevnetProcess$", "--", "--fake-devnet"}
+
+// fakeNode serves the JSON-RPC methods used by Start, and the methods
+// changing its state, its number of blocks.
+type fakeNode struct {
+	seed     uint64
+	accounts int
+	dumpOn   string
+	blocks   uint64
+}
+
+type pathParams struct {
+	Path string `json:"path"`
+}
+
+func (node *fakeNode) SpecVersion() string {
+	return "0.10.0"
+}
+
+func (node *fakeNode) ChainId() string { //nolint:revive,staticcheck // the method name
+	return "0x534e5f5345504f4c4941"
+}
+
+func (node *fakeNode) BlockNumber() uint64 {
+	return node.blocks
+}
+
+func (node *fakeNode) CreateBlock() map[string]*felt.Felt {
+	node.blocks++
+
+	return map[string]*felt.Felt{"block_hash": new(felt.Felt).SetUint64(node.blocks)}
+}
+
+func (node *fakeNode) Dump(params pathParams) error {
+	if node.dumpOn != string(DumpOnRequest) {
+		return errors.New("please provide --dump-on mode on startup")
+	}
+	// like starknet-devnet, nothing is dumped without any event
+	if node.blocks == 0 {
+		return nil
+	}
+
+	return os.WriteFile(params.Path, []byte(strconv.FormatUint(node.blocks, 10)), 0o600)
+}
+
+func (node *fakeNode) Load(params pathParams) error {
+	content, err := os.ReadFile(params.Path)
+	if err != nil {
+		return err
+	}
+	node.blocks, err = strconv.ParseUint(string(content), 10, 64)
+
+	return err
+}
+
+func (node *fakeNode) Restart() {
+	node.blocks = 0
+}
+
+func (node *fakeNode) GetPredeployedAccounts() []TestAccount {
+	accounts := make([]TestAccount, node.accounts)
+	for i := range accounts {
+		accounts[i] = TestAccount{
+			PrivateKey: "0x1",
+			PublicKey:  "0x2",
+			Address:    fmt.Sprintf("%#x", node.seed*100+uint64(i)),
+		}
+	}
+
+	return accounts
+}
+
+// TestFakeDevnetProcess is a fake starknet-devnet process, run by the other
+// tests with the fakeDevnetArgs. It serves until it is killed.
+func TestFakeDevnetProcess(t *testing.T) {
+	args := flag.Args()
+	if len(args) == 0 || args[0] != "--fake-devnet" {
+		t.Skip("only run as a fake starknet-devnet process")
+	}
+
+	flags := flag.NewFlagSet("starknet-devnet", flag.ContinueOnError)
+	flags.Bool("fake-devnet", true, "")
+	fail := flags.Bool("fail", false, "exit right away")
+	bindErrors := flags.String("bind-errors", "", "fail to bind while the file is not empty")
+	host := flags.String("host", "", "")
+	port := flags.Int("port", 0, "")
+	seed := flags.Uint64("seed", 0, "")
+	accounts := flags.Int("accounts", 10, "")
+	dumpOn := flags.String("dump-on", "", "")
+	require.NoError(t, flags.Parse(args))
+	if *fail {
+		fmt.Println("invalid configuration")
+		os.Exit(1)
+	}
+	if *bindErrors != "" {
+		// the file holds a character per remaining bind error
+		content, err := os.ReadFile(*bindErrors)
+		require.NoError(t, err)
+		if len(content) > 0 {
+			require.NoError(t, os.WriteFile(*bindErrors, content[1:], 0o600))
+			fmt.Println("Error: Address already in use (os error 98)")
+			os.Exit(1)
+		}
+	}
+
+	node := &fakeNode{seed: *seed, accounts: *accounts, dumpOn: *dumpOn}
+	rpcServer := client.NewServer()
+	require.NoError(t, rpcServer.RegisterName("starknet", node))
+	require.NoError(t, rpcServer.RegisterName("devnet", node))
+	mux := http.NewServeMux()
+	mux.Handle("/rpc", rpcServer)
+	mux.HandleFunc("/is_alive", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(*host, strconv.Itoa(*port)))
+	require.NoError(t, err)
+	//nolint:gosec // only used in tests
+	require.NoError(t, http.Serve(listener, mux))
+}
+
+// TestStart tests launching devnets with the fake starknet-devnet process.
+func TestStart(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	t.Run("parallel instances", func(t *testing.T) {
+		for _, seed := range []uint32{1, 2} {
+			t.Run(fmt.Sprintf("seed %d", seed), func(t *testing.T) {
+				t.Parallel()
+
+				instance := Start(t, &Options{
+					BinaryPath: os.Args[0],
+					Args:       fakeDevnetArgs,
+					Seed:       &seed,
+					Accounts:   3,
+				})
+				assert.True(t, instance.IsAlive())
+				require.Len(t, instance.Accounts, 3)
+				assert.Equal(
+					t,
+					fmt.Sprintf("%#x", uint64(seed)*100+2),
+					instance.Accounts[2].Address.String(),
+				)
+				assert.Equal(
+					t,
+					new(felt.Felt).SetBytes([]byte("SN_SEPOLIA")),
+					instance.Accounts[0].ChainID,
+				)
+				chainID, err := instance.Provider.ChainID(t.Context())
+				require.NoError(t, err)
+				assert.Equal(t, "SN_SEPOLIA", chainID)
+			})
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		process, err := Launch(t.Context(), &Options{BinaryPath: os.Args[0], Args: fakeDevnetArgs})
+		require.NoError(t, err)
+		require.True(t, process.IsAlive())
+
+		require.NoError(t, process.Stop())
+		require.NoError(t, process.Stop())
+		assert.False(t, process.IsAlive())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := Launch(t.Context(), &Options{BinaryPath: "/nonexistent/starknet-devnet"})
+		require.ErrorIs(t, err, ErrBinaryNotFound)
+
+		_, err = Launch(t.Context(), &Options{
+			BinaryPath: os.Args[0],
+			Args:       append(slices.Clone(fakeDevnetArgs), "--fail"),
+		})
+		require.ErrorIs(t, err, ErrDevnetExited)
+		assert.ErrorContains(t, err, "invalid configuration")
+	})
+
+	t.Run("port taken", func(t *testing.T) {
+		bindErrors := filepath.Join(t.TempDir(), "bind-errors")
+		launchWithBindErrors := func(count int) (*Process, error) {
+			require.NoError(t, os.WriteFile(bindErrors, bytes.Repeat([]byte{'x'}, count), 0o600))
+
+			return Launch(t.Context(), &Options{
+				BinaryPath: os.Args[0],
+				Args:       append(slices.Clone(fakeDevnetArgs), "--bind-errors", bindErrors),
+			})
+		}
+
+		// launched again on another port
+		process, err := launchWithBindErrors(2)
+		require.NoError(t, err)
+		assert.True(t, process.IsAlive())
+		require.NoError(t, process.Stop())
+
+		_, err = launchWithBindErrors(maxLaunchAttempts)
+		require.ErrorIs(t, err, ErrDevnetExited)
+		assert.ErrorContains(t, err, "Address already in use")
+	})
+}
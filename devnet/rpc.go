@@ -0,0 +1,163 @@
+package devnet
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/client"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// Unit is the unit of an amount of fee tokens.
+type Unit string
+
+const (
+	// UnitWei is the unit of ETH amounts
+	UnitWei Unit = "WEI"
+	// UnitFri is the unit of STRK amounts
+	UnitFri Unit = "FRI"
+)
+
+// callCloser is the interface of the JSON-RPC client of the devnet. It
+// matches the Client struct from the 'client' package.
+type callCloser interface {
+	CallContext(ctx context.Context, result interface{}, method string, args interface{}) error
+	Close()
+}
+
+// rpcClient returns the JSON-RPC client of the devnet, dialing its "/rpc"
+// endpoint on first use.
+//
+// Returns:
+//   - callCloser: the JSON-RPC client
+//   - error: an error if the client creation fails
+func (devnet *DevNet) rpcClient() (callCloser, error) {
+	devnet.mu.Lock()
+	defer devnet.mu.Unlock()
+
+	if devnet.c == nil {
+		c, err := client.DialHTTP(devnet.api("/rpc"))
+		if err != nil {
+			return nil, err
+		}
+		devnet.c = c
+	}
+
+	return devnet.c, nil
+}
+
+// call calls a `devnet_*` JSON-RPC method, passing the params as an object.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//   - result: a pointer to store the result of the call, or nil to ignore it
+//   - method: the method name, without the "devnet_" prefix
+//   - params: the params object of the method, or nil if it has none
+//
+// Returns:
+//   - error: an error if any
+func (devnet *DevNet) call(ctx context.Context, result any, method string, params any) error {
+	c, err := devnet.rpcClient()
+	if err != nil {
+		return err
+	}
+
+	return c.CallContext(ctx, result, "devnet_"+method, params)
+}
+
+// MintWithUnit mints an amount of ETH or STRK for a given address, with the
+// `devnet_mint` JSON-RPC method.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//   - address: the address to mint tokens for
+//   - amount: the amount of tokens to mint, in the given unit
+//   - unit: UnitWei to mint ETH, or UnitFri to mint STRK
+//
+// Returns:
+//   - *MintResponse: a MintResponse
+//   - error: an error if any
+func (devnet *DevNet) MintWithUnit(
+	ctx context.Context,
+	address *felt.Felt,
+	amount *big.Int,
+	unit Unit,
+) (*MintResponse, error) {
+	params := struct {
+		Address *felt.Felt `json:"address"`
+		Amount  *big.Int   `json:"amount"`
+		Unit    Unit       `json:"unit"`
+	}{
+		Address: address,
+		Amount:  amount,
+		Unit:    unit,
+	}
+
+	var mint MintResponse
+	if err := devnet.call(ctx, &mint, "mint", params); err != nil {
+		return nil, err
+	}
+
+	return &mint, nil
+}
+
+// Balance is the balance of an account in a fee token.
+type Balance struct {
+	Amount string `json:"amount"`
+	Unit   Unit   `json:"unit"`
+}
+
+// AccountBalance returns the ETH or STRK balance of an account, with the
+// `devnet_getAccountBalance` JSON-RPC method.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//   - address: the address of the account
+//   - unit: UnitWei for the ETH balance, or UnitFri for the STRK balance
+//   - blockID: the block of the balance
+//
+// Returns:
+//   - *Balance: the balance, as a decimal amount
+//   - error: an error if any
+func (devnet *DevNet) AccountBalance(
+	ctx context.Context,
+	address *felt.Felt,
+	unit Unit,
+	blockID rpc.BlockID,
+) (*Balance, error) {
+	params := struct {
+		Address *felt.Felt  `json:"address"`
+		Unit    Unit        `json:"unit"`
+		BlockID rpc.BlockID `json:"block_id"`
+	}{
+		Address: address,
+		Unit:    unit,
+		BlockID: blockID,
+	}
+
+	var balance Balance
+	if err := devnet.call(ctx, &balance, "getAccountBalance", params); err != nil {
+		return nil, err
+	}
+
+	return &balance, nil
+}
+
+// PredeployedAccounts returns the predeployed accounts of the devnet, with the
+// `devnet_getPredeployedAccounts` JSON-RPC method.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//
+// Returns:
+//   - []TestAccount: the predeployed accounts
+//   - error: an error if any
+func (devnet *DevNet) PredeployedAccounts(ctx context.Context) ([]TestAccount, error) {
+	var accounts []TestAccount
+	if err := devnet.call(ctx, &accounts, "getPredeployedAccounts", nil); err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
@@ -0,0 +1,265 @@
+package devnet
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/client"
+	"github.com/NethermindEth/starknet.go/internal/tests"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const messagingContract = "0xe7f1725e7734ce288f8367e1bb143e90bb3f0512"
+
+// fakeDevnet serves the `devnet_*` JSON-RPC methods, recording their params.
+type fakeDevnet struct {
+	params map[string]any
+}
+
+type addressParams struct {
+	Address *felt.Felt  `json:"address"`
+	Amount  *big.Int    `json:"amount"`
+	Unit    Unit        `json:"unit"`
+	BlockID rpc.BlockID `json:"block_id"`
+}
+
+type timeParams struct {
+	Time          uint64 `json:"time"`
+	GenerateBlock bool   `json:"generate_block"`
+}
+
+func (fake *fakeDevnet) Mint(params addressParams) MintResponse {
+	fake.params["mint"] = params
+
+	return MintResponse{
+		NewBalance:      params.Amount.String(),
+		Unit:            string(params.Unit),
+		TransactionHash: "0x1",
+	}
+}
+
+func (fake *fakeDevnet) GetAccountBalance(params addressParams) Balance {
+	fake.params["getAccountBalance"] = params
+
+	return Balance{Amount: "10", Unit: params.Unit}
+}
+
+func (fake *fakeDevnet) Dump(params struct {
+	Path string `json:"path"`
+}) json.RawMessage {
+	fake.params["dump"] = params.Path
+	if params.Path != "" {
+		return json.RawMessage("null")
+	}
+
+	return json.RawMessage(`[{"type":"create_block"}]`)
+}
+
+func (fake *fakeDevnet) Load(params struct {
+	Path string `json:"path"`
+}) error {
+	fake.params["load"] = params.Path
+	if params.Path == "missing.json" {
+		return errors.New("the file does not exist")
+	}
+
+	return nil
+}
+
+func (fake *fakeDevnet) SetTime(params timeParams) SetTimeResponse {
+	fake.params["setTime"] = params
+	resp := SetTimeResponse{BlockTimestamp: params.Time}
+	if params.GenerateBlock {
+		resp.BlockHash = new(felt.Felt).SetUint64(1)
+	}
+
+	return resp
+}
+
+func (fake *fakeDevnet) IncreaseTime(params timeParams) IncreaseTimeResponse {
+	fake.params["increaseTime"] = params
+
+	return IncreaseTimeResponse{
+		TimestampIncreasedBy: params.Time,
+		BlockHash:            new(felt.Felt).SetUint64(2),
+	}
+}
+
+func (fake *fakeDevnet) CreateBlock() map[string]*felt.Felt {
+	fake.params["createBlock"] = nil
+
+	return map[string]*felt.Felt{"block_hash": new(felt.Felt).SetUint64(3)}
+}
+
+func (fake *fakeDevnet) AbortBlocks(params struct {
+	StartingBlockID rpc.BlockID `json:"starting_block_id"`
+}) map[string][]*felt.Felt {
+	fake.params["abortBlocks"] = params.StartingBlockID
+
+	return map[string][]*felt.Felt{"aborted": {new(felt.Felt).SetUint64(3)}}
+}
+
+func (fake *fakeDevnet) ImpersonateAccount(params struct {
+	AccountAddress *felt.Felt `json:"account_address"`
+}) {
+	fake.params["impersonateAccount"] = params.AccountAddress
+}
+
+func (fake *fakeDevnet) AutoImpersonate() {
+	fake.params["autoImpersonate"] = nil
+}
+
+func (fake *fakeDevnet) PostmanLoad(params struct {
+	NetworkURL        string `json:"network_url"`
+	MessagingContract string `json:"messaging_contract_address"`
+}) map[string]string {
+	fake.params["postmanLoad"] = params.NetworkURL
+
+	return map[string]string{"messaging_contract_address": messagingContract}
+}
+
+func (fake *fakeDevnet) PostmanFlush(params struct {
+	DryRun bool `json:"dry_run"`
+}) FlushResponse {
+	fake.params["postmanFlush"] = params.DryRun
+
+	return FlushResponse{
+		MessagesToL1: []rpc.MsgToL1{{
+			FromAddress: new(felt.Felt).SetUint64(4),
+			ToAddress:   new(felt.Felt).SetUint64(5),
+			Payload:     []*felt.Felt{new(felt.Felt).SetUint64(6)},
+		}},
+		MessagesToL2:            []MessageToL2{},
+		GeneratedL2Transactions: []*felt.Felt{},
+		L1Provider:              "dry run",
+	}
+}
+
+func (fake *fakeDevnet) GetConfig() json.RawMessage {
+	fake.params["getConfig"] = nil
+
+	return json.RawMessage(`{
+		"seed": 42,
+		"total_accounts": 10,
+		"chain_id": "SN_SEPOLIA",
+		"start_time": null,
+		"lite_mode": false,
+		"block_generation_on": {"interval": 5},
+		"fork_config": {"url": null, "block_number": null},
+		"server_config": {"host": "127.0.0.1", "port": 5050, "restricted_methods": null},
+		"unknown_field": true
+	}`)
+}
+
+// TestDevnet_RPC tests the `devnet_*` JSON-RPC methods against a fake devnet.
+func TestDevnet_RPC(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	fake := &fakeDevnet{params: make(map[string]any)}
+	rpcServer := client.NewServer()
+	require.NoError(t, rpcServer.RegisterName("devnet", fake))
+	mux := http.NewServeMux()
+	mux.Handle("/rpc", rpcServer)
+	httpServer := httptest.NewServer(mux)
+	t.Cleanup(httpServer.Close)
+
+	ctx := t.Context()
+	d := NewDevNet(httpServer.URL + "/")
+	address := internalUtils.TestHexToFelt(t, "0x1234")
+
+	t.Run("mint and balance", func(t *testing.T) {
+		mint, err := d.MintWithUnit(ctx, address, big.NewInt(100), UnitFri)
+		require.NoError(t, err)
+		assert.Equal(t, &MintResponse{NewBalance: "100", Unit: "FRI", TransactionHash: "0x1"}, mint)
+		assert.Equal(
+			t,
+			addressParams{Address: address, Amount: big.NewInt(100), Unit: UnitFri},
+			fake.params["mint"],
+		)
+
+		latest := rpc.WithBlockTag(rpc.BlockTagLatest)
+		balance, err := d.AccountBalance(ctx, address, UnitWei, latest)
+		require.NoError(t, err)
+		assert.Equal(t, &Balance{Amount: "10", Unit: UnitWei}, balance)
+		assert.Equal(t, latest, fake.params["getAccountBalance"].(addressParams).BlockID)
+	})
+
+	t.Run("state", func(t *testing.T) {
+		dump, err := d.Dump(ctx)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"type":"create_block"}]`, string(dump))
+
+		require.NoError(t, d.DumpToFile(ctx, "dump.json"))
+		assert.Equal(t, "dump.json", fake.params["dump"])
+
+		require.NoError(t, d.Load(ctx, "dump.json"))
+		assert.Equal(t, "dump.json", fake.params["load"])
+		require.ErrorContains(t, d.Load(ctx, "missing.json"), "the file does not exist")
+	})
+
+	t.Run("time and blocks", func(t *testing.T) {
+		setTime, err := d.SetTime(ctx, 1_700_000_000, false)
+		require.NoError(t, err)
+		assert.Equal(t, &SetTimeResponse{BlockTimestamp: 1_700_000_000}, setTime)
+
+		setTime, err = d.SetTime(ctx, 1_700_000_000, true)
+		require.NoError(t, err)
+		assert.Equal(t, new(felt.Felt).SetUint64(1), setTime.BlockHash)
+
+		increase, err := d.IncreaseTime(ctx, 3600)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3600), increase.TimestampIncreasedBy)
+		assert.Equal(t, timeParams{Time: 3600}, fake.params["increaseTime"])
+
+		blockHash, err := d.CreateBlock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, new(felt.Felt).SetUint64(3), blockHash)
+
+		aborted, err := d.AbortBlocks(ctx, rpc.WithBlockHash(blockHash))
+		require.NoError(t, err)
+		assert.Equal(t, []*felt.Felt{blockHash}, aborted)
+		assert.Equal(t, rpc.WithBlockHash(blockHash), fake.params["abortBlocks"])
+	})
+
+	t.Run("impersonation", func(t *testing.T) {
+		require.NoError(t, d.ImpersonateAccount(ctx, address))
+		assert.Equal(t, address, fake.params["impersonateAccount"])
+		require.NoError(t, d.AutoImpersonate(ctx))
+
+		// not served by the fake devnet
+		require.Error(t, d.StopAutoImpersonate(ctx))
+	})
+
+	t.Run("postman", func(t *testing.T) {
+		contract, err := d.PostmanLoad(ctx, "http://localhost:8545", "")
+		require.NoError(t, err)
+		assert.Equal(t, messagingContract, contract)
+		assert.Equal(t, "http://localhost:8545", fake.params["postmanLoad"])
+
+		flush, err := d.PostmanFlush(ctx, true)
+		require.NoError(t, err)
+		assert.Equal(t, true, fake.params["postmanFlush"])
+		require.Len(t, flush.MessagesToL1, 1)
+		assert.Equal(t, new(felt.Felt).SetUint64(5), flush.MessagesToL1[0].ToAddress)
+		assert.Equal(t, "dry run", flush.L1Provider)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		config, err := d.Config(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(42), config.Seed)
+		assert.Equal(t, "SN_SEPOLIA", config.ChainID)
+		assert.Nil(t, config.StartTime)
+		assert.Nil(t, config.ForkConfig.URL)
+		assert.Equal(t, uint16(5050), config.ServerConfig.Port)
+		assert.JSONEq(t, `{"interval": 5}`, string(config.BlockGenerationOn))
+	})
+}
@@ -0,0 +1,71 @@
+package devnet
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Dump returns a snapshot of the devnet state, with the `devnet_dump` JSON-RPC
+// method. The snapshot can be restored with the Load method after writing it
+// to a file.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//
+// Returns:
+//   - json.RawMessage: the snapshot, as the list of events that led to the current state
+//   - error: an error if any
+func (devnet *DevNet) Dump(ctx context.Context) (json.RawMessage, error) {
+	var dump json.RawMessage
+	if err := devnet.call(ctx, &dump, "dump", struct{}{}); err != nil {
+		return nil, err
+	}
+
+	return dump, nil
+}
+
+// DumpToFile writes a snapshot of the devnet state to a file on the devnet
+// host, with the `devnet_dump` JSON-RPC method.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//   - path: the path of the file, on the devnet host
+//
+// Returns:
+//   - error: an error if any
+func (devnet *DevNet) DumpToFile(ctx context.Context, path string) error {
+	params := struct {
+		Path string `json:"path"`
+	}{Path: path}
+
+	return devnet.call(ctx, nil, "dump", params)
+}
+
+// Load restores the devnet state from a snapshot file on the devnet host,
+// with the `devnet_load` JSON-RPC method.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//   - path: the path of the file, on the devnet host
+//
+// Returns:
+//   - error: an error if any
+func (devnet *DevNet) Load(ctx context.Context, path string) error {
+	params := struct {
+		Path string `json:"path"`
+	}{Path: path}
+
+	return devnet.call(ctx, nil, "load", params)
+}
+
+// Restart resets the devnet to its initial state, with the `devnet_restart`
+// JSON-RPC method.
+//
+// Parameters:
+//   - ctx: the context.Context of the call
+//
+// Returns:
+//   - error: an error if any
+func (devnet *DevNet) Restart(ctx context.Context) error {
+	return devnet.call(ctx, nil, "restart", nil)
+}
@@ -0,0 +1,85 @@
+package devnet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// Instance is a devnet launched for a test, with a provider and the funded
+// predeployed accounts.
+type Instance struct {
+	*Process
+	// The provider connected to the devnet
+	Provider *rpc.Provider
+	// The predeployed accounts of the devnet, funded with ETH and STRK
+	Accounts []*account.Account
+}
+
+// Start launches a devnet for a test, with Launch, and stops it when the test
+// and its subtests complete. The test fails if the devnet can't be launched,
+// e.g. when the starknet-devnet binary isn't installed. Each call launches
+// its own devnet, so parallel tests don't share any state.
+//
+// Parameters:
+//   - t: the test using the devnet
+//   - opts: the devnet options, or nil for the defaults
+//
+// Returns:
+//   - *Instance: the alive devnet
+func Start(t testing.TB, opts *Options) *Instance {
+	t.Helper()
+
+	ctx := context.Background()
+	process, err := Launch(ctx, opts)
+	if err != nil {
+		t.Fatalf("failed to launch the devnet: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := process.Stop(); err != nil {
+			t.Errorf("failed to stop the devnet: %v", err)
+		}
+	})
+
+	provider, err := rpc.NewProvider(ctx, process.URL+"/rpc")
+	// a devnet implementing another RPC version is still usable
+	if err != nil && !errors.Is(err, rpc.ErrIncompatibleVersion) {
+		t.Fatalf("failed to connect to the devnet: %v", err)
+	}
+
+	predeployed, err := process.PredeployedAccounts(ctx)
+	if err != nil {
+		t.Fatalf("failed to get the devnet accounts: %v", err)
+	}
+	accounts := make([]*account.Account, len(predeployed))
+	for i, acc := range predeployed {
+		accounts[i], err = newAccount(provider, acc)
+		if err != nil {
+			t.Fatalf("failed to create the devnet account %s: %v", acc.Address, err)
+		}
+	}
+
+	return &Instance{Process: process, Provider: provider, Accounts: accounts}
+}
+
+// newAccount returns the account.Account of a predeployed account.
+func newAccount(provider *rpc.Provider, acc TestAccount) (*account.Account, error) {
+	address, err := new(felt.Felt).SetString(acc.Address)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := new(felt.Felt).SetString(acc.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ks := account.NewMemKeystore()
+	ks.Put(acc.PublicKey, privateKey.BigInt(new(big.Int)))
+
+	return account.NewAccount(provider, address, acc.PublicKey, ks, account.CairoV2)
+}
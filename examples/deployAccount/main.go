@@ -65,7 +65,7 @@ func main() {
 
 	// Convert the estimated fee to STRK. The multiplier is 1, as we already estimated the
 	// fee in BuildAndEstimateDeployAccountTxn multiplying by 1.5.
-	overallFee, err := utils.ResBoundsMapToOverallFee(
+	feeInSTRK, err := utils.ResBoundsMapToOverallFeeAmount(
 		deployAccountTxn.ResourceBounds,
 		1,
 		deployAccountTxn.Tip,
@@ -73,7 +73,6 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	feeInSTRK := utils.FRIToSTRK(overallFee)
 
 	// At this point you need to add funds to precomputed address to use it.
 	var input string
@@ -82,7 +81,7 @@ func main() {
 		"\nThe `precomputedAddress` account needs to have enough STRK to perform a transaction.",
 	)
 	fmt.Printf(
-		"You can use the starknet faucet or send STRK to your `precomputedAddress`. You need approximately %f STRK. \n",
+		"You can use the starknet faucet or send STRK to your `precomputedAddress`. You need approximately %s STRK. \n",
 		feeInSTRK,
 	)
 	fmt.Println("When your account has been funded, press any key, then `enter` to continue: ")
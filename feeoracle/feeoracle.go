@@ -0,0 +1,289 @@
+// Package feeoracle recommends the tips and the resource bounds of the
+// transactions from the gas prices and tips of the recent blocks, for a chosen
+// urgency.
+//
+// An Oracle can be plugged into the transaction building of the account
+// package with its Strategy method:
+//
+//	oracle := feeoracle.New(provider, 20)
+//	opts := &account.TxnOptions{FeeStrategy: oracle.Strategy(feeoracle.Fast)}
+package feeoracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+var (
+	ErrNoBlocks       = errors.New("no blocks to sample")
+	ErrUnknownUrgency = errors.New("unknown urgency")
+)
+
+// DefaultBlocks is the number of blocks sampled when none is specified.
+const DefaultBlocks = 10
+
+// The sizes of the resource bounds
+const (
+	u64Bits  = 64
+	u128Bits = 128
+)
+
+// Urgency is how fast a transaction should be included in a block.
+type Urgency int
+
+const (
+	// The transaction can wait: low tip and small safety margins
+	Slow Urgency = iota
+	// The default urgency
+	Normal
+	// The transaction should be included as soon as possible: high tip and
+	// large safety margins
+	Fast
+)
+
+// String returns the name of the urgency.
+func (u Urgency) String() string {
+	switch u {
+	case Slow:
+		return "slow"
+	case Normal:
+		return "normal"
+	case Fast:
+		return "fast"
+	default:
+		return fmt.Sprintf("Urgency(%d)", int(u))
+	}
+}
+
+// Profile holds how the recommendations are computed for an urgency.
+type Profile struct {
+	// The percentile of the sampled gas prices and tips to use, from 0 to 100
+	Percentile float64
+	// The multiplier applied to the gas amounts of the fee estimate
+	AmountMultiplier float64
+	// The multiplier applied to the recommended gas prices
+	PriceMultiplier float64
+	// The number of blocks the transaction is expected to wait before its
+	// inclusion. A rising price trend is extrapolated over these blocks.
+	InclusionBlocks int
+}
+
+// DefaultProfiles are the profiles used by a new Oracle. The Normal profile
+// applies the same 1.5 multiplier as the default account.TxnOptions.FeeMultiplier.
+//
+//nolint:mnd // the default values
+var DefaultProfiles = map[Urgency]Profile{
+	Slow:   {Percentile: 25, AmountMultiplier: 1.2, PriceMultiplier: 1.2, InclusionBlocks: 10},
+	Normal: {Percentile: 50, AmountMultiplier: 1.5, PriceMultiplier: 1.5, InclusionBlocks: 3},
+	Fast:   {Percentile: 90, AmountMultiplier: 1.5, PriceMultiplier: 2, InclusionBlocks: 1},
+}
+
+// Oracle samples the gas prices and tips of the last blocks to recommend the
+// fees of the transactions. The samples are cached, so only the new blocks
+// are fetched by the next calls. It is safe for concurrent use.
+type Oracle struct {
+	Provider rpc.RPCProvider
+	// The number of blocks sampled
+	Blocks int
+	// The profiles of each urgency. Initialised with a copy of DefaultProfiles.
+	Profiles map[Urgency]Profile
+
+	mu    sync.Mutex
+	cache map[uint64]*blockSample
+}
+
+// New creates a new fee oracle.
+//
+// Parameters:
+//   - provider: the provider used to fetch the blocks
+//   - blocks: the number of blocks to sample. If <= 0, DefaultBlocks is used.
+//
+// Returns:
+//   - *Oracle: the fee oracle
+func New(provider rpc.RPCProvider, blocks int) *Oracle {
+	if blocks <= 0 {
+		blocks = DefaultBlocks
+	}
+	profiles := make(map[Urgency]Profile, len(DefaultProfiles))
+	for urgency, profile := range DefaultProfiles {
+		profiles[urgency] = profile
+	}
+
+	return &Oracle{
+		Provider: provider,
+		Blocks:   blocks,
+		Profiles: profiles,
+		cache:    make(map[uint64]*blockSample),
+	}
+}
+
+// Recommendation is the recommended tip and maximum gas prices for an urgency.
+type Recommendation struct {
+	Urgency Urgency
+	// The statistics the recommendation is computed from
+	Stats *Stats
+	// The recommended tip, in FRI
+	Tip rpc.U64
+	// The recommended maximum price per unit of each resource, in FRI
+	L1GasPrice     *big.Int
+	L1DataGasPrice *big.Int
+	L2GasPrice     *big.Int
+
+	profile Profile
+}
+
+// Recommend samples the last blocks and recommends a tip and maximum gas
+// prices for an urgency.
+//
+// Parameters:
+//   - ctx: the context.Context for the requests
+//   - urgency: the urgency of the transaction
+//
+// Returns:
+//   - *Recommendation: the recommendation
+//   - error: an error if the urgency has no profile or the blocks can't be fetched
+func (o *Oracle) Recommend(ctx context.Context, urgency Urgency) (*Recommendation, error) {
+	profile, ok := o.Profiles[urgency]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownUrgency, urgency)
+	}
+
+	stats, err := o.Stats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	price := func(series *Series) *big.Int {
+		value := series.Percentile(profile.Percentile)
+		// extrapolate a rising trend over the inclusion delay
+		if series.Trend > 0 && profile.InclusionBlocks > 0 {
+			value = mulFloat(value, 1+series.Trend*float64(profile.InclusionBlocks))
+		}
+
+		return mulFloat(value, profile.PriceMultiplier)
+	}
+
+	tip := stats.Tip.Percentile(profile.Percentile)
+	if !tip.IsUint64() {
+		tip = new(big.Int).SetUint64(^uint64(0))
+	}
+
+	return &Recommendation{
+		Urgency:        urgency,
+		Stats:          stats,
+		Tip:            rpc.U64(fmt.Sprintf("%#x", tip)),
+		L1GasPrice:     price(&stats.L1GasPrice),
+		L1DataGasPrice: price(&stats.L1DataGasPrice),
+		L2GasPrice:     price(&stats.L2GasPrice),
+		profile:        profile,
+	}, nil
+}
+
+// ResourceBounds computes the resource bounds of a transaction from its fee
+// estimate. The maximum amounts are the estimated amounts multiplied by the
+// amount multiplier of the urgency. The maximum prices are the recommended
+// prices, or the estimated prices with the price multiplier if higher. The
+// values are capped to the U64 and U128 maximums.
+//
+// Parameters:
+//   - estimate: the fee estimate of the transaction
+//
+// Returns:
+//   - *rpc.ResourceBoundsMapping: the resource bounds
+func (r *Recommendation) ResourceBounds(estimate rpc.FeeEstimation) *rpc.ResourceBoundsMapping {
+	bounds := func(consumed, estimatedPrice *felt.Felt, price *big.Int) rpc.ResourceBounds {
+		amount := mulFloat(feltToBig(consumed), r.profile.AmountMultiplier)
+		maxPrice := mulFloat(feltToBig(estimatedPrice), r.profile.PriceMultiplier)
+		if price.Cmp(maxPrice) > 0 {
+			maxPrice = price
+		}
+
+		return rpc.ResourceBounds{
+			MaxAmount:       rpc.U64(fmt.Sprintf("%#x", capBits(amount, u64Bits))),
+			MaxPricePerUnit: rpc.U128(fmt.Sprintf("%#x", capBits(maxPrice, u128Bits))),
+		}
+	}
+
+	return &rpc.ResourceBoundsMapping{
+		L1Gas:     bounds(estimate.L1GasConsumed, estimate.L1GasPrice, r.L1GasPrice),
+		L1DataGas: bounds(estimate.L1DataGasConsumed, estimate.L1DataGasPrice, r.L1DataGasPrice),
+		L2Gas:     bounds(estimate.L2GasConsumed, estimate.L2GasPrice, r.L2GasPrice),
+	}
+}
+
+// Strategy is an account.FeeStrategy using the recommendations of an Oracle
+// for an urgency.
+type Strategy struct {
+	Oracle  *Oracle
+	Urgency Urgency
+}
+
+// Strategy returns an account.FeeStrategy using the recommendations of the
+// oracle for an urgency, to be set in the account.TxnOptions.
+func (o *Oracle) Strategy(urgency Urgency) *Strategy {
+	return &Strategy{Oracle: o, Urgency: urgency}
+}
+
+// Tip returns the recommended tip.
+func (s *Strategy) Tip(ctx context.Context) (rpc.U64, error) {
+	recommendation, err := s.Oracle.Recommend(ctx, s.Urgency)
+	if err != nil {
+		return "", err
+	}
+
+	return recommendation.Tip, nil
+}
+
+// ResourceBounds returns the recommended resource bounds for a fee estimate.
+func (s *Strategy) ResourceBounds(
+	ctx context.Context,
+	estimate rpc.FeeEstimation,
+) (*rpc.ResourceBoundsMapping, error) {
+	recommendation, err := s.Oracle.Recommend(ctx, s.Urgency)
+	if err != nil {
+		return nil, err
+	}
+
+	return recommendation.ResourceBounds(estimate), nil
+}
+
+func feltToBig(value *felt.Felt) *big.Int {
+	if value == nil {
+		return new(big.Int)
+	}
+
+	return value.BigInt(new(big.Int))
+}
+
+// mulFloat multiplies an integer by a float, rounding the result up. The float
+// is taken as its shortest decimal representation, so e.g. 1000 * 1.2 is 1200.
+func mulFloat(value *big.Int, multiplier float64) *big.Int {
+	ratio, ok := new(big.Rat).SetString(strconv.FormatFloat(multiplier, 'g', -1, 64))
+	if !ok {
+		return new(big.Int)
+	}
+	num := new(big.Int).Mul(value, ratio.Num())
+	quo, rem := new(big.Int).QuoRem(num, ratio.Denom(), new(big.Int))
+	if rem.Sign() > 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+
+	return quo
+}
+
+// capBits caps a value to the maximum unsigned integer of a number of bits.
+func capBits(value *big.Int, bits uint) *big.Int {
+	maxValue := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), bits), big.NewInt(1))
+	if value.Cmp(maxValue) > 0 {
+		return maxValue
+	}
+
+	return value
+}
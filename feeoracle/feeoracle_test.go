@@ -0,0 +1,160 @@
+package feeoracle_test
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/feeoracle"
+	"github.com/NethermindEth/starknet.go/internal/tests/mocks/rpcv10mock"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var _ account.FeeStrategy = (*feeoracle.Strategy)(nil)
+
+// newBlock builds a block with an L2 gas price, and an invoke transaction for
+// each tip.
+func newBlock(number, l2GasPrice uint64, tips ...uint64) *rpc.Block {
+	price := func(value uint64) rpc.ResourcePrice {
+		return rpc.ResourcePrice{PriceInFRI: new(felt.Felt).SetUint64(value)}
+	}
+	block := &rpc.Block{
+		BlockHeader: rpc.BlockHeader{
+			Number:         number,
+			L1GasPrice:     price(1000),
+			L1DataGasPrice: price(10),
+			L2GasPrice:     price(l2GasPrice),
+		},
+	}
+	for _, tip := range tips {
+		block.Transactions = append(block.Transactions, rpc.BlockTransaction{
+			Transaction: rpc.InvokeTxnV3{Tip: rpc.U64("0x" + strconv.FormatUint(tip, 16))},
+		})
+	}
+	// the L1 handler transactions have no tip
+	block.Transactions = append(block.Transactions, rpc.BlockTransaction{
+		Transaction: rpc.L1HandlerTxn{},
+	})
+
+	return block
+}
+
+// TestOracle tests the statistics and recommendations of the oracle, and that
+// the blocks are only fetched once.
+func TestOracle(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+
+	blocks := map[uint64]*rpc.Block{
+		7:  newBlock(7, 100, 0, 10),
+		8:  newBlock(8, 100, 20),
+		9:  newBlock(9, 110, 30, 40),
+		10: newBlock(10, 120, 50),
+	}
+	for number, block := range blocks {
+		mockRPCProvider.EXPECT().
+			ConfirmedBlockWithTxs(gomock.Any(), rpc.WithConfirmedBlockNumber(number)).
+			Return(block, nil).
+			Times(1)
+	}
+
+	oracle := feeoracle.New(mockRPCProvider, 3)
+
+	mockRPCProvider.EXPECT().BlockNumber(gomock.Any()).Return(uint64(9), nil).Times(1)
+	stats, err := oracle.Stats(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), stats.FromBlock)
+	assert.Equal(t, uint64(9), stats.ToBlock)
+	assert.Len(t, stats.L2GasPrice.Samples, 3)
+	assert.Len(t, stats.Tip.Samples, 5)
+	assert.Positive(t, stats.L2GasPrice.Trend)
+	assert.Zero(t, stats.L1GasPrice.Trend)
+
+	// only the new block 10 is fetched
+	mockRPCProvider.EXPECT().BlockNumber(gomock.Any()).Return(uint64(10), nil).AnyTimes()
+
+	type testSetType struct {
+		Urgency    feeoracle.Urgency
+		Tip        rpc.U64
+		L1GasPrice *big.Int
+	}
+	testSet := []testSetType{
+		{Urgency: feeoracle.Slow, Tip: "0x14", L1GasPrice: big.NewInt(1200)},
+		{Urgency: feeoracle.Normal, Tip: "0x1e", L1GasPrice: big.NewInt(1500)},
+		{Urgency: feeoracle.Fast, Tip: "0x32", L1GasPrice: big.NewInt(2000)},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Urgency.String(), func(t *testing.T) {
+			recommendation, err := oracle.Recommend(t.Context(), test.Urgency)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(8), recommendation.Stats.FromBlock)
+			assert.Equal(t, test.Tip, recommendation.Tip)
+			assert.Equal(t, test.L1GasPrice, recommendation.L1GasPrice)
+			// the rising L2 gas price is extrapolated
+			profile := oracle.Profiles[test.Urgency]
+			l2GasPrice := recommendation.Stats.L2GasPrice.Percentile(profile.Percentile)
+			assert.Equal(
+				t,
+				1,
+				recommendation.L2GasPrice.Cmp(
+					new(big.Int).Mul(l2GasPrice, big.NewInt(int64(profile.PriceMultiplier))),
+				),
+			)
+		})
+	}
+
+	t.Run("resource bounds", func(t *testing.T) {
+		strategy := oracle.Strategy(feeoracle.Normal)
+		tip, err := strategy.Tip(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, rpc.U64("0x1e"), tip)
+
+		resBounds, err := strategy.ResourceBounds(t.Context(), rpc.FeeEstimation{
+			FeeEstimationCommon: rpc.FeeEstimationCommon{
+				L1GasConsumed:     new(felt.Felt).SetUint64(0),
+				L1GasPrice:        new(felt.Felt).SetUint64(1000),
+				L1DataGasConsumed: new(felt.Felt).SetUint64(100),
+				// higher than the sampled prices
+				L1DataGasPrice: new(felt.Felt).SetUint64(40),
+				L2GasConsumed:  new(felt.Felt).SetUint64(1000000),
+				L2GasPrice:     new(felt.Felt).SetUint64(100),
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x5dc"},
+			resBounds.L1Gas,
+		)
+		assert.Equal(
+			t,
+			rpc.ResourceBounds{MaxAmount: "0x96", MaxPricePerUnit: "0x3c"},
+			resBounds.L1DataGas,
+		)
+		assert.Equal(t, rpc.U64("0x16e360"), resBounds.L2Gas.MaxAmount)
+	})
+
+	t.Run("unknown urgency", func(t *testing.T) {
+		_, err := oracle.Recommend(t.Context(), feeoracle.Urgency(5))
+		require.ErrorIs(t, err, feeoracle.ErrUnknownUrgency)
+	})
+}
+
+func TestSeriesPercentile(t *testing.T) {
+	series := feeoracle.Series{
+		Samples: []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4)},
+	}
+
+	assert.Equal(t, big.NewInt(1), series.Percentile(0))
+	assert.Equal(t, big.NewInt(1), series.Percentile(25))
+	assert.Equal(t, big.NewInt(2), series.Percentile(50))
+	assert.Equal(t, big.NewInt(4), series.Percentile(90))
+	assert.Equal(t, big.NewInt(4), series.Percentile(150))
+	assert.Equal(t, new(big.Int), new(feeoracle.Series).Percentile(50))
+}
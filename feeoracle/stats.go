@@ -0,0 +1,247 @@
+package feeoracle
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// Series is the distribution of a sampled value over the last blocks.
+type Series struct {
+	// The samples, in ascending order
+	Samples []*big.Int
+	// The relative change of the value per block, from a linear regression
+	// over the blocks. E.g. 0.02 means the value rises by about 2% per block.
+	Trend float64
+}
+
+// Percentile returns a percentile of the samples, using the nearest-rank
+// method, or zero if there are no samples.
+//
+// Parameters:
+//   - p: the percentile, from 0 to 100
+//
+// Returns:
+//   - *big.Int: the percentile
+func (s *Series) Percentile(p float64) *big.Int {
+	if len(s.Samples) == 0 {
+		return new(big.Int)
+	}
+	p = math.Min(math.Max(p, 0), 100)                         //nolint:mnd // percentage
+	rank := int(math.Ceil(p / 100 * float64(len(s.Samples)))) //nolint:mnd // percentage
+	rank = max(rank, 1)
+
+	return new(big.Int).Set(s.Samples[rank-1])
+}
+
+// Stats holds the gas prices and tips sampled over the last blocks. The prices
+// and tips are in FRI.
+type Stats struct {
+	// The first and last sampled blocks
+	FromBlock uint64
+	ToBlock   uint64
+
+	L1GasPrice     Series
+	L1DataGasPrice Series
+	L2GasPrice     Series
+	// The tips of all the sampled transactions. Their trend is computed from
+	// the median tip of each block.
+	Tip Series
+}
+
+// blockSample holds the values sampled from a block.
+type blockSample struct {
+	l1GasPrice     *big.Int
+	l1DataGasPrice *big.Int
+	l2GasPrice     *big.Int
+	tips           []*big.Int
+}
+
+// Stats samples the gas prices and tips of the last blocks.
+//
+// Parameters:
+//   - ctx: the context.Context for the requests
+//
+// Returns:
+//   - *Stats: the statistics of the sampled blocks
+//   - error: an error if the blocks can't be fetched
+func (o *Oracle) Stats(ctx context.Context) (*Stats, error) {
+	latest, err := o.Provider.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the latest block number: %w", err)
+	}
+
+	blocks := uint64(max(o.Blocks, 1))
+	from := uint64(0)
+	if latest+1 > blocks {
+		from = latest + 1 - blocks
+	}
+
+	samples := make([]*blockSample, 0, latest-from+1)
+	for number := from; number <= latest; number++ {
+		sample, err := o.sampleBlock(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+	}
+	if len(samples) == 0 {
+		return nil, ErrNoBlocks
+	}
+	o.pruneCache(from)
+
+	perBlock := func(value func(*blockSample) []*big.Int) [][]*big.Int {
+		values := make([][]*big.Int, len(samples))
+		for i, sample := range samples {
+			values[i] = value(sample)
+		}
+
+		return values
+	}
+
+	return &Stats{
+		FromBlock: from,
+		ToBlock:   latest,
+		L1GasPrice: newSeries(perBlock(func(s *blockSample) []*big.Int {
+			return []*big.Int{s.l1GasPrice}
+		})),
+		L1DataGasPrice: newSeries(perBlock(func(s *blockSample) []*big.Int {
+			return []*big.Int{s.l1DataGasPrice}
+		})),
+		L2GasPrice: newSeries(perBlock(func(s *blockSample) []*big.Int {
+			return []*big.Int{s.l2GasPrice}
+		})),
+		Tip: newSeries(perBlock(func(s *blockSample) []*big.Int {
+			return s.tips
+		})),
+	}, nil
+}
+
+// sampleBlock returns the sample of a block, fetching it if it isn't cached.
+func (o *Oracle) sampleBlock(ctx context.Context, number uint64) (*blockSample, error) {
+	o.mu.Lock()
+	sample, ok := o.cache[number]
+	o.mu.Unlock()
+	if ok {
+		return sample, nil
+	}
+
+	block, err := o.Provider.ConfirmedBlockWithTxs(ctx, rpc.WithConfirmedBlockNumber(number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", number, err)
+	}
+
+	sample = &blockSample{
+		l1GasPrice:     feltToBig(block.L1GasPrice.PriceInFRI),
+		l1DataGasPrice: feltToBig(block.L1DataGasPrice.PriceInFRI),
+		l2GasPrice:     feltToBig(block.L2GasPrice.PriceInFRI),
+	}
+	for _, txn := range block.Transactions {
+		tip, ok := transactionTip(txn.Transaction)
+		if !ok {
+			continue
+		}
+		value, err := tip.ToUint64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid tip in block %d: %w", number, err)
+		}
+		sample.tips = append(sample.tips, new(big.Int).SetUint64(value))
+	}
+
+	o.mu.Lock()
+	if o.cache == nil {
+		o.cache = make(map[uint64]*blockSample)
+	}
+	o.cache[number] = sample
+	o.mu.Unlock()
+
+	return sample, nil
+}
+
+// pruneCache removes the samples of the blocks before a block number.
+func (o *Oracle) pruneCache(from uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for number := range o.cache {
+		if number < from {
+			delete(o.cache, number)
+		}
+	}
+}
+
+// transactionTip returns the tip of a V3 transaction. The other transactions
+// have no tip.
+func transactionTip(txn rpc.Transaction) (rpc.U64, bool) {
+	switch txn := txn.(type) {
+	case rpc.InvokeTxnV3:
+		return txn.Tip, true
+	case *rpc.InvokeTxnV3:
+		return txn.Tip, true
+	case rpc.DeclareTxnV3:
+		return txn.Tip, true
+	case *rpc.DeclareTxnV3:
+		return txn.Tip, true
+	case rpc.DeployAccountTxnV3:
+		return txn.Tip, true
+	case *rpc.DeployAccountTxnV3:
+		return txn.Tip, true
+	default:
+		return "", false
+	}
+}
+
+// newSeries builds a series from the values of each block, in block order.
+func newSeries(perBlock [][]*big.Int) Series {
+	var samples []*big.Int
+	var xs, ys []float64
+	for i, values := range perBlock {
+		if len(values) == 0 {
+			continue
+		}
+		samples = append(samples, values...)
+
+		sorted := slices.Clone(values)
+		slices.SortFunc(sorted, (*big.Int).Cmp)
+		median, _ := new(big.Float).SetInt(sorted[(len(sorted)-1)/2]).Float64()
+		xs = append(xs, float64(i))
+		ys = append(ys, median)
+	}
+	slices.SortFunc(samples, (*big.Int).Cmp)
+
+	return Series{Samples: samples, Trend: relativeSlope(xs, ys)}
+}
+
+// relativeSlope returns the slope of the least squares regression of ys over
+// xs, divided by the mean of ys.
+func relativeSlope(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	if n < 2 { //nolint:mnd // a slope needs two points
+		return 0
+	}
+
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+	if meanY == 0 {
+		return 0
+	}
+
+	var cov, varX float64
+	for i := range xs {
+		cov += (xs[i] - meanX) * (ys[i] - meanY)
+		varX += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+	if varX == 0 {
+		return 0
+	}
+
+	return cov / varX / meanY
+}
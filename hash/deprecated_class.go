@@ -0,0 +1,360 @@
+package hash
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/curve"
+)
+
+// deprecatedAPIVersion is the API version used by the Cairo 0 class hash.
+const deprecatedAPIVersion = 0
+
+var ErrInvalidDeprecatedProgram = errors.New("invalid deprecated contract class program")
+
+// DeprecatedClassHash calculates the hash of a deprecated (Cairo 0) contract class.
+//
+// The hash is the Pedersen hash of the API version, the external, L1 handler and
+// constructor entry points, the program builtins, the hinted class hash and the
+// program bytecode. The hinted class hash is the StarknetKeccak of the program
+// and ABI serialised the same way as the Python `json.dumps(..., sort_keys=True)`
+// call used by cairo-lang.
+// [specification]: https://github.com/starkware-libs/cairo-lang/blob/v0.13.1/src/starkware/starknet/core/os/contract_class/deprecated_class_hash.py
+//
+// Parameters:
+//   - contract: A contract class object of type contracts.DeprecatedContractClass.
+//
+// Returns:
+//   - *felt.Felt: a pointer to a felt.Felt object that represents the calculated hash.
+//   - error: an error if the program cannot be decoded
+//
+//nolint:lll // The link would be unclickable if we break the line.
+func DeprecatedClassHash(contract *contracts.DeprecatedContractClass) (*felt.Felt, error) {
+	programJSON, err := contracts.DecodeProgram(contract.Program)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDeprecatedProgram, err)
+	}
+
+	var program struct {
+		Builtins []string `json:"builtins"`
+		Data     []string `json:"data"`
+	}
+	if err = json.Unmarshal(programJSON, &program); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDeprecatedProgram, err)
+	}
+
+	externalHash, err := hashDeprecatedEntryPoints(contract.DeprecatedEntryPointsByType.External)
+	if err != nil {
+		return nil, err
+	}
+	l1HandlerHash, err := hashDeprecatedEntryPoints(contract.DeprecatedEntryPointsByType.L1Handler)
+	if err != nil {
+		return nil, err
+	}
+	constructorHash, err := hashDeprecatedEntryPoints(
+		contract.DeprecatedEntryPointsByType.Constructor,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	builtins := make([]*felt.Felt, len(program.Builtins))
+	for i, builtin := range program.Builtins {
+		builtins[i] = new(felt.Felt).SetBytes([]byte(builtin))
+	}
+
+	bytecode := make([]*felt.Felt, len(program.Data))
+	for i, word := range program.Data {
+		bytecode[i], err = new(felt.Felt).SetString(word)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidDeprecatedProgram, err)
+		}
+	}
+
+	hintedHash, err := hintedClassHash(programJSON, contract.ABI)
+	if err != nil {
+		return nil, err
+	}
+
+	return curve.PedersenArray(
+		new(felt.Felt).SetUint64(deprecatedAPIVersion),
+		externalHash,
+		l1HandlerHash,
+		constructorHash,
+		curve.PedersenArray(builtins...),
+		hintedHash,
+		curve.PedersenArray(bytecode...),
+	), nil
+}
+
+// hashDeprecatedEntryPoints calculates the Pedersen hash of the flattened
+// [selector, offset] pairs of the given entry points.
+//
+// Parameters:
+//   - entryPoints: A slice of contracts.DeprecatedCairoEntryPoint objects
+//
+// Returns:
+//   - *felt.Felt: the calculated hash
+//   - error: an error if an offset is not a valid felt
+func hashDeprecatedEntryPoints(
+	entryPoints []contracts.DeprecatedCairoEntryPoint,
+) (*felt.Felt, error) {
+	flattened := make([]*felt.Felt, 0, len(entryPoints)*2) //nolint:mnd // selector and offset
+	for _, elt := range entryPoints {
+		offset, err := new(felt.Felt).SetString(string(elt.Offset))
+		if err != nil {
+			return nil, fmt.Errorf("invalid entry point offset %q: %w", elt.Offset, err)
+		}
+		flattened = append(flattened, elt.Selector, offset)
+	}
+
+	return curve.PedersenArray(flattened...), nil
+}
+
+// hintedClassHash calculates the StarknetKeccak of the JSON object
+// {"abi": abi, "program": program}, after applying the same backward compatibility
+// adjustments to the program as cairo-lang does.
+//
+// Parameters:
+//   - programJSON: the decoded JSON representation of the program
+//   - abi: the ABI of the contract class, may be nil
+//
+// Returns:
+//   - *felt.Felt: the hinted class hash
+//   - error: an error if any
+func hintedClassHash(programJSON []byte, abi *contracts.ABI) (*felt.Felt, error) {
+	program, err := decodeJSONWithNumbers(programJSON)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDeprecatedProgram, err)
+	}
+	programMap, ok := program.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: program is not a JSON object", ErrInvalidDeprecatedProgram)
+	}
+
+	// The debug info is not part of the hash.
+	programMap["debug_info"] = nil
+
+	// Remove fields added in later versions of cairo-lang, for hash backward
+	// compatibility of contracts deployed prior to adding them.
+	attributes, _ := programMap["attributes"].([]any)
+	if len(attributes) == 0 {
+		delete(programMap, "attributes")
+	} else {
+		for _, attribute := range attributes {
+			attributeMap, ok := attribute.(map[string]any)
+			if !ok {
+				continue
+			}
+			if scopes, ok := attributeMap["accessible_scopes"].([]any); ok && len(scopes) == 0 {
+				delete(attributeMap, "accessible_scopes")
+			}
+			if data, ok := attributeMap["flow_tracking_data"]; ok && data == nil {
+				delete(attributeMap, "flow_tracking_data")
+			}
+		}
+	}
+
+	// The hints are keyed by PC, which cairo-lang dumps as integers, so Python
+	// sorts them numerically rather than lexicographically.
+	if hints, ok := programMap["hints"].(map[string]any); ok {
+		programMap["hints"] = intKeyedMap(hints)
+	}
+
+	// Programs compiled before Cairo 0.10.0 have no 'compiler_version' field and
+	// format named tuples as "(a : felt)" instead of "(a: felt)".
+	if _, ok := programMap["compiler_version"]; !ok {
+		addExtraSpaceToNamedTuples(programMap["identifiers"])
+		addExtraSpaceToNamedTuples(programMap["reference_manager"])
+	}
+
+	var abiValue any
+	if abi != nil {
+		abiJSON, err := json.Marshal(abi)
+		if err != nil {
+			return nil, err
+		}
+		abiValue, err = decodeJSONWithNumbers(abiJSON)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := writePythonJSON(&buf, map[string]any{
+		"abi":     abiValue,
+		"program": programMap,
+	}); err != nil {
+		return nil, err
+	}
+
+	return curve.StarknetKeccak(buf.Bytes()), nil
+}
+
+// intKeyedMap is a JSON object whose keys are integers, and are therefore sorted
+// numerically when serialised by writePythonJSON.
+type intKeyedMap map[string]any
+
+// decodeJSONWithNumbers decodes JSON data into generic values, keeping numbers
+// as json.Number so that they are serialised back exactly as they were.
+func decodeJSONWithNumbers(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// addExtraSpaceToNamedTuples walks the given JSON value and rewrites every
+// "cairo_type" and "value" string to the pre Cairo 0.10.0 named tuple format.
+func addExtraSpaceToNamedTuples(value any) {
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			addExtraSpaceToNamedTuples(item)
+		}
+	case map[string]any:
+		for key, item := range v {
+			str, ok := item.(string)
+			if !ok {
+				addExtraSpaceToNamedTuples(item)
+
+				continue
+			}
+			if key == "cairo_type" || key == "value" {
+				// An already correct " : " is turned into "  : " by the first
+				// replacement, which is fixed by the second one.
+				v[key] = strings.ReplaceAll(strings.ReplaceAll(str, ": ", " : "), "  :", " :")
+			}
+		}
+	}
+}
+
+// writePythonJSON serialises a generic JSON value the same way as Python's
+// `json.dumps(value, sort_keys=True)`: keys are sorted, items are separated by
+// ", " and keys by ": ", and every non-ASCII character is escaped.
+func writePythonJSON(buf *bytes.Buffer, value any) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		if v {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case json.Number:
+		buf.WriteString(v.String())
+	case string:
+		writePythonString(buf, v)
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if err := writePythonJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case intKeyedMap:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.SortFunc(keys, func(a, b string) int {
+			aInt, _ := strconv.ParseUint(a, 10, 64)
+			bInt, _ := strconv.ParseUint(b, 10, 64)
+
+			return cmp.Compare(aInt, bInt)
+		})
+
+		return writePythonObject(buf, keys, v)
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		return writePythonObject(buf, keys, v)
+	default:
+		return fmt.Errorf("unexpected JSON value of type %T", value)
+	}
+
+	return nil
+}
+
+// writePythonObject writes the entries of a JSON object in the given key order.
+func writePythonObject(buf *bytes.Buffer, keys []string, object map[string]any) error {
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		writePythonString(buf, key)
+		buf.WriteString(": ")
+		if err := writePythonJSON(buf, object[key]); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+
+	return nil
+}
+
+// writePythonString writes a JSON string escaped like Python's json module does
+// with `ensure_ascii=True`.
+func writePythonString(buf *bytes.Buffer, s string) {
+	const hexDigits = "0123456789abcdef"
+
+	writeEscapedRune := func(r rune) {
+		buf.WriteString(`\u`)
+		for shift := 12; shift >= 0; shift -= 4 {
+			buf.WriteByte(hexDigits[(r>>shift)&0xf])
+		}
+	}
+
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"':
+			buf.WriteString(`\"`)
+		case r == '\\':
+			buf.WriteString(`\\`)
+		case r == '\n':
+			buf.WriteString(`\n`)
+		case r == '\r':
+			buf.WriteString(`\r`)
+		case r == '\t':
+			buf.WriteString(`\t`)
+		case r == '\b':
+			buf.WriteString(`\b`)
+		case r == '\f':
+			buf.WriteString(`\f`)
+		case r >= ' ' && r <= '~':
+			buf.WriteRune(r)
+		case r > 0xffff:
+			high, low := utf16.EncodeRune(r)
+			writeEscapedRune(high)
+			writeEscapedRune(low)
+		default:
+			writeEscapedRune(r)
+		}
+	}
+	buf.WriteByte('"')
+}
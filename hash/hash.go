@@ -14,6 +14,7 @@ var (
 	prefixInvoke        = new(felt.Felt).SetBytes([]byte("invoke"))
 	prefixDeclare       = new(felt.Felt).SetBytes([]byte("declare"))
 	prefixDeployAccount = new(felt.Felt).SetBytes([]byte("deploy_account"))
+	prefixL1Handler     = new(felt.Felt).SetBytes([]byte("l1_handler"))
 )
 
 var (
@@ -790,6 +791,40 @@ func TransactionHashDeployAccountV3(
 	), nil
 }
 
+// TransactionHashL1HandlerV0 calculates the transaction hash for a L1 handler
+// V0 transaction, the L2 transaction executing a message sent from L1.
+//
+// Parameters:
+//   - txn: The L1 handler transaction to calculate the hash for. The first
+//     element of the calldata is the L1 address sending the message.
+//   - chainID: The chain ID as a *felt.Felt
+//
+// Returns:
+//   - *felt.Felt: the calculated transaction hash
+//   - error: an error if any
+func TransactionHashL1HandlerV0(txn *rpc.L1HandlerTxn, chainID *felt.Felt) (*felt.Felt, error) {
+	if txn.Nonce == "" || len(txn.Calldata) == 0 || txn.ContractAddress == nil ||
+		txn.EntryPointSelector == nil {
+		return nil, ErrNotAllParametersSet
+	}
+
+	nonce, err := new(felt.Felt).SetString(txn.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return CalculateDeprecatedTransactionHashCommon(
+		prefixL1Handler,
+		&felt.Zero,
+		txn.ContractAddress,
+		txn.EntryPointSelector,
+		curve.PedersenArray(txn.Calldata...),
+		&felt.Zero,
+		chainID,
+		[]*felt.Felt{nonce},
+	), nil
+}
+
 func TipAndResourcesHash(
 	tip uint64,
 	resourceBounds *rpc.ResourceBoundsMapping,
@@ -159,4 +159,55 @@ func TestClassHashes(t *testing.T) {
 	})
 }
 
+// TestDeprecatedClassHash verifies the DeprecatedClassHash function against
+// legacy Cairo 0 classes declared on Starknet, whose file names are their class hashes.
+func TestDeprecatedClassHash(t *testing.T) {
+	type testSetType struct {
+		Description       string
+		FilePath          string
+		Subfield          []string
+		ExpectedClassHash string
+	}
+
+	testSet := []testSetType{
+		{
+			Description:       "compiled before Cairo 0.10.0, no 'compiler_version' field",
+			FilePath:          "./testData/deprecated_0x10455c752b86932ce552f2b0fe81a880746649b9aee7e0d842bf3f52378f9f8.json",
+			ExpectedClassHash: "0x10455c752b86932ce552f2b0fe81a880746649b9aee7e0d842bf3f52378f9f8",
+		},
+		{
+			Description:       "Cairo 0.10.3, encoded program from the RPC",
+			FilePath:          "../rpc/testData/class/0x036c7e49a16f8fc760a6fbdf71dde543d98be1fee2eda5daff59a0eeae066ed9.json",
+			Subfield:          []string{"result"},
+			ExpectedClassHash: "0x36c7e49a16f8fc760a6fbdf71dde543d98be1fee2eda5daff59a0eeae066ed9",
+		},
+		{
+			Description:       "Cairo 0.12.3, encoded program",
+			FilePath:          "../contracts/testData/0x01b661756bf7d16210fc611626e1af4569baa1781ffc964bd018f4585ae241c1.json",
+			ExpectedClassHash: "0x1b661756bf7d16210fc611626e1af4569baa1781ffc964bd018f4585ae241c1",
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Description, func(t *testing.T) {
+			class := internalUtils.TestUnmarshalJSONFileToType[contracts.DeprecatedContractClass](
+				t,
+				test.FilePath,
+				test.Subfield...,
+			)
+
+			hashResult, err := hash.DeprecatedClassHash(&class)
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedClassHash, hashResult.String())
+		})
+	}
+
+	t.Run("invalid program", func(t *testing.T) {
+		class := contracts.DeprecatedContractClass{Program: "not a valid program"}
+
+		_, err := hash.DeprecatedClassHash(&class)
+		require.ErrorIs(t, err, hash.ErrInvalidDeprecatedProgram)
+	})
+}
+
 // Note: Tests for TransactionHash... methods are located in the account_test.go file from the account package
@@ -135,10 +135,10 @@ func (mr *MockRPCProviderMockRecorder) BlockTransactionCount(ctx, blockID any) *
 }
 
 // BlockWithReceipts mocks base method.
-func (m *MockRPCProvider) BlockWithReceipts(ctx context.Context, blockID rpc.BlockID) (any, error) {
+func (m *MockRPCProvider) BlockWithReceipts(ctx context.Context, blockID rpc.BlockID) (rpc.BlockView, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "BlockWithReceipts", ctx, blockID)
-	ret0, _ := ret[0].(any)
+	ret0, _ := ret[0].(rpc.BlockView)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -150,10 +150,10 @@ func (mr *MockRPCProviderMockRecorder) BlockWithReceipts(ctx, blockID any) *gomo
 }
 
 // BlockWithTxHashes mocks base method.
-func (m *MockRPCProvider) BlockWithTxHashes(ctx context.Context, blockID rpc.BlockID) (any, error) {
+func (m *MockRPCProvider) BlockWithTxHashes(ctx context.Context, blockID rpc.BlockID) (rpc.BlockView, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "BlockWithTxHashes", ctx, blockID)
-	ret0, _ := ret[0].(any)
+	ret0, _ := ret[0].(rpc.BlockView)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -165,10 +165,10 @@ func (mr *MockRPCProviderMockRecorder) BlockWithTxHashes(ctx, blockID any) *gomo
 }
 
 // BlockWithTxs mocks base method.
-func (m *MockRPCProvider) BlockWithTxs(ctx context.Context, blockID rpc.BlockID) (any, error) {
+func (m *MockRPCProvider) BlockWithTxs(ctx context.Context, blockID rpc.BlockID) (rpc.BlockView, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "BlockWithTxs", ctx, blockID)
-	ret0, _ := ret[0].(any)
+	ret0, _ := ret[0].(rpc.BlockView)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -269,6 +269,51 @@ func (mr *MockRPCProviderMockRecorder) CompiledCasm(ctx, classHash any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompiledCasm", reflect.TypeOf((*MockRPCProvider)(nil).CompiledCasm), ctx, classHash)
 }
 
+// ConfirmedBlockWithReceipts mocks base method.
+func (m *MockRPCProvider) ConfirmedBlockWithReceipts(ctx context.Context, blockID rpc.ConfirmedBlockID) (*rpc.BlockWithReceipts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmedBlockWithReceipts", ctx, blockID)
+	ret0, _ := ret[0].(*rpc.BlockWithReceipts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmedBlockWithReceipts indicates an expected call of ConfirmedBlockWithReceipts.
+func (mr *MockRPCProviderMockRecorder) ConfirmedBlockWithReceipts(ctx, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmedBlockWithReceipts", reflect.TypeOf((*MockRPCProvider)(nil).ConfirmedBlockWithReceipts), ctx, blockID)
+}
+
+// ConfirmedBlockWithTxHashes mocks base method.
+func (m *MockRPCProvider) ConfirmedBlockWithTxHashes(ctx context.Context, blockID rpc.ConfirmedBlockID) (*rpc.BlockTxHashes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmedBlockWithTxHashes", ctx, blockID)
+	ret0, _ := ret[0].(*rpc.BlockTxHashes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmedBlockWithTxHashes indicates an expected call of ConfirmedBlockWithTxHashes.
+func (mr *MockRPCProviderMockRecorder) ConfirmedBlockWithTxHashes(ctx, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmedBlockWithTxHashes", reflect.TypeOf((*MockRPCProvider)(nil).ConfirmedBlockWithTxHashes), ctx, blockID)
+}
+
+// ConfirmedBlockWithTxs mocks base method.
+func (m *MockRPCProvider) ConfirmedBlockWithTxs(ctx context.Context, blockID rpc.ConfirmedBlockID) (*rpc.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmedBlockWithTxs", ctx, blockID)
+	ret0, _ := ret[0].(*rpc.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmedBlockWithTxs indicates an expected call of ConfirmedBlockWithTxs.
+func (mr *MockRPCProviderMockRecorder) ConfirmedBlockWithTxs(ctx, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmedBlockWithTxs", reflect.TypeOf((*MockRPCProvider)(nil).ConfirmedBlockWithTxs), ctx, blockID)
+}
+
 // EstimateFee mocks base method.
 func (m *MockRPCProvider) EstimateFee(ctx context.Context, requests []rpc.BroadcastTxn, simulationFlags []rpc.SimulationFlag, blockID rpc.BlockID) ([]rpc.FeeEstimation, error) {
 	m.ctrl.T.Helper()
@@ -344,6 +389,51 @@ func (mr *MockRPCProviderMockRecorder) Nonce(ctx, blockID, contractAddress any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Nonce", reflect.TypeOf((*MockRPCProvider)(nil).Nonce), ctx, blockID, contractAddress)
 }
 
+// PreConfirmedBlockWithReceipts mocks base method.
+func (m *MockRPCProvider) PreConfirmedBlockWithReceipts(ctx context.Context) (*rpc.PreConfirmedBlockWithReceipts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreConfirmedBlockWithReceipts", ctx)
+	ret0, _ := ret[0].(*rpc.PreConfirmedBlockWithReceipts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreConfirmedBlockWithReceipts indicates an expected call of PreConfirmedBlockWithReceipts.
+func (mr *MockRPCProviderMockRecorder) PreConfirmedBlockWithReceipts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreConfirmedBlockWithReceipts", reflect.TypeOf((*MockRPCProvider)(nil).PreConfirmedBlockWithReceipts), ctx)
+}
+
+// PreConfirmedBlockWithTxHashes mocks base method.
+func (m *MockRPCProvider) PreConfirmedBlockWithTxHashes(ctx context.Context) (*rpc.PreConfirmedBlockTxHashes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreConfirmedBlockWithTxHashes", ctx)
+	ret0, _ := ret[0].(*rpc.PreConfirmedBlockTxHashes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreConfirmedBlockWithTxHashes indicates an expected call of PreConfirmedBlockWithTxHashes.
+func (mr *MockRPCProviderMockRecorder) PreConfirmedBlockWithTxHashes(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreConfirmedBlockWithTxHashes", reflect.TypeOf((*MockRPCProvider)(nil).PreConfirmedBlockWithTxHashes), ctx)
+}
+
+// PreConfirmedBlockWithTxs mocks base method.
+func (m *MockRPCProvider) PreConfirmedBlockWithTxs(ctx context.Context) (*rpc.PreConfirmedBlock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreConfirmedBlockWithTxs", ctx)
+	ret0, _ := ret[0].(*rpc.PreConfirmedBlock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreConfirmedBlockWithTxs indicates an expected call of PreConfirmedBlockWithTxs.
+func (mr *MockRPCProviderMockRecorder) PreConfirmedBlockWithTxs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreConfirmedBlockWithTxs", reflect.TypeOf((*MockRPCProvider)(nil).PreConfirmedBlockWithTxs), ctx)
+}
+
 // SimulateTransactions mocks base method.
 func (m *MockRPCProvider) SimulateTransactions(ctx context.Context, blockID rpc.BlockID, txns []rpc.BroadcastTxn, simulationFlags []rpc.SimulationFlag) ([]rpc.SimulatedTransaction, error) {
 	m.ctrl.T.Helper()
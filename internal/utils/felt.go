@@ -333,3 +333,49 @@ func U256FeltToHex(u256 []*felt.Felt) (string, error) {
 
 	return hexStr, nil
 }
+
+// ErrInvalidU256 is returned when a value doesn't fit in a Cairo u256.
+var ErrInvalidU256 = errors.New("invalid u256")
+
+// u128Bits is the number of bits of each half of a u256
+const u128Bits = 128
+
+// BigIntToU256Felts splits a value into the low and high 128-bit felts of a
+// Cairo u256.
+//
+// Parameters:
+//   - value: the value to split
+//
+// Returns:
+//   - []*felt.Felt: a slice containing two felt.Felt values [low, high]
+//   - error: ErrInvalidU256 if the value is nil, negative or overflows 256 bits
+func BigIntToU256Felts(value *big.Int) ([]*felt.Felt, error) {
+	if value == nil || value.Sign() < 0 || value.BitLen() > 2*u128Bits {
+		return nil, fmt.Errorf("%w: %v overflows u256", ErrInvalidU256, value)
+	}
+
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), u128Bits), big.NewInt(1))
+	low := new(big.Int).And(value, mask)
+	high := new(big.Int).Rsh(value, u128Bits)
+
+	return []*felt.Felt{BigIntToFelt(low), BigIntToFelt(high)}, nil
+}
+
+// U256FeltsToBigInt joins the low and high 128-bit felts of a Cairo u256.
+//
+// Parameters:
+//   - low: the 128 least significant bits
+//   - high: the 128 most significant bits
+//
+// Returns:
+//   - *big.Int: the u256 value
+//   - error: ErrInvalidU256 if a felt doesn't fit in 128 bits
+func U256FeltsToBigInt(low, high *felt.Felt) (*big.Int, error) {
+	lowInt := FeltToBigInt(low)
+	highInt := FeltToBigInt(high)
+	if lowInt.BitLen() > u128Bits || highInt.BitLen() > u128Bits {
+		return nil, fmt.Errorf("%w: u256 felts overflow 128 bits", ErrInvalidU256)
+	}
+
+	return highInt.Lsh(highInt, u128Bits).Or(highInt, lowInt), nil
+}
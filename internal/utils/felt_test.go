@@ -331,3 +331,34 @@ func TestU256FeltToHex(t *testing.T) {
 		})
 	}
 }
+
+func TestU256Felts(t *testing.T) {
+	maxU256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	values := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(7),
+		new(big.Int).Lsh(big.NewInt(1), 128),
+		maxU256,
+	}
+
+	for _, value := range values {
+		t.Run(value.String(), func(t *testing.T) {
+			felts, err := BigIntToU256Felts(value)
+			require.NoError(t, err)
+			require.Len(t, felts, 2)
+
+			result, err := U256FeltsToBigInt(felts[0], felts[1])
+			require.NoError(t, err)
+			assert.Zero(t, value.Cmp(result))
+		})
+	}
+
+	overflow := new(big.Int).Add(maxU256, big.NewInt(1))
+	for _, value := range []*big.Int{nil, big.NewInt(-1), overflow} {
+		_, err := BigIntToU256Felts(value)
+		require.ErrorIs(t, err, ErrInvalidU256)
+	}
+
+	_, err := U256FeltsToBigInt(new(felt.Felt), BigIntToFelt(maxU256))
+	require.ErrorIs(t, err, ErrInvalidU256)
+}
@@ -0,0 +1,166 @@
+package messaging
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var (
+	ErrNotMessageToL2Log = errors.New("not a LogMessageToL2 log")
+	ErrInvalidLog        = errors.New("invalid log")
+)
+
+// LogMessageToL2Topic is the topic of the LogMessageToL2 event of the Starknet
+// core contract, the keccak256 hash of
+// "LogMessageToL2(address,uint256,uint256,uint256[],uint256,uint256)".
+const LogMessageToL2Topic = "0xdb80dd488acf86d17c747445b0eabb5d57c541d3bd7b6b87af987858e5066b2b"
+
+// The number of topics of a LogMessageToL2 log: the event topic, the L1
+// sender, the L2 recipient and the selector
+const logMessageToL2Topics = 4
+
+// The minimum number of words of the data of a LogMessageToL2 log: the offset
+// of the payload, the nonce and the fee
+const logMessageToL2Words = 3
+
+// The size of an ABI-encoded word
+const wordSize = 32
+
+// L1Log is a log emitted on L1, in the format of the eth_getLogs and
+// eth_getTransactionReceipt methods of the Ethereum JSON-RPC API.
+type L1Log struct {
+	// The address of the contract emitting the log
+	Address string `json:"address"`
+	// The indexed topics of the log, as 0x-prefixed 32-byte hexadecimal strings
+	Topics []string `json:"topics"`
+	// The ABI-encoded non-indexed fields of the log
+	Data string `json:"data"`
+	// The hash of the transaction emitting the log
+	TransactionHash string `json:"transactionHash"`
+	// Whether the log was removed by a chain reorganisation
+	Removed bool `json:"removed"`
+}
+
+// ParseLogMessageToL2 parses the message of a LogMessageToL2 log.
+//
+// Parameters:
+//   - log: the log, emitted by the Starknet core contract
+//
+// Returns:
+//   - *L1ToL2Message: the message
+//   - error: ErrNotMessageToL2Log if the log isn't a LogMessageToL2 log, or
+//     ErrInvalidLog if it can't be decoded
+func ParseLogMessageToL2(log *L1Log) (*L1ToL2Message, error) {
+	if len(log.Topics) == 0 || !strings.EqualFold(log.Topics[0], LogMessageToL2Topic) {
+		return nil, ErrNotMessageToL2Log
+	}
+	if len(log.Topics) != logMessageToL2Topics {
+		return nil, fmt.Errorf("%w: %d topics", ErrInvalidLog, len(log.Topics))
+	}
+
+	indexed := make([]*felt.Felt, len(log.Topics)-1)
+	for i, topic := range log.Topics[1:] {
+		word, err := decodeHex(topic)
+		if err != nil || len(word) != wordSize {
+			return nil, fmt.Errorf("%w: topic %s", ErrInvalidLog, topic)
+		}
+		if indexed[i], err = wordToFelt(word); err != nil {
+			return nil, err
+		}
+	}
+
+	// the data encodes (uint256[] payload, uint256 nonce, uint256 fee)
+	data, err := decodeHex(log.Data)
+	if err != nil || len(data)%wordSize != 0 || len(data) < logMessageToL2Words*wordSize {
+		return nil, fmt.Errorf("%w: data %s", ErrInvalidLog, log.Data)
+	}
+	words := make([]*felt.Felt, len(data)/wordSize)
+	for i := range words {
+		if words[i], err = wordToFelt(data[i*wordSize : (i+1)*wordSize]); err != nil {
+			return nil, err
+		}
+	}
+	payload, err := decodeArray(words, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &L1ToL2Message{
+		FromAddress: indexed[0],
+		ToAddress:   indexed[1],
+		Selector:    indexed[2],
+		Payload:     payload,
+		Nonce:       words[1],
+		Fee:         words[2],
+		L1TxnHash:   log.TransactionHash,
+	}, nil
+}
+
+// MessagesToL2 parses the messages of the LogMessageToL2 logs, ignoring the
+// other logs and the removed ones. The logs should be filtered by the address
+// of the Starknet core contract beforehand, as any contract can emit a log
+// with the LogMessageToL2 topic.
+//
+// Parameters:
+//   - logs: the logs, e.g. of the receipt of the L1 transaction sending the
+//     messages
+//
+// Returns:
+//   - []*L1ToL2Message: the messages, in the order of the logs
+//   - error: ErrInvalidLog if a LogMessageToL2 log can't be decoded
+func MessagesToL2(logs []L1Log) ([]*L1ToL2Message, error) {
+	var messages []*L1ToL2Message
+	for i := range logs {
+		if logs[i].Removed {
+			continue
+		}
+		msg, err := ParseLogMessageToL2(&logs[i])
+		if errors.Is(err, ErrNotMessageToL2Log) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// decodeArray decodes the ABI-encoded dynamic array whose offset is at the
+// given word.
+func decodeArray(words []*felt.Felt, offsetWord int) ([]*felt.Felt, error) {
+	if offsetWord >= len(words) {
+		return nil, fmt.Errorf("%w: missing array offset", ErrInvalidLog)
+	}
+	offset := words[offsetWord].Uint64()
+	if offset%wordSize != 0 || offset/wordSize >= uint64(len(words)) {
+		return nil, fmt.Errorf("%w: array offset %d", ErrInvalidLog, offset)
+	}
+	start := offset / wordSize
+	length := words[start].Uint64()
+	if length > uint64(len(words))-start-1 {
+		return nil, fmt.Errorf("%w: array length %d", ErrInvalidLog, length)
+	}
+
+	return words[start+1 : start+1+length], nil
+}
+
+// wordToFelt converts a 32-byte word into a felt.
+func wordToFelt(word []byte) (*felt.Felt, error) {
+	value := new(felt.Felt)
+	if err := value.SetBytesCanonical(word); err != nil {
+		return nil, fmt.Errorf("%w: 0x%x overflows a felt", ErrInvalidLog, word)
+	}
+
+	return value, nil
+}
+
+// decodeHex decodes a 0x-prefixed hexadecimal string.
+func decodeHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
@@ -0,0 +1,194 @@
+// Package messaging computes the hashes of the messages sent between Starknet
+// and its settlement layer (L1), and follows the L1 to L2 messages until their
+// execution on Starknet.
+//
+// The L1 to L2 messages are read from the LogMessageToL2 events emitted by the
+// Starknet core contract on L1:
+//
+//	messages, err := messaging.MessagesToL2(receipt.Logs)
+//	latest := rpc.WithBlockTag(rpc.BlockTagLatest)
+//	fee, err := messaging.EstimateFee(ctx, provider, messages[0], latest)
+//	lifecycle, err := messaging.WaitForMessage(
+//		ctx, provider, messages[0], messaging.StageAcceptedOnL2, time.Second,
+//	)
+//
+// The L2 to L1 messages are hashed from the messages sent by a transaction, as
+// reported in its receipt, to be consumed on L1.
+package messaging
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"golang.org/x/crypto/sha3"
+)
+
+// MessageHash is the keccak256 hash identifying a message in the Starknet core
+// contract on L1.
+type MessageHash [32]byte
+
+// String returns the hexadecimal representation of the hash, with the 0x
+// prefix.
+func (h MessageHash) String() string {
+	return "0x" + hex.EncodeToString(h[:])
+}
+
+// L1ToL2Message is a message sent from L1 to a contract on Starknet, executed
+// by an L1 handler transaction.
+type L1ToL2Message struct {
+	// The L1 address sending the message
+	FromAddress *felt.Felt
+	// The L2 contract receiving the message
+	ToAddress *felt.Felt
+	// The selector of the l1_handler function receiving the message
+	Selector *felt.Felt
+	// The payload of the message
+	Payload []*felt.Felt
+	// The nonce of the message, assigned by the Starknet core contract
+	Nonce *felt.Felt
+	// The fee paid on L1 for the message, in wei
+	Fee *felt.Felt
+	// The hash of the L1 transaction that sent the message. Empty if the
+	// message wasn't read from a log.
+	L1TxnHash string
+}
+
+// Hash calculates the hash of the message, as computed by the
+// StarknetMessaging.sol contract.
+//
+// Returns:
+//   - MessageHash: the hash of the message
+func (m *L1ToL2Message) Hash() MessageHash {
+	return keccakWords(
+		append(
+			[]*felt.Felt{m.FromAddress, m.ToAddress, m.Nonce, m.Selector, lenFelt(m.Payload)},
+			m.Payload...,
+		)...,
+	)
+}
+
+// L1HandlerTxn returns the L1 handler transaction executing the message on
+// Starknet.
+//
+// Returns:
+//   - rpc.L1HandlerTxn: the L1 handler transaction
+func (m *L1ToL2Message) L1HandlerTxn() rpc.L1HandlerTxn {
+	return rpc.L1HandlerTxn{
+		Type:    rpc.TransactionTypeL1Handler,
+		Version: rpc.TransactionV0,
+		Nonce:   m.Nonce.String(),
+		FunctionCall: rpc.FunctionCall{
+			ContractAddress:    m.ToAddress,
+			EntryPointSelector: m.Selector,
+			Calldata:           append([]*felt.Felt{m.FromAddress}, m.Payload...),
+		},
+	}
+}
+
+// TransactionHash calculates the hash of the L1 handler transaction executing
+// the message on Starknet.
+//
+// Parameters:
+//   - chainID: the chain ID of the Starknet network receiving the message
+//
+// Returns:
+//   - *felt.Felt: the hash of the L1 handler transaction
+//   - error: an error if any
+func (m *L1ToL2Message) TransactionHash(chainID *felt.Felt) (*felt.Felt, error) {
+	txn := m.L1HandlerTxn()
+
+	return hash.TransactionHashL1HandlerV0(&txn, chainID)
+}
+
+// MsgFromL1 returns the message as the input of rpc.Provider.EstimateMessageFee.
+//
+// Returns:
+//   - rpc.MsgFromL1: the message
+func (m *L1ToL2Message) MsgFromL1() rpc.MsgFromL1 {
+	return rpc.MsgFromL1{
+		FromAddress: fmt.Sprintf("0x%040x", m.FromAddress.BigInt(new(big.Int))),
+		ToAddress:   m.ToAddress,
+		Selector:    m.Selector,
+		Payload:     m.Payload,
+	}
+}
+
+// EstimateFee estimates the L2 fee of the L1 handler transaction executing a
+// message. The fee is paid on L1 when the message is sent.
+//
+// Parameters:
+//   - ctx: the context
+//   - provider: the provider of the Starknet network receiving the message
+//   - msg: the message
+//   - blockID: the block to estimate the fee in
+//
+// Returns:
+//   - rpc.MessageFeeEstimation: the estimated fee
+//   - error: an error if any
+func EstimateFee(
+	ctx context.Context,
+	provider rpc.RPCProvider,
+	msg *L1ToL2Message,
+	blockID rpc.BlockID,
+) (rpc.MessageFeeEstimation, error) {
+	return provider.EstimateMessageFee(ctx, msg.MsgFromL1(), blockID)
+}
+
+// L2ToL1MessageHash calculates the hash of a message sent from Starknet to L1,
+// as computed by the StarknetMessaging.sol contract to consume the message.
+//
+// Parameters:
+//   - msg: the message, as reported by the receipt of the transaction sending it
+//
+// Returns:
+//   - MessageHash: the hash of the message
+func L2ToL1MessageHash(msg *rpc.MsgToL1) MessageHash {
+	return keccakWords(
+		append(
+			[]*felt.Felt{msg.FromAddress, msg.ToAddress, lenFelt(msg.Payload)},
+			msg.Payload...,
+		)...,
+	)
+}
+
+// MessagesSentHashes calculates the hashes of the messages sent to L1 by a
+// transaction.
+//
+// Parameters:
+//   - receipt: the receipt of the transaction
+//
+// Returns:
+//   - []MessageHash: the hashes of the messages, in the order they were sent
+func MessagesSentHashes(receipt *rpc.TransactionReceipt) []MessageHash {
+	hashes := make([]MessageHash, len(receipt.MessagesSent))
+	for i := range receipt.MessagesSent {
+		hashes[i] = L2ToL1MessageHash(&receipt.MessagesSent[i])
+	}
+
+	return hashes
+}
+
+// keccakWords returns the keccak256 hash of the felts encoded as 32-byte
+// words, i.e. the abi.encodePacked encoding of uint256 values.
+func keccakWords(words ...*felt.Felt) MessageHash {
+	digest := sha3.NewLegacyKeccak256()
+	for _, word := range words {
+		bytes := word.Bytes()
+		digest.Write(bytes[:])
+	}
+
+	var result MessageHash
+	digest.Sum(result[:0])
+
+	return result
+}
+
+// lenFelt returns the length of a payload as a felt.
+func lenFelt(payload []*felt.Felt) *felt.Felt {
+	return new(felt.Felt).SetUint64(uint64(len(payload)))
+}
@@ -0,0 +1,237 @@
+package messaging_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/internal/tests/mocks/rpcv10mock"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/NethermindEth/starknet.go/messaging"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/sha3"
+)
+
+// l1Receipt is the receipt of an L1 transaction, as returned by the
+// eth_getTransactionReceipt method.
+type l1Receipt struct {
+	Logs []messaging.L1Log `json:"logs"`
+}
+
+// messageFixtures are L1 transactions sending a message to Starknet, with the
+// hashes of the message and of its L1 handler transaction.
+var messageFixtures = []struct {
+	Description      string
+	FilePath         string
+	ChainID          string
+	MessageHash      string
+	L1HandlerTxnHash string
+}{
+	{
+		// https://etherscan.io/tx/0x5780c6fe46f958a7ebf9308e6db16d819ff9e06b1e88f9e718c50cde10898f38
+		Description:      "mainnet",
+		FilePath:         "./testData/l1_receipt_mainnet.json",
+		ChainID:          "SN_MAIN",
+		MessageHash:      "0xd8824a75a588f0726d7d83b3e9560810c763043e979fdb77b11c1a51a991235d",
+		L1HandlerTxnHash: "0xc470e30f97f64255a62215633e35a7c6ae10332a9011776dde1143ab0202c3",
+	},
+	{
+		// https://sepolia.etherscan.io/tx/0xeafadb9958437ef43ce7ed19f8ac0c8071c18f4a55fd778cecc23d8b6f86026f
+		Description:      "sepolia",
+		FilePath:         "./testData/l1_receipt_sepolia.json",
+		ChainID:          "SN_SEPOLIA",
+		MessageHash:      "0x162e74b4ccf7e350a1668de856f892057e0da112e1ad2262603306ee5dffb158",
+		L1HandlerTxnHash: "0x304c78cccf0569159d4b2aff2117f060509b7c6d590ae740d2031d1eb507b10",
+	},
+}
+
+// loadMessage returns the message sent by the L1 transaction of a fixture.
+func loadMessage(t *testing.T, filePath string) *messaging.L1ToL2Message {
+	t.Helper()
+
+	receipt := internalUtils.TestUnmarshalJSONFileToType[l1Receipt](t, filePath)
+	messages, err := messaging.MessagesToL2(receipt.Logs)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+
+	return messages[0]
+}
+
+// TestL1ToL2Message tests the parsing of the messages from the L1 logs, and
+// the hashes of the messages and of their L1 handler transactions.
+func TestL1ToL2Message(t *testing.T) {
+	for _, test := range messageFixtures {
+		t.Run(test.Description, func(t *testing.T) {
+			msg := loadMessage(t, test.FilePath)
+			assert.NotEmpty(t, msg.L1TxnHash)
+
+			assert.Equal(t, test.MessageHash, msg.Hash().String())
+
+			txnHash, err := msg.TransactionHash(new(felt.Felt).SetBytes([]byte(test.ChainID)))
+			require.NoError(t, err)
+			assert.Equal(t, test.L1HandlerTxnHash, txnHash.String())
+
+			txn := msg.L1HandlerTxn()
+			assert.Equal(t, msg.ToAddress, txn.ContractAddress)
+			assert.Equal(t, msg.FromAddress, txn.Calldata[0])
+			assert.Equal(t, msg.Payload, txn.Calldata[1:])
+		})
+	}
+
+	t.Run("mainnet fields", func(t *testing.T) {
+		msg := loadMessage(t, messageFixtures[0].FilePath)
+		assert.Equal(t, "0x7ad94e71308bb65c6bc9df35cc69cc9f953d69e5", msg.FromAddress.String())
+		assert.Equal(t, "0x195c3c", msg.Nonce.String())
+		assert.Equal(t, "0x48c27395000", msg.Fee.String())
+		assert.Equal(t, internalUtils.TestHexArrToFelt(t, []string{
+			"0xc3b49b03a6d9d71f8d3fa6582437374e650f3c46",
+			"0x3a1bf949fa7424b4bd48661a62ded82bc6f6e3c5f5c6d5904c07e6143187d1b",
+			"0x61",
+		}), msg.Payload)
+		assert.Equal(t, "0x7ad94e71308bb65c6bc9df35cc69cc9f953d69e5", msg.MsgFromL1().FromAddress)
+	})
+}
+
+// TestParseLogMessageToL2 tests the errors of the parsing of the L1 logs.
+func TestParseLogMessageToL2(t *testing.T) {
+	receipt := internalUtils.TestUnmarshalJSONFileToType[l1Receipt](
+		t,
+		messageFixtures[0].FilePath,
+	)
+	require.Len(t, receipt.Logs, 2)
+
+	// the second log is emitted by the bridge
+	_, err := messaging.ParseLogMessageToL2(&receipt.Logs[1])
+	require.ErrorIs(t, err, messaging.ErrNotMessageToL2Log)
+
+	testSet := []struct {
+		Description string
+		Modify      func(log *messaging.L1Log)
+	}{
+		{
+			Description: "missing topic",
+			Modify:      func(log *messaging.L1Log) { log.Topics = log.Topics[:3] },
+		},
+		{
+			Description: "invalid topic",
+			Modify:      func(log *messaging.L1Log) { log.Topics[1] = "0x1234" },
+		},
+		{
+			Description: "truncated data",
+			Modify:      func(log *messaging.L1Log) { log.Data = log.Data[:len(log.Data)-64] },
+		},
+		{
+			Description: "invalid payload length",
+			Modify: func(log *messaging.L1Log) {
+				log.Data = log.Data[:2+3*64] + strings.Repeat("0", 63) + "9" + log.Data[2+4*64:]
+			},
+		},
+		{
+			Description: "word overflowing a felt",
+			Modify: func(log *messaging.L1Log) {
+				log.Topics[2] = "0x" + strings.Repeat("f", 64)
+			},
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Description, func(t *testing.T) {
+			log := receipt.Logs[0]
+			log.Topics = append([]string(nil), log.Topics...)
+			test.Modify(&log)
+
+			_, err := messaging.ParseLogMessageToL2(&log)
+			require.ErrorIs(t, err, messaging.ErrInvalidLog)
+
+			_, err = messaging.MessagesToL2([]messaging.L1Log{log})
+			require.ErrorIs(t, err, messaging.ErrInvalidLog)
+		})
+	}
+
+	t.Run("removed log", func(t *testing.T) {
+		log := receipt.Logs[0]
+		log.Removed = true
+		messages, err := messaging.MessagesToL2([]messaging.L1Log{log})
+		require.NoError(t, err)
+		assert.Empty(t, messages)
+	})
+}
+
+// TestL2ToL1MessageHash tests the hashes of the messages sent to L1, against
+// the abi.encodePacked encoding of the message.
+func TestL2ToL1MessageHash(t *testing.T) {
+	receipt := rpc.TransactionReceipt{
+		MessagesSent: []rpc.MsgToL1{
+			{
+				FromAddress: internalUtils.TestHexToFelt(t, "0x73314940630fd6dcda0d772d4c972c4e0a9946bef9dabf4ef84eda8ef542b82"),
+				ToAddress:   internalUtils.TestHexToFelt(t, "0xae0ee0a63a2ce6baeeffe56e7714fb4efe48d419"),
+				Payload: internalUtils.TestHexArrToFelt(t, []string{
+					"0x0",
+					"0xc3511006c04ef1d78af4c8e0e74ec18a6e64ff9e",
+					"0x11c37937e08000",
+					"0x0",
+				}),
+			},
+			{
+				FromAddress: internalUtils.TestHexToFelt(t, "0x1"),
+				ToAddress:   internalUtils.TestHexToFelt(t, "0x2"),
+				Payload:     []*felt.Felt{},
+			},
+		},
+	}
+
+	encoded := []string{
+		"073314940630fd6dcda0d772d4c972c4e0a9946bef9dabf4ef84eda8ef542b82" +
+			"000000000000000000000000ae0ee0a63a2ce6baeeffe56e7714fb4efe48d419" +
+			"0000000000000000000000000000000000000000000000000000000000000004" +
+			"0000000000000000000000000000000000000000000000000000000000000000" +
+			"000000000000000000000000c3511006c04ef1d78af4c8e0e74ec18a6e64ff9e" +
+			"0000000000000000000000000000000000000000000000000011c37937e08000" +
+			"0000000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+			"0000000000000000000000000000000000000000000000000000000000000002" +
+			"0000000000000000000000000000000000000000000000000000000000000000",
+	}
+	hashes := messaging.MessagesSentHashes(&receipt)
+	require.Len(t, hashes, len(encoded))
+	for i, packed := range encoded {
+		data, err := hex.DecodeString(packed)
+		require.NoError(t, err)
+		digest := sha3.NewLegacyKeccak256()
+		digest.Write(data)
+		assert.Equal(t, "0x"+hex.EncodeToString(digest.Sum(nil)), hashes[i].String())
+	}
+}
+
+// TestEstimateFee tests that the fee of a message is estimated from its L1
+// sender, L2 recipient and payload.
+func TestEstimateFee(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+
+	msg := loadMessage(t, messageFixtures[0].FilePath)
+	blockID := rpc.WithBlockTag(rpc.BlockTagLatest)
+	expected := rpc.MessageFeeEstimation{
+		FeeEstimationCommon: rpc.FeeEstimationCommon{
+			L1GasConsumed: new(felt.Felt).SetUint64(100),
+			OverallFee:    new(felt.Felt).SetUint64(1000),
+		},
+		Unit: rpc.WeiUnit,
+	}
+	mockRPCProvider.EXPECT().
+		EstimateMessageFee(t.Context(), rpc.MsgFromL1{
+			FromAddress: "0x7ad94e71308bb65c6bc9df35cc69cc9f953d69e5",
+			ToAddress:   msg.ToAddress,
+			Selector:    msg.Selector,
+			Payload:     msg.Payload,
+		}, blockID).
+		Return(expected, nil)
+
+	fee, err := messaging.EstimateFee(t.Context(), mockRPCProvider, msg, blockID)
+	require.NoError(t, err)
+	assert.Equal(t, expected, fee)
+}
@@ -0,0 +1,201 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+var ErrMessageHashMismatch = errors.New("the L1 handler transaction executes another message")
+
+// Stage is the stage of an L1 to L2 message on Starknet.
+type Stage int
+
+const (
+	// The message was sent on L1, but the node doesn't know its L1 handler
+	// transaction yet
+	StageSentOnL1 Stage = iota
+	// The L1 handler transaction is in the pre-confirmed block
+	StagePreConfirmed
+	// The L1 handler transaction is in a block accepted on L2
+	StageAcceptedOnL2
+	// The L1 handler transaction is in a block accepted on L1
+	StageAcceptedOnL1
+)
+
+// String returns the name of the stage.
+func (s Stage) String() string {
+	switch s {
+	case StageSentOnL1:
+		return "SENT_ON_L1"
+	case StagePreConfirmed:
+		return "PRE_CONFIRMED"
+	case StageAcceptedOnL2:
+		return "ACCEPTED_ON_L2"
+	case StageAcceptedOnL1:
+		return "ACCEPTED_ON_L1"
+	default:
+		return fmt.Sprintf("Stage(%d)", int(s))
+	}
+}
+
+// stageOf returns the stage of a message whose L1 handler transaction has the
+// given finality status.
+func stageOf(status rpc.TxnFinalityStatus) Stage {
+	switch status {
+	case rpc.TxnFinalityStatusPreConfirmed:
+		return StagePreConfirmed
+	case rpc.TxnFinalityStatusAcceptedOnL2:
+		return StageAcceptedOnL2
+	case rpc.TxnFinalityStatusAcceptedOnL1:
+		return StageAcceptedOnL1
+	default:
+		return StageSentOnL1
+	}
+}
+
+// Lifecycle is the state of an L1 to L2 message on Starknet.
+type Lifecycle struct {
+	// The hash of the message
+	MessageHash MessageHash
+	// The hash of the L1 handler transaction executing the message
+	L1HandlerTxnHash *felt.Felt
+	Stage            Stage
+	// The execution status of the L1 handler transaction. Empty at StageSentOnL1.
+	ExecutionStatus rpc.TxnExecutionStatus
+	// The failure reason. Only set if the L1 handler transaction was reverted.
+	FailureReason string
+	// The receipt of the L1 handler transaction. Nil at StageSentOnL1.
+	Receipt *rpc.TransactionReceiptWithBlockInfo
+}
+
+// Reverted returns whether the L1 handler transaction was reverted, i.e. the
+// message was received but not consumed on Starknet.
+func (l *Lifecycle) Reverted() bool {
+	return l.ExecutionStatus == rpc.TxnExecutionStatusREVERTED
+}
+
+// Track returns the current state of an L1 to L2 message on Starknet.
+//
+// If the hash of the L1 transaction sending the message is known, the status
+// of the message is read with rpc.Provider.MessagesStatus. The state is then
+// completed with the receipt of the L1 handler transaction.
+//
+// Parameters:
+//   - ctx: the context
+//   - provider: the provider of the Starknet network receiving the message
+//   - msg: the message
+//
+// Returns:
+//   - *Lifecycle: the state of the message
+//   - error: ErrMessageHashMismatch if the L1 handler transaction found by its
+//     hash executes another message, or an error from the provider
+func Track(ctx context.Context, provider rpc.RPCProvider, msg *L1ToL2Message) (*Lifecycle, error) {
+	chainID, err := provider.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	txnHash, err := msg.TransactionHash(new(felt.Felt).SetBytes([]byte(chainID)))
+	if err != nil {
+		return nil, err
+	}
+	lifecycle := &Lifecycle{
+		MessageHash:      msg.Hash(),
+		L1HandlerTxnHash: txnHash,
+		Stage:            StageSentOnL1,
+	}
+
+	if msg.L1TxnHash != "" {
+		statuses, err := provider.MessagesStatus(ctx, rpc.NumAsHex(msg.L1TxnHash))
+		if err != nil && !isHashNotFound(err) {
+			return nil, err
+		}
+		for _, status := range statuses {
+			if status.Hash != nil && status.Hash.Equal(txnHash) {
+				lifecycle.Stage = stageOf(status.FinalityStatus)
+				lifecycle.ExecutionStatus = status.ExecutionStatus
+				lifecycle.FailureReason = status.FailureReason
+			}
+		}
+	}
+
+	receipt, err := provider.TransactionReceipt(ctx, txnHash)
+	if isHashNotFound(err) {
+		return lifecycle, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if receipt.MessageHash != "" && !sameHash(receipt.MessageHash, lifecycle.MessageHash) {
+		return nil, fmt.Errorf(
+			"%w: %s instead of %s",
+			ErrMessageHashMismatch,
+			receipt.MessageHash,
+			lifecycle.MessageHash,
+		)
+	}
+	lifecycle.Receipt = receipt
+	lifecycle.Stage = stageOf(receipt.FinalityStatus)
+	lifecycle.ExecutionStatus = receipt.ExecutionStatus
+	lifecycle.FailureReason = receipt.RevertReason
+
+	return lifecycle, nil
+}
+
+// WaitForMessage waits until an L1 to L2 message reaches a stage on Starknet,
+// or its L1 handler transaction is reverted.
+//
+// Parameters:
+//   - ctx: the context, to stop waiting
+//   - provider: the provider of the Starknet network receiving the message
+//   - msg: the message
+//   - stage: the stage to wait for
+//   - pollInterval: the interval between the checks of the state of the message
+//
+// Returns:
+//   - *Lifecycle: the state of the message
+//   - error: the error of the context if it's done before, or an error from Track
+func WaitForMessage(
+	ctx context.Context,
+	provider rpc.RPCProvider,
+	msg *L1ToL2Message,
+	stage Stage,
+	pollInterval time.Duration,
+) (*Lifecycle, error) {
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+	for {
+		lifecycle, err := Track(ctx, provider, msg)
+		if err != nil {
+			return nil, err
+		}
+		if lifecycle.Stage >= stage || lifecycle.Reverted() {
+			return lifecycle, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// isHashNotFound returns whether the error is rpc.ErrHashNotFound.
+func isHashNotFound(err error) bool {
+	var rpcErr *rpc.RPCError
+
+	return errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrHashNotFound.Code
+}
+
+// sameHash returns whether the message hash of a receipt is the given hash.
+func sameHash(receiptHash rpc.NumAsHex, messageHash MessageHash) bool {
+	value, ok := new(big.Int).SetString(string(receiptHash), 0)
+
+	return ok && value.Cmp(new(big.Int).SetBytes(messageHash[:])) == 0
+}
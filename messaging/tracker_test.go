@@ -0,0 +1,227 @@
+package messaging_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/internal/tests/mocks/rpcv10mock"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/NethermindEth/starknet.go/messaging"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// TestTrack tests the stages of a message, from the statuses of the messages
+// sent by its L1 transaction and the receipt of its L1 handler transaction.
+func TestTrack(t *testing.T) {
+	fixture := messageFixtures[0]
+	txnHash := internalUtils.TestHexToFelt(t, fixture.L1HandlerTxnHash)
+	otherTxnHash := new(felt.Felt).SetUint64(1)
+
+	receipt := func(
+		finality rpc.TxnFinalityStatus,
+		execution rpc.TxnExecutionStatus,
+		messageHash string,
+	) *rpc.TransactionReceiptWithBlockInfo {
+		return &rpc.TransactionReceiptWithBlockInfo{
+			TransactionReceipt: rpc.TransactionReceipt{
+				Hash:            txnHash,
+				Type:            rpc.TransactionTypeL1Handler,
+				FinalityStatus:  finality,
+				ExecutionStatus: execution,
+				MessageHash:     rpc.NumAsHex(messageHash),
+			},
+			BlockNumber: 763497,
+		}
+	}
+
+	testSet := []struct {
+		Description      string
+		L1TxnHash        string
+		Statuses         []rpc.MessageStatus
+		StatusesErr      error
+		Receipt          *rpc.TransactionReceiptWithBlockInfo
+		ReceiptErr       error
+		ExpectedStage    messaging.Stage
+		ExpectedReverted bool
+		ExpectedErr      error
+	}{
+		{
+			Description:   "L1 transaction unknown to the node",
+			L1TxnHash:     "0x5780c6fe46f958a7ebf9308e6db16d819ff9e06b1e88f9e718c50cde10898f38",
+			StatusesErr:   rpc.ErrHashNotFound,
+			ReceiptErr:    rpc.ErrHashNotFound,
+			ExpectedStage: messaging.StageSentOnL1,
+		},
+		{
+			Description: "other message of the L1 transaction",
+			L1TxnHash:   "0x5780c6fe46f958a7ebf9308e6db16d819ff9e06b1e88f9e718c50cde10898f38",
+			Statuses: []rpc.MessageStatus{{
+				Hash:            otherTxnHash,
+				FinalityStatus:  rpc.TxnFinalityStatusAcceptedOnL2,
+				ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+			}},
+			ReceiptErr:    rpc.ErrHashNotFound,
+			ExpectedStage: messaging.StageSentOnL1,
+		},
+		{
+			Description: "receipt not available yet",
+			L1TxnHash:   "0x5780c6fe46f958a7ebf9308e6db16d819ff9e06b1e88f9e718c50cde10898f38",
+			Statuses: []rpc.MessageStatus{{
+				Hash:            txnHash,
+				FinalityStatus:  rpc.TxnFinalityStatusPreConfirmed,
+				ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+			}},
+			ReceiptErr:    rpc.ErrHashNotFound,
+			ExpectedStage: messaging.StagePreConfirmed,
+		},
+		{
+			Description: "accepted on L1",
+			L1TxnHash:   "0x5780c6fe46f958a7ebf9308e6db16d819ff9e06b1e88f9e718c50cde10898f38",
+			Statuses: []rpc.MessageStatus{{
+				Hash:            txnHash,
+				FinalityStatus:  rpc.TxnFinalityStatusAcceptedOnL1,
+				ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+			}},
+			Receipt: receipt(
+				rpc.TxnFinalityStatusAcceptedOnL1,
+				rpc.TxnExecutionStatusSUCCEEDED,
+				fixture.MessageHash,
+			),
+			ExpectedStage: messaging.StageAcceptedOnL1,
+		},
+		{
+			Description: "reverted, without the L1 transaction hash",
+			Receipt: receipt(
+				rpc.TxnFinalityStatusAcceptedOnL2,
+				rpc.TxnExecutionStatusREVERTED,
+				fixture.MessageHash,
+			),
+			ExpectedStage:    messaging.StageAcceptedOnL2,
+			ExpectedReverted: true,
+		},
+		{
+			Description: "receipt of another message",
+			Receipt: receipt(
+				rpc.TxnFinalityStatusAcceptedOnL2,
+				rpc.TxnExecutionStatusSUCCEEDED,
+				"0x1234",
+			),
+			ExpectedErr: messaging.ErrMessageHashMismatch,
+		},
+		{
+			Description: "node error",
+			L1TxnHash:   "0x5780c6fe46f958a7ebf9308e6db16d819ff9e06b1e88f9e718c50cde10898f38",
+			StatusesErr: rpc.ErrInvalidTxnHash,
+			ExpectedErr: rpc.ErrInvalidTxnHash,
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Description, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+
+			msg := loadMessage(t, fixture.FilePath)
+			msg.L1TxnHash = test.L1TxnHash
+
+			mockRPCProvider.EXPECT().ChainID(t.Context()).Return(fixture.ChainID, nil)
+			if test.L1TxnHash != "" {
+				mockRPCProvider.EXPECT().
+					MessagesStatus(t.Context(), rpc.NumAsHex(test.L1TxnHash)).
+					Return(test.Statuses, test.StatusesErr)
+			}
+			if test.Receipt != nil || test.ReceiptErr != nil {
+				mockRPCProvider.EXPECT().
+					TransactionReceipt(t.Context(), txnHash).
+					Return(test.Receipt, test.ReceiptErr)
+			}
+
+			lifecycle, err := messaging.Track(t.Context(), mockRPCProvider, msg)
+			if test.ExpectedErr != nil {
+				require.ErrorIs(t, err, test.ExpectedErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, fixture.MessageHash, lifecycle.MessageHash.String())
+			assert.Equal(t, txnHash, lifecycle.L1HandlerTxnHash)
+			assert.Equal(t, test.ExpectedStage, lifecycle.Stage)
+			assert.Equal(t, test.ExpectedReverted, lifecycle.Reverted())
+			assert.Equal(t, test.Receipt, lifecycle.Receipt)
+		})
+	}
+}
+
+// TestWaitForMessage tests that the message is tracked until it reaches the
+// expected stage, or the context is done.
+func TestWaitForMessage(t *testing.T) {
+	fixture := messageFixtures[0]
+	txnHash := internalUtils.TestHexToFelt(t, fixture.L1HandlerTxnHash)
+	msg := loadMessage(t, fixture.FilePath)
+	msg.L1TxnHash = ""
+
+	t.Run("accepted on L2", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+
+		mockRPCProvider.EXPECT().ChainID(gomock.Any()).Return(fixture.ChainID, nil).Times(3)
+		gomock.InOrder(
+			mockRPCProvider.EXPECT().
+				TransactionReceipt(gomock.Any(), txnHash).
+				Return(nil, rpc.ErrHashNotFound),
+			mockRPCProvider.EXPECT().
+				TransactionReceipt(gomock.Any(), txnHash).
+				Return(&rpc.TransactionReceiptWithBlockInfo{
+					TransactionReceipt: rpc.TransactionReceipt{
+						FinalityStatus:  rpc.TxnFinalityStatusPreConfirmed,
+						ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+					},
+				}, nil),
+			mockRPCProvider.EXPECT().
+				TransactionReceipt(gomock.Any(), txnHash).
+				Return(&rpc.TransactionReceiptWithBlockInfo{
+					TransactionReceipt: rpc.TransactionReceipt{
+						FinalityStatus:  rpc.TxnFinalityStatusAcceptedOnL2,
+						ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+					},
+				}, nil),
+		)
+
+		lifecycle, err := messaging.WaitForMessage(
+			t.Context(),
+			mockRPCProvider,
+			msg,
+			messaging.StageAcceptedOnL2,
+			time.Millisecond,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, messaging.StageAcceptedOnL2, lifecycle.Stage)
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+
+		mockRPCProvider.EXPECT().ChainID(gomock.Any()).Return(fixture.ChainID, nil).AnyTimes()
+		mockRPCProvider.EXPECT().
+			TransactionReceipt(gomock.Any(), txnHash).
+			Return(nil, rpc.ErrHashNotFound).
+			AnyTimes()
+
+		ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+		defer cancel()
+		_, err := messaging.WaitForMessage(
+			ctx,
+			mockRPCProvider,
+			msg,
+			messaging.StagePreConfirmed,
+			time.Millisecond,
+		)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/NethermindEth/starknet.go/client/rpcerr"
+	"github.com/NethermindEth/starknet.go/paymaster"
+)
+
+// errRelayerPolicy is wrapped by the errors of the relayer fee strategy, to be
+// told apart from the errors of the relayer provider.
+var errRelayerPolicy = errors.New("relayer policy")
+
+// rpcError is a SNIP-29 error returned to the clients. The client.Server uses
+// its message, code and data to build the JSON-RPC error.
+type rpcError struct {
+	err *paymaster.RPCError
+}
+
+// Error returns the message of the error.
+func (e rpcError) Error() string {
+	return e.err.Message
+}
+
+// ErrorCode returns the code of the error.
+func (e rpcError) ErrorCode() int {
+	return e.err.Code
+}
+
+// ErrorData returns the data of the error, if any.
+func (e rpcError) ErrorData() any {
+	if e.err.Data == nil {
+		return nil
+	}
+
+	return e.err.Data
+}
+
+// errWithData returns a copy of a SNIP-29 error with a message as its data.
+func errWithData(base *paymaster.RPCError, message string) *paymaster.RPCError {
+	var data rpcerr.RPCData = paymaster.StringErrData(message)
+	if base.Code == paymaster.ErrTransactionExecutionError.Code {
+		data = &paymaster.TxnExecutionErrData{ExecutionError: message}
+	}
+
+	return &paymaster.RPCError{Code: base.Code, Message: base.Message, Data: data}
+}
+
+// toRPCError converts an error to a SNIP-29 error. The errors that aren't
+// SNIP-29 errors are returned as UNKNOWN_ERROR.
+func toRPCError(err error) error {
+	var snipErr *paymaster.RPCError
+	if errors.As(err, &snipErr) {
+		return rpcError{err: snipErr}
+	}
+
+	return rpcError{err: errWithData(paymaster.ErrUnknownError, err.Error())}
+}
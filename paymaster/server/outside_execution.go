@@ -0,0 +1,122 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/paymaster"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/typeddata"
+)
+
+// outsideExecutionTypes are the SNIP-12 types of the SNIP-9 v2 outside execution.
+const outsideExecutionTypes = `{
+	"StarknetDomain": [
+		{"name": "name", "type": "shortstring"},
+		{"name": "version", "type": "shortstring"},
+		{"name": "chainId", "type": "shortstring"},
+		{"name": "revision", "type": "shortstring"}
+	],
+	"OutsideExecution": [
+		{"name": "Caller", "type": "ContractAddress"},
+		{"name": "Nonce", "type": "felt"},
+		{"name": "Execute After", "type": "u128"},
+		{"name": "Execute Before", "type": "u128"},
+		{"name": "Calls", "type": "Call*"}
+	],
+	"Call": [
+		{"name": "To", "type": "ContractAddress"},
+		{"name": "Selector", "type": "selector"},
+		{"name": "Calldata", "type": "felt*"}
+	]
+}`
+
+// outsideExecution is a SNIP-9 v2 outside execution.
+type outsideExecution struct {
+	Caller        *felt.Felt
+	Nonce         *felt.Felt
+	ExecuteAfter  uint64
+	ExecuteBefore uint64
+	Calls         []paymaster.Call
+}
+
+// typedData returns the SNIP-12 typed data of the outside execution, to be
+// signed by the user account.
+func (execution *outsideExecution) typedData(chainID *felt.Felt) (*typeddata.TypedData, error) {
+	type call struct {
+		To       *felt.Felt   `json:"To"`
+		Selector *felt.Felt   `json:"Selector"`
+		Calldata []*felt.Felt `json:"Calldata"`
+	}
+	calls := make([]call, len(execution.Calls))
+	for i, c := range execution.Calls {
+		calldata := c.Calldata
+		if calldata == nil {
+			calldata = []*felt.Felt{}
+		}
+		calls[i] = call{To: c.To, Selector: c.Selector, Calldata: calldata}
+	}
+
+	chainIDBytes := chainID.Bytes()
+	raw, err := json.Marshal(map[string]any{
+		"types":       json.RawMessage(outsideExecutionTypes),
+		"primaryType": "OutsideExecution",
+		"domain": map[string]string{
+			"name":     "Account.execute_from_outside",
+			"version":  "2",
+			"chainId":  string(bytes.TrimLeft(chainIDBytes[:], "\x00")),
+			"revision": "1",
+		},
+		"message": map[string]any{
+			"Caller":         execution.Caller,
+			"Nonce":          execution.Nonce,
+			"Execute After":  fmt.Sprintf("%#x", execution.ExecuteAfter),
+			"Execute Before": fmt.Sprintf("%#x", execution.ExecuteBefore),
+			"Calls":          calls,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var typedData typeddata.TypedData
+	if err := json.Unmarshal(raw, &typedData); err != nil {
+		return nil, err
+	}
+
+	return &typedData, nil
+}
+
+// executeCall returns the call to the `execute_from_outside_v2` function of the
+// user account, executing the outside execution with the user signature.
+func (execution *outsideExecution) executeCall(
+	user *felt.Felt,
+	signature []*felt.Felt,
+) rpc.InvokeFunctionCall {
+	calldata := []*felt.Felt{
+		execution.Caller,
+		execution.Nonce,
+		new(felt.Felt).SetUint64(execution.ExecuteAfter),
+		new(felt.Felt).SetUint64(execution.ExecuteBefore),
+		new(felt.Felt).SetUint64(uint64(len(execution.Calls))),
+	}
+	for _, call := range execution.Calls {
+		calldata = append(
+			calldata,
+			call.To,
+			call.Selector,
+			new(felt.Felt).SetUint64(uint64(len(call.Calldata))),
+		)
+		calldata = append(calldata, call.Calldata...)
+	}
+	calldata = append(calldata, new(felt.Felt).SetUint64(uint64(len(signature))))
+	calldata = append(calldata, signature...)
+
+	return rpc.InvokeFunctionCall{
+		ContractAddress: user,
+		FunctionName:    "execute_from_outside_v2",
+		CallData:        calldata,
+	}
+}
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/paymaster"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// SponsorshipPolicy decides which transactions are sponsored, i.e. have their
+// fees paid by the relayer, when the `sponsored` fee mode is requested.
+type SponsorshipPolicy interface {
+	// Sponsor returns nil if the transaction is sponsored. It is called both
+	// when building and when executing the transaction. The context holds the
+	// client.PeerInfo of the request, e.g. to read an API key header.
+	Sponsor(ctx context.Context, txn *paymaster.UserTransaction) error
+}
+
+// SponsorshipFunc is a function implementing the SponsorshipPolicy interface.
+type SponsorshipFunc func(ctx context.Context, txn *paymaster.UserTransaction) error
+
+// Sponsor calls the function.
+func (f SponsorshipFunc) Sponsor(ctx context.Context, txn *paymaster.UserTransaction) error {
+	return f(ctx, txn)
+}
+
+// TokenPricing lists the tokens the fees can be paid with in the `default`
+// fee mode, with their prices in STRK.
+type TokenPricing interface {
+	SupportedTokens(ctx context.Context) ([]paymaster.TokenData, error)
+}
+
+// FixedPrices is a TokenPricing with fixed token prices.
+type FixedPrices []paymaster.TokenData
+
+// SupportedTokens returns the tokens.
+func (prices FixedPrices) SupportedTokens(context.Context) ([]paymaster.TokenData, error) {
+	return prices, nil
+}
+
+// LimitPolicy limits the transactions executed by the relayer.
+type LimitPolicy interface {
+	// Allow is called before sending a transaction, with the fee estimated for
+	// the relayer in FRI. It returns nil if the transaction can be sent.
+	Allow(ctx context.Context, txn *paymaster.UserTransaction, fee *felt.Felt) error
+}
+
+// UserLimits is a LimitPolicy limiting the fee of each transaction and the
+// number of transactions of each user within a time window. The zero value of
+// each field disables its limit.
+type UserLimits struct {
+	// The maximum fee of a transaction, in FRI
+	MaxFee *felt.Felt
+	// The limit of transactions per user address
+	RateLimit *account.RateLimit
+	// Returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	mu       sync.Mutex
+	signedAt map[felt.Felt][]time.Time
+}
+
+// Allow checks the transaction against the limits and records it.
+func (limits *UserLimits) Allow(
+	_ context.Context,
+	txn *paymaster.UserTransaction,
+	fee *felt.Felt,
+) error {
+	if limits.MaxFee != nil && fee.Cmp(limits.MaxFee) > 0 {
+		return fmt.Errorf("the fee %s exceeds the limit of %s FRI", fee, limits.MaxFee)
+	}
+	if limits.RateLimit == nil {
+		return nil
+	}
+
+	user := userAddress(txn)
+	now := time.Now()
+	if limits.Now != nil {
+		now = limits.Now()
+	}
+
+	limits.mu.Lock()
+	defer limits.mu.Unlock()
+
+	if limits.signedAt == nil {
+		limits.signedAt = make(map[felt.Felt][]time.Time)
+	}
+	signedAt := slices.DeleteFunc(limits.signedAt[*user], func(at time.Time) bool {
+		return now.Sub(at) >= limits.RateLimit.Window
+	})
+	if len(signedAt) >= limits.RateLimit.MaxTxns {
+		limits.signedAt[*user] = signedAt
+
+		return fmt.Errorf(
+			"%d transactions already sent for %s in the last %s",
+			len(signedAt),
+			user,
+			limits.RateLimit.Window,
+		)
+	}
+	limits.signedAt[*user] = append(signedAt, now)
+
+	return nil
+}
+
+// FeeEstimator estimates the fee of the user transactions when building them,
+// before the user signs the outside execution.
+type FeeEstimator interface {
+	// EstimateFee returns the estimated fee of the transaction, in FRI.
+	EstimateFee(ctx context.Context, txn *paymaster.UserTransaction) (*felt.Felt, error)
+}
+
+// SimulationEstimator is a FeeEstimator simulating the user calls as if the
+// user account was sending them, without validation since no signature is
+// available. A deployment is simulated as a DEPLOY_ACCOUNT transaction
+// followed by the invoke transaction. The overhead of the outside execution
+// isn't included, it should be covered by the fee multiplier of the Server.
+type SimulationEstimator struct {
+	Provider rpc.RPCProvider
+}
+
+// EstimateFee simulates the transaction and returns its overall fee.
+func (estimator SimulationEstimator) EstimateFee(
+	ctx context.Context,
+	txn *paymaster.UserTransaction,
+) (*felt.Felt, error) {
+	var txns []rpc.BroadcastTxn
+	nonce := new(felt.Felt)
+
+	if txn.Deployment != nil {
+		txns = append(txns, utils.BuildDeployAccountTxn(
+			nonce,
+			txn.Deployment.Salt,
+			txn.Deployment.Calldata,
+			txn.Deployment.ClassHash,
+			zeroResourceBounds(),
+			nil,
+		))
+		nonce = new(felt.Felt).SetUint64(1)
+	} else {
+		var err error
+		nonce, err = estimator.Provider.Nonce(
+			ctx,
+			rpc.WithBlockTag(rpc.BlockTagPreConfirmed),
+			txn.Invoke.UserAddress,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if txn.Invoke != nil {
+		calls := make([]rpc.FunctionCall, len(txn.Invoke.Calls))
+		for i, call := range txn.Invoke.Calls {
+			calls[i] = rpc.FunctionCall{
+				ContractAddress:    call.To,
+				EntryPointSelector: call.Selector,
+				Calldata:           call.Calldata,
+			}
+		}
+		txns = append(txns, utils.BuildInvokeTxn(
+			txn.Invoke.UserAddress,
+			nonce,
+			account.FmtCallDataCairo2(calls),
+			zeroResourceBounds(),
+			nil,
+		))
+	}
+
+	estimates, err := estimator.Provider.EstimateFee(
+		ctx,
+		txns,
+		[]rpc.SimulationFlag{rpc.SkipValidate},
+		rpc.WithBlockTag(rpc.BlockTagPreConfirmed),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	total := new(big.Int)
+	for _, estimate := range estimates {
+		if estimate.OverallFee != nil {
+			total.Add(total, estimate.OverallFee.BigInt(new(big.Int)))
+		}
+	}
+
+	return new(felt.Felt).SetBigInt(total), nil
+}
+
+func zeroResourceBounds() *rpc.ResourceBoundsMapping {
+	zero := rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"}
+
+	return &rpc.ResourceBoundsMapping{L1Gas: zero, L1DataGas: zero, L2Gas: zero}
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/paymaster"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// relayerStrategy is the account.FeeStrategy of the relayer transactions. It
+// checks the fee against the amount paid by the user and the limits.
+type relayerStrategy struct {
+	server  *Server
+	txn     *paymaster.UserTransaction
+	feeMode paymaster.FeeMode
+	// the fee paid by the user, in FRI. Nil if sponsored.
+	paidFee *felt.Felt
+	// the tip returned by Tip, included in the max fee of the resource bounds
+	tip rpc.U64
+}
+
+// tipMultipliers are the multipliers of the estimated tip for each priority.
+//
+//nolint:mnd // the multipliers
+var tipMultipliers = map[paymaster.TipPriorityEnum]float64{
+	paymaster.TipPrioritySlow:   0.8,
+	paymaster.TipPriorityNormal: 1,
+	paymaster.TipPriorityFast:   1.5,
+}
+
+// Tip returns the custom tip of the fee mode, or estimates it from its
+// priority.
+func (strategy *relayerStrategy) Tip(ctx context.Context) (rpc.U64, error) {
+	tip := strategy.feeMode.Tip
+	if tip != nil && tip.Custom != nil {
+		strategy.tip = rpc.U64(fmt.Sprintf("%#x", *tip.Custom))
+
+		return strategy.tip, nil
+	}
+	multiplier := tipMultipliers[paymaster.TipPriorityNormal]
+	if tip != nil && tip.Priority != 0 {
+		multiplier = tipMultipliers[tip.Priority]
+	}
+
+	estimated, err := rpc.EstimateTip(ctx, strategy.server.Relayer.Provider, multiplier)
+	if err != nil {
+		return "", err
+	}
+	strategy.tip = estimated
+
+	return estimated, nil
+}
+
+// ResourceBounds checks the estimated fee, and returns the resource bounds
+// with the fee multiplier of the server. If the max fee of these bounds
+// exceeds the fee paid by the user, the bounds of the estimate itself are
+// returned, as long as their max fee doesn't exceed it.
+func (strategy *relayerStrategy) ResourceBounds(
+	ctx context.Context,
+	estimate rpc.FeeEstimation,
+) (*rpc.ResourceBoundsMapping, error) {
+	fee := estimate.OverallFee
+	if fee == nil {
+		fee = new(felt.Felt)
+	}
+
+	resBounds := utils.FeeEstToResBoundsMap(estimate, strategy.server.FeeMultiplier)
+	if strategy.paidFee != nil {
+		var err error
+		if resBounds, err = strategy.paidBounds(estimate, resBounds); err != nil {
+			return nil, err
+		}
+	}
+	if limits := strategy.server.Limits; limits != nil {
+		if err := limits.Allow(ctx, strategy.txn, fee); err != nil {
+			return nil, fmt.Errorf("%w: %w", errRelayerPolicy, err)
+		}
+	}
+
+	return resBounds, nil
+}
+
+// paidBounds returns the resource bounds with the fee multiplier, or the
+// bounds of the estimate, whose max fee doesn't exceed the fee paid by the
+// user.
+func (strategy *relayerStrategy) paidBounds(
+	estimate rpc.FeeEstimation,
+	multiplied *rpc.ResourceBoundsMapping,
+) (*rpc.ResourceBoundsMapping, error) {
+	tip := strategy.tip
+	if tip == "" {
+		tip = "0x0"
+	}
+
+	var maxFee *felt.Felt
+	for _, resBounds := range []*rpc.ResourceBoundsMapping{
+		multiplied,
+		utils.FeeEstToResBoundsMap(estimate, 1),
+	} {
+		var err error
+		if maxFee, err = utils.ResBoundsMapToOverallFee(resBounds, 1, tip); err != nil {
+			return nil, err
+		}
+		if maxFee.Cmp(strategy.paidFee) <= 0 {
+			return resBounds, nil
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"%w: %w",
+		errRelayerPolicy,
+		errWithData(
+			paymaster.ErrMaxAmountTooLow,
+			fmt.Sprintf("the max fee of %s FRI exceeds the paid %s FRI", maxFee, strategy.paidFee),
+		),
+	)
+}
@@ -0,0 +1,765 @@
+// Package server is a reference implementation of a SNIP-29 paymaster service,
+// served by a client.Server. A relayer account pays the fees of the user
+// transactions and executes them as SNIP-9 outside executions, the users
+// paying the relayer back in a supported token or being sponsored.
+//
+// The service is registered on a client.Server, that can be served over HTTP
+// and used with the paymaster.Paymaster client:
+//
+//	pmServer := server.New(relayer)
+//	pmServer.Sponsorship = server.SponsorshipFunc(checkAPIKey)
+//	pmServer.Pricing = server.FixedPrices{strkTokenData}
+//
+//	rpcServer := client.NewServer()
+//	if err := pmServer.Register(rpcServer); err != nil {
+//		return err
+//	}
+//	http.ListenAndServe(":8080", rpcServer)
+//
+// The user accounts must support the SNIP-9 v2 outside execution.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/client"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/paymaster"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// The default values of a new Server
+const (
+	DefaultFeeMultiplier     = 1.5
+	DefaultValidityPeriod    = time.Hour
+	DefaultMaxValidityPeriod = 24 * time.Hour
+	DefaultMaxPendingBuilds  = 10_000
+	DefaultDropTimeout       = 10 * time.Minute
+	DefaultTrackingPeriod    = 24 * time.Hour
+)
+
+// errSponsorshipUnsupported is returned when the sponsored fee mode is
+// requested without a sponsorship policy.
+var errSponsorshipUnsupported = errWithData(
+	paymaster.ErrUnknownError,
+	"sponsored transactions not supported",
+)
+
+// errTooManyPendingBuilds is returned when a transaction is built while the
+// maximum number of built transactions are waiting to be executed.
+var errTooManyPendingBuilds = errWithData(
+	paymaster.ErrUnknownError,
+	"too many transactions waiting to be executed",
+)
+
+// transferSelector is the selector of the ERC-20 `transfer` function.
+var transferSelector = utils.GetSelectorFromNameFelt("transfer")
+
+// Server implements the SNIP-29 paymaster methods. Create it with New, set
+// its policies, and register it on a client.Server with Register. It is safe
+// for concurrent use, but its fields should not be modified after it is
+// registered.
+type Server struct {
+	// The account paying the fees and sending the transactions
+	Relayer *account.Account
+	// Decides which transactions are sponsored. If nil, the `sponsored` fee
+	// mode is rejected.
+	Sponsorship SponsorshipPolicy
+	// The tokens accepted in the `default` fee mode. If nil, the `default` fee
+	// mode is rejected.
+	Pricing TokenPricing
+	// Limits the transactions sent by the relayer. If nil, there is no limit.
+	Limits LimitPolicy
+	// Estimates the fees of the transactions when building them. Defaults to
+	// a SimulationEstimator using the relayer provider.
+	Estimator FeeEstimator
+	// The class hashes of the accounts that can be deployed. If nil, any class
+	// hash is accepted.
+	AccountClassHashes []*felt.Felt
+	// The multiplier applied to the estimated fees to get the suggested max
+	// fees, which the users pay in the `default` fee mode
+	FeeMultiplier float64
+	// How long a built transaction can be executed, when the request has no
+	// time bounds
+	ValidityPeriod time.Duration
+	// The maximum time a built transaction can be executed, from when it is
+	// built. The requests with a later `execute_before` are rejected. If 0,
+	// there is no maximum.
+	MaxValidityPeriod time.Duration
+	// The maximum number of built transactions waiting to be executed, kept in
+	// memory until they expire. The new builds are rejected once it is reached.
+	// If 0, there is no maximum.
+	MaxPendingBuilds int
+	// How long a transaction can't be found by the relayer provider before it
+	// is reported as dropped
+	DropTimeout time.Duration
+	// How long a transaction can still be tracked once it is accepted or
+	// dropped. A transaction never tracked again is kept for this period after
+	// its drop timeout. If 0, the transactions are tracked forever.
+	TrackingPeriod time.Duration
+	// Returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	mu       sync.Mutex
+	builds   map[felt.Felt]*pendingBuild
+	tracking map[felt.Felt]*trackedTxn
+	lastID   uint64
+}
+
+// pendingBuild is a transaction built for a user, waiting to be signed and
+// executed.
+type pendingBuild struct {
+	txn       paymaster.UserTransaction
+	params    paymaster.UserParameters
+	execution *outsideExecution
+	msgHash   *felt.Felt
+	// the fee paid by the user, in FRI. Nil if sponsored.
+	paidFee *felt.Felt
+}
+
+// trackedTxn is a transaction sent by the relayer.
+type trackedTxn struct {
+	hash   *felt.Felt
+	sentAt time.Time
+	status paymaster.TxnStatus
+	// when the transaction was accepted or dropped
+	doneAt time.Time
+}
+
+// New creates a paymaster server with a relayer account and the default
+// values. The Sponsorship and Pricing policies must be set to accept
+// transactions.
+//
+// Parameters:
+//   - relayer: the account paying the fees and sending the transactions
+//
+// Returns:
+//   - *Server: the paymaster server
+func New(relayer *account.Account) *Server {
+	return &Server{
+		Relayer:           relayer,
+		Estimator:         SimulationEstimator{Provider: relayer.Provider},
+		FeeMultiplier:     DefaultFeeMultiplier,
+		ValidityPeriod:    DefaultValidityPeriod,
+		MaxValidityPeriod: DefaultMaxValidityPeriod,
+		MaxPendingBuilds:  DefaultMaxPendingBuilds,
+		DropTimeout:       DefaultDropTimeout,
+		TrackingPeriod:    DefaultTrackingPeriod,
+		builds:            make(map[felt.Felt]*pendingBuild),
+		tracking:          make(map[felt.Felt]*trackedTxn),
+	}
+}
+
+// Register registers the paymaster methods on an RPC server, under the
+// `paymaster` namespace.
+//
+// Parameters:
+//   - rpcServer: the RPC server
+//
+// Returns:
+//   - error: an error if the methods can't be registered
+func (s *Server) Register(rpcServer *client.Server) error {
+	return rpcServer.RegisterName("paymaster", &api{s: s})
+}
+
+// api holds the RPC methods of the Server. Its exported methods are exposed
+// as `paymaster_<method name>`.
+type api struct {
+	s *Server
+}
+
+// IsAvailable implements `paymaster_isAvailable`.
+func (a *api) IsAvailable(ctx context.Context) (bool, error) {
+	if _, err := a.s.Relayer.Provider.ChainID(ctx); err != nil {
+		return false, nil //nolint:nilerr // the service is just unavailable
+	}
+
+	return true, nil
+}
+
+// GetSupportedTokens implements `paymaster_getSupportedTokens`.
+func (a *api) GetSupportedTokens(ctx context.Context) ([]paymaster.TokenData, error) {
+	if a.s.Pricing == nil {
+		return []paymaster.TokenData{}, nil
+	}
+	tokens, err := a.s.Pricing.SupportedTokens(ctx)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+
+	return tokens, nil
+}
+
+// BuildTransaction implements `paymaster_buildTransaction`.
+func (a *api) BuildTransaction(
+	ctx context.Context,
+	request *paymaster.BuildTransactionRequest,
+) (*paymaster.BuildTransactionResponse, error) {
+	response, err := a.s.buildTransaction(ctx, request)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+
+	return response, nil
+}
+
+// ExecuteTransaction implements `paymaster_executeTransaction`.
+func (a *api) ExecuteTransaction(
+	ctx context.Context,
+	request *paymaster.ExecuteTransactionRequest,
+) (*paymaster.ExecuteTransactionResponse, error) {
+	response, err := a.s.executeTransaction(ctx, request)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+
+	return response, nil
+}
+
+// TrackingIdToLatestHash implements `paymaster_trackingIdToLatestHash`.
+//
+//nolint:revive,staticcheck // The name must match the SNIP-29 method name.
+func (a *api) TrackingIdToLatestHash(
+	ctx context.Context,
+	trackingID *felt.Felt,
+) (*paymaster.TrackingIDResponse, error) {
+	response, err := a.s.trackingIDToLatestHash(ctx, trackingID)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+
+	return response, nil
+}
+
+func (s *Server) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+
+	return time.Now()
+}
+
+func (s *Server) buildTransaction(
+	ctx context.Context,
+	request *paymaster.BuildTransactionRequest,
+) (*paymaster.BuildTransactionResponse, error) {
+	txn := request.Transaction
+	if err := s.checkTransaction(&txn); err != nil {
+		return nil, err
+	}
+	if txn.Type == paymaster.UserTxnDeploy {
+		// only the deployment is executed, there is nothing to sign
+		return s.buildDeployment(ctx, request)
+	}
+
+	executeAfter, executeBefore, err := s.timeBounds(request.Parameters.TimeBounds)
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := s.quote(ctx, &txn, request.Parameters.FeeMode)
+	if err != nil {
+		return nil, err
+	}
+
+	calls := slices.Clone(txn.Invoke.Calls)
+	var paidFee *felt.Felt
+	if request.Parameters.FeeMode.Mode == paymaster.FeeModeDefault {
+		amount, err := utils.NewAmount(
+			fee.SuggestedMaxFeeInGasToken.BigInt(new(big.Int)),
+			0,
+		).ToU256Felts()
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, paymaster.Call{
+			To:       request.Parameters.FeeMode.GasToken,
+			Selector: transferSelector,
+			Calldata: append([]*felt.Felt{s.Relayer.Address}, amount...),
+		})
+		paidFee = fee.SuggestedMaxFeeInStrk
+	}
+
+	execution := &outsideExecution{
+		Caller:        s.Relayer.Address,
+		Nonce:         felt.NewRandom[felt.Felt](),
+		ExecuteAfter:  executeAfter,
+		ExecuteBefore: executeBefore,
+		Calls:         calls,
+	}
+	typedData, err := execution.typedData(s.Relayer.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	msgHash, err := typedData.GetMessageHash(txn.Invoke.UserAddress.String())
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.pruneBuilds()
+	if s.MaxPendingBuilds > 0 && len(s.builds) >= s.MaxPendingBuilds {
+		s.mu.Unlock()
+
+		return nil, errTooManyPendingBuilds
+	}
+	s.builds[*execution.Nonce] = &pendingBuild{
+		txn:       txn,
+		params:    request.Parameters,
+		execution: execution,
+		msgHash:   msgHash,
+		paidFee:   paidFee,
+	}
+	s.mu.Unlock()
+
+	return &paymaster.BuildTransactionResponse{
+		Type:       txn.Type,
+		Deployment: txn.Deployment,
+		Parameters: &request.Parameters,
+		TypedData:  typedData,
+		Fee:        fee,
+	}, nil
+}
+
+// buildDeployment builds a `deploy` transaction. Since there is no typed data
+// to sign, only the sponsored fee mode is accepted.
+func (s *Server) buildDeployment(
+	ctx context.Context,
+	request *paymaster.BuildTransactionRequest,
+) (*paymaster.BuildTransactionResponse, error) {
+	if request.Parameters.FeeMode.Mode != paymaster.FeeModeSponsored {
+		return nil, errWithData(
+			paymaster.ErrUnknownError,
+			"deployments without invoke can only be sponsored",
+		)
+	}
+	txn := request.Transaction
+	fee, err := s.quote(ctx, &txn, request.Parameters.FeeMode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &paymaster.BuildTransactionResponse{
+		Type:       txn.Type,
+		Deployment: txn.Deployment,
+		Parameters: &request.Parameters,
+		Fee:        fee,
+	}, nil
+}
+
+// checkTransaction checks the user transaction is well-formed, and that its
+// deployment data is valid.
+func (s *Server) checkTransaction(txn *paymaster.UserTransaction) error {
+	needsInvoke := txn.Type == paymaster.UserTxnInvoke ||
+		txn.Type == paymaster.UserTxnDeployAndInvoke
+	needsDeployment := txn.Type == paymaster.UserTxnDeploy ||
+		txn.Type == paymaster.UserTxnDeployAndInvoke
+
+	if needsInvoke && (txn.Invoke == nil || txn.Invoke.UserAddress == nil) {
+		return errWithData(paymaster.ErrInvalidAddress, "missing invoke data")
+	}
+	for _, call := range txnCalls(txn) {
+		if call.To == nil || call.Selector == nil {
+			return errWithData(paymaster.ErrUnknownError, "invalid call")
+		}
+	}
+	if !needsDeployment {
+		return nil
+	}
+
+	deployment := txn.Deployment
+	if deployment == nil || deployment.Address == nil || deployment.ClassHash == nil ||
+		deployment.Salt == nil {
+		return paymaster.ErrInvalidDeploymentData
+	}
+	if s.AccountClassHashes != nil &&
+		!slices.ContainsFunc(s.AccountClassHashes, deployment.ClassHash.Equal) {
+		return paymaster.ErrClassHashNotSupported
+	}
+	address := contracts.PrecomputeAddress(
+		new(felt.Felt),
+		deployment.Salt,
+		deployment.ClassHash,
+		deployment.Calldata,
+	)
+	if !address.Equal(deployment.Address) ||
+		(txn.Invoke != nil && !address.Equal(txn.Invoke.UserAddress)) {
+		return paymaster.ErrInvalidDeploymentData
+	}
+
+	return nil
+}
+
+// timeBounds returns the time bounds of the outside execution.
+func (s *Server) timeBounds(bounds *paymaster.TimeBounds) (after, before uint64, err error) {
+	now := s.now()
+	if bounds == nil {
+		return 1, uint64(now.Add(s.ValidityPeriod).Unix()), nil
+	}
+
+	after, errAfter := strconv.ParseUint(bounds.ExecuteAfter, 0, 64)
+	before, errBefore := strconv.ParseUint(bounds.ExecuteBefore, 0, 64)
+	if errAfter != nil || errBefore != nil || after >= before ||
+		before <= uint64(now.Unix()) {
+		return 0, 0, paymaster.ErrInvalidTimeBounds
+	}
+	if s.MaxValidityPeriod > 0 && before > uint64(now.Add(s.MaxValidityPeriod).Unix()) {
+		return 0, 0, errWithData(
+			paymaster.ErrInvalidTimeBounds,
+			fmt.Sprintf("execute_before is more than %s away", s.MaxValidityPeriod),
+		)
+	}
+
+	return after, before, nil
+}
+
+// quote estimates the fee of a transaction, in STRK and in the gas token.
+func (s *Server) quote(
+	ctx context.Context,
+	txn *paymaster.UserTransaction,
+	feeMode paymaster.FeeMode,
+) (*paymaster.FeeEstimate, error) {
+	// 1 STRK
+	price := pow10(utils.STRKDecimals)
+	decimals := utils.STRKDecimals
+
+	switch feeMode.Mode {
+	case paymaster.FeeModeSponsored:
+		if err := s.sponsor(ctx, txn); err != nil {
+			return nil, err
+		}
+	case paymaster.FeeModeDefault:
+		token, err := s.token(ctx, feeMode.GasToken)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		if price, ok = new(big.Int).SetString(token.PriceInStrk, 0); !ok || price.Sign() <= 0 {
+			return nil, errWithData(paymaster.ErrUnknownError, "invalid token price")
+		}
+		decimals = token.Decimals
+	default:
+		return nil, errWithData(paymaster.ErrUnknownError, "unknown fee mode")
+	}
+
+	estimated, err := s.Estimator.EstimateFee(ctx, txn)
+	if err != nil {
+		return nil, errWithData(paymaster.ErrTransactionExecutionError, err.Error())
+	}
+	suggested := utils.AmountFromFelt(estimated, utils.STRKDecimals).
+		MulRat(new(big.Rat).SetFloat64(s.FeeMultiplier))
+
+	// the amount of gas token worth an amount of FRI, rounded up
+	inGasToken := func(fri *big.Int) *felt.Felt {
+		scaled := new(big.Int).Mul(fri, pow10(decimals))
+		quo, rem := new(big.Int).QuoRem(scaled, price, new(big.Int))
+		if rem.Sign() > 0 {
+			quo.Add(quo, big.NewInt(1))
+		}
+
+		return new(felt.Felt).SetBigInt(quo)
+	}
+	suggestedFelt, err := suggested.ToFelt()
+	if err != nil {
+		return nil, err
+	}
+
+	return &paymaster.FeeEstimate{
+		GasTokenPriceInStrk:       new(felt.Felt).SetBigInt(price),
+		EstimatedFeeInStrk:        estimated,
+		EstimatedFeeInGasToken:    inGasToken(estimated.BigInt(new(big.Int))),
+		SuggestedMaxFeeInStrk:     suggestedFelt,
+		SuggestedMaxFeeInGasToken: inGasToken(suggested.BaseUnits()),
+	}, nil
+}
+
+// sponsor checks the transaction is sponsored by the sponsorship policy.
+func (s *Server) sponsor(ctx context.Context, txn *paymaster.UserTransaction) error {
+	if s.Sponsorship == nil {
+		return errSponsorshipUnsupported
+	}
+	if err := s.Sponsorship.Sponsor(ctx, txn); err != nil {
+		return fmt.Errorf("transaction not sponsored: %w", err)
+	}
+
+	return nil
+}
+
+// token returns the data of a supported gas token.
+func (s *Server) token(ctx context.Context, gasToken *felt.Felt) (*paymaster.TokenData, error) {
+	if s.Pricing == nil || gasToken == nil {
+		return nil, paymaster.ErrTokenNotSupported
+	}
+	tokens, err := s.Pricing.SupportedTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		if token.TokenAddress != nil && token.TokenAddress.Equal(gasToken) {
+			return &token, nil
+		}
+	}
+
+	return nil, paymaster.ErrTokenNotSupported
+}
+
+// pruneBuilds removes the expired builds. The lock must be held.
+func (s *Server) pruneBuilds() {
+	now := uint64(s.now().Unix())
+	for nonce, build := range s.builds {
+		if build.execution.ExecuteBefore <= now {
+			delete(s.builds, nonce)
+		}
+	}
+}
+
+// pruneTracking removes the transactions accepted or dropped for longer than
+// the tracking period. The lock must be held.
+func (s *Server) pruneTracking() {
+	if s.TrackingPeriod <= 0 {
+		return
+	}
+	now := s.now()
+	for id, tracked := range s.tracking {
+		doneAt := tracked.doneAt
+		if tracked.status == paymaster.TxnStatusActive {
+			// accepted or dropped by then, if it had been tracked
+			doneAt = tracked.sentAt.Add(s.DropTimeout)
+		}
+		if now.Sub(doneAt) >= s.TrackingPeriod {
+			delete(s.tracking, id)
+		}
+	}
+}
+
+func (s *Server) executeTransaction(
+	ctx context.Context,
+	request *paymaster.ExecuteTransactionRequest,
+) (*paymaster.ExecuteTransactionResponse, error) {
+	txn := request.Transaction
+	var calls []rpc.InvokeFunctionCall
+	var build *pendingBuild
+	var sent bool
+	userTxn := paymaster.UserTransaction{Type: txn.Type, Deployment: txn.Deployment}
+
+	if txn.Type != paymaster.UserTxnDeploy {
+		var err error
+		if build, err = s.takeBuild(&txn); err != nil {
+			return nil, err
+		}
+		// the build can be executed again if the transaction isn't sent
+		defer func() {
+			if !sent {
+				s.restoreBuild(build)
+			}
+		}()
+		userTxn = build.txn
+	} else if err := s.checkTransaction(&userTxn); err != nil {
+		return nil, err
+	}
+
+	if userTxn.Deployment != nil {
+		deployCall, _, err := utils.BuildUDCCalldata(
+			userTxn.Deployment.ClassHash,
+			userTxn.Deployment.Calldata,
+			&utils.UDCOptions{
+				Salt:              userTxn.Deployment.Salt,
+				OriginIndependent: true,
+				UDCVersion:        utils.UDCCairoV2,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, deployCall)
+	}
+
+	feeMode := request.Parameters.FeeMode
+	if build != nil {
+		calls = append(
+			calls,
+			build.execution.executeCall(txn.Invoke.UserAddress, txn.Invoke.Signature),
+		)
+		feeMode = build.params.FeeMode
+	} else if feeMode.Mode != paymaster.FeeModeSponsored {
+		return nil, errWithData(
+			paymaster.ErrUnknownError,
+			"deployments without invoke can only be sponsored",
+		)
+	}
+	if feeMode.Mode == paymaster.FeeModeSponsored {
+		if err := s.sponsor(ctx, &userTxn); err != nil {
+			return nil, err
+		}
+	}
+
+	strategy := &relayerStrategy{server: s, txn: &userTxn, feeMode: feeMode}
+	if build != nil {
+		strategy.paidFee = build.paidFee
+	}
+	response, err := s.Relayer.BuildAndSendInvokeTxn(
+		ctx,
+		calls,
+		&account.TxnOptions{FeeStrategy: strategy},
+	)
+	if err != nil {
+		if errors.Is(err, errRelayerPolicy) {
+			return nil, err
+		}
+
+		return nil, errWithData(paymaster.ErrTransactionExecutionError, err.Error())
+	}
+	sent = true
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pruneTracking()
+	s.lastID++
+	trackingID := new(felt.Felt).SetUint64(s.lastID)
+	s.tracking[*trackingID] = &trackedTxn{
+		hash:   response.Hash,
+		sentAt: s.now(),
+		status: paymaster.TxnStatusActive,
+	}
+
+	return &paymaster.ExecuteTransactionResponse{
+		TrackingID:      trackingID,
+		TransactionHash: response.Hash,
+	}, nil
+}
+
+// takeBuild returns and removes the build matching the signed typed data of a
+// transaction, so that it is executed once. It must be restored if the
+// execution fails.
+func (s *Server) takeBuild(txn *paymaster.ExecutableUserTransaction) (*pendingBuild, error) {
+	invoke := txn.Invoke
+	if invoke == nil || invoke.TypedData == nil || invoke.UserAddress == nil {
+		return nil, errWithData(paymaster.ErrInvalidAddress, "missing invoke data")
+	}
+	nonceValue, _ := invoke.TypedData.Message["Nonce"].(string)
+	nonce, err := new(felt.Felt).SetString(nonceValue)
+	if err != nil {
+		return nil, errWithData(paymaster.ErrUnknownError, "invalid outside execution nonce")
+	}
+	msgHash, err := invoke.TypedData.GetMessageHash(invoke.UserAddress.String())
+	if err != nil {
+		return nil, errWithData(paymaster.ErrUnknownError, err.Error())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pruneBuilds()
+	build, ok := s.builds[*nonce]
+	if !ok || !build.msgHash.Equal(msgHash) {
+		return nil, errWithData(
+			paymaster.ErrUnknownError,
+			"the typed data was not built by this paymaster, or has expired",
+		)
+	}
+	if build.txn.Type != txn.Type {
+		return nil, errWithData(
+			paymaster.ErrUnknownError,
+			"transaction type differs from the built one",
+		)
+	}
+	delete(s.builds, *nonce)
+
+	return build, nil
+}
+
+// restoreBuild puts back a build taken by an execution that failed, so that it
+// can be executed again until it expires.
+func (s *Server) restoreBuild(build *pendingBuild) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.builds[*build.execution.Nonce] = build
+}
+
+func (s *Server) trackingIDToLatestHash(
+	ctx context.Context,
+	trackingID *felt.Felt,
+) (*paymaster.TrackingIDResponse, error) {
+	if trackingID == nil {
+		return nil, paymaster.ErrInvalidID
+	}
+	s.mu.Lock()
+	tracked, ok := s.tracking[*trackingID]
+	var snapshot trackedTxn
+	if ok {
+		snapshot = *tracked
+	}
+	s.mu.Unlock()
+	if !ok {
+		return nil, paymaster.ErrInvalidID
+	}
+
+	status := snapshot.status
+	if status == paymaster.TxnStatusActive {
+		txnStatus, err := s.Relayer.Provider.TransactionStatus(ctx, snapshot.hash)
+		// SNIP-29 has no reverted status: a reverted transaction is accepted,
+		// and the callers check its receipt
+		switch {
+		case err == nil && (txnStatus.FinalityStatus == rpc.TxnStatusAcceptedOnL2 ||
+			txnStatus.FinalityStatus == rpc.TxnStatusAcceptedOnL1):
+			status = paymaster.TxnStatusAccepted
+		case isHashNotFound(err):
+			if s.now().Sub(snapshot.sentAt) >= s.DropTimeout {
+				status = paymaster.TxnStatusDropped
+			}
+		case err != nil:
+			return nil, errWithData(paymaster.ErrUnknownError, err.Error())
+		}
+
+		s.mu.Lock()
+		tracked.status = status
+		if status != paymaster.TxnStatusActive {
+			tracked.doneAt = s.now()
+		}
+		s.mu.Unlock()
+	}
+
+	return &paymaster.TrackingIDResponse{TransactionHash: snapshot.hash, Status: status}, nil
+}
+
+// isHashNotFound returns whether an error of the relayer provider is a
+// TXN_HASH_NOT_FOUND error.
+func isHashNotFound(err error) bool {
+	var rpcErr *rpc.RPCError
+
+	return errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrHashNotFound.Code
+}
+
+// txnCalls returns the calls of the user transaction.
+func txnCalls(txn *paymaster.UserTransaction) []paymaster.Call {
+	if txn.Invoke == nil {
+		return nil
+	}
+
+	return txn.Invoke.Calls
+}
+
+// userAddress returns the address of the user account of the transaction.
+func userAddress(txn *paymaster.UserTransaction) *felt.Felt {
+	if txn.Invoke != nil && txn.Invoke.UserAddress != nil {
+		return txn.Invoke.UserAddress
+	}
+	if txn.Deployment != nil && txn.Deployment.Address != nil {
+		return txn.Deployment.Address
+	}
+
+	return new(felt.Felt)
+}
+
+func pow10(n uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil) //nolint:mnd // base 10
+}
@@ -0,0 +1,609 @@
+package server_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/client"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/internal/tests/mocks/rpcv10mock"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/NethermindEth/starknet.go/paymaster"
+	"github.com/NethermindEth/starknet.go/paymaster/server"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// testEnv is a paymaster server served over HTTP, with a relayer and a user
+// account sharing a mocked provider.
+type testEnv struct {
+	server     *server.Server
+	client     *paymaster.Paymaster
+	relayer    *account.Account
+	user       *account.Account
+	deployment *paymaster.AccountDeploymentData
+	// the relayer transactions sent to the provider
+	sent []*rpc.BroadcastInvokeTxnV3
+	// the fee estimated for the relayer transactions, in FRI
+	relayerFee uint64
+	// the status of the relayer transactions returned by the provider
+	txnStatus rpc.TxnStatusResult
+	// the error returned instead of the status, if any
+	txnErr error
+	// the error returned when sending a relayer transaction, if any
+	sendErr error
+}
+
+var (
+	strkToken = internalUtils.TestHexToFelt(
+		&testing.T{},
+		"0x4718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d",
+	)
+	usdcToken = internalUtils.TestHexToFelt(&testing.T{}, "0x5555")
+	target    = internalUtils.TestHexToFelt(&testing.T{}, "0x1234")
+)
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	env := &testEnv{
+		relayerFee: 1_000_000_000_000_000,
+		txnStatus: rpc.TxnStatusResult{
+			FinalityStatus:  rpc.TxnStatusAcceptedOnL2,
+			ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+		},
+	}
+
+	mockRPCProvider := rpcv10mock.NewMockRPCProvider(gomock.NewController(t))
+	mockRPCProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil).AnyTimes()
+	mockRPCProvider.EXPECT().
+		Nonce(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(new(felt.Felt).SetUint64(1), nil).
+		AnyTimes()
+	mockRPCProvider.EXPECT().
+		EstimateFee(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			txns []rpc.BroadcastTxn,
+			_ []rpc.SimulationFlag,
+			_ rpc.BlockID,
+		) ([]rpc.FeeEstimation, error) {
+			estimates := make([]rpc.FeeEstimation, len(txns))
+			for i, txn := range txns {
+				// the user transactions, simulated when building, are cheaper
+				fee := uint64(1_000_000_000_000_000)
+				if invoke, ok := txn.(*rpc.BroadcastInvokeTxnV3); ok &&
+					invoke.SenderAddress.Equal(env.relayer.Address) {
+					fee = env.relayerFee
+				}
+				estimates[i] = rpc.FeeEstimation{
+					FeeEstimationCommon: rpc.FeeEstimationCommon{
+						L1GasConsumed:     new(felt.Felt),
+						L1GasPrice:        new(felt.Felt).SetUint64(1),
+						L1DataGasConsumed: new(felt.Felt),
+						L1DataGasPrice:    new(felt.Felt).SetUint64(1),
+						L2GasConsumed:     new(felt.Felt).SetUint64(fee / 1_000_000_000),
+						L2GasPrice:        new(felt.Felt).SetUint64(1_000_000_000),
+						OverallFee:        new(felt.Felt).SetUint64(fee),
+					},
+				}
+			}
+
+			return estimates, nil
+		}).
+		AnyTimes()
+	mockRPCProvider.EXPECT().
+		AddInvokeTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			txn *rpc.BroadcastInvokeTxnV3,
+		) (rpc.AddInvokeTransactionResponse, error) {
+			if env.sendErr != nil {
+				return rpc.AddInvokeTransactionResponse{}, env.sendErr
+			}
+			env.sent = append(env.sent, txn)
+
+			return rpc.AddInvokeTransactionResponse{
+				Hash: new(felt.Felt).SetUint64(uint64(len(env.sent))),
+			}, nil
+		}).
+		AnyTimes()
+	mockRPCProvider.EXPECT().
+		TransactionStatus(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, *felt.Felt) (*rpc.TxnStatusResult, error) {
+			if env.txnErr != nil {
+				return nil, env.txnErr
+			}
+			status := env.txnStatus
+
+			return &status, nil
+		}).
+		AnyTimes()
+
+	ks, pubKey, _ := account.GetRandomKeys()
+	relayer, err := account.NewAccount(
+		mockRPCProvider,
+		internalUtils.DeadBeef,
+		pubKey.String(),
+		ks,
+		account.CairoV2,
+	)
+	require.NoError(t, err)
+	env.relayer = relayer
+
+	userKs, userPubKey, _ := account.GetRandomKeys()
+	env.deployment = &paymaster.AccountDeploymentData{
+		ClassHash: internalUtils.TestHexToFelt(t, "0xacc0"),
+		Salt:      userPubKey,
+		Calldata:  []*felt.Felt{userPubKey},
+		Version:   paymaster.Cairo1,
+	}
+	env.deployment.Address = contracts.PrecomputeAddress(
+		new(felt.Felt),
+		env.deployment.Salt,
+		env.deployment.ClassHash,
+		env.deployment.Calldata,
+	)
+	user, err := account.NewAccount(
+		mockRPCProvider,
+		env.deployment.Address,
+		userPubKey.String(),
+		userKs,
+		account.CairoV2,
+	)
+	require.NoError(t, err)
+	env.user = user
+
+	env.server = server.New(relayer)
+	env.server.Pricing = server.FixedPrices{
+		{TokenAddress: strkToken, Decimals: 18, PriceInStrk: "0xde0b6b3a7640000"},
+		// 0.5 STRK per USDC
+		{TokenAddress: usdcToken, Decimals: 6, PriceInStrk: "0x6f05b59d3b20000"},
+	}
+
+	rpcServer := client.NewServer()
+	require.NoError(t, env.server.Register(rpcServer))
+	httpServer := httptest.NewServer(rpcServer)
+	t.Cleanup(httpServer.Close)
+
+	env.client, err = paymaster.New(t.Context(), httpServer.URL)
+	require.NoError(t, err)
+
+	return env
+}
+
+// relayerCalls decodes the calls of a relayer transaction.
+func relayerCalls(t *testing.T, txn *rpc.BroadcastInvokeTxnV3) []rpc.FunctionCall {
+	t.Helper()
+	calls, _, err := account.DecodeCallData(txn.Calldata, account.CairoV2)
+	require.NoError(t, err)
+
+	return calls
+}
+
+func requireRPCError(t *testing.T, err error, expected *paymaster.RPCError) {
+	t.Helper()
+	require.Error(t, err)
+	var rpcErr *paymaster.RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, expected.Code, rpcErr.Code, rpcErr.Error())
+}
+
+// TestServer tests the paymaster server in-process, through the paymaster
+// client and the paymaster flow of the account.
+func TestServer(t *testing.T) {
+	functionCalls := []rpc.InvokeFunctionCall{
+		{
+			ContractAddress: target,
+			FunctionName:    "mint",
+			CallData:        []*felt.Felt{new(felt.Felt).SetUint64(100), new(felt.Felt)},
+		},
+	}
+	noTip := uint64(0)
+	strkFeeMode := paymaster.FeeMode{
+		Mode:     paymaster.FeeModeDefault,
+		GasToken: strkToken,
+		Tip:      &paymaster.TipPriority{Custom: &noTip},
+	}
+	sponsored := paymaster.FeeMode{
+		Mode: paymaster.FeeModeSponsored,
+		Tip:  &paymaster.TipPriority{Custom: &noTip},
+	}
+	strkParams := paymaster.UserParameters{Version: paymaster.UserParamV1, FeeMode: strkFeeMode}
+	// the outside executions are executed by the relayer
+	opts := func(env *testEnv) *account.PaymasterOptions {
+		return &account.PaymasterOptions{
+			PollInterval: time.Millisecond,
+			Caller:       env.relayer.Address,
+		}
+	}
+	send := func(
+		t *testing.T,
+		env *testEnv,
+		feeMode paymaster.FeeMode,
+	) (paymaster.ExecuteTransactionResponse, error) {
+		t.Helper()
+
+		return env.user.SendViaPaymaster(t.Context(), env.client, functionCalls, feeMode, opts(env))
+	}
+
+	t.Run("available and supported tokens", func(t *testing.T) {
+		env := newTestEnv(t)
+
+		available, err := env.client.IsAvailable(t.Context())
+		require.NoError(t, err)
+		assert.True(t, available)
+
+		tokens, err := env.client.GetSupportedTokens(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, []paymaster.TokenData(env.server.Pricing.(server.FixedPrices)), tokens)
+	})
+
+	t.Run("invoke paid in STRK", func(t *testing.T) {
+		env := newTestEnv(t)
+
+		resp, err := send(t, env, strkFeeMode)
+		require.NoError(t, err)
+		require.Len(t, env.sent, 1)
+		assert.Equal(t, env.sent[0].SenderAddress, env.relayer.Address)
+		assert.Equal(t, new(felt.Felt).SetUint64(1), resp.TransactionHash)
+
+		calls := relayerCalls(t, env.sent[0])
+		require.Len(t, calls, 1)
+		assert.Equal(t, env.user.Address, calls[0].ContractAddress)
+		assert.Equal(
+			t,
+			internalUtils.GetSelectorFromNameFelt("execute_from_outside_v2"),
+			calls[0].EntryPointSelector,
+		)
+
+		status, err := env.client.TrackingIDToLatestHash(t.Context(), resp.TrackingID)
+		require.NoError(t, err)
+		assert.Equal(t, paymaster.TxnStatusAccepted, status.Status)
+	})
+
+	t.Run("reverted", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.txnStatus.ExecutionStatus = rpc.TxnExecutionStatusREVERTED
+
+		// SNIP-29 reports a reverted transaction as accepted
+		resp, err := send(t, env, strkFeeMode)
+		require.NoError(t, err)
+		require.Len(t, env.sent, 1)
+
+		status, err := env.client.TrackingIDToLatestHash(t.Context(), resp.TrackingID)
+		require.NoError(t, err)
+		assert.Equal(t, paymaster.TxnStatusAccepted, status.Status)
+	})
+
+	t.Run("dropped", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.txnErr = rpc.ErrHashNotFound
+		env.server.DropTimeout = 0
+
+		resp, err := send(t, env, strkFeeMode)
+		require.ErrorIs(t, err, account.ErrPaymasterTxnDropped)
+
+		status, err := env.client.TrackingIDToLatestHash(t.Context(), resp.TrackingID)
+		require.NoError(t, err)
+		assert.Equal(t, paymaster.TxnStatusDropped, status.Status)
+
+		// the other provider errors are returned
+		env = newTestEnv(t)
+		env.txnErr = rpc.ErrInvalidTxnHash
+		_, err = send(t, env, strkFeeMode)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, account.ErrPaymasterTxnDropped)
+	})
+
+	t.Run("pending builds", func(t *testing.T) {
+		env := newTestEnv(t)
+		now := time.Unix(1_700_000_000, 0)
+		env.server.Now = func() time.Time { return now }
+		env.server.MaxPendingBuilds = 1
+		build := func(timeBounds *paymaster.TimeBounds) error {
+			_, err := env.client.BuildTransaction(t.Context(), &paymaster.BuildTransactionRequest{
+				Transaction: paymaster.UserTransaction{
+					Type: paymaster.UserTxnInvoke,
+					Invoke: &paymaster.UserInvoke{
+						UserAddress: env.user.Address,
+						Calls: []paymaster.Call{{
+							To:       target,
+							Selector: internalUtils.GetSelectorFromNameFelt("mint"),
+							Calldata: functionCalls[0].CallData,
+						}},
+					},
+				},
+				Parameters: paymaster.UserParameters{
+					Version:    paymaster.UserParamV1,
+					FeeMode:    strkFeeMode,
+					TimeBounds: timeBounds,
+				},
+			})
+
+			return err
+		}
+
+		// the validity window is capped
+		farFuture := now.Add(server.DefaultMaxValidityPeriod + time.Second)
+		err := build(&paymaster.TimeBounds{
+			ExecuteAfter:  "1",
+			ExecuteBefore: strconv.FormatInt(farFuture.Unix(), 10),
+		})
+		requireRPCError(t, err, paymaster.ErrInvalidTimeBounds)
+
+		require.NoError(t, build(nil))
+		err = build(nil)
+		requireRPCError(t, err, paymaster.ErrUnknownError)
+
+		// the expired builds are removed
+		now = now.Add(server.DefaultValidityPeriod)
+		require.NoError(t, build(nil))
+	})
+
+	t.Run("relayer resource bounds", func(t *testing.T) {
+		env := newTestEnv(t)
+		// the user pays 1.5 times the estimated fee of 1e15 FRI
+		paidFee := new(felt.Felt).SetUint64(1_500_000_000_000_000)
+		maxFee := func(txn *rpc.BroadcastInvokeTxnV3) *felt.Felt {
+			fee, err := utils.ResBoundsMapToOverallFee(txn.ResourceBounds, 1, txn.Tip)
+			require.NoError(t, err)
+
+			return fee
+		}
+
+		// the bounds with the fee multiplier are within the paid fee
+		env.relayerFee = 600_000_000_000_000
+		_, err := send(t, env, strkFeeMode)
+		require.NoError(t, err)
+		require.Len(t, env.sent, 1)
+		assert.Equal(t, rpc.U64("0xdbba0"), env.sent[0].ResourceBounds.L2Gas.MaxAmount)
+		assert.LessOrEqual(t, maxFee(env.sent[0]).Cmp(paidFee), 0)
+
+		// otherwise the bounds of the estimate are used
+		env.relayerFee = 1_000_000_000_000_000
+		_, err = send(t, env, strkFeeMode)
+		require.NoError(t, err)
+		require.Len(t, env.sent, 2)
+		assert.Equal(t, rpc.U64("0xf4240"), env.sent[1].ResourceBounds.L2Gas.MaxAmount)
+		assert.LessOrEqual(t, maxFee(env.sent[1]).Cmp(paidFee), 0)
+	})
+
+	t.Run("retry after a relayer error", func(t *testing.T) {
+		env := newTestEnv(t)
+
+		built, err := env.client.BuildTransaction(t.Context(), &paymaster.BuildTransactionRequest{
+			Transaction: paymaster.UserTransaction{
+				Type: paymaster.UserTxnInvoke,
+				Invoke: &paymaster.UserInvoke{
+					UserAddress: env.user.Address,
+					Calls: []paymaster.Call{{
+						To:       target,
+						Selector: internalUtils.GetSelectorFromNameFelt("mint"),
+						Calldata: functionCalls[0].CallData,
+					}},
+				},
+			},
+			Parameters: strkParams,
+		})
+		require.NoError(t, err)
+		execute := func() error {
+			_, err := env.client.ExecuteTransaction(
+				t.Context(),
+				&paymaster.ExecuteTransactionRequest{
+					Transaction: paymaster.ExecutableUserTransaction{
+						Type: paymaster.UserTxnInvoke,
+						Invoke: &paymaster.ExecutableUserInvoke{
+							UserAddress: env.user.Address,
+							TypedData:   built.TypedData,
+							Signature:   []*felt.Felt{new(felt.Felt), new(felt.Felt)},
+						},
+					},
+					Parameters: strkParams,
+				},
+			)
+
+			return err
+		}
+
+		env.sendErr = rpc.ErrFailedToReceiveTxn
+		requireRPCError(t, execute(), paymaster.ErrTransactionExecutionError)
+		assert.Empty(t, env.sent)
+
+		// the same signed typed data is executed once the relayer recovers
+		env.sendErr = nil
+		require.NoError(t, execute())
+		assert.Len(t, env.sent, 1)
+		requireRPCError(t, execute(), paymaster.ErrUnknownError)
+		assert.Len(t, env.sent, 1)
+	})
+
+	t.Run("tracking period", func(t *testing.T) {
+		env := newTestEnv(t)
+		now := time.Unix(1_700_000_000, 0)
+		env.server.Now = func() time.Time { return now }
+
+		first, err := send(t, env, strkFeeMode)
+		require.NoError(t, err)
+		_, err = env.client.TrackingIDToLatestHash(t.Context(), first.TrackingID)
+		require.NoError(t, err)
+
+		// the accepted transactions are removed after the tracking period
+		now = now.Add(server.DefaultTrackingPeriod)
+		second, err := send(t, env, strkFeeMode)
+		require.NoError(t, err)
+		_, err = env.client.TrackingIDToLatestHash(t.Context(), first.TrackingID)
+		requireRPCError(t, err, paymaster.ErrInvalidID)
+		status, err := env.client.TrackingIDToLatestHash(t.Context(), second.TrackingID)
+		require.NoError(t, err)
+		assert.Equal(t, paymaster.TxnStatusAccepted, status.Status)
+	})
+
+	t.Run("fee in another token", func(t *testing.T) {
+		env := newTestEnv(t)
+
+		built, err := env.client.BuildTransaction(t.Context(), &paymaster.BuildTransactionRequest{
+			Transaction: paymaster.UserTransaction{
+				Type: paymaster.UserTxnInvoke,
+				Invoke: &paymaster.UserInvoke{
+					UserAddress: env.user.Address,
+					Calls: []paymaster.Call{{
+						To:       target,
+						Selector: internalUtils.GetSelectorFromNameFelt("mint"),
+						Calldata: functionCalls[0].CallData,
+					}},
+				},
+			},
+			Parameters: paymaster.UserParameters{
+				Version: paymaster.UserParamV1,
+				FeeMode: paymaster.FeeMode{Mode: paymaster.FeeModeDefault, GasToken: usdcToken},
+			},
+		})
+		require.NoError(t, err)
+		// 0.001 STRK is worth 0.002 USDC
+		assert.Equal(t, new(felt.Felt).SetUint64(2000), built.Fee.EstimatedFeeInGasToken)
+		assert.Equal(t, new(felt.Felt).SetUint64(3000), built.Fee.SuggestedMaxFeeInGasToken)
+
+		// the typed data holds the requested call and the fee transfer
+		calls := []paymaster.Call{{
+			To:       target,
+			Selector: internalUtils.GetSelectorFromNameFelt("mint"),
+			Calldata: functionCalls[0].CallData,
+		}}
+		require.NoError(t, account.VerifyPaymasterTypedData(
+			built.TypedData,
+			env.user.ChainID,
+			env.relayer.Address,
+			calls,
+			usdcToken,
+			built.Fee.SuggestedMaxFeeInGasToken,
+		))
+	})
+
+	t.Run("deploy and invoke sponsored", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.server.AccountClassHashes = []*felt.Felt{env.deployment.ClassHash}
+
+		// no sponsorship policy
+		_, err := env.user.SendViaPaymasterWithDeployment(
+			t.Context(), env.client, env.deployment, functionCalls, sponsored, opts(env),
+		)
+		requireRPCError(t, err, paymaster.ErrUnknownError)
+
+		var sponsoredTxns []*paymaster.UserTransaction
+		env.server.Sponsorship = server.SponsorshipFunc(
+			func(_ context.Context, txn *paymaster.UserTransaction) error {
+				sponsoredTxns = append(sponsoredTxns, txn)
+
+				return nil
+			},
+		)
+		_, err = env.user.SendViaPaymasterWithDeployment(
+			t.Context(), env.client, env.deployment, functionCalls, sponsored, opts(env),
+		)
+		require.NoError(t, err)
+		// checked when building and executing
+		assert.Len(t, sponsoredTxns, 2)
+
+		require.Len(t, env.sent, 1)
+		calls := relayerCalls(t, env.sent[0])
+		require.Len(t, calls, 2)
+		assert.Equal(
+			t,
+			internalUtils.GetSelectorFromNameFelt("deploy_contract"),
+			calls[0].EntryPointSelector,
+		)
+		assert.Equal(t, env.user.Address, calls[1].ContractAddress)
+
+		// unsupported class hash
+		env.server.AccountClassHashes = []*felt.Felt{internalUtils.DeadBeef}
+		_, err = env.user.SendViaPaymasterWithDeployment(
+			t.Context(), env.client, env.deployment, functionCalls, sponsored, opts(env),
+		)
+		requireRPCError(t, err, paymaster.ErrClassHashNotSupported)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		env := newTestEnv(t)
+
+		// unsupported token
+		feeMode := strkFeeMode
+		feeMode.GasToken = internalUtils.DeadBeef
+		_, err := send(t, env, feeMode)
+		requireRPCError(t, err, paymaster.ErrTokenNotSupported)
+
+		// the relayer fee exceeds the fee paid by the user
+		env.relayerFee = 2_000_000_000_000_000
+		_, err = send(t, env, strkFeeMode)
+		requireRPCError(t, err, paymaster.ErrMaxAmountTooLow)
+		assert.Empty(t, env.sent)
+		env.relayerFee = 1_000_000_000_000_000
+
+		// the limits are checked before sending
+		env.server.Limits = &server.UserLimits{
+			RateLimit: &account.RateLimit{MaxTxns: 1, Window: time.Hour},
+		}
+		_, err = send(t, env, strkFeeMode)
+		require.NoError(t, err)
+		_, err = send(t, env, strkFeeMode)
+		requireRPCError(t, err, paymaster.ErrUnknownError)
+		assert.Len(t, env.sent, 1)
+
+		// unknown tracking ID
+		_, err = env.client.TrackingIDToLatestHash(t.Context(), internalUtils.DeadBeef)
+		requireRPCError(t, err, paymaster.ErrInvalidID)
+
+		// typed data not built by the paymaster
+		built, err := env.client.BuildTransaction(t.Context(), &paymaster.BuildTransactionRequest{
+			Transaction: paymaster.UserTransaction{
+				Type: paymaster.UserTxnInvoke,
+				Invoke: &paymaster.UserInvoke{
+					UserAddress: env.user.Address,
+					Calls: []paymaster.Call{{
+						To:       target,
+						Selector: internalUtils.GetSelectorFromNameFelt("mint"),
+						Calldata: functionCalls[0].CallData,
+					}},
+				},
+			},
+			Parameters: strkParams,
+		})
+		require.NoError(t, err)
+		// removing the fee transfer
+		calls := built.TypedData.Message["Calls"].([]any)
+		built.TypedData.Message["Calls"] = calls[:1]
+		_, err = env.client.ExecuteTransaction(t.Context(), &paymaster.ExecuteTransactionRequest{
+			Transaction: paymaster.ExecutableUserTransaction{
+				Type: paymaster.UserTxnInvoke,
+				Invoke: &paymaster.ExecutableUserInvoke{
+					UserAddress: env.user.Address,
+					TypedData:   built.TypedData,
+					Signature:   []*felt.Felt{new(felt.Felt), new(felt.Felt)},
+				},
+			},
+			Parameters: strkParams,
+		})
+		requireRPCError(t, err, paymaster.ErrUnknownError)
+
+		// invalid time bounds
+		_, err = env.client.BuildTransaction(t.Context(), &paymaster.BuildTransactionRequest{
+			Transaction: paymaster.UserTransaction{
+				Type:   paymaster.UserTxnInvoke,
+				Invoke: &paymaster.UserInvoke{UserAddress: env.user.Address},
+			},
+			Parameters: paymaster.UserParameters{
+				Version:    paymaster.UserParamV1,
+				FeeMode:    strkFeeMode,
+				TimeBounds: &paymaster.TimeBounds{ExecuteAfter: "10", ExecuteBefore: "5"},
+			},
+		})
+		requireRPCError(t, err, paymaster.ErrInvalidTimeBounds)
+	})
+}
@@ -0,0 +1,390 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// ErrUnsupportedBySpecVersion is returned when a request can't be translated
+// for the version of the JSON-RPC specification implemented by the node, e.g.
+// the l1_accepted block tag for a v0.8 node. The Provider methods return it as
+// the data of an internal RPC error.
+var ErrUnsupportedBySpecVersion = errors.New(
+	"not supported by the JSON-RPC specification version of the node",
+)
+
+// The block tag of the v0.8 specification replaced by the pre_confirmed tag
+const blockTagPending = "pending"
+
+// specAdapter translates the requests and the responses of a Provider between
+// a version of the specification and the previous one, e.g. from v0.10 to v0.9.
+// The adapters work on the JSON values of the params and results, decoded as
+// maps, slices, strings, json.Numbers and bools.
+type specAdapter interface {
+	// adaptParams translates the params of a request for the previous version.
+	adaptParams(method string, params any) (any, error)
+	// adaptResult translates the result of a request from the previous
+	// version. The conn can be used to query the node, without adaptation.
+	adaptResult(ctx context.Context, conn callCloser, method string, result any) (any, error)
+}
+
+// specAdapters returns the adapters translating the requests of a Provider for
+// a node implementing the given version of the specification, from the
+// version of the Provider down to the version of the node. It returns false if
+// the version isn't supported.
+//
+// Parameters:
+//   - nodeVersion: The version of the specification implemented by the node
+//
+// Returns:
+//   - []specAdapter: The adapters to apply to the requests, in order
+//   - bool: Whether the version is supported
+func specAdapters(nodeVersion *semver.Version) ([]specAdapter, bool) {
+	if nodeVersion.Major() != rpcVersion.Major() {
+		return nil, false
+	}
+
+	switch rpcVersion.Minor() - nodeVersion.Minor() {
+	case 0:
+		return nil, true
+	case 1:
+		return []specAdapter{v09Adapter{}}, true
+	case 2:
+		return []specAdapter{v09Adapter{}, v08Adapter{}}, true
+	default:
+		return nil, false
+	}
+}
+
+// adaptedConn is a callCloser translating the requests and the responses
+// through spec adapters.
+type adaptedConn struct {
+	callCloser
+	adapters []specAdapter
+}
+
+// CallContext adapts the params and the result of a call with an object or a
+// single param.
+func (c *adaptedConn) CallContext(
+	ctx context.Context,
+	result interface{},
+	method string,
+	args interface{},
+) error {
+	params := args
+	if args != nil {
+		var err error
+		if params, err = c.adaptParams(method, args); err != nil {
+			return err
+		}
+	}
+
+	var raw json.RawMessage
+	if err := c.callCloser.CallContext(ctx, &raw, method, params); err != nil {
+		return err
+	}
+
+	return c.adaptResult(ctx, method, raw, result)
+}
+
+// CallContextWithSliceArgs adapts the params and the result of a call with
+// positional params.
+func (c *adaptedConn) CallContextWithSliceArgs(
+	ctx context.Context,
+	result interface{},
+	method string,
+	args ...interface{},
+) error {
+	params := args
+	if len(args) != 0 {
+		adapted, err := c.adaptParams(method, args)
+		if err != nil {
+			return err
+		}
+		params = adapted.([]any)
+	}
+
+	var raw json.RawMessage
+	if err := c.callCloser.CallContextWithSliceArgs(ctx, &raw, method, params...); err != nil {
+		return err
+	}
+
+	return c.adaptResult(ctx, method, raw, result)
+}
+
+func (c *adaptedConn) adaptParams(method string, args any) (any, error) {
+	params, err := toJSONValue(args)
+	if err != nil {
+		return nil, err
+	}
+	for _, adapter := range c.adapters {
+		if params, err = adapter.adaptParams(method, params); err != nil {
+			return nil, fmt.Errorf("%s: %w", method, err)
+		}
+	}
+
+	return params, nil
+}
+
+// adaptResult translates a result from the oldest version up, and decodes it
+// into result.
+func (c *adaptedConn) adaptResult(
+	ctx context.Context,
+	method string,
+	raw json.RawMessage,
+	result any,
+) error {
+	if len(raw) == 0 {
+		return json.Unmarshal([]byte("null"), result)
+	}
+
+	value, err := toJSONValue(raw)
+	if err != nil {
+		return err
+	}
+	for i := len(c.adapters) - 1; i >= 0; i-- {
+		if value, err = c.adapters[i].adaptResult(ctx, c.callCloser, method, value); err != nil {
+			return err
+		}
+	}
+	if raw, err = json.Marshal(value); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, result)
+}
+
+// v09Adapter translates the requests of the v0.10 specification for the v0.9
+// nodes.
+//
+// The v0.10 fields of the block headers and of the emitted events are left
+// empty, and the state diffs have no migrated compiled classes, as the v0.9
+// nodes don't report the migration of the compiled class hashes to Blake2s.
+type v09Adapter struct{}
+
+func (v09Adapter) adaptParams(_ string, params any) (any, error) {
+	return params, nil
+}
+
+func (v09Adapter) adaptResult(
+	_ context.Context,
+	_ callCloser,
+	method string,
+	result any,
+) (any, error) {
+	if method != "starknet_getStateUpdate" {
+		return result, nil
+	}
+
+	if stateDiff, ok := field[map[string]any](result, "state_diff"); ok {
+		if _, ok := stateDiff["migrated_compiled_classes"]; !ok {
+			stateDiff["migrated_compiled_classes"] = []any{}
+		}
+	}
+
+	return result, nil
+}
+
+// v08Adapter translates the requests of the v0.9 specification for the v0.8
+// nodes:
+//   - the pre_confirmed block tag of the block ID params is sent as the
+//     pending tag, and the l1_accepted tag is unsupported
+//   - the pending blocks and receipts are returned as pre-confirmed ones, with
+//     the number of the block following the latest block
+//   - the statuses of the rejected transactions are returned as
+//     ErrHashNotFound, as the v0.9 nodes don't keep the rejected transactions
+//
+// The fee estimates need no translation: the v0.8 estimates already have the
+// l1, l2 and l1 data gas amounts and prices of the v0.9 ones, and the fees of
+// the v3 transactions are in FRI. Neither does the switch of the compiled
+// class hashes to Blake2s: the accounts choose the hash of their declare
+// transactions from the Starknet version of the latest block, which the v0.8
+// blocks report as the v0.9 ones.
+type v08Adapter struct{}
+
+// blockIDParams are the positions of the block ID in the positional params of
+// the methods.
+var blockIDParams = map[string]int{
+	"starknet_getBlockWithTxHashes":            0,
+	"starknet_getBlockWithTxs":                 0,
+	"starknet_getBlockWithReceipts":            0,
+	"starknet_getStateUpdate":                  0,
+	"starknet_getBlockTransactionCount":        0,
+	"starknet_getTransactionByBlockIdAndIndex": 0,
+	"starknet_getClass":                        0,
+	"starknet_getClassAt":                      0,
+	"starknet_getClassHashAt":                  0,
+	"starknet_getNonce":                        0,
+	"starknet_traceBlockTransactions":          0,
+	"starknet_simulateTransactions":            0,
+	"starknet_call":                            1,
+	"starknet_estimateMessageFee":              1,
+	"starknet_getStorageAt":                    2,
+	"starknet_estimateFee":                     2,
+}
+
+// blockIDFields are the fields holding a block ID in the object params, e.g.
+// the storage proof input and the events filter.
+var blockIDFields = []string{"block_id", "from_block", "to_block"}
+
+func (v08Adapter) adaptParams(method string, params any) (any, error) {
+	args, ok := params.([]any)
+	if !ok {
+		return params, adaptBlockIDFields(params)
+	}
+
+	if i, ok := blockIDParams[method]; ok && i < len(args) {
+		var err error
+		if args[i], err = adaptBlockID(args[i]); err != nil {
+			return nil, err
+		}
+	}
+	for _, arg := range args {
+		if err := adaptBlockIDFields(arg); err != nil {
+			return nil, err
+		}
+	}
+
+	return args, nil
+}
+
+// adaptBlockIDFields translates the block IDs of an object param, in place.
+func adaptBlockIDFields(param any) error {
+	fields, ok := param.(map[string]any)
+	if !ok {
+		return nil
+	}
+	for _, name := range blockIDFields {
+		value, ok := fields[name]
+		if !ok {
+			continue
+		}
+		var err error
+		if fields[name], err = adaptBlockID(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// adaptBlockID translates the tag of a block ID for a v0.8 node. The block
+// hashes and numbers are unchanged.
+func adaptBlockID(blockID any) (any, error) {
+	tag, ok := blockID.(string)
+	if !ok {
+		return blockID, nil
+	}
+
+	switch BlockTag(tag) {
+	case BlockTagPreConfirmed:
+		return blockTagPending, nil
+	case BlockTagL1Accepted:
+		return nil, fmt.Errorf(
+			"%w: the %s block tag",
+			ErrUnsupportedBySpecVersion,
+			BlockTagL1Accepted,
+		)
+	default:
+		return blockID, nil
+	}
+}
+
+func (v08Adapter) adaptResult(
+	ctx context.Context,
+	conn callCloser,
+	method string,
+	result any,
+) (any, error) {
+	switch method {
+	case "starknet_getBlockWithTxHashes",
+		"starknet_getBlockWithTxs",
+		"starknet_getBlockWithReceipts":
+		block, ok := result.(map[string]any)
+		if !ok || block["block_hash"] != nil {
+			return result, nil
+		}
+		number, err := preConfirmedBlockNumber(ctx, conn)
+		if err != nil {
+			return nil, err
+		}
+		block["block_number"] = number
+		delete(block, "parent_hash")
+		transactions, _ := field[[]any](block, "transactions")
+		for _, txn := range transactions {
+			if receipt, ok := field[map[string]any](txn, "receipt"); ok {
+				receipt["finality_status"] = string(TxnFinalityStatusPreConfirmed)
+			}
+		}
+
+	case "starknet_getTransactionReceipt":
+		receipt, ok := result.(map[string]any)
+		if !ok || receipt["block_hash"] != nil {
+			return result, nil
+		}
+		number, err := preConfirmedBlockNumber(ctx, conn)
+		if err != nil {
+			return nil, err
+		}
+		receipt["block_number"] = number
+		receipt["finality_status"] = string(TxnFinalityStatusPreConfirmed)
+
+	case "starknet_getTransactionStatus":
+		if status, _ := field[string](result, "finality_status"); status == "REJECTED" {
+			return nil, ErrHashNotFound
+		}
+	}
+
+	return result, nil
+}
+
+// preConfirmedBlockNumber returns the number of the block following the latest
+// block, which is the number of the pending block of a v0.8 node.
+func preConfirmedBlockNumber(ctx context.Context, conn callCloser) (json.Number, error) {
+	var latest uint64
+	if err := conn.CallContextWithSliceArgs(ctx, &latest, "starknet_blockNumber"); err != nil {
+		return "", fmt.Errorf("failed to get the number of the pending block: %w", err)
+	}
+
+	return json.Number(strconv.FormatUint(latest+1, 10)), nil
+}
+
+// toJSONValue converts a value into its JSON value: maps, slices, strings,
+// json.Numbers, bools and nils.
+func toJSONValue(value any) (any, error) {
+	raw, ok := value.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var result any
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// field returns a field of a JSON object, if the value is an object and the
+// field has the type T.
+func field[T any](object any, name string) (T, bool) {
+	var zero T
+	fields, ok := object.(map[string]any)
+	if !ok {
+		return zero, false
+	}
+	value, ok := fields[name].(T)
+
+	return value, ok
+}
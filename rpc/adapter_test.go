@@ -0,0 +1,298 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/client"
+	"github.com/NethermindEth/starknet.go/internal/tests"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// legacyNode serves the starknet methods of a node implementing a previous
+// version of the specification, and records the block IDs and the other
+// params it receives.
+type legacyNode struct {
+	version    string
+	messageFee json.RawMessage
+
+	mu       sync.Mutex
+	blockIDs []string
+	params   []string
+}
+
+func (n *legacyNode) record(blockID json.RawMessage) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.blockIDs = append(n.blockIDs, string(blockID))
+}
+
+func (n *legacyNode) recordParam(param json.RawMessage) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.params = append(n.params, string(param))
+}
+
+func (n *legacyNode) SpecVersion() string {
+	return n.version
+}
+
+func (n *legacyNode) BlockNumber() uint64 {
+	return 99
+}
+
+func (n *legacyNode) GetBlockWithTxHashes(blockID json.RawMessage) json.RawMessage {
+	n.record(blockID)
+
+	// the pending block of a v0.8 node has no hash, number and parent hash
+	return json.RawMessage(`{
+		"timestamp": 1,
+		"sequencer_address": "0x1",
+		"l1_gas_price": {"price_in_fri": "0x1", "price_in_wei": "0x1"},
+		"l2_gas_price": {"price_in_fri": "0x1", "price_in_wei": "0x1"},
+		"l1_data_gas_price": {"price_in_fri": "0x1", "price_in_wei": "0x1"},
+		"l1_da_mode": "BLOB",
+		"starknet_version": "0.13.5",
+		"parent_hash": "0x2",
+		"transactions": ["0x3"]
+	}`)
+}
+
+func (n *legacyNode) GetTransactionReceipt(json.RawMessage) json.RawMessage {
+	return json.RawMessage(`{
+		"type": "INVOKE",
+		"transaction_hash": "0x3",
+		"actual_fee": {"amount": "0x1", "unit": "FRI"},
+		"execution_status": "SUCCEEDED",
+		"finality_status": "ACCEPTED_ON_L2",
+		"messages_sent": [],
+		"events": [],
+		"execution_resources": {"l1_gas": 1, "l1_data_gas": 1, "l2_gas": 1}
+	}`)
+}
+
+func (n *legacyNode) GetTransactionStatus(json.RawMessage) json.RawMessage {
+	return json.RawMessage(`{"finality_status": "REJECTED"}`)
+}
+
+func (n *legacyNode) GetStateUpdate(blockID json.RawMessage) json.RawMessage {
+	n.record(blockID)
+
+	return json.RawMessage(`{
+		"block_hash": "0x1",
+		"new_root": "0x2",
+		"old_root": "0x3",
+		"state_diff": {
+			"storage_diffs": [],
+			"deprecated_declared_classes": [],
+			"declared_classes": [],
+			"deployed_contracts": [],
+			"replaced_classes": [],
+			"nonces": []
+		}
+	}`)
+}
+
+func (n *legacyNode) EstimateMessageFee(msg, blockID json.RawMessage) json.RawMessage {
+	n.recordParam(msg)
+	n.record(blockID)
+
+	return n.messageFee
+}
+
+func (n *legacyNode) Call(_, blockID json.RawMessage) json.RawMessage {
+	n.record(blockID)
+
+	return json.RawMessage(`["0x1"]`)
+}
+
+func (n *legacyNode) GetEvents(input json.RawMessage) json.RawMessage {
+	n.recordParam(input)
+
+	return json.RawMessage(`{"events": []}`)
+}
+
+// newLegacyProvider returns a Provider connected to a legacyNode implementing
+// the given version of the specification.
+func newLegacyProvider(t *testing.T, version string) (*Provider, *legacyNode) {
+	t.Helper()
+
+	node := &legacyNode{
+		version: version,
+		messageFee: internalUtils.TestUnmarshalJSONFileToType[json.RawMessage](
+			t,
+			"./testData/estimateMessageFee/sepoliaV08.json",
+			"result",
+		),
+	}
+	server := client.NewServer()
+	require.NoError(t, server.RegisterName("starknet", node))
+	t.Cleanup(server.Stop)
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	provider, err := NewProvider(t.Context(), httpServer.URL)
+	require.NoError(t, err)
+
+	return provider, node
+}
+
+// TestSpecVersionNegotiation tests the versions of the specification
+// supported by NewProvider.
+func TestSpecVersionNegotiation(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	testSet := []struct {
+		version   string
+		supported bool
+	}{
+		{version: "0.7.1", supported: false},
+		{version: "0.8.1", supported: true},
+		{version: "0.9.0", supported: true},
+		{version: "0.10.0", supported: true},
+		{version: "0.11.0", supported: false},
+		{version: "1.10.0", supported: false},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.version, func(t *testing.T) {
+			node := &legacyNode{version: test.version}
+			server := client.NewServer()
+			require.NoError(t, server.RegisterName("starknet", node))
+			t.Cleanup(server.Stop)
+			httpServer := httptest.NewServer(server)
+			t.Cleanup(httpServer.Close)
+
+			provider, err := NewProvider(t.Context(), httpServer.URL)
+			require.NotNil(t, provider)
+			if test.supported {
+				require.NoError(t, err)
+
+				return
+			}
+			assert.Equal(t, errors.Join(
+				ErrIncompatibleVersion,
+				fmt.Errorf("expected version: %s, got: %s", rpcVersion, test.version),
+			), err)
+		})
+	}
+}
+
+// TestV08Adapter tests the translation of the requests for a v0.8 node.
+func TestV08Adapter(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	provider, node := newLegacyProvider(t, "0.8.1")
+
+	t.Run("pre_confirmed block", func(t *testing.T) {
+		result, err := provider.BlockWithTxHashes(t.Context(), WithBlockTag(BlockTagPreConfirmed))
+		require.NoError(t, err)
+		block, ok := result.(*PreConfirmedBlockTxHashes)
+		require.True(t, ok)
+		assert.Equal(t, uint64(100), block.Number)
+		assert.Equal(t, []*felt.Felt{new(felt.Felt).SetUint64(3)}, block.Transactions)
+		assert.Equal(t, `"pending"`, node.blockIDs[len(node.blockIDs)-1])
+	})
+
+	t.Run("l1_accepted block", func(t *testing.T) {
+		_, err := provider.StateUpdate(t.Context(), WithBlockTag(BlockTagL1Accepted))
+		require.ErrorContains(t, err, ErrUnsupportedBySpecVersion.Error())
+	})
+
+	t.Run("pre_confirmed receipt", func(t *testing.T) {
+		receipt, err := provider.TransactionReceipt(t.Context(), new(felt.Felt).SetUint64(3))
+		require.NoError(t, err)
+		assert.Nil(t, receipt.BlockHash)
+		assert.Equal(t, uint(100), receipt.BlockNumber)
+		assert.Equal(t, TxnFinalityStatusPreConfirmed, receipt.FinalityStatus)
+	})
+
+	t.Run("rejected transaction", func(t *testing.T) {
+		_, err := provider.TransactionStatus(t.Context(), new(felt.Felt).SetUint64(3))
+		require.EqualError(t, err, ErrHashNotFound.Error())
+	})
+
+	t.Run("block ID param", func(t *testing.T) {
+		_, err := provider.Call(
+			t.Context(),
+			FunctionCall{ContractAddress: new(felt.Felt), EntryPointSelector: new(felt.Felt)},
+			WithBlockTag(BlockTagPreConfirmed),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, `"pending"`, node.blockIDs[len(node.blockIDs)-1])
+	})
+
+	t.Run("block ID fields", func(t *testing.T) {
+		_, err := provider.Events(t.Context(), EventsInput{
+			EventFilter: EventFilter{
+				FromBlock: WithBlockTag(BlockTagPreConfirmed),
+				ToBlock:   WithBlockTag(BlockTagLatest),
+			},
+			ResultPageRequest: ResultPageRequest{ChunkSize: 1},
+		})
+		require.NoError(t, err)
+		var filter map[string]any
+		require.NoError(t, json.Unmarshal([]byte(node.params[len(node.params)-1]), &filter))
+		assert.Equal(t, "pending", filter["from_block"])
+		assert.Equal(t, "latest", filter["to_block"])
+
+		_, err = provider.Events(t.Context(), EventsInput{
+			EventFilter:       EventFilter{ToBlock: WithBlockTag(BlockTagL1Accepted)},
+			ResultPageRequest: ResultPageRequest{ChunkSize: 1},
+		})
+		require.ErrorContains(t, err, ErrUnsupportedBySpecVersion.Error())
+	})
+
+	t.Run("fee estimate", func(t *testing.T) {
+		// the strings of the other params are sent as is, even if they match
+		// a block tag
+		msg := MsgFromL1{
+			FromAddress: string(BlockTagL1Accepted),
+			ToAddress:   new(felt.Felt),
+			Selector:    new(felt.Felt),
+			Payload:     []*felt.Felt{},
+		}
+		estimate, err := provider.EstimateMessageFee(
+			t.Context(),
+			msg,
+			WithBlockTag(BlockTagPreConfirmed),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, `"pending"`, node.blockIDs[len(node.blockIDs)-1])
+		var sent MsgFromL1
+		require.NoError(t, json.Unmarshal([]byte(node.params[len(node.params)-1]), &sent))
+		assert.Equal(t, msg, sent)
+
+		// the v0.8 fee estimates have the shape of the v0.9 ones
+		expected := internalUtils.TestUnmarshalJSONFileToType[MessageFeeEstimation](
+			t,
+			"./testData/estimateMessageFee/sepoliaV08.json",
+			"result",
+		)
+		assert.Equal(t, expected, estimate)
+		assert.Equal(t, new(felt.Felt).SetUint64(0x1a6a8), estimate.L2GasConsumed)
+		assert.Equal(t, WeiUnit, estimate.Unit)
+	})
+}
+
+// TestV09Adapter tests the translation of the requests for a v0.9 node.
+func TestV09Adapter(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	provider, node := newLegacyProvider(t, "0.9.0")
+
+	stateUpdate, err := provider.StateUpdate(t.Context(), WithBlockTag(BlockTagL1Accepted))
+	require.NoError(t, err)
+	require.NotNil(t, stateUpdate.StateUpdate)
+	assert.NotNil(t, stateUpdate.StateUpdate.StateDiff.MigratedCompiledClasses)
+	assert.Empty(t, stateUpdate.StateUpdate.StateDiff.MigratedCompiledClasses)
+	// the block tags are sent as is
+	assert.Equal(t, []string{`"l1_accepted"`}, node.blockIDs)
+}
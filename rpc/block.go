@@ -91,6 +91,47 @@ func WithBlockTag(tag BlockTag) BlockID {
 	return blockID
 }
 
+// WithConfirmedBlockNumber returns a ConfirmedBlockID with the given block
+// number.
+//
+// Parameters:
+//   - n: The block number to use for the ConfirmedBlockID
+//
+// Returns:
+//   - ConfirmedBlockID: A ConfirmedBlockID with the specified block number
+func WithConfirmedBlockNumber(n uint64) ConfirmedBlockID {
+	return ConfirmedBlockID{blockID: WithBlockNumber(n)}
+}
+
+// WithConfirmedBlockHash returns a ConfirmedBlockID with the given hash.
+//
+// Parameters:
+//   - h: The hash to use for the ConfirmedBlockID
+//
+// Returns:
+//   - ConfirmedBlockID: A ConfirmedBlockID with the specified hash
+func WithConfirmedBlockHash(h *felt.Felt) ConfirmedBlockID {
+	return ConfirmedBlockID{blockID: WithBlockHash(h)}
+}
+
+// WithLatestBlock returns a ConfirmedBlockID with the `latest` tag, the latest
+// block finalised by the consensus on L2.
+//
+// Returns:
+//   - ConfirmedBlockID: A ConfirmedBlockID with the `latest` tag
+func WithLatestBlock() ConfirmedBlockID {
+	return ConfirmedBlockID{blockID: WithBlockTag(BlockTagLatest)}
+}
+
+// WithL1AcceptedBlock returns a ConfirmedBlockID with the `l1_accepted` tag,
+// the latest block included in a state update on L1.
+//
+// Returns:
+//   - ConfirmedBlockID: A ConfirmedBlockID with the `l1_accepted` tag
+func WithL1AcceptedBlock() ConfirmedBlockID {
+	return ConfirmedBlockID{blockID: WithBlockTag(BlockTagL1Accepted)}
+}
+
 // BlockWithTxHashes retrieves the block with transaction hashes for the given block ID.
 //
 // Parameters:
@@ -98,14 +139,15 @@ func WithBlockTag(tag BlockTag) BlockID {
 //   - blockID: The ID of the block to retrieve the transactions from
 //
 // Returns:
-//   - interface{}: The retrieved block
+//   - BlockView: The retrieved block, a *BlockTxHashes or a
+//     *PreConfirmedBlockTxHashes
 //   - error: An error, if any
 //
 //nolint:dupl // Similar to BlockWithTxs, but it's a different method.
 func (provider *Provider) BlockWithTxHashes(
 	ctx context.Context,
 	blockID BlockID,
-) (interface{}, error) {
+) (BlockView, error) {
 	var result BlockTxHashes
 	if err := do(ctx, provider.c, "starknet_getBlockWithTxHashes", &result, blockID); err != nil {
 		return nil, rpcerr.UnwrapToRPCErr(err, ErrBlockNotFound)
@@ -181,11 +223,11 @@ func (provider *Provider) BlockTransactionCount(
 //   - blockID: The ID of the block to retrieve
 //
 // Returns:
-//   - interface{}: The retrieved block
+//   - BlockView: The retrieved block, a *Block or a *PreConfirmedBlock
 //   - error: An error, if any
 //
 //nolint:dupl // Similar to BlockWithTxHashes, but it's a different method.
-func (provider *Provider) BlockWithTxs(ctx context.Context, blockID BlockID) (interface{}, error) {
+func (provider *Provider) BlockWithTxs(ctx context.Context, blockID BlockID) (BlockView, error) {
 	var result Block
 	if err := do(ctx, provider.c, "starknet_getBlockWithTxs", &result, blockID); err != nil {
 		return nil, rpcerr.UnwrapToRPCErr(err, ErrBlockNotFound)
@@ -210,11 +252,13 @@ func (provider *Provider) BlockWithTxs(ctx context.Context, blockID BlockID) (in
 	return &result, nil
 }
 
-// Get block information with full transactions and receipts given the block id
+// Get block information with full transactions and receipts given the block id.
+// The returned BlockView is a *BlockWithReceipts or a
+// *PreConfirmedBlockWithReceipts.
 func (provider *Provider) BlockWithReceipts(
 	ctx context.Context,
 	blockID BlockID,
-) (interface{}, error) {
+) (BlockView, error) {
 	var result json.RawMessage
 	if err := do(ctx, provider.c, "starknet_getBlockWithReceipts", &result, blockID); err != nil {
 		return nil, rpcerr.UnwrapToRPCErr(err, ErrBlockNotFound)
@@ -242,3 +286,132 @@ func (provider *Provider) BlockWithReceipts(
 		return &preConfirmedBlock, nil
 	}
 }
+
+// ConfirmedBlockWithTxHashes retrieves a confirmed block with its transaction
+// hashes.
+//
+// Parameters:
+//   - ctx: The context.Context object for controlling the function call
+//   - blockID: The ID of the block to retrieve
+//
+// Returns:
+//   - *BlockTxHashes: The retrieved block
+//   - error: An error, if any
+func (provider *Provider) ConfirmedBlockWithTxHashes(
+	ctx context.Context,
+	blockID ConfirmedBlockID,
+) (*BlockTxHashes, error) {
+	return confirmedBlock[BlockTxHashes](ctx, provider, "starknet_getBlockWithTxHashes", blockID)
+}
+
+// ConfirmedBlockWithTxs retrieves a confirmed block with its transactions.
+//
+// Parameters:
+//   - ctx: The context.Context object for controlling the function call
+//   - blockID: The ID of the block to retrieve
+//
+// Returns:
+//   - *Block: The retrieved block
+//   - error: An error, if any
+func (provider *Provider) ConfirmedBlockWithTxs(
+	ctx context.Context,
+	blockID ConfirmedBlockID,
+) (*Block, error) {
+	return confirmedBlock[Block](ctx, provider, "starknet_getBlockWithTxs", blockID)
+}
+
+// ConfirmedBlockWithReceipts retrieves a confirmed block with its transactions
+// and their receipts.
+//
+// Parameters:
+//   - ctx: The context.Context object for controlling the function call
+//   - blockID: The ID of the block to retrieve
+//
+// Returns:
+//   - *BlockWithReceipts: The retrieved block
+//   - error: An error, if any
+func (provider *Provider) ConfirmedBlockWithReceipts(
+	ctx context.Context,
+	blockID ConfirmedBlockID,
+) (*BlockWithReceipts, error) {
+	return confirmedBlock[BlockWithReceipts](
+		ctx, provider, "starknet_getBlockWithReceipts", blockID,
+	)
+}
+
+// PreConfirmedBlockWithTxHashes retrieves the pre-confirmed block with its
+// transaction hashes.
+//
+// Parameters:
+//   - ctx: The context.Context object for controlling the function call
+//
+// Returns:
+//   - *PreConfirmedBlockTxHashes: The pre-confirmed block
+//   - error: An error, if any
+func (provider *Provider) PreConfirmedBlockWithTxHashes(
+	ctx context.Context,
+) (*PreConfirmedBlockTxHashes, error) {
+	return preConfirmedBlock[PreConfirmedBlockTxHashes](
+		ctx, provider, "starknet_getBlockWithTxHashes",
+	)
+}
+
+// PreConfirmedBlockWithTxs retrieves the pre-confirmed block with its
+// transactions.
+//
+// Parameters:
+//   - ctx: The context.Context object for controlling the function call
+//
+// Returns:
+//   - *PreConfirmedBlock: The pre-confirmed block
+//   - error: An error, if any
+func (provider *Provider) PreConfirmedBlockWithTxs(
+	ctx context.Context,
+) (*PreConfirmedBlock, error) {
+	return preConfirmedBlock[PreConfirmedBlock](ctx, provider, "starknet_getBlockWithTxs")
+}
+
+// PreConfirmedBlockWithReceipts retrieves the pre-confirmed block with its
+// transactions and their receipts.
+//
+// Parameters:
+//   - ctx: The context.Context object for controlling the function call
+//
+// Returns:
+//   - *PreConfirmedBlockWithReceipts: The pre-confirmed block
+//   - error: An error, if any
+func (provider *Provider) PreConfirmedBlockWithReceipts(
+	ctx context.Context,
+) (*PreConfirmedBlockWithReceipts, error) {
+	return preConfirmedBlock[PreConfirmedBlockWithReceipts](
+		ctx, provider, "starknet_getBlockWithReceipts",
+	)
+}
+
+// confirmedBlock retrieves a confirmed block with a starknet_getBlockWith*
+// method.
+func confirmedBlock[T any](
+	ctx context.Context,
+	provider *Provider,
+	method string,
+	blockID ConfirmedBlockID,
+) (*T, error) {
+	var block T
+	if err := do(ctx, provider.c, method, &block, blockID); err != nil {
+		return nil, rpcerr.UnwrapToRPCErr(err, ErrBlockNotFound)
+	}
+
+	return &block, nil
+}
+
+// preConfirmedBlock retrieves the pre-confirmed block with a
+// starknet_getBlockWith* method.
+func preConfirmedBlock[T any](ctx context.Context, provider *Provider, method string) (*T, error) {
+	var block T
+	err := do(ctx, provider.c, method, &block, WithBlockTag(BlockTagPreConfirmed))
+	if err != nil {
+		return nil, rpcerr.UnwrapToRPCErr(err, ErrBlockNotFound)
+	}
+
+	return &block, nil
+}
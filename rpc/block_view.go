@@ -0,0 +1,227 @@
+package rpc
+
+import "github.com/NethermindEth/juno/core/felt"
+
+// BlockView is the common view of the blocks returned by the
+// starknet_getBlockWith* methods, regardless of whether they are confirmed or
+// pre-confirmed, and of how their transactions are represented.
+//
+// It's implemented by *Block, *PreConfirmedBlock, *BlockTxHashes,
+// *PreConfirmedBlockTxHashes, *BlockWithReceipts and
+// *PreConfirmedBlockWithReceipts. A type switch on the value still gives
+// access to all the fields of the underlying block.
+type BlockView interface {
+	// Header returns the fields of the header shared by the confirmed and
+	// pre-confirmed blocks.
+	Header() PreConfirmedBlockHeader
+	// ConfirmedHeader returns the full header of a confirmed block, or nil for
+	// a pre-confirmed block.
+	ConfirmedHeader() *BlockHeader
+	// BlockStatus returns the status of the block. It's
+	// BlockStatusPreConfirmed for a pre-confirmed block.
+	BlockStatus() BlockStatus
+	// TxnHashes returns the hashes of the transactions of the block.
+	TxnHashes() []*felt.Felt
+	// Txns returns the transactions of the block, or nil if the block only
+	// contains the transaction hashes.
+	Txns() []BlockTransaction
+	// Receipts returns the receipts of the transactions of the block, or nil
+	// if the block doesn't contain the receipts.
+	Receipts() []TransactionReceipt
+}
+
+var (
+	_ BlockView = (*Block)(nil)
+	_ BlockView = (*PreConfirmedBlock)(nil)
+	_ BlockView = (*BlockTxHashes)(nil)
+	_ BlockView = (*PreConfirmedBlockTxHashes)(nil)
+	_ BlockView = (*BlockWithReceipts)(nil)
+	_ BlockView = (*PreConfirmedBlockWithReceipts)(nil)
+)
+
+// Header returns the fields of the block header shared with the pre-confirmed
+// blocks.
+func (b *Block) Header() PreConfirmedBlockHeader { return sharedHeader(&b.BlockHeader) }
+
+// ConfirmedHeader returns the header of the block.
+func (b *Block) ConfirmedHeader() *BlockHeader { return &b.BlockHeader }
+
+// BlockStatus returns the status of the block.
+func (b *Block) BlockStatus() BlockStatus { return b.Status }
+
+// TxnHashes returns the hashes of the transactions of the block.
+func (b *Block) TxnHashes() []*felt.Felt { return blockTxnHashes(b.Transactions) }
+
+// Txns returns the transactions of the block.
+func (b *Block) Txns() []BlockTransaction { return b.Transactions }
+
+// Receipts returns nil, as the block doesn't contain the receipts.
+func (b *Block) Receipts() []TransactionReceipt { return nil }
+
+// Header returns the header of the block.
+func (b *PreConfirmedBlock) Header() PreConfirmedBlockHeader { return b.PreConfirmedBlockHeader }
+
+// ConfirmedHeader returns nil, as the block isn't confirmed.
+func (b *PreConfirmedBlock) ConfirmedHeader() *BlockHeader { return nil }
+
+// BlockStatus returns BlockStatusPreConfirmed.
+func (b *PreConfirmedBlock) BlockStatus() BlockStatus { return BlockStatusPreConfirmed }
+
+// TxnHashes returns the hashes of the transactions of the block.
+func (b *PreConfirmedBlock) TxnHashes() []*felt.Felt { return blockTxnHashes(b.Transactions) }
+
+// Txns returns the transactions of the block.
+func (b *PreConfirmedBlock) Txns() []BlockTransaction { return b.Transactions }
+
+// Receipts returns nil, as the block doesn't contain the receipts.
+func (b *PreConfirmedBlock) Receipts() []TransactionReceipt { return nil }
+
+// Header returns the fields of the block header shared with the pre-confirmed
+// blocks.
+func (b *BlockTxHashes) Header() PreConfirmedBlockHeader { return sharedHeader(&b.BlockHeader) }
+
+// ConfirmedHeader returns the header of the block.
+func (b *BlockTxHashes) ConfirmedHeader() *BlockHeader { return &b.BlockHeader }
+
+// BlockStatus returns the status of the block.
+func (b *BlockTxHashes) BlockStatus() BlockStatus { return b.Status }
+
+// TxnHashes returns the hashes of the transactions of the block.
+func (b *BlockTxHashes) TxnHashes() []*felt.Felt { return b.Transactions }
+
+// Txns returns nil, as the block only contains the transaction hashes.
+func (b *BlockTxHashes) Txns() []BlockTransaction { return nil }
+
+// Receipts returns nil, as the block doesn't contain the receipts.
+func (b *BlockTxHashes) Receipts() []TransactionReceipt { return nil }
+
+// Header returns the header of the block.
+func (b *PreConfirmedBlockTxHashes) Header() PreConfirmedBlockHeader {
+	return b.PreConfirmedBlockHeader
+}
+
+// ConfirmedHeader returns nil, as the block isn't confirmed.
+func (b *PreConfirmedBlockTxHashes) ConfirmedHeader() *BlockHeader { return nil }
+
+// BlockStatus returns BlockStatusPreConfirmed.
+func (b *PreConfirmedBlockTxHashes) BlockStatus() BlockStatus { return BlockStatusPreConfirmed }
+
+// TxnHashes returns the hashes of the transactions of the block.
+func (b *PreConfirmedBlockTxHashes) TxnHashes() []*felt.Felt { return b.Transactions }
+
+// Txns returns nil, as the block only contains the transaction hashes.
+func (b *PreConfirmedBlockTxHashes) Txns() []BlockTransaction { return nil }
+
+// Receipts returns nil, as the block doesn't contain the receipts.
+func (b *PreConfirmedBlockTxHashes) Receipts() []TransactionReceipt { return nil }
+
+// Header returns the fields of the block header shared with the pre-confirmed
+// blocks.
+func (b *BlockWithReceipts) Header() PreConfirmedBlockHeader {
+	return sharedHeader(&b.BlockHeader)
+}
+
+// ConfirmedHeader returns the header of the block.
+func (b *BlockWithReceipts) ConfirmedHeader() *BlockHeader { return &b.BlockHeader }
+
+// BlockStatus returns the status of the block.
+func (b *BlockWithReceipts) BlockStatus() BlockStatus { return b.Status }
+
+// TxnHashes returns the hashes of the transactions of the block.
+func (b *BlockWithReceipts) TxnHashes() []*felt.Felt {
+	return receiptsTxnHashes(b.Transactions)
+}
+
+// Txns returns the transactions of the block, with the hashes from their
+// receipts.
+func (b *BlockWithReceipts) Txns() []BlockTransaction { return receiptsTxns(b.Transactions) }
+
+// Receipts returns the receipts of the transactions of the block.
+func (b *BlockWithReceipts) Receipts() []TransactionReceipt {
+	return receipts(b.Transactions)
+}
+
+// Header returns the header of the block.
+func (b *PreConfirmedBlockWithReceipts) Header() PreConfirmedBlockHeader {
+	return b.PreConfirmedBlockHeader
+}
+
+// ConfirmedHeader returns nil, as the block isn't confirmed.
+func (b *PreConfirmedBlockWithReceipts) ConfirmedHeader() *BlockHeader { return nil }
+
+// BlockStatus returns BlockStatusPreConfirmed.
+func (b *PreConfirmedBlockWithReceipts) BlockStatus() BlockStatus {
+	return BlockStatusPreConfirmed
+}
+
+// TxnHashes returns the hashes of the transactions of the block.
+func (b *PreConfirmedBlockWithReceipts) TxnHashes() []*felt.Felt {
+	return receiptsTxnHashes(b.Transactions)
+}
+
+// Txns returns the transactions of the block, with the hashes from their
+// receipts.
+func (b *PreConfirmedBlockWithReceipts) Txns() []BlockTransaction {
+	return receiptsTxns(b.Transactions)
+}
+
+// Receipts returns the receipts of the transactions of the block.
+func (b *PreConfirmedBlockWithReceipts) Receipts() []TransactionReceipt {
+	return receipts(b.Transactions)
+}
+
+// sharedHeader returns the fields of a block header shared with the
+// pre-confirmed blocks.
+func sharedHeader(header *BlockHeader) PreConfirmedBlockHeader {
+	return PreConfirmedBlockHeader{
+		Number:           header.Number,
+		Timestamp:        header.Timestamp,
+		SequencerAddress: header.SequencerAddress,
+		L1GasPrice:       header.L1GasPrice,
+		L2GasPrice:       header.L2GasPrice,
+		StarknetVersion:  header.StarknetVersion,
+		L1DataGasPrice:   header.L1DataGasPrice,
+		L1DAMode:         header.L1DAMode,
+	}
+}
+
+// blockTxnHashes returns the hashes of the transactions.
+func blockTxnHashes(txns []BlockTransaction) []*felt.Felt {
+	hashes := make([]*felt.Felt, len(txns))
+	for i := range txns {
+		hashes[i] = txns[i].Hash
+	}
+
+	return hashes
+}
+
+// receiptsTxnHashes returns the hashes of the transactions, from their
+// receipts.
+func receiptsTxnHashes(txns []TransactionWithReceipt) []*felt.Felt {
+	hashes := make([]*felt.Felt, len(txns))
+	for i := range txns {
+		hashes[i] = txns[i].Receipt.Hash
+	}
+
+	return hashes
+}
+
+// receiptsTxns returns the transactions, with the hashes from their receipts.
+func receiptsTxns(txns []TransactionWithReceipt) []BlockTransaction {
+	result := make([]BlockTransaction, len(txns))
+	for i := range txns {
+		result[i] = BlockTransaction{Hash: txns[i].Receipt.Hash, Transaction: txns[i].Transaction}
+	}
+
+	return result
+}
+
+// receipts returns the receipts of the transactions.
+func receipts(txns []TransactionWithReceipt) []TransactionReceipt {
+	result := make([]TransactionReceipt, len(txns))
+	for i := range txns {
+		result[i] = txns[i].Receipt
+	}
+
+	return result
+}
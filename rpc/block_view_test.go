@@ -0,0 +1,213 @@
+package rpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/internal/tests"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// TestBlockView tests the BlockView implementations of the block types.
+func TestBlockView(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	testSet := []struct {
+		Description      string
+		Block            BlockView
+		ExpectedNumber   uint64
+		ExpectedStatus   BlockStatus
+		ExpectedTxns     bool
+		ExpectedReceipts bool
+	}{
+		{
+			Description: "block with tx hashes",
+			Block: internalUtils.TestUnmarshalJSONFileToType[*BlockTxHashes](
+				t, "./testData/blockWithHashes/sepolia3100000.json", "result",
+			),
+			ExpectedNumber: 3100000,
+			ExpectedStatus: BlockStatusAcceptedOnL1,
+		},
+		{
+			Description: "pre-confirmed block with tx hashes",
+			Block: internalUtils.TestUnmarshalJSONFileToType[*PreConfirmedBlockTxHashes](
+				t, "./testData/blockWithHashes/sepoliaPreConfirmed.json", "result",
+			),
+			ExpectedStatus: BlockStatusPreConfirmed,
+		},
+		{
+			Description: "block with txs",
+			Block: internalUtils.TestUnmarshalJSONFileToType[*Block](
+				t, "./testData/blockWithTxns/sepolia3100000.json", "result",
+			),
+			ExpectedNumber: 3100000,
+			ExpectedStatus: BlockStatusAcceptedOnL1,
+			ExpectedTxns:   true,
+		},
+		{
+			Description: "pre-confirmed block with txs",
+			Block: internalUtils.TestUnmarshalJSONFileToType[*PreConfirmedBlock](
+				t, "./testData/blockWithTxns/sepoliaPreConfirmed.json", "result",
+			),
+			ExpectedStatus: BlockStatusPreConfirmed,
+			ExpectedTxns:   true,
+		},
+		{
+			Description: "block with receipts",
+			Block: internalUtils.TestUnmarshalJSONFileToType[*BlockWithReceipts](
+				t, "./testData/blockWithReceipts/sepolia3100000.json", "result",
+			),
+			ExpectedNumber:   3100000,
+			ExpectedStatus:   BlockStatusAcceptedOnL1,
+			ExpectedTxns:     true,
+			ExpectedReceipts: true,
+		},
+		{
+			Description: "pre-confirmed block with receipts",
+			Block: internalUtils.TestUnmarshalJSONFileToType[*PreConfirmedBlockWithReceipts](
+				t, "./testData/blockWithReceipts/sepoliaPreConfirmed.json", "result",
+			),
+			ExpectedStatus:   BlockStatusPreConfirmed,
+			ExpectedTxns:     true,
+			ExpectedReceipts: true,
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Description, func(t *testing.T) {
+			block := test.Block
+			header := block.Header()
+			assert.Equal(t, test.ExpectedStatus, block.BlockStatus())
+			assert.NotEmpty(t, header.StarknetVersion)
+			assert.NotNil(t, header.SequencerAddress)
+
+			if test.ExpectedStatus == BlockStatusPreConfirmed {
+				assert.Nil(t, block.ConfirmedHeader())
+			} else {
+				require.NotNil(t, block.ConfirmedHeader())
+				assert.Equal(t, test.ExpectedNumber, header.Number)
+				assert.Equal(t, block.ConfirmedHeader().Timestamp, header.Timestamp)
+				assert.Equal(t, block.ConfirmedHeader().L1GasPrice, header.L1GasPrice)
+			}
+
+			hashes := block.TxnHashes()
+			require.NotEmpty(t, hashes)
+			for _, hash := range hashes {
+				assert.NotNil(t, hash)
+			}
+
+			if test.ExpectedTxns {
+				txns := block.Txns()
+				require.Len(t, txns, len(hashes))
+				for i, txn := range txns {
+					assert.Equal(t, hashes[i], txn.Hash)
+					assert.NotNil(t, txn.Transaction)
+				}
+			} else {
+				assert.Nil(t, block.Txns())
+			}
+
+			if test.ExpectedReceipts {
+				receipts := block.Receipts()
+				require.Len(t, receipts, len(hashes))
+				for i, receipt := range receipts {
+					assert.Equal(t, hashes[i], receipt.Hash)
+				}
+			} else {
+				assert.Nil(t, block.Receipts())
+			}
+		})
+	}
+}
+
+// TestTypedBlockMethods tests that the confirmed and pre-confirmed block
+// methods send the block ID and return the block of the expected type.
+func TestTypedBlockMethods(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	testConfig := BeforeEach(t, false)
+	provider := testConfig.Provider
+
+	// expectCall expects a call to a starknet_getBlockWith* method, replying
+	// with the result of a test file, or with a block not found error.
+	expectCall := func(t *testing.T, method string, blockID any, filePath string) {
+		t.Helper()
+
+		testConfig.MockClient.EXPECT().
+			CallContextWithSliceArgs(t.Context(), gomock.Any(), method, blockID).
+			DoAndReturn(func(_, result, _ any, _ ...any) error {
+				if filePath == "" {
+					return RPCError{Code: 24, Message: "Block not found"}
+				}
+				rawResp := result.(*json.RawMessage)
+				*rawResp = internalUtils.TestUnmarshalJSONFileToType[json.RawMessage](
+					t, filePath, "result",
+				)
+
+				return nil
+			}).
+			Times(1)
+	}
+
+	preConfirmed := WithBlockTag(BlockTagPreConfirmed)
+
+	t.Run("ConfirmedBlockWithTxHashes", func(t *testing.T) {
+		expectCall(t, "starknet_getBlockWithTxHashes", WithLatestBlock(),
+			"./testData/blockWithHashes/sepolia3100000.json")
+
+		block, err := provider.ConfirmedBlockWithTxHashes(t.Context(), WithLatestBlock())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3100000), block.Number)
+		assert.NotEmpty(t, block.Transactions)
+	})
+
+	t.Run("ConfirmedBlockWithTxs", func(t *testing.T) {
+		blockID := WithConfirmedBlockNumber(3100000)
+		expectCall(t, "starknet_getBlockWithTxs", blockID,
+			"./testData/blockWithTxns/sepolia3100000.json")
+
+		block, err := provider.ConfirmedBlockWithTxs(t.Context(), blockID)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3100000), block.Number)
+		assert.NotEmpty(t, block.Transactions)
+	})
+
+	t.Run("ConfirmedBlockWithReceipts", func(t *testing.T) {
+		blockID := WithConfirmedBlockHash(internalUtils.DeadBeef)
+		expectCall(t, "starknet_getBlockWithReceipts", blockID, "")
+
+		_, err := provider.ConfirmedBlockWithReceipts(t.Context(), blockID)
+		require.Error(t, err)
+		assert.EqualError(t, err, ErrBlockNotFound.Error())
+	})
+
+	t.Run("PreConfirmedBlockWithTxHashes", func(t *testing.T) {
+		expectCall(t, "starknet_getBlockWithTxHashes", preConfirmed,
+			"./testData/blockWithHashes/sepoliaPreConfirmed.json")
+
+		block, err := provider.PreConfirmedBlockWithTxHashes(t.Context())
+		require.NoError(t, err)
+		assert.NotEmpty(t, block.Transactions)
+	})
+
+	t.Run("PreConfirmedBlockWithTxs", func(t *testing.T) {
+		expectCall(t, "starknet_getBlockWithTxs", preConfirmed,
+			"./testData/blockWithTxns/sepoliaPreConfirmed.json")
+
+		block, err := provider.PreConfirmedBlockWithTxs(t.Context())
+		require.NoError(t, err)
+		assert.NotEmpty(t, block.Transactions)
+	})
+
+	t.Run("PreConfirmedBlockWithReceipts", func(t *testing.T) {
+		expectCall(t, "starknet_getBlockWithReceipts", preConfirmed,
+			"./testData/blockWithReceipts/sepoliaPreConfirmed.json")
+
+		block, err := provider.PreConfirmedBlockWithReceipts(t.Context())
+		require.NoError(t, err)
+		assert.NotEmpty(t, block.Transactions)
+	})
+}
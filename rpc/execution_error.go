@@ -0,0 +1,326 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/NethermindEth/juno/core/felt"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+)
+
+var (
+	// matches the header of a call in a revert reason, in all the formats used by
+	// the Starknet versions:
+	// - "Error in the called contract (0x...):"
+	// - "Error in the called contract (contract address: 0x.., class hash: 0x.., selector: 0x..):"
+	// - "Error in contract (contract address: 0x.., class hash: 0x.., selector: 0x..):"
+	// - "Error in the contract class constructor (contract address: 0x.., class hash: 0x.., ...):"
+	//nolint:lll // The regex would be harder to read if we break the line.
+	errorFrameRegex = regexp.MustCompile(
+		`(?:\d+: )?Error in (?:the called contract|contract|the contract class constructor) \((?:contract address: )?(0x[0-9a-fA-F]+)(?:, class hash: (0x[0-9a-fA-F]+|UNKNOWN))?(?:, selector: (0x[0-9a-fA-F]+|UNKNOWN))?\):`,
+	)
+	// matches a felt of a failure reason, optionally followed by its short string
+	// representation, e.g. "0x753235365f737562204f766572666c6f77 ('u256_sub Overflow')"
+	failureFeltRegex = regexp.MustCompile(`0x[0-9a-fA-F]+(?: ?\('(?:[^']|'[^),])*'\))?`)
+	// matches a pc of the program segment in an error message, e.g. "pc=0:4835"
+	pcRegex = regexp.MustCompile(`pc=0:(\d+)`)
+	// the characters allowed around the felts of a failure reason
+	failureSeparators = "()[],.\"' \t\n"
+	// the characters trimmed from the end of a failure reason
+	failureTrailing = ". \t\n"
+)
+
+const (
+	revertReasonPrefix  = "Transaction execution has failed:"
+	failureReasonPrefix = "Failure reason:"
+
+	// byteArrayMagic is the first felt of a panic with a ByteArray message:
+	// the selector of "ByteArray"
+	byteArrayMagic = "0x46a6158a16a947e5916b2a2ca68501a45e93d7110e81aa2d6438b1c57c879a3"
+)
+
+// ExecutionErrorFrame is a call of the call stack of a failed execution.
+type ExecutionErrorFrame struct {
+	// The address of the called contract
+	ContractAddress *felt.Felt
+	// The class hash of the called contract. Nil if unknown.
+	ClassHash *felt.Felt
+	// The selector of the called function. Nil if unknown.
+	Selector *felt.Felt
+	// The error message of the call, without the nested calls, e.g. the pc of the
+	// error and the Cairo traceback. Empty if there is none.
+	Message string
+}
+
+// ExecutionErrorStack is the structured form of a revert reason or a contract
+// execution error: the stack of the calls that failed, and the failure reason of
+// the innermost one.
+type ExecutionErrorStack struct {
+	// The failed calls, from the outermost to the innermost one
+	Frames []ExecutionErrorFrame
+	// The raw failure reason of the innermost call
+	Reason string
+	// The felts of the failure reason, when it is a felt array (a Cairo panic).
+	// Nil otherwise.
+	ReasonFelts []*felt.Felt
+	// The failure reason decoded as strings: one short string per felt of
+	// ReasonFelts, or a single string when the panic is a ByteArray. Non-printable
+	// felts are decoded as empty strings.
+	ReasonStrings []string
+}
+
+// ParseRevertReason parses a revert reason, as found in the `RevertReason`
+// field of the transaction receipts and traces, or in the `FailureReason` of a
+// transaction status, into its call stack and failure reason.
+//
+// Parameters:
+//   - reason: the revert reason
+//
+// Returns:
+//   - *ExecutionErrorStack: the structured revert reason. If no call is found,
+//     the whole revert reason is used as failure reason.
+func ParseRevertReason(reason string) *ExecutionErrorStack {
+	stack := new(ExecutionErrorStack)
+	stack.parseMessage(reason)
+
+	return stack
+}
+
+// ParseExecutionError parses the data of a contract execution error, returned
+// by methods such as EstimateFee, SimulateTransactions or Call, into its call
+// stack and failure reason.
+//
+// Parameters:
+//   - err: the error returned by the provider
+//
+// Returns:
+//   - *ExecutionErrorStack: the structured error
+//   - bool: false if the error is not a contract execution error
+func ParseExecutionError(err error) (*ExecutionErrorStack, bool) {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		return nil, false
+	}
+
+	switch data := rpcErr.Data.(type) {
+	case *ContractErrData:
+		return data.RevertError.Stack(), true
+	case *TransactionExecErrData:
+		return data.ExecutionError.Stack(), true
+	case StringErrData:
+		return ParseRevertReason(string(data)), true
+	case *StringErrData:
+		return ParseRevertReason(string(*data)), true
+	default:
+		return nil, false
+	}
+}
+
+// Stack returns the structured form of the contract execution error, walking
+// its nested errors and parsing the innermost message.
+func (contractEx *ContractExecutionError) Stack() *ExecutionErrorStack {
+	stack := new(ExecutionErrorStack)
+
+	current := contractEx
+	for current != nil && current.ContractExecErrInner != nil {
+		inner := current.ContractExecErrInner
+		stack.Frames = append(stack.Frames, ExecutionErrorFrame{
+			ContractAddress: inner.ContractAddress,
+			ClassHash:       inner.ClassHash,
+			Selector:        inner.Selector,
+		})
+		current = inner.Error
+	}
+	if current != nil {
+		stack.parseMessage(current.Message)
+	}
+
+	return stack
+}
+
+// PCs returns the pcs reported in the error message of the call: the pc of
+// the error, followed by the pcs of the Cairo traceback. They can be resolved
+// with the contracts.CasmDisassembly of the compiled class of ClassHash.
+//
+// Returns:
+//   - []uint64: the pcs, nil if the message has none
+func (frame *ExecutionErrorFrame) PCs() []uint64 {
+	var pcs []uint64
+	for _, match := range pcRegex.FindAllStringSubmatch(frame.Message, -1) {
+		pc, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		pcs = append(pcs, pc)
+	}
+
+	return pcs
+}
+
+// Innermost returns the innermost failed call, or nil if there is none.
+func (stack *ExecutionErrorStack) Innermost() *ExecutionErrorFrame {
+	if len(stack.Frames) == 0 {
+		return nil
+	}
+
+	return &stack.Frames[len(stack.Frames)-1]
+}
+
+// DecodedReason returns the failure reason in a human-readable form: the
+// decoded strings joined by ", " when the reason is a felt array, or the raw
+// reason otherwise.
+func (stack *ExecutionErrorStack) DecodedReason() string {
+	if len(stack.ReasonStrings) == 0 {
+		return stack.Reason
+	}
+
+	return strings.Join(stack.ReasonStrings, ", ")
+}
+
+// String returns a one line summary of the error: the innermost failed call and
+// the decoded failure reason.
+func (stack *ExecutionErrorStack) String() string {
+	frame := stack.Innermost()
+	if frame == nil {
+		return stack.DecodedReason()
+	}
+
+	selector := "unknown"
+	if frame.Selector != nil {
+		selector = frame.Selector.String()
+	}
+
+	return fmt.Sprintf(
+		"contract %s, selector %s: %s",
+		frame.ContractAddress,
+		selector,
+		stack.DecodedReason(),
+	)
+}
+
+// parseMessage parses an error message, appending the calls found in it to the
+// stack frames, and setting the message left after the innermost call as the
+// failure reason.
+func (stack *ExecutionErrorStack) parseMessage(message string) {
+	message = strings.TrimPrefix(strings.TrimSpace(message), revertReasonPrefix)
+	message = strings.TrimSpace(message)
+
+	matches := errorFrameRegex.FindAllStringSubmatchIndex(message, -1)
+	if len(matches) == 0 {
+		stack.setReason(message)
+
+		return
+	}
+
+	for i, match := range matches {
+		end := len(message)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+
+		frame := ExecutionErrorFrame{
+			ContractAddress: parseFrameFelt(message, match[2], match[3]),
+			ClassHash:       parseFrameFelt(message, match[4], match[5]),
+			Selector:        parseFrameFelt(message, match[6], match[7]),
+		}
+
+		body := strings.TrimSpace(message[match[1]:end])
+		if i+1 < len(matches) {
+			// the failure reason of an outer call is the nested call
+			if before, _, found := splitFailureReason(body); found {
+				body = before
+			}
+			frame.Message = body
+		} else {
+			// the message of the innermost call contains the failure reason
+			frame.Message, body, _ = splitFailureReason(body)
+			stack.setReason(body)
+		}
+		stack.Frames = append(stack.Frames, frame)
+	}
+}
+
+// setReason sets the failure reason of the stack, decoding it when it is a
+// felt array.
+func (stack *ExecutionErrorStack) setReason(reason string) {
+	_, reason, _ = splitFailureReason(reason)
+	stack.Reason = reason
+
+	// the reason is a felt array if only separators are left once the felts are removed
+	leftover := failureFeltRegex.ReplaceAllString(reason, "")
+	if reason == "" || strings.Trim(leftover, failureSeparators) != "" {
+		return
+	}
+
+	hexFelts := failureFeltRegex.FindAllString(reason, -1)
+	felts := make([]*felt.Felt, 0, len(hexFelts))
+	for _, hexFelt := range hexFelts {
+		hexFelt, _, _ = strings.Cut(hexFelt, "(")
+		hexFelt = strings.TrimSpace(hexFelt)
+		f, err := new(felt.Felt).SetString(hexFelt)
+		if err != nil {
+			return
+		}
+		felts = append(felts, f)
+	}
+	stack.ReasonFelts = felts
+
+	if len(felts) > 1 && felts[0].String() == byteArrayMagic {
+		if str, err := internalUtils.ByteArrFeltToString(felts[1:]); err == nil {
+			stack.ReasonStrings = []string{str}
+
+			return
+		}
+	}
+
+	stack.ReasonStrings = make([]string, len(felts))
+	for i, f := range felts {
+		stack.ReasonStrings[i] = decodeShortString(f)
+	}
+}
+
+// splitFailureReason splits a message around its "Failure reason:" marker,
+// returning the message before the marker and the trimmed reason after it.
+// If there is no marker, the whole message is returned as reason.
+func splitFailureReason(message string) (before, reason string, found bool) {
+	before, reason, found = strings.Cut(message, failureReasonPrefix)
+	if !found {
+		return "", strings.TrimRight(strings.TrimSpace(message), failureTrailing), false
+	}
+
+	before = strings.TrimSpace(before)
+	before = strings.TrimSpace(strings.TrimSuffix(before, "Execution failed."))
+
+	return before, strings.TrimRight(strings.TrimSpace(reason), failureTrailing), true
+}
+
+// parseFrameFelt parses the felt at message[start:end], returning nil if it is
+// absent or not a valid felt (e.g. "UNKNOWN").
+func parseFrameFelt(message string, start, end int) *felt.Felt {
+	if start < 0 {
+		return nil
+	}
+	f, err := new(felt.Felt).SetString(message[start:end])
+	if err != nil {
+		return nil
+	}
+
+	return f
+}
+
+// decodeShortString decodes a felt as a Cairo short string, returning an empty
+// string if it contains non-printable characters.
+func decodeShortString(f *felt.Felt) string {
+	str := internalUtils.HexToShortStr(f.String())
+	for _, r := range str {
+		if !unicode.IsPrint(r) {
+			return ""
+		}
+	}
+
+	return str
+}
@@ -0,0 +1,268 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/internal/tests"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseRevertReason tests parsing the revert reasons of the different
+// Starknet versions into an ExecutionErrorStack.
+func TestParseRevertReason(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	type testFrame struct {
+		ContractAddress string
+		ClassHash       string
+		Selector        string
+		Message         string
+		PCs             []uint64
+	}
+
+	testSet := []struct {
+		Description           string
+		RevertReason          string
+		ExpectedFrames        []testFrame
+		ExpectedReason        string
+		ExpectedReasonStrings []string
+		ExpectedString        string
+	}{
+		{
+			Description: "Starknet 0.13, not deployed contract",
+			RevertReason: internalUtils.TestUnmarshalJSONFileToType[TxnStatusResult](
+				t,
+				"./testData/txnStatus/sepoliaStatus.json",
+				"result",
+			).FailureReason,
+			ExpectedFrames: []testFrame{
+				{
+					ContractAddress: "0x36d67ab362562a97f9fba8a1051cf8e37ff1a1449530fb9f1f0e32ac2da7d06",
+					ClassHash:       "0x61dac032f228abef9c6626f995015233097ae253a7f72d68552db02f2971b8f",
+					Selector:        "0x15d40a3d6ca2ac30f4031e42be28da9b056fef9bb7357ac5e85627ee876e5ad",
+					Message: "Error at pc=0:4835:\nCairo traceback (most recent call last):\n" +
+						"Unknown location (pc=0:67)\nUnknown location (pc=0:1835)\n" +
+						"Unknown location (pc=0:2554)\nUnknown location (pc=0:3436)\n" +
+						"Unknown location (pc=0:4040)",
+					PCs: []uint64{4835, 67, 1835, 2554, 3436, 4040},
+				},
+				{
+					ContractAddress: "0xffffffff",
+					ClassHash:       "0x0",
+					Selector:        "0x2f0b3c5710379609eb5495f1ecd348cb28167711b73609fe565a72734550354",
+				},
+			},
+			ExpectedReason: "Requested contract address " +
+				"0x00000000000000000000000000000000000000000000000000000000ffffffff is not deployed",
+			ExpectedString: "contract 0xffffffff, " +
+				"selector 0x2f0b3c5710379609eb5495f1ecd348cb28167711b73609fe565a72734550354: " +
+				"Requested contract address " +
+				"0x00000000000000000000000000000000000000000000000000000000ffffffff is not deployed",
+		},
+		{
+			Description: "Starknet 0.13, felt array failure reason",
+			RevertReason: "Transaction execution has failed:\n" +
+				"0: Error in the called contract (contract address: 0x066e2765db75d46c2fdf61ca5710e75fe857b88d75f4e2d671638850b1532730, " +
+				"class hash: 0x036078334509b514626504edc9fb252328d1a240e4e948bef8d0c08dff45927f, " +
+				"selector: 0x015d40a3d6ca2ac30f4031e42be28da9b056fef9bb7357ac5e85627ee876e5ad):\n" +
+				"Execution failed. Failure reason:\n" +
+				"(0x617267656e742f6d756c746963616c6c2d6661696c6564 ('argent/multicall-failed'), 0x0 (''), " +
+				"0x434c4541525f41545f4c454153545f4d494e494d554d ('CLEAR_AT_LEAST_MINIMUM'), " +
+				"0x454e545259504f494e545f4641494c4544 ('ENTRYPOINT_FAILED')).\n",
+			ExpectedFrames: []testFrame{
+				{
+					ContractAddress: "0x66e2765db75d46c2fdf61ca5710e75fe857b88d75f4e2d671638850b1532730",
+					ClassHash:       "0x36078334509b514626504edc9fb252328d1a240e4e948bef8d0c08dff45927f",
+					Selector:        "0x15d40a3d6ca2ac30f4031e42be28da9b056fef9bb7357ac5e85627ee876e5ad",
+				},
+			},
+			ExpectedReason: "(0x617267656e742f6d756c746963616c6c2d6661696c6564 ('argent/multicall-failed'), 0x0 (''), " +
+				"0x434c4541525f41545f4c454153545f4d494e494d554d ('CLEAR_AT_LEAST_MINIMUM'), " +
+				"0x454e545259504f494e545f4641494c4544 ('ENTRYPOINT_FAILED'))",
+			ExpectedReasonStrings: []string{
+				"argent/multicall-failed",
+				"",
+				"CLEAR_AT_LEAST_MINIMUM",
+				"ENTRYPOINT_FAILED",
+			},
+		},
+		{
+			Description: "Starknet 0.14, nested calls in the failure reason",
+			RevertReason: "Transaction execution has failed:\n" +
+				"0: Error in the called contract (contract address: 0x1, class hash: 0x2, selector: 0x3):\n" +
+				"Execution failed. Failure reason:\n" +
+				"Error in contract (contract address: 0x4, class hash: 0x5, selector: 0x6):\n" +
+				"0x4f7574206f6620676173 ('Out of gas').\n.\n",
+			ExpectedFrames: []testFrame{
+				{ContractAddress: "0x1", ClassHash: "0x2", Selector: "0x3"},
+				{ContractAddress: "0x4", ClassHash: "0x5", Selector: "0x6"},
+			},
+			ExpectedReason:        "0x4f7574206f6620676173 ('Out of gas')",
+			ExpectedReasonStrings: []string{"Out of gas"},
+			ExpectedString:        "contract 0x4, selector 0x6: Out of gas",
+		},
+		{
+			Description: "legacy format, only the contract address",
+			RevertReason: "Error in the called contract (0x0123):\nError at pc=0:10:\n" +
+				"Execution failed. Failure reason: 0x753235365f737562204f766572666c6f77('u256_sub Overflow').",
+			ExpectedFrames: []testFrame{
+				{ContractAddress: "0x123", Message: "Error at pc=0:10:", PCs: []uint64{10}},
+			},
+			ExpectedReason:        "0x753235365f737562204f766572666c6f77('u256_sub Overflow')",
+			ExpectedReasonStrings: []string{"u256_sub Overflow"},
+			ExpectedString:        "contract 0x123, selector unknown: u256_sub Overflow",
+		},
+		{
+			Description: "constructor with unknown selector",
+			RevertReason: "Error in the contract class constructor (contract address: 0x7, " +
+				"class hash: 0x8, selector: UNKNOWN):\nExecution failed. Failure reason: 0x4e6f7065 ('Nope').",
+			ExpectedFrames: []testFrame{
+				{ContractAddress: "0x7", ClassHash: "0x8"},
+			},
+			ExpectedReason:        "0x4e6f7065 ('Nope')",
+			ExpectedReasonStrings: []string{"Nope"},
+		},
+		{
+			Description: "ByteArray panic",
+			RevertReason: "Execution failed. Failure reason: " +
+				"(0x46a6158a16a947e5916b2a2ca68501a45e93d7110e81aa2d6438b1c57c879a3, 0x0, 0x68656c6c6f, 0x5).",
+			ExpectedReason: "(0x46a6158a16a947e5916b2a2ca68501a45e93d7110e81aa2d6438b1c57c879a3, " +
+				"0x0, 0x68656c6c6f, 0x5)",
+			ExpectedReasonStrings: []string{"hello"},
+			ExpectedString:        "hello",
+		},
+		{
+			Description:    "plain message",
+			RevertReason:   "Insufficient max L2Gas: max amount: 1, actual used: 2.",
+			ExpectedReason: "Insufficient max L2Gas: max amount: 1, actual used: 2",
+			ExpectedString: "Insufficient max L2Gas: max amount: 1, actual used: 2",
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Description, func(t *testing.T) {
+			stack := ParseRevertReason(test.RevertReason)
+
+			require.Len(t, stack.Frames, len(test.ExpectedFrames))
+			for i, frame := range stack.Frames {
+				expected := test.ExpectedFrames[i]
+				assert.Equal(t, expected.ContractAddress, fmt.Sprint(frame.ContractAddress))
+				if expected.ClassHash == "" {
+					assert.Nil(t, frame.ClassHash)
+				} else {
+					assert.Equal(t, expected.ClassHash, frame.ClassHash.String())
+				}
+				if expected.Selector == "" {
+					assert.Nil(t, frame.Selector)
+				} else {
+					assert.Equal(t, expected.Selector, frame.Selector.String())
+				}
+				assert.Equal(t, expected.Message, frame.Message)
+				assert.Equal(t, expected.PCs, frame.PCs())
+			}
+
+			assert.Equal(t, test.ExpectedReason, stack.Reason)
+			assert.Equal(t, test.ExpectedReasonStrings, stack.ReasonStrings)
+			if test.ExpectedString != "" {
+				assert.Equal(t, test.ExpectedString, stack.String())
+			}
+		})
+	}
+}
+
+// TestParseExecutionError tests parsing the structured contract execution
+// errors returned by the RPC methods.
+func TestParseExecutionError(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	var contractErrData ContractErrData
+	rawData := internalUtils.TestUnmarshalJSONFileToType[json.RawMessage](
+		t,
+		"./testData/errors/contractError.json",
+		"error",
+		"data",
+	)
+	require.NoError(t, json.Unmarshal(rawData, &contractErrData))
+
+	expectedFrame := ExecutionErrorFrame{
+		ContractAddress: internalUtils.TestHexToFelt(
+			t,
+			"0x4c5772d1914fe6ce891b64eb35bf3522aeae1315647314aac58b01137607f3f",
+		),
+		ClassHash: internalUtils.TestHexToFelt(
+			t,
+			"0x358663e6ed9d37efd33d4661e20b2bad143e0f92076b0c91fe65f31ccf55046",
+		),
+		Selector: internalUtils.TestHexToFelt(
+			t,
+			"0x1b64b1b3b690b43b9b514fb81377518f4039cd3e4f4914d8a6bdf01d679fb19",
+		),
+	}
+
+	testSet := []struct {
+		Description    string
+		Err            error
+		ExpectedOK     bool
+		ExpectedFrames []ExecutionErrorFrame
+		ExpectedReason []string
+	}{
+		{
+			Description: "contract error",
+			Err: &RPCError{
+				Code:    ErrContractError.Code,
+				Message: ErrContractError.Message,
+				Data:    &contractErrData,
+			},
+			ExpectedOK:     true,
+			ExpectedFrames: []ExecutionErrorFrame{expectedFrame, expectedFrame},
+			ExpectedReason: []string{"Failed to deserialize param #2"},
+		},
+		{
+			Description: "transaction execution error, string execution error",
+			Err: fmt.Errorf("wrapped: %w", &RPCError{
+				Code:    ErrTxnExec.Code,
+				Message: ErrTxnExec.Message,
+				Data: &TransactionExecErrData{
+					TransactionIndex: 0,
+					ExecutionError: ContractExecutionError{
+						Message: "Error in the called contract (contract address: 0x1, " +
+							"class hash: 0x2, selector: 0x3):\n" +
+							"Execution failed. Failure reason: 0x4e6f7065 ('Nope').",
+					},
+				},
+			}),
+			ExpectedOK: true,
+			ExpectedFrames: []ExecutionErrorFrame{
+				{
+					ContractAddress: new(felt.Felt).SetUint64(1),
+					ClassHash:       new(felt.Felt).SetUint64(2),
+					Selector:        new(felt.Felt).SetUint64(3),
+				},
+			},
+			ExpectedReason: []string{"Nope"},
+		},
+		{
+			Description: "not a contract execution error",
+			Err:         ErrBlockNotFound,
+			ExpectedOK:  false,
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Description, func(t *testing.T) {
+			stack, ok := ParseExecutionError(test.Err)
+			require.Equal(t, test.ExpectedOK, ok)
+			if !ok {
+				return
+			}
+
+			assert.Equal(t, test.ExpectedFrames, stack.Frames)
+			assert.Equal(t, test.ExpectedReason, stack.ReasonStrings)
+		})
+	}
+}
@@ -25,7 +25,7 @@ var (
 	errNotFound = errors.New("not found")
 
 	// ErrIncompatibleVersion is returned when the JSON-RPC specification  implemented
-	// by the node is different from the version implemented by the Provider type.
+	// by the node isn't supported by the Provider type.
 	ErrIncompatibleVersion = errors.New("incompatible JSON-RPC specification version")
 )
 
@@ -47,17 +47,25 @@ func (ws *WsProvider) Close() {
 
 // NewProvider creates a new HTTP rpc Provider instance.
 //
+// The Provider negotiates the version of the JSON-RPC specification with the
+// node. Besides its own version, it supports the nodes implementing the two
+// previous versions (v0.9 and v0.8), translating its requests and the node
+// responses so that the methods keep the same types and behaviour. The
+// requests that can't be translated, e.g. with the l1_accepted block tag for a
+// v0.8 node, return ErrUnsupportedBySpecVersion.
+//
 // Parameters:
 //   - ctx: The context for the function.
-//   - url: The URL of the RPC endpoint.
+//   - url: The URL of the RPC endpoint, or the path of the IPC socket of a
+//     node on the same host, e.g. "/path/juno.ipc".
 //   - options: The options for the client.
 //
 // Returns:
 //   - *Provider: The new Provider instance.
 //   - error: An error if any.
-//     If the node JSON-RPC specification version is different from the version
-//     implemented by the Provider type, the ErrIncompatibleVersion will be returned,
-//     but the returned Provider instance is valid.
+//     If the node JSON-RPC specification version isn't supported by the
+//     Provider type, the ErrIncompatibleVersion will be returned, but the
+//     returned Provider instance is valid.
 func NewProvider(
 	ctx context.Context,
 	url string,
@@ -77,22 +85,32 @@ func NewProvider(
 
 	provider := &Provider{c: c, chainID: ""}
 
-	// Check version compatibility
-	isCompatible, nodeVersion, err := IsCompatible(ctx, provider)
+	// Negotiate the version of the specification with the node
+	rawNodeVersion, err := provider.SpecVersion(ctx)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get the node's RPC spec version: %w", err)
+	}
+	nodeVersion, err := semver.NewVersion(rawNodeVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse node version: %w", err)
 	}
-	if !isCompatible {
+	adapters, supported := specAdapters(nodeVersion)
+	if !supported {
 		return provider, errors.Join(
 			ErrIncompatibleVersion,
-			fmt.Errorf("expected version: %s, got: %s", rpcVersion, nodeVersion),
+			fmt.Errorf("expected version: %s, got: %s", rpcVersion, rawNodeVersion),
 		)
 	}
+	if len(adapters) != 0 {
+		provider.c = &adaptedConn{callCloser: c, adapters: adapters}
+	}
 
 	return provider, nil
 }
 
-// NewWebsocketProvider creates a new Websocket rpc Provider instance.
+// NewWebsocketProvider creates a new Websocket rpc Provider instance. The url
+// can also be the path of the IPC socket of a node on the same host, which
+// supports the subscriptions too.
 func NewWebsocketProvider(
 	ctx context.Context,
 	url string,
@@ -131,9 +149,9 @@ type RPCProvider interface {
 	BlockHashAndNumber(ctx context.Context) (*BlockHashAndNumberOutput, error)
 	BlockNumber(ctx context.Context) (uint64, error)
 	BlockTransactionCount(ctx context.Context, blockID BlockID) (uint64, error)
-	BlockWithReceipts(ctx context.Context, blockID BlockID) (interface{}, error)
-	BlockWithTxHashes(ctx context.Context, blockID BlockID) (interface{}, error)
-	BlockWithTxs(ctx context.Context, blockID BlockID) (interface{}, error)
+	BlockWithReceipts(ctx context.Context, blockID BlockID) (BlockView, error)
+	BlockWithTxHashes(ctx context.Context, blockID BlockID) (BlockView, error)
+	BlockWithTxs(ctx context.Context, blockID BlockID) (BlockView, error)
 	Call(ctx context.Context, call FunctionCall, block BlockID) ([]*felt.Felt, error)
 	ChainID(ctx context.Context) (string, error)
 	Class(ctx context.Context, blockID BlockID, classHash *felt.Felt) (ClassOutput, error)
@@ -144,6 +162,15 @@ type RPCProvider interface {
 		contractAddress *felt.Felt,
 	) (*felt.Felt, error)
 	CompiledCasm(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error)
+	ConfirmedBlockWithReceipts(
+		ctx context.Context,
+		blockID ConfirmedBlockID,
+	) (*BlockWithReceipts, error)
+	ConfirmedBlockWithTxHashes(
+		ctx context.Context,
+		blockID ConfirmedBlockID,
+	) (*BlockTxHashes, error)
+	ConfirmedBlockWithTxs(ctx context.Context, blockID ConfirmedBlockID) (*Block, error)
 	EstimateFee(
 		ctx context.Context,
 		requests []BroadcastTxn,
@@ -158,6 +185,9 @@ type RPCProvider interface {
 	Events(ctx context.Context, input EventsInput) (*EventChunk, error)
 	MessagesStatus(ctx context.Context, transactionHash NumAsHex) ([]MessageStatus, error)
 	Nonce(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (*felt.Felt, error)
+	PreConfirmedBlockWithReceipts(ctx context.Context) (*PreConfirmedBlockWithReceipts, error)
+	PreConfirmedBlockWithTxHashes(ctx context.Context) (*PreConfirmedBlockTxHashes, error)
+	PreConfirmedBlockWithTxs(ctx context.Context) (*PreConfirmedBlock, error)
 	SimulateTransactions(
 		ctx context.Context,
 		blockID BlockID,
@@ -8,8 +8,11 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"runtime"
 	"testing"
 
+	"github.com/NethermindEth/starknet.go/client"
 	"github.com/NethermindEth/starknet.go/internal/tests"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -151,3 +154,63 @@ func TestVersionCompatibility(t *testing.T) {
 		})
 	}
 }
+
+// ipcNode serves the starknet methods used by TestIPCProvider.
+type ipcNode struct{}
+
+func (ipcNode) SpecVersion() string {
+	return rpcVersion.String()
+}
+
+func (ipcNode) BlockNumber() uint64 {
+	return 42
+}
+
+func (ipcNode) Counter(ctx context.Context, n int) (*client.Subscription, error) {
+	notifier, _ := client.NotifierFromContext(ctx)
+	subscription := notifier.CreateSubscription()
+	go func() {
+		for i := range n {
+			if err := notifier.Notify(subscription.ID, i); err != nil {
+				return
+			}
+		}
+	}()
+
+	return subscription, nil
+}
+
+// TestIPCProvider tests the providers dialing the IPC socket of a node.
+func TestIPCProvider(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+	if runtime.GOOS == "windows" {
+		t.Skip("IPC is only supported on Unix platforms")
+	}
+
+	server := client.NewServer()
+	require.NoError(t, server.RegisterName("starknet", ipcNode{}))
+	t.Cleanup(server.Stop)
+	endpoint := filepath.Join(t.TempDir(), "juno.ipc")
+	listener, err := server.ServeIPC(endpoint)
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	provider, err := NewProvider(t.Context(), endpoint)
+	require.NoError(t, err)
+	blockNumber, err := provider.BlockNumber(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), blockNumber)
+
+	wsProvider, err := NewWebsocketProvider(t.Context(), endpoint)
+	require.NoError(t, err)
+	defer wsProvider.Close()
+	values := make(chan int)
+	sub, err := wsProvider.c.SubscribeWithSliceArgs(
+		t.Context(), "starknet", "_subscribe", values, "counter", 3,
+	)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+	for i := range 3 {
+		assert.Equal(t, i, <-values)
+	}
+}
@@ -0,0 +1,537 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math/big"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+)
+
+var ErrUnknownTraceType = errors.New("unknown transaction trace type")
+
+// TracePhase is the phase of a transaction execution a call belongs to.
+type TracePhase string
+
+const (
+	TracePhaseValidate    TracePhase = "VALIDATE"
+	TracePhaseExecute     TracePhase = "EXECUTE"
+	TracePhaseConstructor TracePhase = "CONSTRUCTOR"
+	TracePhaseL1Handler   TracePhase = "L1_HANDLER"
+	TracePhaseFeeTransfer TracePhase = "FEE_TRANSFER"
+)
+
+// TraceCall is a call of a TraceTree. The embedded FnInvocation gives access to
+// the call fields: contract address, selector, calldata, caller, class hash,
+// call type, result, etc.
+type TraceCall struct {
+	*FnInvocation
+
+	// The phase of the transaction execution the call belongs to
+	Phase TracePhase
+	// The depth of the call in the tree, 0 for the root calls
+	Depth int
+	// The calling call, nil for the root calls
+	Parent *TraceCall
+	// The calls made by this call, in execution order
+	Children []*TraceCall
+
+	// The name of the called function. Only set by TraceTree.Decode when the
+	// ABI of the class is known.
+	FunctionName string
+	// The decoded calldata and result. Only set by TraceTree.Decode.
+	DecodedCalldata []contracts.NamedValue
+	DecodedResult   []contracts.NamedValue
+	// The error that occurred when decoding the calldata or the result, if any
+	DecodeErr error
+}
+
+// TraceEvent is an event emitted during a transaction execution.
+type TraceEvent struct {
+	// The phase of the transaction execution the event was emitted in
+	Phase TracePhase
+	// The order of the event within its phase
+	Order int
+	// The address of the contract that emitted the event
+	FromAddress *felt.Felt
+	// The call that emitted the event
+	Call *TraceCall
+	Keys []*felt.Felt
+	Data []*felt.Felt
+	// The decoded event. Only set by TraceTree.Decode when the ABI of the
+	// emitting class is known.
+	Decoded *contracts.DecodedEvent
+}
+
+// TraceMessage is an L2 to L1 message sent during a transaction execution.
+type TraceMessage struct {
+	// The phase of the transaction execution the message was sent in
+	Phase TracePhase
+	// The order of the message within its phase
+	Order int
+	// The address of the contract that sent the message
+	FromAddress *felt.Felt
+	// The call that sent the message
+	Call    *TraceCall
+	MsgToL1 MsgToL1
+}
+
+// TraceTree is a typed view of a transaction trace: the call trees of the
+// transaction execution phases, and the events and messages they emitted.
+type TraceTree struct {
+	Type TransactionType
+	// The root calls, in execution order: validate, execute (or constructor or
+	// L1 handler), then fee transfer. The phases that didn't run are absent.
+	Roots []*TraceCall
+	// The revert reason of the execution, empty if it didn't revert
+	RevertReason       string
+	StateDiff          *StateDiff
+	ExecutionResources ExecutionResources
+
+	decodedEvents map[*OrderedEvent]*contracts.DecodedEvent
+}
+
+// phaseOrder is the execution order of the phases
+var phaseOrder = map[TracePhase]int{
+	TracePhaseValidate:    0,
+	TracePhaseExecute:     1,
+	TracePhaseConstructor: 1,
+	TracePhaseL1Handler:   1,
+	TracePhaseFeeTransfer: 2, //nolint:mnd // the last phase
+}
+
+// NewTraceTree builds the call tree of a transaction trace, as returned by
+// TraceTransaction, TraceBlockTransactions or SimulateTransactions.
+//
+// Parameters:
+//   - trace: an InvokeTxnTrace, DeclareTxnTrace, DeployAccountTxnTrace or
+//     L1HandlerTxnTrace, or a pointer to one of them
+//
+// Returns:
+//   - *TraceTree: the call tree
+//   - error: ErrUnknownTraceType if the trace is not one of the supported types
+func NewTraceTree(trace TxnTrace) (*TraceTree, error) {
+	tree := new(TraceTree)
+
+	switch trace := trace.(type) {
+	case InvokeTxnTrace:
+		tree.fromInvoke(&trace)
+	case *InvokeTxnTrace:
+		tree.fromInvoke(trace)
+	case DeclareTxnTrace:
+		tree.fromDeclare(&trace)
+	case *DeclareTxnTrace:
+		tree.fromDeclare(trace)
+	case DeployAccountTxnTrace:
+		tree.fromDeployAccount(&trace)
+	case *DeployAccountTxnTrace:
+		tree.fromDeployAccount(trace)
+	case L1HandlerTxnTrace:
+		tree.fromL1Handler(&trace)
+	case *L1HandlerTxnTrace:
+		tree.fromL1Handler(trace)
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnknownTraceType, trace)
+	}
+
+	return tree, nil
+}
+
+func (tree *TraceTree) fromInvoke(trace *InvokeTxnTrace) {
+	tree.Type = TransactionTypeInvoke
+	tree.RevertReason = trace.ExecuteInvocation.RevertReason
+	tree.StateDiff = trace.StateDiff
+	tree.ExecutionResources = trace.ExecutionResources
+	tree.addRoot(trace.ValidateInvocation, TracePhaseValidate)
+	tree.addRoot(trace.ExecuteInvocation.FnInvocation, TracePhaseExecute)
+	tree.addRoot(trace.FeeTransferInvocation, TracePhaseFeeTransfer)
+}
+
+func (tree *TraceTree) fromDeclare(trace *DeclareTxnTrace) {
+	tree.Type = TransactionTypeDeclare
+	tree.StateDiff = trace.StateDiff
+	tree.ExecutionResources = trace.ExecutionResources
+	tree.addRoot(trace.ValidateInvocation, TracePhaseValidate)
+	tree.addRoot(trace.FeeTransferInvocation, TracePhaseFeeTransfer)
+}
+
+func (tree *TraceTree) fromDeployAccount(trace *DeployAccountTxnTrace) {
+	tree.Type = TransactionTypeDeployAccount
+	tree.StateDiff = trace.StateDiff
+	tree.ExecutionResources = trace.ExecutionResources
+	tree.addRoot(trace.ValidateInvocation, TracePhaseValidate)
+	tree.addRoot(&trace.ConstructorInvocation, TracePhaseConstructor)
+	tree.addRoot(trace.FeeTransferInvocation, TracePhaseFeeTransfer)
+}
+
+func (tree *TraceTree) fromL1Handler(trace *L1HandlerTxnTrace) {
+	tree.Type = TransactionTypeL1Handler
+	tree.StateDiff = trace.StateDiff
+	tree.ExecutionResources = trace.ExecutionResources
+	tree.addRoot(&trace.FunctionInvocation, TracePhaseL1Handler)
+}
+
+func (tree *TraceTree) addRoot(invocation *FnInvocation, phase TracePhase) {
+	if invocation == nil {
+		return
+	}
+	tree.Roots = append(tree.Roots, newTraceCall(invocation, phase, nil))
+}
+
+func newTraceCall(invocation *FnInvocation, phase TracePhase, parent *TraceCall) *TraceCall {
+	call := &TraceCall{
+		FnInvocation: invocation,
+		Phase:        phase,
+		Parent:       parent,
+	}
+	if parent != nil {
+		call.Depth = parent.Depth + 1
+	}
+
+	call.Children = make([]*TraceCall, len(invocation.NestedCalls))
+	for i := range invocation.NestedCalls {
+		call.Children[i] = newTraceCall(&invocation.NestedCalls[i], phase, call)
+	}
+
+	return call
+}
+
+// Walk visits the calls of the tree in execution (depth-first) order. If the
+// visitor returns false, the children of the visited call are skipped.
+//
+// Parameters:
+//   - visit: the function called for each call
+func (tree *TraceTree) Walk(visit func(call *TraceCall) bool) {
+	var walk func(call *TraceCall)
+	walk = func(call *TraceCall) {
+		if !visit(call) {
+			return
+		}
+		for _, child := range call.Children {
+			walk(child)
+		}
+	}
+
+	for _, root := range tree.Roots {
+		walk(root)
+	}
+}
+
+// Calls returns an iterator over the calls of the tree, in execution
+// (depth-first) order.
+func (tree *TraceTree) Calls() iter.Seq[*TraceCall] {
+	return func(yield func(*TraceCall) bool) {
+		stop := false
+		tree.Walk(func(call *TraceCall) bool {
+			if stop {
+				return false
+			}
+			stop = !yield(call)
+
+			return !stop
+		})
+	}
+}
+
+// Events returns all the events emitted during the transaction, in execution
+// order: by phase, then by order within the phase.
+func (tree *TraceTree) Events() []TraceEvent {
+	var events []TraceEvent
+	for call := range tree.Calls() {
+		for i := range call.InvocationEvents {
+			event := &call.InvocationEvents[i]
+			traceEvent := TraceEvent{
+				Phase:       call.Phase,
+				Order:       event.Order,
+				FromAddress: call.ContractAddress,
+				Call:        call,
+				Decoded:     tree.decodedEvents[event],
+			}
+			if event.EventContent != nil {
+				traceEvent.Keys = event.Keys
+				traceEvent.Data = event.Data
+			}
+			events = append(events, traceEvent)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return lessPhaseOrder(events[i].Phase, events[i].Order, events[j].Phase, events[j].Order)
+	})
+
+	return events
+}
+
+// Messages returns all the L2 to L1 messages sent during the transaction, in
+// execution order: by phase, then by order within the phase.
+func (tree *TraceTree) Messages() []TraceMessage {
+	var messages []TraceMessage
+	for call := range tree.Calls() {
+		for _, msg := range call.L1Messages {
+			messages = append(messages, TraceMessage{
+				Phase:       call.Phase,
+				Order:       msg.Order,
+				FromAddress: call.ContractAddress,
+				Call:        call,
+				MsgToL1:     msg.MsgToL1,
+			})
+		}
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		return lessPhaseOrder(
+			messages[i].Phase,
+			messages[i].Order,
+			messages[j].Phase,
+			messages[j].Order,
+		)
+	})
+
+	return messages
+}
+
+// RevertError returns the structured revert reason of the execution, or nil if
+// it didn't revert.
+func (tree *TraceTree) RevertError() *ExecutionErrorStack {
+	if tree.RevertReason == "" {
+		return nil
+	}
+
+	return ParseRevertReason(tree.RevertReason)
+}
+
+// Decode decodes the calldata and results of the calls, and the events of the
+// tree, with the ABIs of the called classes. The calls and events of classes
+// without ABI are left undecoded. The decoded events are only returned by the
+// next calls to Events.
+//
+// Parameters:
+//   - abis: the parsed ABIs, keyed by class hash
+func (tree *TraceTree) Decode(abis map[felt.Felt]*contracts.ParsedABI) {
+	for call := range tree.Calls() {
+		call.FunctionName = ""
+		call.DecodedCalldata, call.DecodedResult, call.DecodeErr = nil, nil, nil
+
+		abi := callABI(call, abis)
+		if abi == nil {
+			continue
+		}
+
+		function, err := abi.FunctionBySelector(call.EntryPointSelector)
+		if err != nil {
+			call.DecodeErr = err
+
+			continue
+		}
+		call.FunctionName = function.Name
+
+		call.DecodedCalldata, err = abi.DecodeValues(function.Inputs, call.Calldata)
+		if err != nil {
+			call.DecodeErr = fmt.Errorf("calldata: %w", err)
+
+			continue
+		}
+		if !call.IsReverted {
+			call.DecodedResult, err = abi.DecodeValues(function.Outputs, call.Result)
+			if err != nil {
+				call.DecodeErr = fmt.Errorf("result: %w", err)
+			}
+		}
+	}
+
+	tree.decodedEvents = make(map[*OrderedEvent]*contracts.DecodedEvent)
+	for call := range tree.Calls() {
+		abi := callABI(call, abis)
+		if abi == nil {
+			continue
+		}
+		for i := range call.InvocationEvents {
+			event := &call.InvocationEvents[i]
+			if event.EventContent == nil {
+				continue
+			}
+			if decoded, err := abi.DecodeEvent(event.Keys, event.Data); err == nil {
+				tree.decodedEvents[event] = decoded
+			}
+		}
+	}
+}
+
+// Render writes an indented text representation of the tree, for debugging.
+// The decoded names and values are used when the tree has been decoded.
+//
+// Parameters:
+//   - w: the writer
+//
+// Returns:
+//   - error: an error if writing fails
+func (tree *TraceTree) Render(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s transaction trace\n", tree.Type)
+
+	phase := TracePhase("")
+	tree.Walk(func(call *TraceCall) bool {
+		if call.Phase != phase {
+			phase = call.Phase
+			fmt.Fprintf(&sb, "%s\n", phase)
+		}
+		tree.renderCall(&sb, call)
+
+		return true
+	})
+	if tree.RevertReason != "" {
+		fmt.Fprintf(&sb, "REVERTED: %s\n", tree.RevertError())
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// String returns the text representation of the tree written by Render.
+func (tree *TraceTree) String() string {
+	var sb strings.Builder
+	_ = tree.Render(&sb)
+
+	return sb.String()
+}
+
+func (tree *TraceTree) renderCall(sb *strings.Builder, call *TraceCall) {
+	indent := strings.Repeat("  ", call.Depth+1)
+
+	function := fmt.Sprint(call.EntryPointSelector)
+	if call.FunctionName != "" {
+		function = call.FunctionName
+	}
+	args := formatFelts(call.Calldata)
+	if call.DecodedCalldata != nil {
+		args = formatNamedValues(call.DecodedCalldata)
+	}
+	fmt.Fprintf(sb, "%s%s %s.%s(%s)", indent, call.CallType, call.ContractAddress, function, args)
+
+	switch {
+	case call.IsReverted:
+		sb.WriteString(" REVERTED")
+	case call.DecodedResult != nil:
+		fmt.Fprintf(sb, " -> (%s)", formatNamedValues(call.DecodedResult))
+	case len(call.Result) > 0:
+		fmt.Fprintf(sb, " -> [%s]", formatFelts(call.Result))
+	}
+	sb.WriteString("\n")
+
+	for i := range call.InvocationEvents {
+		event := &call.InvocationEvents[i]
+		if decoded := tree.decodedEvents[event]; decoded != nil {
+			fmt.Fprintf(
+				sb,
+				"%s  event %s(%s)\n",
+				indent,
+				decoded.Name,
+				formatNamedValues(decoded.Fields),
+			)
+		} else if event.EventContent != nil {
+			fmt.Fprintf(
+				sb,
+				"%s  event keys=[%s] data=[%s]\n",
+				indent,
+				formatFelts(event.Keys),
+				formatFelts(event.Data),
+			)
+		}
+	}
+	for _, msg := range call.L1Messages {
+		fmt.Fprintf(
+			sb,
+			"%s  message to %s: [%s]\n",
+			indent,
+			msg.MsgToL1.ToAddress,
+			formatFelts(msg.MsgToL1.Payload),
+		)
+	}
+}
+
+// callABI returns the ABI of the class of a call, or nil if it is unknown.
+func callABI(call *TraceCall, abis map[felt.Felt]*contracts.ParsedABI) *contracts.ParsedABI {
+	if call.ClassHash == nil {
+		return nil
+	}
+
+	return abis[*call.ClassHash]
+}
+
+func lessPhaseOrder(phaseA TracePhase, orderA int, phaseB TracePhase, orderB int) bool {
+	if phaseOrder[phaseA] != phaseOrder[phaseB] {
+		return phaseOrder[phaseA] < phaseOrder[phaseB]
+	}
+
+	return orderA < orderB
+}
+
+func formatFelts(felts []*felt.Felt) string {
+	strs := make([]string, len(felts))
+	for i, f := range felts {
+		strs[i] = f.String()
+	}
+
+	return strings.Join(strs, ", ")
+}
+
+func formatNamedValues(values []contracts.NamedValue) string {
+	strs := make([]string, len(values))
+	for i, value := range values {
+		if value.Name == "" {
+			strs[i] = formatABIValue(value.Value)
+		} else {
+			strs[i] = value.Name + ": " + formatABIValue(value.Value)
+		}
+	}
+
+	return strings.Join(strs, ", ")
+}
+
+// formatABIValue formats a value decoded by a contracts.ParsedABI.
+func formatABIValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "()"
+	case *felt.Felt:
+		return v.String()
+	case *big.Int:
+		return v.String()
+	case string:
+		return fmt.Sprintf("%q", v)
+	case []any:
+		strs := make([]string, len(v))
+		for i, elem := range v {
+			strs[i] = formatABIValue(elem)
+		}
+
+		return "[" + strings.Join(strs, ", ") + "]"
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		strs := make([]string, len(keys))
+		for i, key := range keys {
+			strs[i] = key + ": " + formatABIValue(v[key])
+		}
+
+		return "{" + strings.Join(strs, ", ") + "}"
+	case contracts.ABIEnum:
+		if v.Value == nil {
+			return v.Variant
+		}
+
+		return v.Variant + "(" + formatABIValue(v.Value) + ")"
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,201 @@
+package rpc
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/internal/tests"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewTraceTree tests building, walking and decoding the call tree of an
+// invoke transaction trace.
+func TestNewTraceTree(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	trace := internalUtils.TestUnmarshalJSONFileToType[InvokeTxnTrace](
+		t,
+		"./testData/trace/sepoliaInvokeTrace_0x6a4a9c4f1a530f7d6dd7bba9b71f090a70d1e3bbde80998fde11a08aab8b282.json",
+		"result",
+	)
+
+	tree, err := NewTraceTree(trace)
+	require.NoError(t, err)
+	assert.Equal(t, TransactionTypeInvoke, tree.Type)
+	assert.Empty(t, tree.RevertReason)
+	assert.Nil(t, tree.RevertError())
+
+	require.Len(t, tree.Roots, 3)
+	assert.Equal(t, TracePhaseValidate, tree.Roots[0].Phase)
+	assert.Equal(t, TracePhaseExecute, tree.Roots[1].Phase)
+	assert.Equal(t, TracePhaseFeeTransfer, tree.Roots[2].Phase)
+
+	t.Run("walk", func(t *testing.T) {
+		var depths []int
+		for call := range tree.Calls() {
+			depths = append(depths, call.Depth)
+		}
+		assert.Equal(t, []int{0, 0, 1, 0}, depths)
+
+		inner := tree.Roots[1].Children[0]
+		assert.Same(t, tree.Roots[1], inner.Parent)
+		assert.Equal(t, TracePhaseExecute, inner.Phase)
+		assert.Equal(t, tree.Roots[1].ContractAddress, inner.CallerAddress)
+		assert.Equal(t, CallTypeCall, inner.CallType)
+
+		visited := 0
+		tree.Walk(func(call *TraceCall) bool {
+			visited++
+
+			return call.Phase != TracePhaseExecute
+		})
+		assert.Equal(t, 3, visited, "the children of the execute call should be skipped")
+
+		visited = 0
+		for range tree.Calls() {
+			visited++
+
+			break
+		}
+		assert.Equal(t, 1, visited)
+	})
+
+	t.Run("events", func(t *testing.T) {
+		events := tree.Events()
+		require.Len(t, events, 2)
+		assert.Equal(t, TracePhaseExecute, events[0].Phase)
+		assert.Same(t, tree.Roots[1].Children[0], events[0].Call)
+		assert.Equal(t, tree.Roots[1].Children[0].ContractAddress, events[0].FromAddress)
+		assert.Equal(t, TracePhaseFeeTransfer, events[1].Phase)
+		assert.Nil(t, events[1].Decoded)
+		assert.Empty(t, tree.Messages())
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		content, err := os.ReadFile("./testData/trace/erc20_abi.json")
+		require.NoError(t, err)
+		erc20ABI, err := contracts.ParseSierraABI(contracts.NestedString(content))
+		require.NoError(t, err)
+
+		feeTransfer := tree.Roots[2]
+		tree.Decode(map[felt.Felt]*contracts.ParsedABI{*feeTransfer.ClassHash: erc20ABI})
+
+		require.NoError(t, feeTransfer.DecodeErr)
+		assert.Equal(t, "transfer", feeTransfer.FunctionName)
+		require.Len(t, feeTransfer.DecodedCalldata, 2)
+		assert.Equal(t, "recipient", feeTransfer.DecodedCalldata[0].Name)
+		assert.Equal(t, big.NewInt(0x6a25583aab3700), feeTransfer.DecodedCalldata[1].Value)
+		assert.Equal(t, []contracts.NamedValue{
+			{Type: "core::bool", Value: true},
+		}, feeTransfer.DecodedResult)
+		assert.Empty(t, tree.Roots[0].FunctionName)
+
+		events := tree.Events()
+		require.NotNil(t, events[1].Decoded)
+		assert.Equal(t, "Transfer", events[1].Decoded.Name)
+		assert.Equal(t, trace.ValidateInvocation.ContractAddress, events[1].Decoded.Fields[0].Value)
+
+		rendered := tree.String()
+		assert.Contains(t, rendered, "INVOKE transaction trace\nVALIDATE\n")
+		assert.Contains(t, rendered, "FEE_TRANSFER\n  CALL "+feeTransfer.ContractAddress.String()+
+			".transfer(recipient: 0x1176a1bd84444c89232ec27754698e5d2e7e1a7f1539f12027f28b23ec9f3d8, "+
+			"amount: 29877408402978560) -> (true)\n")
+		assert.Contains(t, rendered, "    event Transfer(from: ")
+	})
+}
+
+// TestTraceTreeRender tests the text rendering of the call tree of an L1
+// handler transaction trace.
+func TestTraceTreeRender(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	felts := func(values ...uint64) []*felt.Felt {
+		result := make([]*felt.Felt, len(values))
+		for i, value := range values {
+			result[i] = new(felt.Felt).SetUint64(value)
+		}
+
+		return result
+	}
+
+	trace := &L1HandlerTxnTrace{
+		Type: TransactionTypeL1Handler,
+		FunctionInvocation: FnInvocation{
+			FunctionCall: FunctionCall{
+				ContractAddress:    new(felt.Felt).SetUint64(0x10),
+				EntryPointSelector: new(felt.Felt).SetUint64(0x20),
+				Calldata:           felts(1, 2),
+			},
+			CallType: CallTypeCall,
+			Result:   felts(3),
+			NestedCalls: []FnInvocation{
+				{
+					FunctionCall: FunctionCall{
+						ContractAddress:    new(felt.Felt).SetUint64(0x11),
+						EntryPointSelector: new(felt.Felt).SetUint64(0x21),
+						Calldata:           []*felt.Felt{},
+					},
+					CallType: CallTypeLibraryCall,
+					InvocationEvents: []OrderedEvent{
+						{Order: 1, EventContent: &EventContent{Keys: felts(0xa), Data: felts(0xb)}},
+					},
+					IsReverted: true,
+				},
+			},
+			InvocationEvents: []OrderedEvent{
+				{Order: 0, EventContent: &EventContent{Keys: felts(0xc), Data: felts()}},
+			},
+			L1Messages: []OrderedMsg{
+				{Order: 0, MsgToL1: MsgToL1{ToAddress: new(felt.Felt).SetUint64(0xe), Payload: felts(4)}},
+			},
+		},
+	}
+
+	tree, err := NewTraceTree(trace)
+	require.NoError(t, err)
+	assert.Equal(t, TransactionTypeL1Handler, tree.Type)
+
+	expected := "L1_HANDLER transaction trace\n" +
+		"L1_HANDLER\n" +
+		"  CALL 0x10.0x20(0x1, 0x2) -> [0x3]\n" +
+		"    event keys=[0xc] data=[]\n" +
+		"    message to 0xe: [0x4]\n" +
+		"    LIBRARY_CALL 0x11.0x21() REVERTED\n" +
+		"      event keys=[0xa] data=[0xb]\n"
+	assert.Equal(t, expected, tree.String())
+
+	events := tree.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, 0, events[0].Order)
+	assert.Equal(t, new(felt.Felt).SetUint64(0x10), events[0].FromAddress)
+	assert.Equal(t, new(felt.Felt).SetUint64(0x11), events[1].FromAddress)
+
+	messages := tree.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, new(felt.Felt).SetUint64(0x10), messages[0].FromAddress)
+
+	t.Run("deploy account and declare traces", func(t *testing.T) {
+		tree, err := NewTraceTree(DeployAccountTxnTrace{
+			ValidateInvocation:    &trace.FunctionInvocation,
+			ConstructorInvocation: trace.FunctionInvocation,
+		})
+		require.NoError(t, err)
+		require.Len(t, tree.Roots, 2)
+		assert.Equal(t, TracePhaseConstructor, tree.Roots[1].Phase)
+
+		tree, err = NewTraceTree(&DeclareTxnTrace{FeeTransferInvocation: &trace.FunctionInvocation})
+		require.NoError(t, err)
+		require.Len(t, tree.Roots, 1)
+		assert.Equal(t, TracePhaseFeeTransfer, tree.Roots[0].Phase)
+	})
+
+	t.Run("unknown trace type", func(t *testing.T) {
+		_, err := NewTraceTree("trace")
+		require.ErrorIs(t, err, ErrUnknownTraceType)
+	})
+}
@@ -286,6 +286,29 @@ func (b SubscriptionBlockID) MarshalJSON() ([]byte, error) {
 	return BlockID(b).MarshalJSON()
 }
 
+// ConfirmedBlockID is a block ID that never refers to the pre-confirmed block:
+// a block hash, a block number, or the `latest` or `l1_accepted` tag. It's
+// created with WithConfirmedBlockHash, WithConfirmedBlockNumber,
+// WithLatestBlock or WithL1AcceptedBlock, so the methods taking it can return
+// the confirmed block types. The zero value is invalid.
+type ConfirmedBlockID struct {
+	blockID BlockID
+}
+
+// BlockID returns the BlockID of a ConfirmedBlockID.
+func (b ConfirmedBlockID) BlockID() BlockID {
+	return b.blockID
+}
+
+// MarshalJSON marshals the ConfirmedBlockID as its BlockID.
+func (b ConfirmedBlockID) MarshalJSON() ([]byte, error) {
+	if b.blockID.Tag == "" && b.blockID.Number == nil && b.blockID.Hash == nil {
+		return nil, ErrInvalidBlockID
+	}
+
+	return b.blockID.MarshalJSON()
+}
+
 // checkForPreConfirmed checks if the block ID has the 'pre_confirmed' tag. If it
 // does, it returns an error. This is used to prevent the user from using the
 // 'pre_confirmed' tag on methods that do not support it.
@@ -70,6 +70,49 @@ func TestBlockID_Marshal(t *testing.T) {
 	}
 }
 
+// TestConfirmedBlockID_Marshal tests the MarshalJSON method of the
+// ConfirmedBlockID struct.
+func TestConfirmedBlockID_Marshal(t *testing.T) {
+	tests.RunTestOn(t, tests.MockEnv)
+
+	for _, test := range []struct {
+		id      ConfirmedBlockID
+		want    string
+		wantErr error
+	}{
+		{
+			id:   WithLatestBlock(),
+			want: `"latest"`,
+		},
+		{
+			id:   WithL1AcceptedBlock(),
+			want: `"l1_accepted"`,
+		},
+		{
+			id:   WithConfirmedBlockNumber(420),
+			want: `{"block_number":420}`,
+		},
+		{
+			id:   WithConfirmedBlockHash(internalUtils.TestHexToFelt(t, "0xdead")),
+			want: `{"block_hash":"0xdead"}`,
+		},
+		{
+			id:      ConfirmedBlockID{},
+			wantErr: ErrInvalidBlockID,
+		},
+	} {
+		b, err := test.id.MarshalJSON()
+		if test.wantErr != nil {
+			require.ErrorIs(t, err, test.wantErr)
+
+			continue
+		}
+		require.NoError(t, err)
+
+		assert.JSONEq(t, test.want, string(b))
+	}
+}
+
 // TestSubscriptionBlockID_Marshal tests the MarshalJSON method of the SubscriptionBlockID struct.
 func TestSubscriptionBlockID_Marshal(t *testing.T) {
 	tests.RunTestOn(t, tests.MockEnv)
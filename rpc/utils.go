@@ -62,16 +62,11 @@ func EstimateTip(
 	tip U64,
 	err error,
 ) {
-	rawLatestBlock, err := provider.BlockWithTxs(ctx, WithBlockTag(BlockTagLatest))
+	latestBlock, err := provider.ConfirmedBlockWithTxs(ctx, WithLatestBlock())
 	if err != nil {
 		return tip, fmt.Errorf("failed to get latest block: %w", err)
 	}
 
-	latestBlock, ok := rawLatestBlock.(*Block)
-	if !ok {
-		return tip, fmt.Errorf("unexpected block type: %T", rawLatestBlock)
-	}
-
 	var tipStruct struct {
 		Tip U64 `json:"tip"`
 	}
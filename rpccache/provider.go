@@ -0,0 +1,570 @@
+// Package rpccache caches the results of the Starknet JSON-RPC methods that
+// can't change anymore, e.g. the classes, the blocks queried by hash and the
+// receipts of the transactions accepted on L1.
+//
+// A Provider wraps any rpc.RPCProvider, and only caches the results that are
+// provably immutable:
+//   - the classes and compiled classes, queried by class hash
+//   - the blocks, state updates, storage values, nonces, class hashes and
+//     transactions queried at a block hash, or at a block number lower than or
+//     equal to the number of the latest block accepted on L1
+//   - the receipts of the transactions accepted on L1
+//
+// The results queried with a block tag (latest, pre_confirmed or l1_accepted)
+// are never cached. The other methods are forwarded to the wrapped provider.
+// The keys of the results are prefixed with the chain ID of the provider, so
+// the providers of several chains can share a store.
+//
+//	store := rpccache.TieredStore{rpccache.NewMemoryStore(256 << 20), diskStore}
+//	provider, err := rpccache.New(rpcProvider, store)
+//	if err != nil {
+//		return err
+//	}
+//	class, err := provider.Class(ctx, rpc.WithBlockNumber(100), classHash)
+package rpccache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// DefaultL1HeadRefreshInterval is the minimum interval between two queries of
+// the latest block accepted on L1, when none is specified.
+const DefaultL1HeadRefreshInterval = 30 * time.Second
+
+// Provider is an rpc.RPCProvider caching the immutable results of the wrapped
+// provider in a Store. It is safe for concurrent use.
+type Provider struct {
+	rpc.RPCProvider
+
+	store Store
+	// the prefix of the keys, with the chain ID of the provider
+	keyPrefix       string
+	refreshInterval time.Duration
+	onError         func(error)
+
+	mu sync.Mutex
+	// the number of the latest block accepted on L1, and when it was fetched
+	l1Head        uint64
+	l1HeadKnown   bool
+	l1HeadFetched time.Time
+	// closed once the head being fetched is known, nil if it isn't fetched
+	l1HeadFetching chan struct{}
+}
+
+var _ rpc.RPCProvider = (*Provider)(nil)
+
+// Option configures a Provider.
+type Option func(*Provider)
+
+// WithL1HeadRefreshInterval sets the minimum interval between two queries of
+// the latest block accepted on L1. The head is only queried to know whether a
+// block number is immutable, when the number is above the last known head.
+func WithL1HeadRefreshInterval(interval time.Duration) Option {
+	return func(p *Provider) {
+		p.refreshInterval = interval
+	}
+}
+
+// WithErrorHandler sets a function called with the errors of the store, and
+// with the errors decoding the cached values. These errors never fail the
+// requests: the failing entries are fetched from the wrapped provider instead.
+func WithErrorHandler(onError func(err error)) Option {
+	return func(p *Provider) {
+		p.onError = onError
+	}
+}
+
+// New creates a caching provider. The chain ID of the wrapped provider is
+// fetched to namespace the cached results.
+//
+// Parameters:
+//   - provider: The wrapped provider, queried on cache misses
+//   - store: The store of the cached results, e.g. a MemoryStore
+//   - opts: The options of the provider
+//
+// Returns:
+//   - *Provider: The new provider
+//   - error: An error if the chain ID can't be fetched
+func New(provider rpc.RPCProvider, store Store, opts ...Option) (*Provider, error) {
+	chainID, err := provider.ChainID(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the chain ID: %w", err)
+	}
+
+	p := &Provider{
+		RPCProvider:     provider,
+		store:           store,
+		keyPrefix:       chainID + "/",
+		refreshInterval: DefaultL1HeadRefreshInterval,
+		onError:         func(error) {},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p, nil
+}
+
+// Class returns the class of a class hash, from the cache if it was fetched
+// before at an immutable block.
+func (p *Provider) Class(
+	ctx context.Context,
+	blockID rpc.BlockID,
+	classHash *felt.Felt,
+) (rpc.ClassOutput, error) {
+	if blockID.Tag != "" {
+		return p.RPCProvider.Class(ctx, blockID, classHash)
+	}
+
+	// the class of a hash is the same at all the blocks where it's declared
+	key := "class/" + classHash.String()
+	if class, ok := p.getClass(key); ok {
+		return class, nil
+	}
+	class, err := p.RPCProvider.Class(ctx, blockID, classHash)
+	if err != nil {
+		return nil, err
+	}
+	if p.immutable(ctx, blockID) {
+		p.set(key, class)
+	}
+
+	return class, nil
+}
+
+// ClassAt returns the class of a contract, from the cache if the block is
+// immutable.
+func (p *Provider) ClassAt(
+	ctx context.Context,
+	blockID rpc.BlockID,
+	contractAddress *felt.Felt,
+) (rpc.ClassOutput, error) {
+	key, ok := blockKey(blockID, "classAt/"+contractAddress.String())
+	if !ok {
+		return p.RPCProvider.ClassAt(ctx, blockID, contractAddress)
+	}
+
+	if class, ok := p.getClass(key); ok {
+		return class, nil
+	}
+	class, err := p.RPCProvider.ClassAt(ctx, blockID, contractAddress)
+	if err != nil {
+		return nil, err
+	}
+	if p.immutable(ctx, blockID) {
+		p.set(key, class)
+	}
+
+	return class, nil
+}
+
+// ClassHashAt returns the class hash of a contract, from the cache if the
+// block is immutable.
+func (p *Provider) ClassHashAt(
+	ctx context.Context,
+	blockID rpc.BlockID,
+	contractAddress *felt.Felt,
+) (*felt.Felt, error) {
+	return cachedAt(ctx, p, blockID, "classHashAt/"+contractAddress.String(),
+		func() (*felt.Felt, error) {
+			return p.RPCProvider.ClassHashAt(ctx, blockID, contractAddress)
+		})
+}
+
+// CompiledCasm returns the compiled class of a class hash, from the cache if
+// it was fetched before.
+func (p *Provider) CompiledCasm(
+	ctx context.Context,
+	classHash *felt.Felt,
+) (*contracts.CasmClass, error) {
+	key := "casm/" + classHash.String()
+	if casm, ok := get[*contracts.CasmClass](p, key); ok {
+		return casm, nil
+	}
+	casm, err := p.RPCProvider.CompiledCasm(ctx, classHash)
+	if err != nil {
+		return nil, err
+	}
+	p.set(key, casm)
+
+	return casm, nil
+}
+
+// BlockTransactionCount returns the number of transactions of a block, from
+// the cache if the block is immutable.
+func (p *Provider) BlockTransactionCount(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	return cachedAt(ctx, p, blockID, "txCount", func() (uint64, error) {
+		return p.RPCProvider.BlockTransactionCount(ctx, blockID)
+	})
+}
+
+// BlockWithTxHashes returns a block with its transaction hashes, from the
+// cache if the block is immutable.
+func (p *Provider) BlockWithTxHashes(
+	ctx context.Context,
+	blockID rpc.BlockID,
+) (rpc.BlockView, error) {
+	return cachedBlock[*rpc.BlockTxHashes](ctx, p, blockID, "blockWithTxHashes",
+		p.RPCProvider.BlockWithTxHashes)
+}
+
+// BlockWithTxs returns a block with its transactions, from the cache if the
+// block is immutable.
+func (p *Provider) BlockWithTxs(ctx context.Context, blockID rpc.BlockID) (rpc.BlockView, error) {
+	return cachedBlock[*rpc.Block](ctx, p, blockID, "blockWithTxs", p.RPCProvider.BlockWithTxs)
+}
+
+// BlockWithReceipts returns a block with its transactions and receipts, from
+// the cache if the block is immutable.
+func (p *Provider) BlockWithReceipts(
+	ctx context.Context,
+	blockID rpc.BlockID,
+) (rpc.BlockView, error) {
+	return cachedBlock[*rpc.BlockWithReceipts](ctx, p, blockID, "blockWithReceipts",
+		p.RPCProvider.BlockWithReceipts)
+}
+
+// ConfirmedBlockWithTxHashes returns a confirmed block with its transaction
+// hashes, from the cache if the block is immutable.
+func (p *Provider) ConfirmedBlockWithTxHashes(
+	ctx context.Context,
+	blockID rpc.ConfirmedBlockID,
+) (*rpc.BlockTxHashes, error) {
+	return cachedAt(ctx, p, blockID.BlockID(), "blockWithTxHashes",
+		func() (*rpc.BlockTxHashes, error) {
+			return p.RPCProvider.ConfirmedBlockWithTxHashes(ctx, blockID)
+		})
+}
+
+// ConfirmedBlockWithTxs returns a confirmed block with its transactions, from
+// the cache if the block is immutable.
+func (p *Provider) ConfirmedBlockWithTxs(
+	ctx context.Context,
+	blockID rpc.ConfirmedBlockID,
+) (*rpc.Block, error) {
+	return cachedAt(ctx, p, blockID.BlockID(), "blockWithTxs", func() (*rpc.Block, error) {
+		return p.RPCProvider.ConfirmedBlockWithTxs(ctx, blockID)
+	})
+}
+
+// ConfirmedBlockWithReceipts returns a confirmed block with its transactions
+// and receipts, from the cache if the block is immutable.
+func (p *Provider) ConfirmedBlockWithReceipts(
+	ctx context.Context,
+	blockID rpc.ConfirmedBlockID,
+) (*rpc.BlockWithReceipts, error) {
+	return cachedAt(ctx, p, blockID.BlockID(), "blockWithReceipts",
+		func() (*rpc.BlockWithReceipts, error) {
+			return p.RPCProvider.ConfirmedBlockWithReceipts(ctx, blockID)
+		})
+}
+
+// StateUpdate returns the state update of a block, from the cache if the block
+// is immutable.
+func (p *Provider) StateUpdate(
+	ctx context.Context,
+	blockID rpc.BlockID,
+) (*rpc.StateUpdateOutput, error) {
+	key, ok := blockKey(blockID, "stateUpdate")
+	if !ok {
+		return p.RPCProvider.StateUpdate(ctx, blockID)
+	}
+
+	if stateUpdate, ok := get[*rpc.StateUpdate](p, key); ok {
+		return &rpc.StateUpdateOutput{StateUpdate: stateUpdate}, nil
+	}
+	output, err := p.RPCProvider.StateUpdate(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	// the state updates of the pre-confirmed blocks are never cached
+	if output.StateUpdate != nil && p.immutable(ctx, blockID) {
+		p.set(key, output.StateUpdate)
+	}
+
+	return output, nil
+}
+
+// StorageAt returns the value of a storage key, from the cache if the block is
+// immutable.
+func (p *Provider) StorageAt(
+	ctx context.Context,
+	contractAddress *felt.Felt,
+	key string,
+	blockID rpc.BlockID,
+) (string, error) {
+	return cachedAt(ctx, p, blockID, "storageAt/"+contractAddress.String()+"/"+key,
+		func() (string, error) {
+			return p.RPCProvider.StorageAt(ctx, contractAddress, key, blockID)
+		})
+}
+
+// Nonce returns the nonce of a contract, from the cache if the block is
+// immutable.
+func (p *Provider) Nonce(
+	ctx context.Context,
+	blockID rpc.BlockID,
+	contractAddress *felt.Felt,
+) (*felt.Felt, error) {
+	return cachedAt(ctx, p, blockID, "nonce/"+contractAddress.String(),
+		func() (*felt.Felt, error) {
+			return p.RPCProvider.Nonce(ctx, blockID, contractAddress)
+		})
+}
+
+// TransactionByBlockIDAndIndex returns a transaction of a block, from the
+// cache if the block is immutable.
+func (p *Provider) TransactionByBlockIDAndIndex(
+	ctx context.Context,
+	blockID rpc.BlockID,
+	index uint64,
+) (*rpc.BlockTransaction, error) {
+	return cachedAt(ctx, p, blockID, "txByIndex/"+strconv.FormatUint(index, 10),
+		func() (*rpc.BlockTransaction, error) {
+			return p.RPCProvider.TransactionByBlockIDAndIndex(ctx, blockID, index)
+		})
+}
+
+// TransactionReceipt returns the receipt of a transaction, from the cache if
+// the transaction was accepted on L1 when it was fetched before.
+func (p *Provider) TransactionReceipt(
+	ctx context.Context,
+	transactionHash *felt.Felt,
+) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	key := "receipt/" + transactionHash.String()
+	if receipt, ok := get[*rpc.TransactionReceiptWithBlockInfo](p, key); ok {
+		return receipt, nil
+	}
+	receipt, err := p.RPCProvider.TransactionReceipt(ctx, transactionHash)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.FinalityStatus == rpc.TxnFinalityStatusAcceptedOnL1 && receipt.BlockHash != nil {
+		p.set(key, receipt)
+	}
+
+	return receipt, nil
+}
+
+// cachedAt returns the result of a method queried at a block, from the cache
+// if the block is immutable, or from fetch.
+func cachedAt[T any](
+	ctx context.Context,
+	p *Provider,
+	blockID rpc.BlockID,
+	method string,
+	fetch func() (T, error),
+) (T, error) {
+	key, ok := blockKey(blockID, method)
+	if !ok {
+		return fetch()
+	}
+
+	if result, ok := get[T](p, key); ok {
+		return result, nil
+	}
+	result, err := fetch()
+	if err != nil {
+		return result, err
+	}
+	if p.immutable(ctx, blockID) {
+		p.set(key, result)
+	}
+
+	return result, nil
+}
+
+// cachedBlock returns a block queried with a method returning either a block
+// of type T or a pre-confirmed block, from the cache if the block is immutable.
+func cachedBlock[T rpc.BlockView](
+	ctx context.Context,
+	p *Provider,
+	blockID rpc.BlockID,
+	method string,
+	fetch func(ctx context.Context, blockID rpc.BlockID) (rpc.BlockView, error),
+) (rpc.BlockView, error) {
+	key, ok := blockKey(blockID, method)
+	if !ok {
+		return fetch(ctx, blockID)
+	}
+
+	if block, ok := get[T](p, key); ok {
+		return block, nil
+	}
+	result, err := fetch(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	// the pre-confirmed blocks have another type, and are never cached
+	if block, ok := result.(T); ok && p.immutable(ctx, blockID) {
+		p.set(key, block)
+	}
+
+	return result, nil
+}
+
+// blockKey returns the cache key of a method queried at a block, or false if
+// the block ID is a tag, whose results are never cached.
+func blockKey(blockID rpc.BlockID, method string) (string, bool) {
+	switch {
+	case blockID.Tag != "":
+		return "", false
+	case blockID.Hash != nil:
+		return method + "/hash/" + blockID.Hash.String(), true
+	case blockID.Number != nil:
+		return method + "/number/" + strconv.FormatUint(*blockID.Number, 10), true
+	default:
+		return "", false
+	}
+}
+
+// immutable reports whether the state at a block can't change anymore: the
+// block is queried by hash, or by a number lower than or equal to the number
+// of the latest block accepted on L1.
+func (p *Provider) immutable(ctx context.Context, blockID rpc.BlockID) bool {
+	switch {
+	case blockID.Tag != "":
+		return false
+	case blockID.Hash != nil:
+		return true
+	case blockID.Number == nil:
+		return false
+	}
+
+	head, ok := p.l1HeadNumber(ctx, *blockID.Number)
+
+	return ok && *blockID.Number <= head
+}
+
+// l1HeadNumber returns the number of the latest block accepted on L1. The
+// head is only fetched again if it's below the wanted block number, and at
+// most once per refresh interval. The concurrent lookups needing the head
+// while it's fetched wait for it, instead of fetching it again, and the lock
+// isn't held while fetching.
+func (p *Provider) l1HeadNumber(ctx context.Context, wanted uint64) (uint64, bool) {
+	p.mu.Lock()
+	if p.l1HeadKnown && (wanted <= p.l1Head || time.Since(p.l1HeadFetched) < p.refreshInterval) {
+		defer p.mu.Unlock()
+
+		return p.l1Head, true
+	}
+	if fetching := p.l1HeadFetching; fetching != nil {
+		p.mu.Unlock()
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+		}
+
+		return p.knownL1Head()
+	}
+	fetching := make(chan struct{})
+	p.l1HeadFetching = fetching
+	p.mu.Unlock()
+
+	block, err := p.RPCProvider.ConfirmedBlockWithTxHashes(ctx, rpc.WithL1AcceptedBlock())
+
+	p.mu.Lock()
+	p.l1HeadFetching = nil
+	close(fetching)
+	if err == nil {
+		p.l1Head, p.l1HeadKnown, p.l1HeadFetched = block.Number, true, time.Now()
+	}
+	p.mu.Unlock()
+	if err != nil {
+		p.onError(fmt.Errorf("failed to fetch the latest block accepted on L1: %w", err))
+	}
+
+	return p.knownL1Head()
+}
+
+// knownL1Head returns the last known number of the latest block accepted on
+// L1, or false if it was never fetched.
+func (p *Provider) knownL1Head() (uint64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.l1Head, p.l1HeadKnown
+}
+
+// getClass returns a cached class, decoded as a contract class or a
+// deprecated contract class.
+func (p *Provider) getClass(key string) (rpc.ClassOutput, bool) {
+	value, ok := p.get(key)
+	if !ok {
+		return nil, false
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(value, &fields); err != nil {
+		p.onError(fmt.Errorf("failed to decode %q: %w", key, err))
+
+		return nil, false
+	}
+	// if contract_class_version exists, then it's a ContractClass type
+	if _, exists := fields["contract_class_version"]; exists {
+		return decode[*contracts.ContractClass](p, key, value)
+	}
+
+	return decode[*contracts.DeprecatedContractClass](p, key, value)
+}
+
+// get returns a cached result, or false if it isn't cached.
+func get[T any](p *Provider, key string) (T, bool) {
+	value, ok := p.get(key)
+	if !ok {
+		var zero T
+
+		return zero, false
+	}
+
+	return decode[T](p, key, value)
+}
+
+// get returns a cached value, or false if it isn't cached.
+func (p *Provider) get(key string) ([]byte, bool) {
+	value, err := p.store.Get(p.keyPrefix + key)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			p.onError(fmt.Errorf("failed to get %q: %w", key, err))
+		}
+
+		return nil, false
+	}
+
+	return value, true
+}
+
+// decode decodes a cached value, or returns false if it's invalid.
+func decode[T any](p *Provider, key string, value []byte) (T, bool) {
+	var result T
+	if err := json.Unmarshal(value, &result); err != nil {
+		p.onError(fmt.Errorf("failed to decode %q: %w", key, err))
+
+		return result, false
+	}
+
+	return result, true
+}
+
+// set caches a result.
+func (p *Provider) set(key string, result any) {
+	value, err := json.Marshal(result)
+	if err != nil {
+		p.onError(fmt.Errorf("failed to encode %q: %w", key, err))
+
+		return
+	}
+	if err := p.store.Set(p.keyPrefix+key, value); err != nil {
+		p.onError(fmt.Errorf("failed to set %q: %w", key, err))
+	}
+}
@@ -0,0 +1,531 @@
+package rpccache_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/internal/tests/mocks/rpcv10mock"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/rpccache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testData = "../rpc/testData/"
+
+// loadResult loads the result of a JSON-RPC response of the rpc test data.
+func loadResult[T any](t *testing.T, file string) T {
+	t.Helper()
+
+	result, err := internalUtils.UnmarshalJSONFileToType[T](testData+file, "result")
+	require.NoError(t, err)
+
+	return result
+}
+
+// expectL1Head expects the latest block accepted on L1 to be fetched once.
+func expectL1Head(mockRPCProvider *rpcv10mock.MockRPCProvider, number uint64) {
+	mockRPCProvider.EXPECT().
+		ConfirmedBlockWithTxHashes(gomock.Any(), rpc.WithL1AcceptedBlock()).
+		Return(&rpc.BlockTxHashes{BlockHeader: rpc.BlockHeader{Number: number}}, nil)
+}
+
+// newProvider creates a caching provider of a mock provider on Sepolia.
+func newProvider(
+	t *testing.T,
+	mockRPCProvider *rpcv10mock.MockRPCProvider,
+	store rpccache.Store,
+	opts ...rpccache.Option,
+) *rpccache.Provider {
+	t.Helper()
+
+	mockRPCProvider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+	provider, err := rpccache.New(mockRPCProvider, store, opts...)
+	require.NoError(t, err)
+
+	return provider
+}
+
+// TestProvider_Results tests that the results are decoded back from the store
+// into their types, with a disk store.
+func TestProvider_Results(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+	store, err := rpccache.NewDiskStore(t.TempDir())
+	require.NoError(t, err)
+	provider := newProvider(t, mockRPCProvider, store, rpccache.WithErrorHandler(func(err error) {
+		t.Errorf("unexpected cache error: %v", err)
+	}))
+	ctx := t.Context()
+	block := rpc.WithBlockHash(internalUtils.TestHexToFelt(t, "0x1"))
+
+	t.Run("classes", func(t *testing.T) {
+		classHash := internalUtils.TestHexToFelt(t, "0x2")
+		sierraClass := loadResult[*contracts.ContractClass](
+			t,
+			"class/0x01f372292df22d28f2d4c5798734421afe9596e6a566b8bc9b7b50e26521b855.json",
+		)
+		mockRPCProvider.EXPECT().Class(ctx, block, classHash).Return(sierraClass, nil)
+		deprecatedClass := loadResult[*contracts.DeprecatedContractClass](
+			t,
+			"class/0x036c7e49a16f8fc760a6fbdf71dde543d98be1fee2eda5daff59a0eeae066ed9.json",
+		)
+		address := internalUtils.TestHexToFelt(t, "0x3")
+		mockRPCProvider.EXPECT().ClassAt(ctx, block, address).Return(deprecatedClass, nil)
+
+		for range 2 {
+			class, err := provider.Class(ctx, block, classHash)
+			require.NoError(t, err)
+			assert.Equal(t, sierraClass, class)
+
+			class, err = provider.ClassAt(ctx, block, address)
+			require.NoError(t, err)
+			assert.Equal(t, deprecatedClass, class)
+		}
+	})
+
+	t.Run("compiled class", func(t *testing.T) {
+		classHash := internalUtils.TestHexToFelt(t, "0x4")
+		casm := loadResult[*contracts.CasmClass](t, "compiledCasm/sepolia.json")
+		mockRPCProvider.EXPECT().CompiledCasm(ctx, classHash).Return(casm, nil)
+
+		for range 2 {
+			result, err := provider.CompiledCasm(ctx, classHash)
+			require.NoError(t, err)
+			assert.Equal(t, casm, result)
+		}
+	})
+
+	t.Run("blocks", func(t *testing.T) {
+		blockWithTxs := loadResult[*rpc.Block](t, "blockWithTxns/sepolia3100000.json")
+		mockRPCProvider.EXPECT().BlockWithTxs(ctx, block).Return(blockWithTxs, nil)
+		blockWithReceipts := loadResult[*rpc.BlockWithReceipts](
+			t,
+			"blockWithReceipts/sepolia3100000.json",
+		)
+		mockRPCProvider.EXPECT().BlockWithReceipts(ctx, block).Return(blockWithReceipts, nil)
+		stateUpdate := loadResult[*rpc.StateUpdateOutput](t, "stateUpdate/sepolia3100000.json")
+		mockRPCProvider.EXPECT().StateUpdate(ctx, block).Return(stateUpdate, nil)
+
+		for range 2 {
+			result, err := provider.BlockWithTxs(ctx, block)
+			require.NoError(t, err)
+			assert.Equal(t, blockWithTxs, result)
+
+			result, err = provider.BlockWithReceipts(ctx, block)
+			require.NoError(t, err)
+			assert.Equal(t, blockWithReceipts, result)
+
+			output, err := provider.StateUpdate(ctx, block)
+			require.NoError(t, err)
+			assert.Equal(t, stateUpdate, output)
+		}
+
+		// the typed methods share the cache entries of the untyped ones
+		confirmedID := rpc.WithConfirmedBlockHash(block.Hash)
+		confirmedBlock, err := provider.ConfirmedBlockWithTxs(ctx, confirmedID)
+		require.NoError(t, err)
+		assert.Equal(t, blockWithTxs, confirmedBlock)
+		confirmedReceipts, err := provider.ConfirmedBlockWithReceipts(ctx, confirmedID)
+		require.NoError(t, err)
+		assert.Equal(t, blockWithReceipts, confirmedReceipts)
+	})
+
+	t.Run("receipt", func(t *testing.T) {
+		txHash := internalUtils.TestHexToFelt(t, "0x5")
+		receipt := loadResult[*rpc.TransactionReceiptWithBlockInfo](
+			t,
+			"receipt/sepoliaReceipt.json",
+		)
+		mockRPCProvider.EXPECT().TransactionReceipt(ctx, txHash).Return(receipt, nil)
+
+		for range 2 {
+			result, err := provider.TransactionReceipt(ctx, txHash)
+			require.NoError(t, err)
+			assert.Equal(t, receipt, result)
+		}
+	})
+}
+
+// TestProvider_Chains tests that the providers of several chains sharing a
+// store don't return the results of each other.
+func TestProvider_Chains(t *testing.T) {
+	ctx := t.Context()
+	store, err := rpccache.NewDiskStore(t.TempDir())
+	require.NoError(t, err)
+	blockID := rpc.WithBlockNumber(100)
+	address := new(felt.Felt).SetUint64(0x1234)
+
+	newChainProvider := func(chainID string, nonce uint64) *rpccache.Provider {
+		mockRPCProvider := rpcv10mock.NewMockRPCProvider(gomock.NewController(t))
+		mockRPCProvider.EXPECT().ChainID(gomock.Any()).Return(chainID, nil)
+		expectL1Head(mockRPCProvider, 100)
+		mockRPCProvider.EXPECT().
+			Nonce(ctx, blockID, address).
+			Return(new(felt.Felt).SetUint64(nonce), nil)
+		provider, err := rpccache.New(mockRPCProvider, store)
+		require.NoError(t, err)
+
+		return provider
+	}
+	mainnet := newChainProvider("SN_MAIN", 1)
+	sepolia := newChainProvider("SN_SEPOLIA", 2)
+
+	for range 2 {
+		nonce, err := mainnet.Nonce(ctx, blockID, address)
+		require.NoError(t, err)
+		assert.Equal(t, new(felt.Felt).SetUint64(1), nonce)
+
+		nonce, err = sepolia.Nonce(ctx, blockID, address)
+		require.NoError(t, err)
+		assert.Equal(t, new(felt.Felt).SetUint64(2), nonce)
+	}
+
+	// the chain ID is required
+	errChainID := errors.New("chain ID unavailable")
+	mockRPCProvider := rpcv10mock.NewMockRPCProvider(gomock.NewController(t))
+	mockRPCProvider.EXPECT().ChainID(gomock.Any()).Return("", errChainID)
+	_, err = rpccache.New(mockRPCProvider, store)
+	require.ErrorIs(t, err, errChainID)
+}
+
+// TestProvider_Immutability tests which results are cached, depending on the
+// block ID and on the finality of the receipts.
+func TestProvider_Immutability(t *testing.T) {
+	ctx := t.Context()
+	address := new(felt.Felt).SetUint64(0x1234)
+	classHash := new(felt.Felt).SetUint64(0x5678)
+
+	testSet := []struct {
+		name    string
+		blockID rpc.BlockID
+		// the number of the latest block accepted on L1, if it's fetched
+		l1Head *uint64
+		cached bool
+	}{
+		{
+			name:    "block hash",
+			blockID: rpc.WithBlockHash(new(felt.Felt).SetUint64(1)),
+			cached:  true,
+		},
+		{
+			name:    "number below the L1 head",
+			blockID: rpc.WithBlockNumber(90),
+			l1Head:  ptr(100),
+			cached:  true,
+		},
+		{
+			name:    "number of the L1 head",
+			blockID: rpc.WithBlockNumber(100),
+			l1Head:  ptr(100),
+			cached:  true,
+		},
+		{name: "number above the L1 head", blockID: rpc.WithBlockNumber(101), l1Head: ptr(100)},
+		{name: "latest", blockID: rpc.WithBlockTag(rpc.BlockTagLatest)},
+		{name: "pre_confirmed", blockID: rpc.WithBlockTag(rpc.BlockTagPreConfirmed)},
+		{name: "l1_accepted", blockID: rpc.WithBlockTag(rpc.BlockTagL1Accepted)},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+			provider := newProvider(t, mockRPCProvider, rpccache.NewMemoryStore(1<<20))
+			if test.l1Head != nil {
+				// the head is fetched once, as the refresh interval isn't over
+				expectL1Head(mockRPCProvider, *test.l1Head)
+			}
+			times := 2
+			if test.cached {
+				times = 1
+			}
+			mockRPCProvider.EXPECT().
+				Nonce(ctx, test.blockID, address).
+				Return(new(felt.Felt).SetUint64(3), nil).
+				Times(times)
+			mockRPCProvider.EXPECT().
+				StorageAt(ctx, address, "0x1", test.blockID).
+				Return("0x2", nil).
+				Times(times)
+			mockRPCProvider.EXPECT().
+				ClassHashAt(ctx, test.blockID, address).
+				Return(classHash, nil).
+				Times(times)
+
+			for range 2 {
+				nonce, err := provider.Nonce(ctx, test.blockID, address)
+				require.NoError(t, err)
+				assert.Equal(t, new(felt.Felt).SetUint64(3), nonce)
+
+				value, err := provider.StorageAt(ctx, address, "0x1", test.blockID)
+				require.NoError(t, err)
+				assert.Equal(t, "0x2", value)
+
+				result, err := provider.ClassHashAt(ctx, test.blockID, address)
+				require.NoError(t, err)
+				assert.Equal(t, classHash, result)
+			}
+		})
+	}
+
+	t.Run("L1 head refresh", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+		provider := newProvider(
+			t,
+			mockRPCProvider,
+			rpccache.NewMemoryStore(1<<20),
+			rpccache.WithL1HeadRefreshInterval(0),
+		)
+		blockID := rpc.WithBlockNumber(101)
+		mockRPCProvider.EXPECT().BlockTransactionCount(ctx, blockID).Return(uint64(4), nil).Times(2)
+
+		// the block is above the head, then accepted on L1
+		expectL1Head(mockRPCProvider, 100)
+		expectL1Head(mockRPCProvider, 101)
+		for range 3 {
+			count, err := provider.BlockTransactionCount(ctx, blockID)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(4), count)
+		}
+
+		// the head isn't fetched again for the blocks below it
+		older := rpc.WithBlockNumber(50)
+		mockRPCProvider.EXPECT().BlockTransactionCount(ctx, older).Return(uint64(1), nil)
+		_, err := provider.BlockTransactionCount(ctx, older)
+		require.NoError(t, err)
+	})
+
+	t.Run("concurrent L1 head fetch", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+		provider := newProvider(
+			t,
+			mockRPCProvider,
+			rpccache.NewMemoryStore(1<<20),
+			rpccache.WithL1HeadRefreshInterval(0),
+		)
+		known, below, above := rpc.WithBlockNumber(50), rpc.WithBlockNumber(60),
+			rpc.WithBlockNumber(101)
+		mockRPCProvider.EXPECT().BlockTransactionCount(ctx, known).Return(uint64(1), nil)
+		mockRPCProvider.EXPECT().BlockTransactionCount(ctx, below).Return(uint64(2), nil)
+		mockRPCProvider.EXPECT().
+			BlockTransactionCount(ctx, above).
+			Return(uint64(4), nil).
+			MinTimes(1).
+			MaxTimes(2)
+		expectL1Head(mockRPCProvider, 100)
+		fetching, release := make(chan struct{}), make(chan struct{})
+		mockRPCProvider.EXPECT().
+			ConfirmedBlockWithTxHashes(gomock.Any(), rpc.WithL1AcceptedBlock()).
+			DoAndReturn(func(context.Context, rpc.ConfirmedBlockID) (*rpc.BlockTxHashes, error) {
+				close(fetching)
+				<-release
+
+				return &rpc.BlockTxHashes{BlockHeader: rpc.BlockHeader{Number: 101}}, nil
+			})
+
+		_, err := provider.BlockTransactionCount(ctx, known)
+		require.NoError(t, err)
+
+		// the lookups above the head share a single fetch
+		var wg sync.WaitGroup
+		for range 2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				count, err := provider.BlockTransactionCount(ctx, above)
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(4), count)
+			}()
+		}
+		<-fetching
+
+		// the lookups below the known head don't wait for the fetch
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			count, err := provider.BlockTransactionCount(ctx, below)
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(2), count)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("the lookup waited for the L1 head fetch")
+		}
+		close(release)
+		wg.Wait()
+		<-done
+
+		// the block above the previous head is now immutable
+		count, err := provider.BlockTransactionCount(ctx, above)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(4), count)
+	})
+
+	t.Run("pre-confirmed results", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+		provider := newProvider(t, mockRPCProvider, rpccache.NewMemoryStore(1<<20))
+		blockID := rpc.WithBlockNumber(10)
+		mockRPCProvider.EXPECT().
+			BlockWithTxHashes(ctx, blockID).
+			Return(&rpc.PreConfirmedBlockTxHashes{}, nil).
+			Times(2)
+
+		for range 2 {
+			block, err := provider.BlockWithTxHashes(ctx, blockID)
+			require.NoError(t, err)
+			assert.IsType(t, &rpc.PreConfirmedBlockTxHashes{}, block)
+		}
+	})
+
+	t.Run("receipt finality", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+		provider := newProvider(t, mockRPCProvider, rpccache.NewMemoryStore(1<<20))
+		txHash := new(felt.Felt).SetUint64(7)
+		receipt := &rpc.TransactionReceiptWithBlockInfo{
+			TransactionReceipt: rpc.TransactionReceipt{
+				Hash:           txHash,
+				FinalityStatus: rpc.TxnFinalityStatusAcceptedOnL2,
+			},
+			BlockHash: new(felt.Felt).SetUint64(8),
+		}
+		mockRPCProvider.EXPECT().TransactionReceipt(ctx, txHash).Return(receipt, nil).Times(2)
+
+		for range 2 {
+			_, err := provider.TransactionReceipt(ctx, txHash)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+		provider := newProvider(t, mockRPCProvider, rpccache.NewMemoryStore(1<<20))
+		mockRPCProvider.EXPECT().
+			CompiledCasm(ctx, classHash).
+			Return(nil, rpc.ErrClassHashNotFound).
+			Times(2)
+
+		for range 2 {
+			_, err := provider.CompiledCasm(ctx, classHash)
+			require.ErrorIs(t, err, rpc.ErrClassHashNotFound)
+		}
+	})
+}
+
+// failingStore is a store failing all its operations.
+type failingStore struct{}
+
+var errStore = errors.New("store failure")
+
+func (failingStore) Get(string) ([]byte, error) { return nil, errStore }
+
+func (failingStore) Set(string, []byte) error { return errStore }
+
+// TestProvider_StoreErrors tests that the errors of the store and the invalid
+// entries don't fail the requests.
+func TestProvider_StoreErrors(t *testing.T) {
+	ctx := t.Context()
+	classHash := new(felt.Felt).SetUint64(1)
+	casm := &contracts.CasmClass{CompilerVersion: "2.11.4"}
+
+	t.Run("failing store", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+		var errs []error
+		provider := newProvider(t, mockRPCProvider, failingStore{}, rpccache.WithErrorHandler(
+			func(err error) { errs = append(errs, err) },
+		))
+		mockRPCProvider.EXPECT().CompiledCasm(ctx, classHash).Return(casm, nil)
+
+		result, err := provider.CompiledCasm(ctx, classHash)
+		require.NoError(t, err)
+		assert.Equal(t, casm, result)
+		require.Len(t, errs, 2)
+		require.ErrorIs(t, errs[0], errStore)
+		require.ErrorIs(t, errs[1], errStore)
+	})
+
+	t.Run("corrupted entry", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+		dir := t.TempDir()
+		store, err := rpccache.NewDiskStore(dir)
+		require.NoError(t, err)
+		var errs []error
+		provider := newProvider(t, mockRPCProvider, store, rpccache.WithErrorHandler(
+			func(err error) { errs = append(errs, err) },
+		))
+		mockRPCProvider.EXPECT().CompiledCasm(ctx, classHash).Return(casm, nil).Times(2)
+
+		_, err = provider.CompiledCasm(ctx, classHash)
+		require.NoError(t, err)
+		files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		require.NoError(t, os.WriteFile(files[0], []byte("{"), 0o600))
+
+		// the entry is fetched again and overwritten
+		result, err := provider.CompiledCasm(ctx, classHash)
+		require.NoError(t, err)
+		assert.Equal(t, casm, result)
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "failed to decode")
+	})
+
+	t.Run("L1 head failure", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+		provider := newProvider(t, mockRPCProvider, rpccache.NewMemoryStore(1<<20))
+		blockID := rpc.WithBlockNumber(1)
+		mockRPCProvider.EXPECT().
+			ConfirmedBlockWithTxHashes(gomock.Any(), rpc.WithL1AcceptedBlock()).
+			Return(nil, rpc.ErrBlockNotFound).
+			Times(2)
+		mockRPCProvider.EXPECT().
+			BlockTransactionCount(ctx, blockID).
+			Return(uint64(1), nil).
+			Times(2)
+
+		// the immutability of the block is unknown, so it isn't cached
+		for range 2 {
+			_, err := provider.BlockTransactionCount(ctx, blockID)
+			require.NoError(t, err)
+		}
+	})
+}
+
+// TestProvider_Passthrough tests that the other methods are forwarded to the
+// wrapped provider.
+func TestProvider_Passthrough(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+	provider := newProvider(t, mockRPCProvider, rpccache.NewMemoryStore(1<<20))
+	mockRPCProvider.EXPECT().BlockNumber(gomock.Any()).Return(uint64(1), nil)
+	mockRPCProvider.EXPECT().BlockNumber(gomock.Any()).Return(uint64(2), nil)
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+	defer cancel()
+	for _, want := range []uint64{1, 2} {
+		number, err := provider.BlockNumber(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, want, number)
+	}
+}
+
+func ptr(value uint64) *uint64 {
+	return &value
+}
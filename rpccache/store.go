@@ -0,0 +1,219 @@
+package rpccache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var ErrNotFound = errors.New("cache entry not found")
+
+// Store stores the cached results of a Provider, as JSON. The keys are
+// printable strings, e.g. "class/0x1234". The stores must be safe for
+// concurrent use.
+type Store interface {
+	// Get returns the value of a key, or ErrNotFound if the key isn't stored.
+	Get(key string) ([]byte, error)
+	// Set stores the value of a key. The store must not modify the value.
+	Set(key string, value []byte) error
+}
+
+// MemoryStore is an in-memory Store evicting the least recently used entries
+// once its size exceeds its capacity.
+type MemoryStore struct {
+	mu       sync.Mutex
+	capacity int
+	size     int
+	entries  map[string]*list.Element
+	// the entries, from the most to the least recently used
+	lru *list.List
+}
+
+type memoryEntry struct {
+	key   string
+	value []byte
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an in-memory LRU store.
+//
+// Parameters:
+//   - capacity: The maximum total size of the stored values, in bytes. The
+//     values larger than the capacity aren't stored.
+//
+// Returns:
+//   - *MemoryStore: The new store
+func NewMemoryStore(capacity int) *MemoryStore {
+	return &MemoryStore{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// Get returns the value of a key, or ErrNotFound, and marks the key as the most
+// recently used.
+func (s *MemoryStore) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.entries[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	s.lru.MoveToFront(elem)
+
+	return elem.Value.(*memoryEntry).value, nil
+}
+
+// Set stores the value of a key, evicting the least recently used entries
+// until the store fits its capacity.
+func (s *MemoryStore) Set(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.entries[key]; ok {
+		s.remove(elem)
+	}
+	if len(value) > s.capacity {
+		return nil
+	}
+
+	s.entries[key] = s.lru.PushFront(&memoryEntry{key: key, value: value})
+	s.size += len(value)
+	for s.size > s.capacity {
+		s.remove(s.lru.Back())
+	}
+
+	return nil
+}
+
+// Len returns the number of stored entries.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lru.Len()
+}
+
+// Size returns the total size of the stored values, in bytes.
+func (s *MemoryStore) Size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.size
+}
+
+func (s *MemoryStore) remove(elem *list.Element) {
+	entry := s.lru.Remove(elem).(*memoryEntry)
+	delete(s.entries, entry.key)
+	s.size -= len(entry.value)
+}
+
+// DiskStore is a Store keeping each entry in a file of a directory, so that
+// the cache outlives the process and can be shared by several processes.
+// Nothing is ever evicted.
+type DiskStore struct {
+	dir string
+}
+
+var _ Store = (*DiskStore)(nil)
+
+// NewDiskStore creates a store in a directory, created if it doesn't exist.
+//
+// Parameters:
+//   - dir: The directory of the entries
+//
+// Returns:
+//   - *DiskStore: The new store
+//   - error: An error if the directory can't be created
+func NewDiskStore(dir string) (*DiskStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create the cache directory: %w", err)
+	}
+
+	return &DiskStore{dir: dir}, nil
+}
+
+// Get returns the value of a key, or ErrNotFound.
+func (s *DiskStore) Get(key string) ([]byte, error) {
+	value, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	return value, err
+}
+
+// Set writes the value of a key. The file is written under a temporary name
+// and renamed, so that a concurrent Get never reads a partial value.
+func (s *DiskStore) Set(key string, value []byte) error {
+	file, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err = file.Write(value); err != nil {
+		file.Close()
+
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), s.path(key))
+}
+
+// path returns the file of a key, named after the hash of the key as the keys
+// aren't valid file names.
+func (s *DiskStore) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+
+	return filepath.Join(s.dir, hex.EncodeToString(hash[:])+".json")
+}
+
+// TieredStore chains stores from the fastest to the slowest, e.g. a
+// MemoryStore in front of a DiskStore.
+type TieredStore []Store
+
+var _ Store = TieredStore(nil)
+
+// Get returns the value of a key from the first store holding it, and copies
+// it into the faster stores. Failing to copy the value doesn't fail the Get.
+func (s TieredStore) Get(key string) ([]byte, error) {
+	for i, store := range s {
+		value, err := store.Get(key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, faster := range s[:i] {
+			_ = faster.Set(key, value)
+		}
+
+		return value, nil
+	}
+
+	return nil, ErrNotFound
+}
+
+// Set stores the value of a key in all the stores.
+func (s TieredStore) Set(key string, value []byte) error {
+	var errs []error
+	for _, store := range s {
+		errs = append(errs, store.Set(key, value))
+	}
+
+	return errors.Join(errs...)
+}
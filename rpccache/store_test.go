@@ -0,0 +1,99 @@
+package rpccache_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/NethermindEth/starknet.go/rpccache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMemoryStore tests the LRU eviction of the memory store.
+func TestMemoryStore(t *testing.T) {
+	store := rpccache.NewMemoryStore(10)
+
+	require.NoError(t, store.Set("a", []byte("1234")))
+	require.NoError(t, store.Set("b", []byte("1234")))
+	// "a" becomes the most recently used entry
+	value, err := store.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1234"), value)
+
+	// "b" is evicted to fit "c"
+	require.NoError(t, store.Set("c", []byte("1234")))
+	_, err = store.Get("b")
+	require.ErrorIs(t, err, rpccache.ErrNotFound)
+	assert.Equal(t, 2, store.Len())
+	assert.Equal(t, 8, store.Size())
+
+	// replacing an entry updates the size
+	require.NoError(t, store.Set("a", []byte("12")))
+	assert.Equal(t, 6, store.Size())
+
+	// the values larger than the capacity aren't stored
+	require.NoError(t, store.Set("d", []byte("12345678901")))
+	_, err = store.Get("d")
+	require.ErrorIs(t, err, rpccache.ErrNotFound)
+	assert.Equal(t, 2, store.Len())
+
+	t.Run("concurrency", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Go(func() {
+				key := string(rune('a' + i))
+				for range 100 {
+					assert.NoError(t, store.Set(key, []byte("12")))
+					_, _ = store.Get(key)
+				}
+			})
+		}
+		wg.Wait()
+		assert.LessOrEqual(t, store.Size(), 10)
+	})
+}
+
+// TestDiskStore tests that the entries of the disk store are shared by the
+// stores of a directory.
+func TestDiskStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := rpccache.NewDiskStore(dir)
+	require.NoError(t, err)
+
+	_, err = store.Get("class/0x1")
+	require.ErrorIs(t, err, rpccache.ErrNotFound)
+	require.NoError(t, store.Set("class/0x1", []byte(`{"abi":"[]"}`)))
+	require.NoError(t, store.Set("class/0x1", []byte(`{"abi":"[1]"}`)))
+
+	reopened, err := rpccache.NewDiskStore(dir)
+	require.NoError(t, err)
+	value, err := reopened.Get("class/0x1")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"abi":"[1]"}`, string(value))
+}
+
+// TestTieredStore tests that the entries of the slower stores are copied into
+// the faster ones.
+func TestTieredStore(t *testing.T) {
+	memory := rpccache.NewMemoryStore(100)
+	disk, err := rpccache.NewDiskStore(t.TempDir())
+	require.NoError(t, err)
+	store := rpccache.TieredStore{memory, disk}
+
+	require.NoError(t, disk.Set("a", []byte("1")))
+	value, err := store.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+	value, err = memory.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+
+	require.NoError(t, store.Set("b", []byte("2")))
+	_, err = memory.Get("b")
+	require.NoError(t, err)
+	_, err = disk.Get("b")
+	require.NoError(t, err)
+
+	_, err = store.Get("c")
+	require.ErrorIs(t, err, rpccache.ErrNotFound)
+}
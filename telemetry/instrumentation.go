@@ -0,0 +1,265 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/NethermindEth/starknet.go/client"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// The names of the metrics.
+const (
+	// The latency of the requests, in milliseconds
+	MetricDuration = "rpc.client.duration"
+	// The number of requests waiting for their response
+	MetricInFlight = "rpc.client.requests.in_flight"
+	// The number of reconnection attempts of the websocket and IPC clients
+	MetricReconnects = "rpc.client.reconnects"
+	// The number of messages received by the subscriptions
+	MetricSubscriptionMessages = "rpc.client.subscription.messages"
+)
+
+// The keys of the attributes.
+const (
+	// "jsonrpc"
+	AttrSystem = "rpc.system"
+	// The method of the request, e.g. "starknet_getBlockWithTxs", or "batch"
+	AttrMethod = "rpc.method"
+	// The kind of the request: "call", "batch" or "subscribe"
+	AttrKind = "rpc.kind"
+	// The JSON-RPC error code of a failed request
+	AttrErrorCode = "rpc.jsonrpc.error_code"
+	// Whether the request failed
+	AttrError = "error"
+	// The block ID param of the request, e.g. "latest", "123" or a block hash
+	AttrBlockID = "starknet.block_id"
+	// The number of calls of a batch
+	AttrBatchSize = "rpc.batch.size"
+	// The number of failed calls of a batch
+	AttrBatchErrors = "rpc.batch.errors"
+)
+
+// batchMethod is the method attribute of the batches.
+const batchMethod = "batch"
+
+// Instrumentation traces the requests of the JSON-RPC clients and records
+// their metrics.
+type Instrumentation struct {
+	tracer               Tracer
+	duration             Float64Histogram
+	inFlight             Int64Counter
+	reconnects           Int64Counter
+	subscriptionMessages Int64Counter
+}
+
+// New returns an Instrumentation with the given tracer and meter.
+//
+// Parameters:
+//   - tracer: the tracer of the request spans, or nil to disable the traces
+//   - meter: the meter of the metrics, or nil to disable the metrics
+//
+// Returns:
+//   - *Instrumentation: the instrumentation, to be added to the clients with its
+//     ClientOptions method
+func New(tracer Tracer, meter Meter) *Instrumentation {
+	if tracer == nil {
+		tracer = noopTracer{}
+	}
+	if meter == nil {
+		meter = noopMeter{}
+	}
+
+	return &Instrumentation{
+		tracer: tracer,
+		duration: meter.Float64Histogram(
+			MetricDuration, "ms", "The latency of the JSON-RPC requests",
+		),
+		inFlight: meter.Int64UpDownCounter(
+			MetricInFlight, "{request}", "The number of JSON-RPC requests waiting for a response",
+		),
+		reconnects: meter.Int64Counter(
+			MetricReconnects, "{attempt}", "The number of reconnection attempts",
+		),
+		subscriptionMessages: meter.Int64Counter(
+			MetricSubscriptionMessages, "{message}", "The number of subscription messages received",
+		),
+	}
+}
+
+// ClientOptions returns the options adding the instrumentation to a client,
+// to be passed to rpc.NewProvider, rpc.NewWebsocketProvider, paymaster.New or
+// client.DialOptions.
+//
+// Returns:
+//   - []client.ClientOption: the interceptor and the connection hooks of the
+//     instrumentation
+func (inst *Instrumentation) ClientOptions() []client.ClientOption {
+	return []client.ClientOption{
+		client.WithInterceptors(inst.Interceptor()),
+		client.WithConnectionHooks(inst.ConnectionHooks()),
+	}
+}
+
+// Interceptor returns the client.Interceptor tracing the requests and
+// recording their latency and the in-flight requests.
+//
+// Returns:
+//   - client.Interceptor: the interceptor
+func (inst *Instrumentation) Interceptor() client.Interceptor {
+	return func(ctx context.Context, call *client.Call, invoke client.Invoker) error {
+		method := call.Method
+		if call.Kind == client.CallKindBatch {
+			method = batchMethod
+		}
+		metricAttrs := []Attribute{String(AttrSystem, "jsonrpc"), String(AttrMethod, method)}
+
+		spanAttrs := append(slices.Clone(metricAttrs), String(AttrKind, kindName(call.Kind)))
+		if call.Kind == client.CallKindBatch {
+			spanAttrs = append(spanAttrs, Int(AttrBatchSize, len(call.Batch)))
+		} else if blockID, ok := findBlockID(call.Params); ok {
+			spanAttrs = append(spanAttrs, String(AttrBlockID, blockID))
+		}
+		ctx, span := inst.tracer.Start(ctx, method, spanAttrs...)
+		defer span.End()
+
+		inst.inFlight.Add(ctx, 1, metricAttrs...)
+		start := time.Now()
+		err := invoke(ctx)
+		elapsed := time.Since(start)
+		inst.inFlight.Add(ctx, -1, metricAttrs...)
+
+		if err != nil {
+			span.RecordError(err)
+			var rpcErr client.Error
+			if errors.As(err, &rpcErr) {
+				span.SetAttributes(Int(AttrErrorCode, rpcErr.ErrorCode()))
+			}
+		} else if call.Kind == client.CallKindBatch {
+			span.SetAttributes(Int(AttrBatchErrors, batchErrors(call.Batch)))
+		}
+
+		inst.duration.Record(
+			ctx,
+			float64(elapsed)/float64(time.Millisecond),
+			append(slices.Clone(metricAttrs), Bool(AttrError, err != nil))...,
+		)
+
+		return err
+	}
+}
+
+// ConnectionHooks returns the client.ConnectionHooks counting the
+// reconnection attempts and the subscription messages.
+//
+// Returns:
+//   - client.ConnectionHooks: the hooks
+func (inst *Instrumentation) ConnectionHooks() client.ConnectionHooks {
+	return client.ConnectionHooks{
+		OnReconnect: func(err error) {
+			inst.reconnects.Add(context.Background(), 1, Bool(AttrError, err != nil))
+		},
+		OnSubscriptionMessage: func(method string) {
+			inst.subscriptionMessages.Add(context.Background(), 1, String(AttrMethod, method))
+		},
+	}
+}
+
+// kindName returns the name of a call kind.
+func kindName(kind client.CallKind) string {
+	switch kind {
+	case client.CallKindBatch:
+		return "batch"
+	case client.CallKindSubscribe:
+		return "subscribe"
+	default:
+		return "call"
+	}
+}
+
+// batchErrors returns the number of failed calls of a batch.
+func batchErrors(batch []client.BatchElem) int {
+	count := 0
+	for _, elem := range batch {
+		if elem.Error != nil {
+			count++
+		}
+	}
+
+	return count
+}
+
+var (
+	blockIDType             = reflect.TypeFor[rpc.BlockID]()
+	subscriptionBlockIDType = reflect.TypeFor[rpc.SubscriptionBlockID]()
+)
+
+// maxBlockIDDepth is the depth of the nested params searched for a block ID,
+// e.g. the block ID of the filter of an EventsInput.
+const maxBlockIDDepth = 3
+
+// findBlockID returns the first block ID of the params of a request, formatted
+// as a string.
+func findBlockID(params any) (string, bool) {
+	return findBlockIDValue(reflect.ValueOf(params), maxBlockIDDepth)
+}
+
+func findBlockIDValue(value reflect.Value, depth int) (string, bool) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return "", false
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return "", false
+	}
+
+	switch {
+	case value.Type() == blockIDType:
+		return formatBlockID(value.Interface().(rpc.BlockID)), true
+	case value.Type() == subscriptionBlockIDType:
+		return formatBlockID(rpc.BlockID(value.Interface().(rpc.SubscriptionBlockID))), true
+	case depth == 0:
+		return "", false
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := range value.Len() {
+			if id, ok := findBlockIDValue(value.Index(i), depth-1); ok {
+				return id, true
+			}
+		}
+	case reflect.Struct:
+		for i := range value.NumField() {
+			if !value.Type().Field(i).IsExported() {
+				continue
+			}
+			if id, ok := findBlockIDValue(value.Field(i), depth-1); ok {
+				return id, true
+			}
+		}
+	default:
+	}
+
+	return "", false
+}
+
+// formatBlockID formats a block ID as its tag, hash or number.
+func formatBlockID(id rpc.BlockID) string {
+	switch {
+	case id.Tag != "":
+		return string(id.Tag)
+	case id.Hash != nil:
+		return id.Hash.String()
+	case id.Number != nil:
+		return strconv.FormatUint(*id.Number, 10)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,200 @@
+package telemetry_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/client"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// codeError is a JSON-RPC error with a code.
+type codeError struct{}
+
+func (codeError) Error() string { return "Block not found" }
+
+func (codeError) ErrorCode() int { return 24 }
+
+// testService serves the JSON-RPC methods of the tests.
+type testService struct{}
+
+func (testService) BlockNumber() uint64 {
+	return 7
+}
+
+func (testService) GetBlockTransactionCount(blockID rpc.BlockID) (uint64, error) {
+	if blockID.Hash != nil {
+		return 0, codeError{}
+	}
+
+	return 3, nil
+}
+
+func (testService) GetEvents(rpc.EventsInput) []any {
+	return []any{}
+}
+
+func (testService) Counter(ctx context.Context, n int) (*client.Subscription, error) {
+	notifier, supported := client.NotifierFromContext(ctx)
+	if !supported {
+		return nil, client.ErrNotificationsUnsupported
+	}
+	subscription := notifier.CreateSubscription()
+	go func() {
+		for i := range n {
+			if err := notifier.Notify(subscription.ID, i); err != nil {
+				return
+			}
+		}
+	}()
+
+	return subscription, nil
+}
+
+// TestInstrumentation tests the spans and metrics of the requests, with the
+// in-memory tracer and meter.
+func TestInstrumentation(t *testing.T) {
+	server := client.NewServer()
+	require.NoError(t, server.RegisterName("starknet", testService{}))
+	require.NoError(t, server.RegisterName("test", testService{}))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	wsServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	t.Cleanup(wsServer.Close)
+
+	tracer := &telemetry.MemoryTracer{}
+	meter := &telemetry.MemoryMeter{}
+	instrumentation := telemetry.New(tracer, meter)
+	ctx := t.Context()
+
+	t.Run("calls", func(t *testing.T) {
+		tracer.Reset()
+		meter.Reset()
+		c, err := client.DialOptions(ctx, httpServer.URL, instrumentation.ClientOptions()...)
+		require.NoError(t, err)
+		t.Cleanup(c.Close)
+
+		var count uint64
+		require.NoError(t, c.CallContextWithSliceArgs(
+			ctx, &count, "starknet_getBlockTransactionCount", rpc.WithBlockTag(rpc.BlockTagLatest),
+		))
+		err = c.CallContextWithSliceArgs(
+			ctx,
+			&count,
+			"starknet_getBlockTransactionCount",
+			rpc.WithBlockHash(new(felt.Felt).SetUint64(1)),
+		)
+		require.Error(t, err)
+		// the block ID is nested in the filter of the input
+		var events []any
+		require.NoError(t, c.CallContext(ctx, &events, "starknet_getEvents", rpc.EventsInput{
+			EventFilter: rpc.EventFilter{FromBlock: rpc.WithBlockNumber(5)},
+		}))
+
+		spans := tracer.Spans()
+		require.Len(t, spans, 3)
+		assert.Equal(t, "starknet_getBlockTransactionCount", spans[0].Name)
+		assert.Equal(t, map[string]any{
+			telemetry.AttrSystem:  "jsonrpc",
+			telemetry.AttrMethod:  "starknet_getBlockTransactionCount",
+			telemetry.AttrKind:    "call",
+			telemetry.AttrBlockID: "latest",
+		}, spans[0].Attributes)
+		assert.True(t, spans[0].Ended)
+		require.NoError(t, spans[0].Err)
+
+		require.Error(t, spans[1].Err)
+		assert.Equal(t, 24, spans[1].Attributes[telemetry.AttrErrorCode])
+		assert.Equal(t, "0x1", spans[1].Attributes[telemetry.AttrBlockID])
+		assert.Equal(t, "5", spans[2].Attributes[telemetry.AttrBlockID])
+
+		durations := meter.Measurements(telemetry.MetricDuration)
+		require.Len(t, durations, 3)
+		assert.Positive(t, durations[0].Value)
+		assert.Equal(t, false, durations[0].Attributes[telemetry.AttrError])
+		assert.Equal(t, true, durations[1].Attributes[telemetry.AttrError])
+		// the in-flight gauge is back to zero
+		assert.Len(t, meter.Measurements(telemetry.MetricInFlight), 6)
+		assert.Zero(t, meter.Sum(telemetry.MetricInFlight))
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		tracer.Reset()
+		var number, count uint64
+		c, err := client.DialOptions(ctx, httpServer.URL, instrumentation.ClientOptions()...)
+		require.NoError(t, err)
+		t.Cleanup(c.Close)
+
+		require.NoError(t, c.BatchCallContext(ctx, []client.BatchElem{
+			{Method: "starknet_blockNumber", Result: &number},
+			{
+				Method: "starknet_getBlockTransactionCount",
+				Args:   []any{rpc.WithBlockHash(new(felt.Felt).SetUint64(1))},
+				Result: &count,
+			},
+		}))
+
+		spans := tracer.Spans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "batch", spans[0].Name)
+		assert.Equal(t, 2, spans[0].Attributes[telemetry.AttrBatchSize])
+		assert.Equal(t, 1, spans[0].Attributes[telemetry.AttrBatchErrors])
+	})
+
+	t.Run("subscription", func(t *testing.T) {
+		tracer.Reset()
+		meter.Reset()
+		wsURL := "ws" + strings.TrimPrefix(wsServer.URL, "http")
+		c, err := client.DialOptions(ctx, wsURL, instrumentation.ClientOptions()...)
+		require.NoError(t, err)
+		t.Cleanup(c.Close)
+
+		values := make(chan int)
+		sub, err := c.SubscribeWithSliceArgs(ctx, "test", "_subscribe", values, "counter", 3)
+		require.NoError(t, err)
+		for range 3 {
+			<-values
+		}
+		sub.Unsubscribe()
+
+		assert.Equal(t, "test_subscribe", tracer.Spans()[0].Name)
+		assert.Equal(t, "subscribe", tracer.Spans()[0].Attributes[telemetry.AttrKind])
+		assert.InDelta(t, 3, meter.Sum(
+			telemetry.MetricSubscriptionMessages,
+			telemetry.String(telemetry.AttrMethod, "test_subscribe"),
+		), 0)
+	})
+
+	t.Run("reconnects", func(t *testing.T) {
+		meter.Reset()
+		hooks := instrumentation.ConnectionHooks()
+		hooks.OnReconnect(nil)
+		hooks.OnReconnect(errors.New("connection refused"))
+
+		assert.InDelta(t, 2, meter.Sum(telemetry.MetricReconnects), 0)
+		assert.InDelta(
+			t,
+			1,
+			meter.Sum(telemetry.MetricReconnects, telemetry.Bool(telemetry.AttrError, true)),
+			0,
+		)
+	})
+
+	t.Run("no tracer and meter", func(t *testing.T) {
+		noop := telemetry.New(nil, nil)
+		c, err := client.DialOptions(ctx, httpServer.URL, noop.ClientOptions()...)
+		require.NoError(t, err)
+		t.Cleanup(c.Close)
+
+		var number uint64
+		require.NoError(t, c.CallContext(ctx, &number, "starknet_blockNumber", nil))
+		assert.Equal(t, uint64(7), number)
+	})
+}
@@ -0,0 +1,177 @@
+package telemetry
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryTracer is a Tracer keeping the spans in memory, e.g. to check them in
+// tests. It is safe for concurrent use.
+type MemoryTracer struct {
+	mu    sync.Mutex
+	spans []*MemorySpan
+}
+
+// Start starts a MemorySpan.
+func (tracer *MemoryTracer) Start(
+	ctx context.Context,
+	name string,
+	attrs ...Attribute,
+) (context.Context, Span) {
+	span := &MemorySpan{Name: name, Attributes: make(map[string]any)}
+	span.SetAttributes(attrs...)
+
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	tracer.spans = append(tracer.spans, span)
+
+	return ctx, span
+}
+
+// Spans returns the started spans, in order.
+func (tracer *MemoryTracer) Spans() []*MemorySpan {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+
+	return append([]*MemorySpan(nil), tracer.spans...)
+}
+
+// Reset removes the spans.
+func (tracer *MemoryTracer) Reset() {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	tracer.spans = nil
+}
+
+// MemorySpan is a span of a MemoryTracer. Its fields must not be read before
+// it is ended.
+type MemorySpan struct {
+	Name       string
+	Attributes map[string]any
+	// The recorded error, if any
+	Err   error
+	Ended bool
+
+	mu sync.Mutex
+}
+
+// SetAttributes sets attributes of the span.
+func (span *MemorySpan) SetAttributes(attrs ...Attribute) {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	for _, attr := range attrs {
+		span.Attributes[attr.Key] = attr.Value
+	}
+}
+
+// RecordError records the error of the span.
+func (span *MemorySpan) RecordError(err error) {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	span.Err = err
+}
+
+// End ends the span.
+func (span *MemorySpan) End() {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	span.Ended = true
+}
+
+// Measurement is a value recorded by an instrument of a MemoryMeter.
+type Measurement struct {
+	Value      float64
+	Attributes map[string]any
+}
+
+// MemoryMeter is a Meter keeping the measurements in memory, e.g. to check
+// them in tests. It is safe for concurrent use.
+type MemoryMeter struct {
+	mu           sync.Mutex
+	measurements map[string][]Measurement
+}
+
+// Float64Histogram returns an instrument recording its values in the meter.
+func (meter *MemoryMeter) Float64Histogram(name, _, _ string) Float64Histogram {
+	return memoryInstrument{meter: meter, name: name}
+}
+
+// Int64Counter returns an instrument recording its values in the meter.
+func (meter *MemoryMeter) Int64Counter(name, _, _ string) Int64Counter {
+	return memoryInstrument{meter: meter, name: name}
+}
+
+// Int64UpDownCounter returns an instrument recording its values in the meter.
+func (meter *MemoryMeter) Int64UpDownCounter(name, _, _ string) Int64Counter {
+	return memoryInstrument{meter: meter, name: name}
+}
+
+// Measurements returns the values recorded by an instrument, in order.
+func (meter *MemoryMeter) Measurements(name string) []Measurement {
+	meter.mu.Lock()
+	defer meter.mu.Unlock()
+
+	return append([]Measurement(nil), meter.measurements[name]...)
+}
+
+// Sum returns the sum of the values recorded by an instrument with the given
+// attributes, e.g. the value of a counter.
+func (meter *MemoryMeter) Sum(name string, attrs ...Attribute) float64 {
+	sum := 0.0
+	for _, measurement := range meter.Measurements(name) {
+		if hasAttributes(measurement.Attributes, attrs) {
+			sum += measurement.Value
+		}
+	}
+
+	return sum
+}
+
+// Reset removes the measurements.
+func (meter *MemoryMeter) Reset() {
+	meter.mu.Lock()
+	defer meter.mu.Unlock()
+	meter.measurements = nil
+}
+
+func (meter *MemoryMeter) record(name string, value float64, attrs []Attribute) {
+	attributes := make(map[string]any, len(attrs))
+	for _, attr := range attrs {
+		attributes[attr.Key] = attr.Value
+	}
+
+	meter.mu.Lock()
+	defer meter.mu.Unlock()
+	if meter.measurements == nil {
+		meter.measurements = make(map[string][]Measurement)
+	}
+	meter.measurements[name] = append(
+		meter.measurements[name],
+		Measurement{Value: value, Attributes: attributes},
+	)
+}
+
+// hasAttributes returns whether the attributes include the expected ones.
+func hasAttributes(attributes map[string]any, expected []Attribute) bool {
+	for _, attr := range expected {
+		if value, ok := attributes[attr.Key]; !ok || value != attr.Value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// memoryInstrument is an instrument of a MemoryMeter.
+type memoryInstrument struct {
+	meter *MemoryMeter
+	name  string
+}
+
+func (instrument memoryInstrument) Record(_ context.Context, value float64, attrs ...Attribute) {
+	instrument.meter.record(instrument.name, value, attrs)
+}
+
+func (instrument memoryInstrument) Add(_ context.Context, value int64, attrs ...Attribute) {
+	instrument.meter.record(instrument.name, float64(value), attrs)
+}
@@ -0,0 +1,104 @@
+// Package telemetry instruments the JSON-RPC clients of the rpc and paymaster
+// packages with traces and metrics. It doesn't depend on a telemetry SDK: the
+// Tracer and Meter interfaces follow the OpenTelemetry API, so the
+// OpenTelemetry tracers and meters can be plugged with thin adapters, and the
+// in-memory MemoryTracer and MemoryMeter can be used in tests.
+//
+// Example:
+//
+//	instrumentation := telemetry.New(tracer, meter)
+//	provider, err := rpc.NewProvider(ctx, url, instrumentation.ClientOptions()...)
+package telemetry
+
+import "context"
+
+// Attribute is a key-value pair describing a span or a measurement.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Tracer starts the spans of the JSON-RPC requests, like the OpenTelemetry
+// trace.Tracer.
+type Tracer interface {
+	// Start starts a span, returning a context holding it.
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is the span of a JSON-RPC request, like the OpenTelemetry trace.Span.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	// RecordError records the error of the request, and marks the span as failed.
+	RecordError(err error)
+	End()
+}
+
+// Meter creates the instruments of the metrics, like the OpenTelemetry
+// metric.Meter.
+type Meter interface {
+	Float64Histogram(name, unit, description string) Float64Histogram
+	Int64Counter(name, unit, description string) Int64Counter
+	Int64UpDownCounter(name, unit, description string) Int64Counter
+}
+
+// Float64Histogram records a distribution of values, e.g. latencies.
+type Float64Histogram interface {
+	Record(ctx context.Context, value float64, attrs ...Attribute)
+}
+
+// Int64Counter adds values to a sum. The values of a monotonic counter must be
+// positive, while an up-down counter also accepts negative values.
+type Int64Counter interface {
+	Add(ctx context.Context, value int64, attrs ...Attribute)
+}
+
+// noopTracer is the Tracer used when none is given.
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, _ string, _ ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+
+func (noopSpan) RecordError(error) {}
+
+func (noopSpan) End() {}
+
+// noopMeter is the Meter used when none is given.
+type noopMeter struct{}
+
+func (noopMeter) Float64Histogram(string, string, string) Float64Histogram {
+	return noopInstrument{}
+}
+
+func (noopMeter) Int64Counter(string, string, string) Int64Counter {
+	return noopInstrument{}
+}
+
+func (noopMeter) Int64UpDownCounter(string, string, string) Int64Counter {
+	return noopInstrument{}
+}
+
+type noopInstrument struct{}
+
+func (noopInstrument) Record(context.Context, float64, ...Attribute) {}
+
+func (noopInstrument) Add(context.Context, int64, ...Attribute) {}
@@ -0,0 +1,298 @@
+package token
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// ERC20 is a client of an ERC-20 token contract. The amounts are in base units
+// (e.g. wei or fri); use Amount and ParseAmount to convert them from and to
+// utils.Amount, in token units.
+type ERC20 struct {
+	*tokenContract
+
+	decimalsMu sync.Mutex
+	decimals   *uint8
+}
+
+// NewERC20 creates a new ERC-20 token client.
+//
+// Parameters:
+//   - address: the address of the token contract, e.g. STRKAddress
+//   - provider: the provider used to call the token contract
+//   - acc: the account used to send the transactions. Can be nil for a
+//     read-only client.
+//
+// Returns:
+//   - *ERC20: the token client
+func NewERC20(address *felt.Felt, provider rpc.RPCProvider, acc account.AccountInterface) *ERC20 {
+	return &ERC20{tokenContract: newTokenContract(address, provider, acc)}
+}
+
+// Name returns the name of the token.
+func (token *ERC20) Name(ctx context.Context) (string, error) {
+	result, err := token.call(ctx, "name", "name")
+	if err != nil {
+		return "", err
+	}
+
+	return resultString(result)
+}
+
+// Symbol returns the symbol of the token.
+func (token *ERC20) Symbol(ctx context.Context) (string, error) {
+	result, err := token.call(ctx, "symbol", "symbol")
+	if err != nil {
+		return "", err
+	}
+
+	return resultString(result)
+}
+
+// Decimals returns the number of decimals of the token. It is only fetched once.
+func (token *ERC20) Decimals(ctx context.Context) (uint8, error) {
+	token.decimalsMu.Lock()
+	defer token.decimalsMu.Unlock()
+
+	if token.decimals != nil {
+		return *token.decimals, nil
+	}
+
+	result, err := token.call(ctx, "decimals", "decimals")
+	if err != nil {
+		return 0, err
+	}
+	decimalsFelt, err := resultFelt(result)
+	if err != nil {
+		return 0, err
+	}
+	//nolint:mnd // decimals is a u8
+	if decimalsFelt.Cmp(new(felt.Felt).SetUint64(255)) > 0 {
+		return 0, ErrInvalidResult
+	}
+	decimals := uint8(decimalsFelt.Uint64())
+	token.decimals = &decimals
+
+	return decimals, nil
+}
+
+// TotalSupply returns the total supply of the token, in base units.
+func (token *ERC20) TotalSupply(ctx context.Context) (*big.Int, error) {
+	result, err := token.call(ctx, "total_supply", "totalSupply")
+	if err != nil {
+		return nil, err
+	}
+
+	return resultU256(result)
+}
+
+// BalanceOf returns the balance of an account, in base units.
+//
+// Parameters:
+//   - ctx: the context.Context for the request
+//   - owner: the address of the account
+//
+// Returns:
+//   - *big.Int: the balance
+//   - error: an error if the call fails
+func (token *ERC20) BalanceOf(ctx context.Context, owner *felt.Felt) (*big.Int, error) {
+	result, err := token.call(ctx, "balance_of", "balanceOf", owner)
+	if err != nil {
+		return nil, err
+	}
+
+	return resultU256(result)
+}
+
+// Allowance returns the amount a spender is allowed to transfer on behalf of
+// an owner, in base units.
+//
+// Parameters:
+//   - ctx: the context.Context for the request
+//   - owner: the address of the owner
+//   - spender: the address of the spender
+//
+// Returns:
+//   - *big.Int: the allowance
+//   - error: an error if the call fails
+func (token *ERC20) Allowance(ctx context.Context, owner, spender *felt.Felt) (*big.Int, error) {
+	result, err := token.call(ctx, "allowance", "allowance", owner, spender)
+	if err != nil {
+		return nil, err
+	}
+
+	return resultU256(result)
+}
+
+// Transfer sends a transaction transferring tokens from the client account.
+//
+// Parameters:
+//   - ctx: the context.Context for the request
+//   - to: the recipient address
+//   - amount: the amount, in base units
+//   - opts: the options for building the transaction. Pass `nil` to use default values.
+//
+// Returns:
+//   - rpc.AddInvokeTransactionResponse: the response of the submitted transaction
+//   - error: ErrNoAccount if the client has no account, or an error if the transaction fails
+func (token *ERC20) Transfer(
+	ctx context.Context,
+	to *felt.Felt,
+	amount *big.Int,
+	opts *account.TxnOptions,
+) (rpc.AddInvokeTransactionResponse, error) {
+	amountFelts, err := U256ToFelts(amount)
+	if err != nil {
+		return rpc.AddInvokeTransactionResponse{}, err
+	}
+
+	return token.invoke(ctx, opts, "transfer", "transfer", to, amountFelts[0], amountFelts[1])
+}
+
+// TransferFrom sends a transaction transferring tokens on behalf of their
+// owner, within the allowance of the client account.
+//
+// Parameters:
+//   - ctx: the context.Context for the request
+//   - from: the owner address
+//   - to: the recipient address
+//   - amount: the amount, in base units
+//   - opts: the options for building the transaction. Pass `nil` to use default values.
+//
+// Returns:
+//   - rpc.AddInvokeTransactionResponse: the response of the submitted transaction
+//   - error: ErrNoAccount if the client has no account, or an error if the transaction fails
+func (token *ERC20) TransferFrom(
+	ctx context.Context,
+	from, to *felt.Felt,
+	amount *big.Int,
+	opts *account.TxnOptions,
+) (rpc.AddInvokeTransactionResponse, error) {
+	amountFelts, err := U256ToFelts(amount)
+	if err != nil {
+		return rpc.AddInvokeTransactionResponse{}, err
+	}
+
+	return token.invoke(
+		ctx,
+		opts,
+		"transfer_from",
+		"transferFrom",
+		from,
+		to,
+		amountFelts[0],
+		amountFelts[1],
+	)
+}
+
+// Approve sends a transaction allowing a spender to transfer tokens on behalf
+// of the client account.
+//
+// Parameters:
+//   - ctx: the context.Context for the request
+//   - spender: the spender address
+//   - amount: the allowance, in base units
+//   - opts: the options for building the transaction. Pass `nil` to use default values.
+//
+// Returns:
+//   - rpc.AddInvokeTransactionResponse: the response of the submitted transaction
+//   - error: ErrNoAccount if the client has no account, or an error if the transaction fails
+func (token *ERC20) Approve(
+	ctx context.Context,
+	spender *felt.Felt,
+	amount *big.Int,
+	opts *account.TxnOptions,
+) (rpc.AddInvokeTransactionResponse, error) {
+	amountFelts, err := U256ToFelts(amount)
+	if err != nil {
+		return rpc.AddInvokeTransactionResponse{}, err
+	}
+
+	return token.invoke(ctx, opts, "approve", "approve", spender, amountFelts[0], amountFelts[1])
+}
+
+// TransferCall builds a transfer call, to be sent with other calls in a single
+// transaction with account.BuildAndSendInvokeTxn.
+//
+// Parameters:
+//   - to: the recipient address
+//   - amount: the amount, in base units
+//
+// Returns:
+//   - rpc.InvokeFunctionCall: the function call
+//   - error: ErrInvalidAmount if the amount is not a valid u256
+func (token *ERC20) TransferCall(to *felt.Felt, amount *big.Int) (rpc.InvokeFunctionCall, error) {
+	amountFelts, err := U256ToFelts(amount)
+	if err != nil {
+		return rpc.InvokeFunctionCall{}, err
+	}
+
+	return token.functionCall("transfer", "transfer", to, amountFelts[0], amountFelts[1]), nil
+}
+
+// ApproveCall builds an approve call, to be sent with other calls in a single
+// transaction with account.BuildAndSendInvokeTxn.
+//
+// Parameters:
+//   - spender: the spender address
+//   - amount: the allowance, in base units
+//
+// Returns:
+//   - rpc.InvokeFunctionCall: the function call
+//   - error: ErrInvalidAmount if the amount is not a valid u256
+func (token *ERC20) ApproveCall(
+	spender *felt.Felt,
+	amount *big.Int,
+) (rpc.InvokeFunctionCall, error) {
+	amountFelts, err := U256ToFelts(amount)
+	if err != nil {
+		return rpc.InvokeFunctionCall{}, err
+	}
+
+	return token.functionCall("approve", "approve", spender, amountFelts[0], amountFelts[1]), nil
+}
+
+// Amount returns an amount in base units with the token decimals, e.g. to
+// format it as a decimal string in token units.
+//
+// Parameters:
+//   - ctx: the context.Context for the request
+//   - value: the amount in base units
+//
+// Returns:
+//   - utils.Amount: the amount
+//   - error: an error if the decimals can't be fetched
+func (token *ERC20) Amount(ctx context.Context, value *big.Int) (utils.Amount, error) {
+	decimals, err := token.Decimals(ctx)
+	if err != nil {
+		return utils.Amount{}, err
+	}
+
+	return utils.NewAmount(value, decimals), nil
+}
+
+// ParseAmount parses a decimal string in token units, e.g. "1.5", into an
+// amount with the token decimals.
+//
+// Parameters:
+//   - ctx: the context.Context for the request
+//   - amount: the decimal amount
+//
+// Returns:
+//   - utils.Amount: the amount
+//   - error: utils.ErrInvalidAmount or utils.ErrPrecisionLoss if the string
+//     can't be parsed, or an error if the decimals can't be fetched
+func (token *ERC20) ParseAmount(ctx context.Context, amount string) (utils.Amount, error) {
+	decimals, err := token.Decimals(ctx)
+	if err != nil {
+		return utils.Amount{}, err
+	}
+
+	return utils.ParseAmount(amount, decimals)
+}
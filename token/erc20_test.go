@@ -0,0 +1,283 @@
+package token_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/contracts"
+	"github.com/NethermindEth/starknet.go/internal/tests/mocks/rpcv10mock"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/token"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// invokerAccount is an account that records the function calls it is asked to send.
+type invokerAccount struct {
+	account.AccountInterface
+	calls []rpc.InvokeFunctionCall
+}
+
+func (acc *invokerAccount) BuildAndSendInvokeTxn(
+	_ context.Context,
+	functionCalls []rpc.InvokeFunctionCall,
+	_ *account.TxnOptions,
+) (rpc.AddInvokeTransactionResponse, error) {
+	acc.calls = append(acc.calls, functionCalls...)
+
+	return rpc.AddInvokeTransactionResponse{Hash: internalUtils.DeadBeef}, nil
+}
+
+// expectCall sets up the mock to expect a call of an entrypoint of the token.
+func expectCall(
+	mock *rpcv10mock.MockRPCProvider,
+	address *felt.Felt,
+	entrypoint string,
+	calldata []*felt.Felt,
+	result []*felt.Felt,
+	err error,
+) *gomock.Call {
+	return mock.EXPECT().
+		Call(gomock.Any(), rpc.FunctionCall{
+			ContractAddress:    address,
+			EntryPointSelector: internalUtils.GetSelectorFromNameFelt(entrypoint),
+			Calldata:           calldata,
+		}, rpc.WithBlockTag(rpc.BlockTagLatest)).
+		Return(result, err)
+}
+
+// TestERC20 tests the ERC-20 client, with the detection of the camelCase
+// entrypoints of a Cairo 0 token.
+func TestERC20(t *testing.T) {
+	address := internalUtils.TestHexToFelt(t, "0x1234")
+	owner := internalUtils.TestHexToFelt(t, "0xa")
+	mockCtrl := gomock.NewController(t)
+	mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+
+	acc := new(invokerAccount)
+	erc20 := token.NewERC20(address, mockRPCProvider, acc)
+	assert.Equal(t, token.StyleUnknown, erc20.Style())
+
+	t.Run("balance with style detection", func(t *testing.T) {
+		expectCall(
+			mockRPCProvider,
+			address,
+			"balance_of",
+			[]*felt.Felt{owner},
+			nil,
+			rpc.ErrEntrypointNotFound,
+		).Times(1)
+		expectCall(
+			mockRPCProvider,
+			address,
+			"balanceOf",
+			[]*felt.Felt{owner},
+			[]*felt.Felt{new(felt.Felt).SetUint64(5), new(felt.Felt).SetUint64(1)},
+			nil,
+		).Times(2)
+
+		balance, err := erc20.BalanceOf(t.Context(), owner)
+		require.NoError(t, err)
+		expected := new(big.Int).Lsh(big.NewInt(1), 128)
+		expected.Add(expected, big.NewInt(5))
+		assert.Equal(t, expected, balance)
+		assert.Equal(t, token.StyleCamelCase, erc20.Style())
+
+		// the snake_case entrypoint is not tried again
+		_, err = erc20.BalanceOf(t.Context(), owner)
+		require.NoError(t, err)
+	})
+
+	t.Run("cached decimals", func(t *testing.T) {
+		expectCall(
+			mockRPCProvider,
+			address,
+			"decimals",
+			[]*felt.Felt{},
+			[]*felt.Felt{new(felt.Felt).SetUint64(18)},
+			nil,
+		).Times(1)
+
+		for range 2 {
+			amount, err := erc20.Amount(t.Context(), big.NewInt(1500000000000000000))
+			require.NoError(t, err)
+			assert.Equal(t, "1.5", amount.String())
+		}
+
+		amount, err := erc20.ParseAmount(t.Context(), "1.5")
+		require.NoError(t, err)
+		assert.Equal(t, utils.NewAmount(big.NewInt(1500000000000000000), 18), amount)
+
+		_, err = erc20.ParseAmount(t.Context(), "1e3")
+		require.ErrorIs(t, err, utils.ErrInvalidAmount)
+	})
+
+	t.Run("transfer", func(t *testing.T) {
+		resp, err := erc20.Transfer(t.Context(), owner, big.NewInt(7), nil)
+		require.NoError(t, err)
+		assert.Equal(t, internalUtils.DeadBeef, resp.Hash)
+		require.Len(t, acc.calls, 1)
+		assert.Equal(t, rpc.InvokeFunctionCall{
+			ContractAddress: address,
+			FunctionName:    "transfer",
+			CallData:        []*felt.Felt{owner, new(felt.Felt).SetUint64(7), new(felt.Felt)},
+		}, acc.calls[0])
+
+		call, err := erc20.ApproveCall(owner, big.NewInt(7))
+		require.NoError(t, err)
+		assert.Equal(t, "approve", call.FunctionName)
+
+		_, err = erc20.Transfer(t.Context(), owner, big.NewInt(-1), nil)
+		require.ErrorIs(t, err, token.ErrInvalidAmount)
+	})
+
+	t.Run("read-only client", func(t *testing.T) {
+		readOnly := token.NewERC20(address, mockRPCProvider, nil)
+		_, err := readOnly.Transfer(t.Context(), owner, big.NewInt(7), nil)
+		require.ErrorIs(t, err, token.ErrNoAccount)
+	})
+}
+
+// failingAccount is an account that records the function calls it is asked
+// to send, and fails with the given errors.
+type failingAccount struct {
+	account.AccountInterface
+	calls []rpc.InvokeFunctionCall
+	errs  []error
+}
+
+func (acc *failingAccount) BuildAndSendInvokeTxn(
+	_ context.Context,
+	functionCalls []rpc.InvokeFunctionCall,
+	_ *account.TxnOptions,
+) (rpc.AddInvokeTransactionResponse, error) {
+	acc.calls = append(acc.calls, functionCalls...)
+	err := acc.errs[0]
+	acc.errs = acc.errs[1:]
+
+	return rpc.AddInvokeTransactionResponse{Hash: internalUtils.DeadBeef}, err
+}
+
+// TestERC20_InvokeStyle tests the resolution of the entrypoint style of a
+// transaction from the class ABI.
+func TestERC20_InvokeStyle(t *testing.T) {
+	address := internalUtils.TestHexToFelt(t, "0x1234")
+	owner := internalUtils.TestHexToFelt(t, "0xa")
+	blockID := rpc.WithBlockTag(rpc.BlockTagLatest)
+
+	cairo0Class := func(names ...string) *contracts.DeprecatedContractClass {
+		abi := contracts.ABI{}
+		for _, name := range names {
+			abi = append(abi, &contracts.FunctionABIEntry{
+				Type: contracts.ABITypeFunction,
+				Name: name,
+			})
+		}
+
+		return &contracts.DeprecatedContractClass{ABI: &abi}
+	}
+
+	t.Run("camelCase from the ABI", func(t *testing.T) {
+		mockRPCProvider := rpcv10mock.NewMockRPCProvider(gomock.NewController(t))
+		mockRPCProvider.EXPECT().
+			ClassAt(gomock.Any(), blockID, address).
+			Return(cairo0Class("transferFrom"), nil).
+			Times(1)
+
+		acc := &failingAccount{errs: []error{nil, nil}}
+		erc20 := token.NewERC20(address, mockRPCProvider, acc)
+		for range 2 {
+			_, err := erc20.TransferFrom(t.Context(), owner, owner, big.NewInt(7), nil)
+			require.NoError(t, err)
+		}
+		require.Len(t, acc.calls, 2)
+		assert.Equal(t, "transferFrom", acc.calls[0].FunctionName)
+		assert.Equal(t, "transferFrom", acc.calls[1].FunctionName)
+		assert.Equal(t, token.StyleCamelCase, erc20.Style())
+	})
+
+	t.Run("unrelated error", func(t *testing.T) {
+		mockRPCProvider := rpcv10mock.NewMockRPCProvider(gomock.NewController(t))
+		// a proxy ABI, declaring neither entrypoint
+		mockRPCProvider.EXPECT().
+			ClassAt(gomock.Any(), blockID, address).
+			Return(cairo0Class("upgrade"), nil)
+
+		acc := &failingAccount{errs: []error{rpc.ErrUnexpectedError}}
+		erc20 := token.NewERC20(address, mockRPCProvider, acc)
+		_, err := erc20.TransferFrom(t.Context(), owner, owner, big.NewInt(7), nil)
+		require.ErrorIs(t, err, rpc.ErrUnexpectedError)
+		require.Len(t, acc.calls, 1)
+		assert.Equal(t, "transfer_from", acc.calls[0].FunctionName)
+		assert.Equal(t, token.StyleUnknown, erc20.Style())
+	})
+
+	t.Run("missing entrypoint", func(t *testing.T) {
+		mockRPCProvider := rpcv10mock.NewMockRPCProvider(gomock.NewController(t))
+		mockRPCProvider.EXPECT().
+			ClassAt(gomock.Any(), blockID, address).
+			Return(cairo0Class("upgrade"), nil)
+
+		acc := &failingAccount{errs: []error{rpc.ErrEntrypointNotFound, nil}}
+		erc20 := token.NewERC20(address, mockRPCProvider, acc)
+		_, err := erc20.TransferFrom(t.Context(), owner, owner, big.NewInt(7), nil)
+		require.NoError(t, err)
+		require.Len(t, acc.calls, 2)
+		assert.Equal(t, "transferFrom", acc.calls[1].FunctionName)
+		assert.Equal(t, token.StyleCamelCase, erc20.Style())
+	})
+}
+
+// TestERC721 tests the ERC-721 client with snake_case entrypoints.
+func TestERC721(t *testing.T) {
+	address := internalUtils.TestHexToFelt(t, "0x1234")
+	owner := internalUtils.TestHexToFelt(t, "0xa")
+	recipient := internalUtils.TestHexToFelt(t, "0xb")
+	mockCtrl := gomock.NewController(t)
+	mockRPCProvider := rpcv10mock.NewMockRPCProvider(mockCtrl)
+
+	expectCall(
+		mockRPCProvider,
+		address,
+		"owner_of",
+		[]*felt.Felt{new(felt.Felt).SetUint64(3), new(felt.Felt)},
+		[]*felt.Felt{owner},
+		nil,
+	)
+
+	acc := new(invokerAccount)
+	erc721 := token.NewERC721(address, mockRPCProvider, acc)
+	tokenOwner, err := erc721.OwnerOf(t.Context(), big.NewInt(3))
+	require.NoError(t, err)
+	assert.Equal(t, owner, tokenOwner)
+	assert.Equal(t, token.StyleSnakeCase, erc721.Style())
+
+	_, err = erc721.SafeTransferFrom(
+		t.Context(),
+		owner,
+		recipient,
+		big.NewInt(3),
+		[]*felt.Felt{internalUtils.DeadBeef},
+		nil,
+	)
+	require.NoError(t, err)
+	require.Len(t, acc.calls, 1)
+	assert.Equal(t, rpc.InvokeFunctionCall{
+		ContractAddress: address,
+		FunctionName:    "safe_transfer_from",
+		CallData: []*felt.Felt{
+			owner,
+			recipient,
+			new(felt.Felt).SetUint64(3),
+			new(felt.Felt),
+			new(felt.Felt).SetUint64(1),
+			internalUtils.DeadBeef,
+		},
+	}, acc.calls[0])
+}
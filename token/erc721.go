@@ -0,0 +1,252 @@
+package token
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// ERC721 is a client of an ERC-721 (NFT) token contract.
+type ERC721 struct {
+	*tokenContract
+}
+
+// NewERC721 creates a new ERC-721 token client.
+//
+// Parameters:
+//   - address: the address of the token contract
+//   - provider: the provider used to call the token contract
+//   - acc: the account used to send the transactions. Can be nil for a
+//     read-only client.
+//
+// Returns:
+//   - *ERC721: the token client
+func NewERC721(
+	address *felt.Felt,
+	provider rpc.RPCProvider,
+	acc account.AccountInterface,
+) *ERC721 {
+	return &ERC721{tokenContract: newTokenContract(address, provider, acc)}
+}
+
+// Name returns the name of the token collection.
+func (token *ERC721) Name(ctx context.Context) (string, error) {
+	result, err := token.call(ctx, "name", "name")
+	if err != nil {
+		return "", err
+	}
+
+	return resultString(result)
+}
+
+// Symbol returns the symbol of the token collection.
+func (token *ERC721) Symbol(ctx context.Context) (string, error) {
+	result, err := token.call(ctx, "symbol", "symbol")
+	if err != nil {
+		return "", err
+	}
+
+	return resultString(result)
+}
+
+// TokenURI returns the URI of a token.
+func (token *ERC721) TokenURI(ctx context.Context, tokenID *big.Int) (string, error) {
+	tokenIDFelts, err := U256ToFelts(tokenID)
+	if err != nil {
+		return "", err
+	}
+	result, err := token.call(ctx, "token_uri", "tokenURI", tokenIDFelts...)
+	if err != nil {
+		return "", err
+	}
+
+	return resultString(result)
+}
+
+// BalanceOf returns the number of tokens owned by an account.
+func (token *ERC721) BalanceOf(ctx context.Context, owner *felt.Felt) (*big.Int, error) {
+	result, err := token.call(ctx, "balance_of", "balanceOf", owner)
+	if err != nil {
+		return nil, err
+	}
+
+	return resultU256(result)
+}
+
+// OwnerOf returns the owner of a token.
+func (token *ERC721) OwnerOf(ctx context.Context, tokenID *big.Int) (*felt.Felt, error) {
+	tokenIDFelts, err := U256ToFelts(tokenID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := token.call(ctx, "owner_of", "ownerOf", tokenIDFelts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return resultFelt(result)
+}
+
+// GetApproved returns the account approved to transfer a token, or zero if none.
+func (token *ERC721) GetApproved(ctx context.Context, tokenID *big.Int) (*felt.Felt, error) {
+	tokenIDFelts, err := U256ToFelts(tokenID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := token.call(ctx, "get_approved", "getApproved", tokenIDFelts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return resultFelt(result)
+}
+
+// IsApprovedForAll returns true if an operator is allowed to transfer all the
+// tokens of an owner.
+func (token *ERC721) IsApprovedForAll(
+	ctx context.Context,
+	owner, operator *felt.Felt,
+) (bool, error) {
+	result, err := token.call(ctx, "is_approved_for_all", "isApprovedForAll", owner, operator)
+	if err != nil {
+		return false, err
+	}
+	approved, err := resultFelt(result)
+	if err != nil {
+		return false, err
+	}
+
+	return !approved.IsZero(), nil
+}
+
+// TransferFrom sends a transaction transferring a token.
+//
+// Parameters:
+//   - ctx: the context.Context for the request
+//   - from: the current owner of the token
+//   - to: the recipient address
+//   - tokenID: the token ID
+//   - opts: the options for building the transaction. Pass `nil` to use default values.
+//
+// Returns:
+//   - rpc.AddInvokeTransactionResponse: the response of the submitted transaction
+//   - error: ErrNoAccount if the client has no account, or an error if the transaction fails
+func (token *ERC721) TransferFrom(
+	ctx context.Context,
+	from, to *felt.Felt,
+	tokenID *big.Int,
+	opts *account.TxnOptions,
+) (rpc.AddInvokeTransactionResponse, error) {
+	tokenIDFelts, err := U256ToFelts(tokenID)
+	if err != nil {
+		return rpc.AddInvokeTransactionResponse{}, err
+	}
+
+	return token.invoke(
+		ctx,
+		opts,
+		"transfer_from",
+		"transferFrom",
+		from,
+		to,
+		tokenIDFelts[0],
+		tokenIDFelts[1],
+	)
+}
+
+// SafeTransferFrom sends a transaction transferring a token, checking that the
+// recipient can receive it.
+//
+// Parameters:
+//   - ctx: the context.Context for the request
+//   - from: the current owner of the token
+//   - to: the recipient address
+//   - tokenID: the token ID
+//   - data: the data passed to the recipient
+//   - opts: the options for building the transaction. Pass `nil` to use default values.
+//
+// Returns:
+//   - rpc.AddInvokeTransactionResponse: the response of the submitted transaction
+//   - error: ErrNoAccount if the client has no account, or an error if the transaction fails
+func (token *ERC721) SafeTransferFrom(
+	ctx context.Context,
+	from, to *felt.Felt,
+	tokenID *big.Int,
+	data []*felt.Felt,
+	opts *account.TxnOptions,
+) (rpc.AddInvokeTransactionResponse, error) {
+	tokenIDFelts, err := U256ToFelts(tokenID)
+	if err != nil {
+		return rpc.AddInvokeTransactionResponse{}, err
+	}
+
+	calldata := append(
+		[]*felt.Felt{from, to, tokenIDFelts[0], tokenIDFelts[1]},
+		new(felt.Felt).SetUint64(uint64(len(data))),
+	)
+	calldata = append(calldata, data...)
+
+	return token.invoke(ctx, opts, "safe_transfer_from", "safeTransferFrom", calldata...)
+}
+
+// Approve sends a transaction allowing an account to transfer a token of the
+// client account.
+//
+// Parameters:
+//   - ctx: the context.Context for the request
+//   - to: the approved address
+//   - tokenID: the token ID
+//   - opts: the options for building the transaction. Pass `nil` to use default values.
+//
+// Returns:
+//   - rpc.AddInvokeTransactionResponse: the response of the submitted transaction
+//   - error: ErrNoAccount if the client has no account, or an error if the transaction fails
+func (token *ERC721) Approve(
+	ctx context.Context,
+	to *felt.Felt,
+	tokenID *big.Int,
+	opts *account.TxnOptions,
+) (rpc.AddInvokeTransactionResponse, error) {
+	tokenIDFelts, err := U256ToFelts(tokenID)
+	if err != nil {
+		return rpc.AddInvokeTransactionResponse{}, err
+	}
+
+	return token.invoke(ctx, opts, "approve", "approve", to, tokenIDFelts[0], tokenIDFelts[1])
+}
+
+// SetApprovalForAll sends a transaction allowing or forbidding an operator to
+// transfer all the tokens of the client account.
+//
+// Parameters:
+//   - ctx: the context.Context for the request
+//   - operator: the operator address
+//   - approved: true to allow the operator, false to forbid it
+//   - opts: the options for building the transaction. Pass `nil` to use default values.
+//
+// Returns:
+//   - rpc.AddInvokeTransactionResponse: the response of the submitted transaction
+//   - error: ErrNoAccount if the client has no account, or an error if the transaction fails
+func (token *ERC721) SetApprovalForAll(
+	ctx context.Context,
+	operator *felt.Felt,
+	approved bool,
+	opts *account.TxnOptions,
+) (rpc.AddInvokeTransactionResponse, error) {
+	approvedFelt := new(felt.Felt)
+	if approved {
+		approvedFelt.SetUint64(1)
+	}
+
+	return token.invoke(
+		ctx,
+		opts,
+		"set_approval_for_all",
+		"setApprovalForAll",
+		operator,
+		approvedFelt,
+	)
+}
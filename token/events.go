@@ -0,0 +1,206 @@
+package token
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	// The first key of the `Transfer` events
+	TransferSelector = utils.GetSelectorFromNameFelt("Transfer")
+	// The first key of the `Approval` events
+	ApprovalSelector = utils.GetSelectorFromNameFelt("Approval")
+)
+
+// TransferEvent is a decoded `Transfer` event of an ERC-20 or ERC-721 token.
+type TransferEvent struct {
+	// The address of the token contract
+	Token *felt.Felt
+	From  *felt.Felt
+	To    *felt.Felt
+	// The amount for an ERC-20 transfer, or the token ID for an ERC-721 transfer
+	Value *big.Int
+}
+
+// ApprovalEvent is a decoded `Approval` event of an ERC-20 or ERC-721 token.
+type ApprovalEvent struct {
+	// The address of the token contract
+	Token *felt.Felt
+	Owner *felt.Felt
+	// The spender for an ERC-20 approval, or the approved account for an
+	// ERC-721 approval
+	Spender *felt.Felt
+	// The allowance for an ERC-20 approval, or the token ID for an ERC-721 approval
+	Value *big.Int
+}
+
+// BalanceChange is the net change of the balance of an account for a token.
+type BalanceChange struct {
+	Token   *felt.Felt
+	Account *felt.Felt
+	// The balance change in base units, negative for a decrease
+	Delta *big.Int
+}
+
+// ParseTransferEvent decodes a `Transfer` event. To decode an rpc.EmittedEvent,
+// pass its embedded `Event` field. The three event layouts are supported:
+//   - keys [selector, from, to], data [value_low, value_high]: recent ERC-20 tokens
+//   - keys [selector, from, to, id_low, id_high], data []: recent ERC-721 tokens
+//   - keys [selector], data [from, to, value_low, value_high]: Cairo 0 and
+//     older Cairo tokens
+//
+// Parameters:
+//   - event: the event
+//
+// Returns:
+//   - *TransferEvent: the decoded event
+//   - error: ErrInvalidEvent if it is not a `Transfer` event
+func ParseTransferEvent(event *rpc.Event) (*TransferEvent, error) {
+	addresses, value, err := parseTokenEvent(event, TransferSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransferEvent{
+		Token: event.FromAddress,
+		From:  addresses[0],
+		To:    addresses[1],
+		Value: value,
+	}, nil
+}
+
+// ParseApprovalEvent decodes an `Approval` event. To decode an rpc.EmittedEvent,
+// pass its embedded `Event` field. The same layouts as for ParseTransferEvent
+// are supported.
+//
+// Parameters:
+//   - event: the event
+//
+// Returns:
+//   - *ApprovalEvent: the decoded event
+//   - error: ErrInvalidEvent if it is not an `Approval` event
+func ParseApprovalEvent(event *rpc.Event) (*ApprovalEvent, error) {
+	addresses, value, err := parseTokenEvent(event, ApprovalSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ApprovalEvent{
+		Token:   event.FromAddress,
+		Owner:   addresses[0],
+		Spender: addresses[1],
+		Value:   value,
+	}, nil
+}
+
+// BalanceChanges computes the net balance changes caused by a transaction,
+// from the ERC-20 `Transfer` events of its receipt. The fee payment is included,
+// since it is a transfer of the fee token. The accounts whose balance doesn't
+// change are omitted.
+//
+// The ERC-721 transfers with the recent layout are ignored. With the Cairo 0
+// layout, the ERC-721 and ERC-20 transfers can't be distinguished: use the
+// `tokens` filter to exclude the ERC-721 tokens.
+//
+// Parameters:
+//   - receipt: the transaction receipt
+//   - tokens: if not empty, only the balance changes of these tokens are returned
+//
+// Returns:
+//   - []BalanceChange: the balance changes, in order of first appearance
+func BalanceChanges(receipt *rpc.TransactionReceipt, tokens ...*felt.Felt) []BalanceChange {
+	var changes []BalanceChange
+	indexes := make(map[[2]felt.Felt]int)
+
+	addDelta := func(token, acc *felt.Felt, delta *big.Int) {
+		key := [2]felt.Felt{*token, *acc}
+		i, ok := indexes[key]
+		if !ok {
+			i = len(changes)
+			indexes[key] = i
+			changes = append(
+				changes,
+				BalanceChange{Token: token, Account: acc, Delta: new(big.Int)},
+			)
+		}
+		changes[i].Delta.Add(changes[i].Delta, delta)
+	}
+
+	for i := range receipt.Events {
+		event := &receipt.Events[i]
+		if !isTokenFiltered(event.FromAddress, tokens) || isERC721Layout(event) {
+			continue
+		}
+		transfer, err := ParseTransferEvent(event)
+		if err != nil {
+			continue
+		}
+		addDelta(transfer.Token, transfer.From, new(big.Int).Neg(transfer.Value))
+		addDelta(transfer.Token, transfer.To, transfer.Value)
+	}
+
+	result := make([]BalanceChange, 0, len(changes))
+	for _, change := range changes {
+		if change.Delta.Sign() != 0 {
+			result = append(result, change)
+		}
+	}
+
+	return result
+}
+
+// parseTokenEvent decodes the two addresses and the u256 value of a token event.
+func parseTokenEvent(event *rpc.Event, selector *felt.Felt) ([]*felt.Felt, *big.Int, error) {
+	keys, data := event.Keys, event.Data
+	if len(keys) == 0 || !keys[0].Equal(selector) {
+		return nil, nil, fmt.Errorf("%w: unexpected event selector", ErrInvalidEvent)
+	}
+
+	var fields []*felt.Felt
+	switch {
+	case len(keys) == 3 && len(data) == 2: //nolint:mnd // recent ERC-20 layout
+		fields = append(fields, keys[1:]...)
+		fields = append(fields, data...)
+	case len(keys) == 5 && len(data) == 0: //nolint:mnd // recent ERC-721 layout
+		fields = keys[1:]
+	case len(keys) == 1 && len(data) == 4: //nolint:mnd // Cairo 0 layout
+		fields = data
+	default:
+		return nil, nil, fmt.Errorf(
+			"%w: unexpected layout with %d keys and %d data",
+			ErrInvalidEvent,
+			len(keys),
+			len(data),
+		)
+	}
+
+	value, err := FeltsToU256(fields[2], fields[3])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidEvent, err)
+	}
+
+	return fields[:2], value, nil
+}
+
+// isERC721Layout returns true if the event has the layout of the recent
+// ERC-721 events, with the token ID in the keys.
+func isERC721Layout(event *rpc.Event) bool {
+	return len(event.Keys) == 5 && len(event.Data) == 0 //nolint:mnd // selector, from, to, id
+}
+
+func isTokenFiltered(token *felt.Felt, tokens []*felt.Felt) bool {
+	if len(tokens) == 0 {
+		return true
+	}
+	for _, t := range tokens {
+		if t.Equal(token) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,146 @@
+package token_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEvent(from *felt.Felt, keys, data []*felt.Felt) rpc.Event {
+	return rpc.Event{
+		FromAddress:  from,
+		EventContent: rpc.EventContent{Keys: keys, Data: data},
+	}
+}
+
+// TestParseTransferEvent tests decoding the three layouts of the `Transfer` events.
+func TestParseTransferEvent(t *testing.T) {
+	tokenAddr := internalUtils.TestHexToFelt(t, "0x1234")
+	from := internalUtils.TestHexToFelt(t, "0xa")
+	to := internalUtils.TestHexToFelt(t, "0xb")
+	low := new(felt.Felt).SetUint64(9)
+	zero := new(felt.Felt)
+
+	type testSetType struct {
+		Name        string
+		Event       rpc.Event
+		ExpectedErr error
+	}
+
+	testSet := []testSetType{
+		{
+			Name: "ERC-20",
+			Event: newEvent(
+				tokenAddr,
+				[]*felt.Felt{token.TransferSelector, from, to},
+				[]*felt.Felt{low, zero},
+			),
+		},
+		{
+			Name: "ERC-721",
+			Event: newEvent(
+				tokenAddr,
+				[]*felt.Felt{token.TransferSelector, from, to, low, zero},
+				nil,
+			),
+		},
+		{
+			Name: "Cairo 0",
+			Event: newEvent(
+				tokenAddr,
+				[]*felt.Felt{token.TransferSelector},
+				[]*felt.Felt{from, to, low, zero},
+			),
+		},
+		{
+			Name: "wrong selector",
+			Event: newEvent(
+				tokenAddr,
+				[]*felt.Felt{token.ApprovalSelector, from, to},
+				[]*felt.Felt{low, zero},
+			),
+			ExpectedErr: token.ErrInvalidEvent,
+		},
+		{
+			Name: "wrong layout",
+			Event: newEvent(
+				tokenAddr,
+				[]*felt.Felt{token.TransferSelector, from},
+				[]*felt.Felt{to, low, zero},
+			),
+			ExpectedErr: token.ErrInvalidEvent,
+		},
+	}
+
+	for _, test := range testSet {
+		t.Run(test.Name, func(t *testing.T) {
+			transfer, err := token.ParseTransferEvent(&test.Event)
+			if test.ExpectedErr != nil {
+				require.ErrorIs(t, err, test.ExpectedErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &token.TransferEvent{
+				Token: tokenAddr,
+				From:  from,
+				To:    to,
+				Value: big.NewInt(9),
+			}, transfer)
+		})
+	}
+}
+
+// TestBalanceChanges tests computing the balance changes of a receipt with a
+// token transfer, an NFT transfer and the fee payment.
+func TestBalanceChanges(t *testing.T) {
+	usdc := internalUtils.TestHexToFelt(t, "0x1234")
+	nft := internalUtils.TestHexToFelt(t, "0x5678")
+	sender := internalUtils.TestHexToFelt(t, "0xa")
+	recipient := internalUtils.TestHexToFelt(t, "0xb")
+	sequencer := internalUtils.TestHexToFelt(t, "0xc")
+
+	u256 := func(v uint64) []*felt.Felt {
+		return []*felt.Felt{new(felt.Felt).SetUint64(v), new(felt.Felt)}
+	}
+
+	receipt := &rpc.TransactionReceipt{
+		Events: []rpc.Event{
+			newEvent(usdc, []*felt.Felt{token.TransferSelector, sender, recipient}, u256(100)),
+			newEvent(usdc, []*felt.Felt{token.TransferSelector, recipient, sender}, u256(30)),
+			newEvent(
+				nft,
+				append([]*felt.Felt{token.TransferSelector, sender, recipient}, u256(1)...),
+				nil,
+			),
+			newEvent(
+				usdc,
+				[]*felt.Felt{token.ApprovalSelector, sender, recipient},
+				u256(5),
+			),
+			newEvent(
+				token.STRKAddress,
+				[]*felt.Felt{token.TransferSelector},
+				append([]*felt.Felt{sender, sequencer}, u256(2)...),
+			),
+		},
+	}
+
+	assert.Equal(t, []token.BalanceChange{
+		{Token: usdc, Account: sender, Delta: big.NewInt(-70)},
+		{Token: usdc, Account: recipient, Delta: big.NewInt(70)},
+		{Token: token.STRKAddress, Account: sender, Delta: big.NewInt(-2)},
+		{Token: token.STRKAddress, Account: sequencer, Delta: big.NewInt(2)},
+	}, token.BalanceChanges(receipt))
+
+	assert.Equal(t, []token.BalanceChange{
+		{Token: token.STRKAddress, Account: sender, Delta: big.NewInt(-2)},
+		{Token: token.STRKAddress, Account: sequencer, Delta: big.NewInt(2)},
+	}, token.BalanceChanges(receipt, token.STRKAddress))
+}
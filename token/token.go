@@ -0,0 +1,319 @@
+// Package token provides typed clients for the ERC-20 and ERC-721 token
+// standards, handling both the snake_case and camelCase entrypoints, and helpers
+// to decode their `Transfer` and `Approval` events.
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/contracts/contract"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+var (
+	ErrNoAccount     = errors.New("the token client has no account to send transactions")
+	ErrInvalidResult = errors.New("invalid token call result")
+	ErrInvalidAmount = errors.New("invalid token amount")
+	ErrInvalidEvent  = errors.New("invalid token event")
+)
+
+//nolint:lll // The links would be unclickable if we break the line.
+var (
+	// The STRK token address, on both Mainnet and Sepolia.
+	// https://voyager.online/contract/0x04718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d
+	STRKAddress, _ = new(
+		felt.Felt,
+	).SetString("0x04718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d")
+	// The ETH token address, on both Mainnet and Sepolia.
+	// https://voyager.online/contract/0x049d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7
+	ETHAddress, _ = new(
+		felt.Felt,
+	).SetString("0x049d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+)
+
+// EntrypointStyle is the naming style of the entrypoints of a token contract.
+type EntrypointStyle int
+
+const (
+	// The style is detected on the first call whose entrypoint name differs
+	// between the styles: the snake_case entrypoint is tried first, then the
+	// camelCase one. For a transaction, it is resolved from the class ABI.
+	StyleUnknown EntrypointStyle = iota
+	// snake_case entrypoints, e.g. `balance_of`, used by the recent Cairo tokens
+	StyleSnakeCase
+	// camelCase entrypoints, e.g. `balanceOf`, used by the Cairo 0 tokens
+	StyleCamelCase
+)
+
+// tokenContract holds what the ERC-20 and ERC-721 clients have in common: the
+// token contract, and the detection of its entrypoint style.
+type tokenContract struct {
+	Address  *felt.Felt
+	Provider rpc.RPCProvider
+	// The account used to send the transactions. Nil for a read-only client.
+	Account account.AccountInterface
+	// The block used by the calls. Defaults to the latest block.
+	BlockID rpc.BlockID
+
+	mu    sync.Mutex
+	style EntrypointStyle
+}
+
+func newTokenContract(
+	address *felt.Felt,
+	provider rpc.RPCProvider,
+	acc account.AccountInterface,
+) *tokenContract {
+	return &tokenContract{
+		Address:  address,
+		Provider: provider,
+		Account:  acc,
+		BlockID:  rpc.WithBlockTag(rpc.BlockTagLatest),
+	}
+}
+
+// Style returns the entrypoint style of the token contract, StyleUnknown if it
+// has not been detected yet.
+func (token *tokenContract) Style() EntrypointStyle {
+	token.mu.Lock()
+	defer token.mu.Unlock()
+
+	return token.style
+}
+
+// SetStyle sets the entrypoint style of the token contract, skipping its detection.
+func (token *tokenContract) SetStyle(style EntrypointStyle) {
+	token.mu.Lock()
+	defer token.mu.Unlock()
+
+	token.style = style
+}
+
+// entrypoints returns the entrypoint names to try, in order, for a function.
+func (token *tokenContract) entrypoints(snake, camel string) []string {
+	if snake == camel {
+		return []string{snake}
+	}
+	switch token.Style() {
+	case StyleSnakeCase:
+		return []string{snake}
+	case StyleCamelCase:
+		return []string{camel}
+	default:
+		return []string{snake, camel}
+	}
+}
+
+// setStyleFrom records the style of a successfully called entrypoint.
+func (token *tokenContract) setStyleFrom(entrypoint, snake, camel string) {
+	if snake == camel {
+		return
+	}
+	if entrypoint == snake {
+		token.SetStyle(StyleSnakeCase)
+	} else {
+		token.SetStyle(StyleCamelCase)
+	}
+}
+
+// call calls a function of the token, trying both entrypoint styles if needed.
+func (token *tokenContract) call(
+	ctx context.Context,
+	snake, camel string,
+	calldata ...*felt.Felt,
+) ([]*felt.Felt, error) {
+	if calldata == nil {
+		calldata = []*felt.Felt{}
+	}
+
+	var err error
+	for _, entrypoint := range token.entrypoints(snake, camel) {
+		var result []*felt.Felt
+		result, err = token.Provider.Call(ctx, rpc.FunctionCall{
+			ContractAddress:    token.Address,
+			EntryPointSelector: utils.GetSelectorFromNameFelt(entrypoint),
+			Calldata:           calldata,
+		}, token.BlockID)
+		if err == nil {
+			token.setStyleFrom(entrypoint, snake, camel)
+
+			return result, nil
+		}
+		if !isEntrypointNotFound(err) {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+// functionCall builds the call of a function of the token, using the detected
+// entrypoint style, or snake_case if unknown.
+func (token *tokenContract) functionCall(
+	snake, camel string,
+	calldata ...*felt.Felt,
+) rpc.InvokeFunctionCall {
+	return rpc.InvokeFunctionCall{
+		ContractAddress: token.Address,
+		FunctionName:    token.entrypoints(snake, camel)[0],
+		CallData:        calldata,
+	}
+}
+
+// invoke sends a transaction calling a function of the token. If the
+// entrypoint style is unknown, it is resolved from the ABI of the token class
+// first, so no transaction is sent with the wrong entrypoint. If the ABI
+// declares neither entrypoint, e.g. for a Cairo 0 proxy, both styles are tried,
+// falling back to the other one only if the fee estimation fails with a
+// missing entrypoint.
+func (token *tokenContract) invoke(
+	ctx context.Context,
+	opts *account.TxnOptions,
+	snake, camel string,
+	calldata ...*felt.Felt,
+) (rpc.AddInvokeTransactionResponse, error) {
+	if token.Account == nil {
+		return rpc.AddInvokeTransactionResponse{}, ErrNoAccount
+	}
+	if snake != camel && token.Style() == StyleUnknown {
+		if err := token.resolveStyle(ctx, snake, camel); err != nil {
+			return rpc.AddInvokeTransactionResponse{}, err
+		}
+	}
+
+	var resp rpc.AddInvokeTransactionResponse
+	var err error
+	for _, entrypoint := range token.entrypoints(snake, camel) {
+		resp, err = token.Account.BuildAndSendInvokeTxn(ctx, []rpc.InvokeFunctionCall{{
+			ContractAddress: token.Address,
+			FunctionName:    entrypoint,
+			CallData:        calldata,
+		}}, opts)
+		if err == nil {
+			token.setStyleFrom(entrypoint, snake, camel)
+
+			return resp, nil
+		}
+		if !isEntrypointNotFound(err) {
+			return resp, err
+		}
+	}
+
+	return resp, err
+}
+
+// resolveStyle sets the entrypoint style of the token from the ABI of its
+// class, preferring snake_case if both entrypoints are declared. The style is
+// left unknown if the ABI declares neither.
+func (token *tokenContract) resolveStyle(ctx context.Context, snake, camel string) error {
+	class, err := token.Provider.ClassAt(ctx, token.BlockID, token.Address)
+	if err != nil {
+		return err
+	}
+	abi, err := contract.ParseClassABI(class)
+	if err != nil {
+		return err
+	}
+
+	if _, err := abi.Function(snake); err == nil {
+		token.SetStyle(StyleSnakeCase)
+	} else if _, err := abi.Function(camel); err == nil {
+		token.SetStyle(StyleCamelCase)
+	}
+
+	return nil
+}
+
+// isEntrypointNotFound returns true if the error is caused by a missing entrypoint.
+func isEntrypointNotFound(err error) bool {
+	var rpcErr *rpc.RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrEntrypointNotFound.Code {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+
+	return strings.Contains(msg, "entrypoint_not_found") ||
+		(strings.Contains(msg, "entry point") && strings.Contains(msg, "not found"))
+}
+
+// resultU256 decodes a call result holding a single u256.
+func resultU256(result []*felt.Felt) (*big.Int, error) {
+	//nolint:mnd // a u256 is made of two felts
+	if len(result) != 2 {
+		return nil, fmt.Errorf("%w: expected a u256, got %d felts", ErrInvalidResult, len(result))
+	}
+
+	return FeltsToU256(result[0], result[1])
+}
+
+// resultFelt decodes a call result holding a single felt.
+func resultFelt(result []*felt.Felt) (*felt.Felt, error) {
+	if len(result) != 1 {
+		return nil, fmt.Errorf("%w: expected a felt, got %d felts", ErrInvalidResult, len(result))
+	}
+
+	return result[0], nil
+}
+
+// resultString decodes a call result holding a string: a short string (felt252)
+// for the older tokens, or a ByteArray for the recent ones.
+func resultString(result []*felt.Felt) (string, error) {
+	if len(result) == 1 {
+		return utils.HexToShortStr(result[0].String()), nil
+	}
+
+	str, err := utils.ByteArrFeltToString(result)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidResult, err)
+	}
+
+	return str, nil
+}
+
+// U256ToFelts splits a u256 value into its low and high 128-bit felts, as
+// serialised in the calldata.
+//
+// Parameters:
+//   - value: the u256 value
+//
+// Returns:
+//   - []*felt.Felt: the low and high felts
+//   - error: ErrInvalidAmount if the value is negative or overflows a u256
+func U256ToFelts(value *big.Int) ([]*felt.Felt, error) {
+	if value == nil {
+		return nil, fmt.Errorf("%w: nil is not a valid u256", ErrInvalidAmount)
+	}
+	felts, err := utils.NewAmount(value, 0).ToU256Felts()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+	}
+
+	return felts, nil
+}
+
+// FeltsToU256 joins the low and high 128-bit felts of a u256.
+//
+// Parameters:
+//   - low: the 128 least significant bits
+//   - high: the 128 most significant bits
+//
+// Returns:
+//   - *big.Int: the u256 value
+//   - error: ErrInvalidResult if a felt doesn't fit in 128 bits
+func FeltsToU256(low, high *felt.Felt) (*big.Int, error) {
+	amount, err := utils.AmountFromU256(low, high, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidResult, err)
+	}
+
+	return amount.Value, nil
+}
@@ -0,0 +1,333 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	internalUtils "github.com/NethermindEth/starknet.go/internal/utils"
+)
+
+const (
+	// The number of decimals of ETH: 1 ETH = 10^18 wei
+	ETHDecimals uint8 = 18
+	// The number of decimals of STRK: 1 STRK = 10^18 fri
+	STRKDecimals uint8 = 18
+)
+
+var (
+	ErrInvalidAmount  = errors.New("invalid amount")
+	ErrAmountOverflow = errors.New("amount overflow")
+	ErrPrecisionLoss  = errors.New("amount precision loss")
+)
+
+// Amount is an exact token amount: an integer number of base units (e.g. wei
+// or fri) together with the number of decimals of the token. Unlike the
+// float64 conversions, it never loses precision.
+//
+// The zero value is a zero amount with no decimals. The methods never modify
+// the amount they are called on.
+type Amount struct {
+	// The amount in base units. Nil is zero.
+	Value *big.Int
+	// The number of decimals of the token, e.g. 18 for ETH and STRK
+	Decimals uint8
+}
+
+// NewAmount creates an amount from a value in base units.
+//
+// Parameters:
+//   - value: the amount in base units. It is copied.
+//   - decimals: the number of decimals of the token
+//
+// Returns:
+//   - Amount: the amount
+func NewAmount(value *big.Int, decimals uint8) Amount {
+	return Amount{Value: new(big.Int).Set(bigOrZero(value)), Decimals: decimals}
+}
+
+// ParseAmount parses a human readable decimal string, e.g. "1.5", into an
+// amount.
+//
+// Parameters:
+//   - amount: the decimal amount in token units, optionally negative
+//   - decimals: the number of decimals of the token
+//
+// Returns:
+//   - Amount: the amount, e.g. 1500000000000000000 base units for "1.5" with 18 decimals
+//   - error: ErrInvalidAmount if the string is not a decimal number, or
+//     ErrPrecisionLoss if it has more fractional digits than the token decimals
+func ParseAmount(amount string, decimals uint8) (Amount, error) {
+	str := strings.TrimSpace(amount)
+	negative := strings.HasPrefix(str, "-")
+	str = strings.TrimPrefix(str, "-")
+
+	intPart, fracPart, _ := strings.Cut(str, ".")
+	isNotDigit := func(r rune) bool { return r < '0' || r > '9' }
+	if (intPart == "" && fracPart == "") ||
+		strings.ContainsFunc(intPart+fracPart, isNotDigit) {
+		return Amount{}, fmt.Errorf("%w: '%s'", ErrInvalidAmount, amount)
+	}
+	// the trailing zeros don't carry precision
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > int(decimals) {
+		return Amount{}, fmt.Errorf(
+			"%w: '%s' has more than %d decimals",
+			ErrPrecisionLoss,
+			amount,
+			decimals,
+		)
+	}
+	digits := intPart + fracPart + strings.Repeat("0", int(decimals)-len(fracPart))
+	value, _ := new(big.Int).SetString(digits, 10) //nolint:mnd // base 10
+	if negative {
+		value.Neg(value)
+	}
+
+	return Amount{Value: value, Decimals: decimals}, nil
+}
+
+// AmountFromFelt creates an amount from a felt holding a value in base units,
+// e.g. a fee in wei or fri.
+//
+// Parameters:
+//   - value: the amount in base units
+//   - decimals: the number of decimals of the token
+//
+// Returns:
+//   - Amount: the amount
+func AmountFromFelt(value *felt.Felt, decimals uint8) Amount {
+	if value == nil {
+		return Amount{Value: new(big.Int), Decimals: decimals}
+	}
+
+	return Amount{Value: value.BigInt(new(big.Int)), Decimals: decimals}
+}
+
+// AmountFromU256 creates an amount from the two felts of a Cairo u256, as
+// returned by the `balance_of` function of the ERC-20 tokens.
+//
+// Parameters:
+//   - low: the low 128 bits
+//   - high: the high 128 bits
+//   - decimals: the number of decimals of the token
+//
+// Returns:
+//   - Amount: the amount
+//   - error: ErrInvalidAmount if a felt doesn't fit in 128 bits
+func AmountFromU256(low, high *felt.Felt, decimals uint8) (Amount, error) {
+	value, err := internalUtils.U256FeltsToBigInt(low, high)
+	if err != nil {
+		return Amount{}, fmt.Errorf("%w: %w", ErrInvalidAmount, err)
+	}
+
+	return Amount{Value: value, Decimals: decimals}, nil
+}
+
+// String returns the amount as a human readable decimal string in token units,
+// without trailing zeros, e.g. "1.5" for 1500000000000000000 base units with
+// 18 decimals.
+func (a Amount) String() string {
+	value := bigOrZero(a.Value)
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+	}
+
+	digits := new(big.Int).Abs(value).String()
+	if a.Decimals == 0 {
+		return sign + digits
+	}
+	if len(digits) <= int(a.Decimals) {
+		digits = strings.Repeat("0", int(a.Decimals)-len(digits)+1) + digits
+	}
+	intPart := digits[:len(digits)-int(a.Decimals)]
+	fracPart := strings.TrimRight(digits[len(digits)-int(a.Decimals):], "0")
+	if fracPart == "" {
+		return sign + intPart
+	}
+
+	return sign + intPart + "." + fracPart
+}
+
+// BaseUnits returns a copy of the amount in base units.
+func (a Amount) BaseUnits() *big.Int {
+	return new(big.Int).Set(bigOrZero(a.Value))
+}
+
+// Rescale converts the amount to another number of decimals.
+//
+// Parameters:
+//   - decimals: the new number of decimals
+//
+// Returns:
+//   - Amount: the rescaled amount
+//   - error: ErrPrecisionLoss if decreasing the decimals would truncate the amount
+func (a Amount) Rescale(decimals uint8) (Amount, error) {
+	value := a.BaseUnits()
+	switch {
+	case decimals > a.Decimals:
+		value.Mul(value, pow10(decimals-a.Decimals))
+	case decimals < a.Decimals:
+		quo, rem := new(big.Int).QuoRem(value, pow10(a.Decimals-decimals), new(big.Int))
+		if rem.Sign() != 0 {
+			return Amount{}, fmt.Errorf(
+				"%w: %s can't be represented with %d decimals",
+				ErrPrecisionLoss,
+				a,
+				decimals,
+			)
+		}
+		value = quo
+	}
+
+	return Amount{Value: value, Decimals: decimals}, nil
+}
+
+// Add returns the sum of two amounts. If their decimals differ, the result
+// has the larger number of decimals.
+func (a Amount) Add(b Amount) Amount {
+	x, y := alignAmounts(a, b)
+	x.Value.Add(x.Value, y.Value)
+
+	return x
+}
+
+// Sub returns the difference of two amounts, which can be negative. If their
+// decimals differ, the result has the larger number of decimals.
+func (a Amount) Sub(b Amount) Amount {
+	x, y := alignAmounts(a, b)
+	x.Value.Sub(x.Value, y.Value)
+
+	return x
+}
+
+// Mul returns the amount multiplied by an integer.
+func (a Amount) Mul(n *big.Int) Amount {
+	value := a.BaseUnits()
+
+	return Amount{Value: value.Mul(value, bigOrZero(n)), Decimals: a.Decimals}
+}
+
+// MulRat returns the amount multiplied by a rational number, e.g. 3/2 for a
+// 1.5 fee multiplier, rounded down to a whole number of base units.
+func (a Amount) MulRat(r *big.Rat) Amount {
+	value := a.BaseUnits()
+	value.Mul(value, r.Num())
+	// floor division, consistent for negative values
+	value.Div(value, r.Denom())
+
+	return Amount{Value: value, Decimals: a.Decimals}
+}
+
+// Cmp compares two amounts, whatever their decimals.
+//
+// Returns:
+//   - int: -1 if a < b, 0 if a == b, +1 if a > b
+func (a Amount) Cmp(b Amount) int {
+	x, y := alignAmounts(a, b)
+
+	return x.Value.Cmp(y.Value)
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of the amount.
+func (a Amount) Sign() int {
+	return bigOrZero(a.Value).Sign()
+}
+
+// IsZero returns true if the amount is zero.
+func (a Amount) IsZero() bool {
+	return a.Sign() == 0
+}
+
+// ToFelt converts the amount in base units to a felt.
+//
+// Returns:
+//   - *felt.Felt: the amount in base units
+//   - error: ErrAmountOverflow if the amount is negative or doesn't fit in a felt
+func (a Amount) ToFelt() (*felt.Felt, error) {
+	value := bigOrZero(a.Value)
+	if value.Sign() < 0 {
+		return nil, fmt.Errorf("%w: %s is negative", ErrAmountOverflow, a)
+	}
+	result, err := new(felt.Felt).SetString(fmt.Sprintf("%#x", value))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAmountOverflow, err)
+	}
+
+	return result, nil
+}
+
+// ToU256Felts converts the amount in base units to the two felts of a Cairo
+// u256, e.g. for the calldata of an ERC-20 `transfer`.
+//
+// Returns:
+//   - []*felt.Felt: the low and high 128 bits
+//   - error: ErrAmountOverflow if the amount is negative or doesn't fit in 256 bits
+func (a Amount) ToU256Felts() ([]*felt.Felt, error) {
+	felts, err := internalUtils.BigIntToU256Felts(bigOrZero(a.Value))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is not a valid u256", ErrAmountOverflow, a)
+	}
+
+	return felts, nil
+}
+
+// amountJSON is the JSON representation of an Amount. The value is a decimal
+// string in base units, to be read exactly by any JSON decoder.
+type amountJSON struct {
+	Value    string `json:"value"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// MarshalJSON marshals the amount as `{"value": "<base units>", "decimals": n}`.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(amountJSON{Value: bigOrZero(a.Value).String(), Decimals: a.Decimals})
+}
+
+// UnmarshalJSON unmarshals an amount marshalled with MarshalJSON. The value can
+// also be a JSON number or a hexadecimal string.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Value    json.RawMessage `json:"value"`
+		Decimals uint8           `json:"decimals"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	str := strings.Trim(string(raw.Value), `"`)
+	value, ok := new(big.Int).SetString(str, 0)
+	if !ok {
+		return fmt.Errorf("%w: '%s'", ErrInvalidAmount, raw.Value)
+	}
+	a.Value = value
+	a.Decimals = raw.Decimals
+
+	return nil
+}
+
+// alignAmounts returns copies of two amounts with the same number of decimals.
+func alignAmounts(a, b Amount) (Amount, Amount) {
+	decimals := max(a.Decimals, b.Decimals)
+	// increasing the decimals never loses precision
+	x, _ := a.Rescale(decimals)
+	y, _ := b.Rescale(decimals)
+
+	return x, y
+}
+
+func bigOrZero(value *big.Int) *big.Int {
+	if value == nil {
+		return new(big.Int)
+	}
+
+	return value
+}
+
+func pow10(n uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil) //nolint:mnd // base 10
+}
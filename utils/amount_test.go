@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bigFromString is a test helper parsing a decimal big.Int.
+func bigFromString(t *testing.T, str string) *big.Int {
+	t.Helper()
+
+	value, ok := new(big.Int).SetString(str, 10)
+	require.True(t, ok)
+
+	return value
+}
+
+func TestParseAmount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input       string
+		decimals    uint8
+		expected    string
+		formatted   string
+		expectedErr error
+	}{
+		{input: "1.5", decimals: 18, expected: "1500000000000000000", formatted: "1.5"},
+		// above 2^53 wei, where the float64 conversions lose precision
+		{
+			input:     "12345678.123456789012345678",
+			decimals:  18,
+			expected:  "12345678123456789012345678",
+			formatted: "12345678.123456789012345678",
+		},
+		{input: "0.000001", decimals: 6, expected: "1", formatted: "0.000001"},
+		{input: "-12.340", decimals: 2, expected: "-1234", formatted: "-12.34"},
+		{input: " 42 ", decimals: 3, expected: "42000", formatted: "42"},
+		{input: ".5", decimals: 1, expected: "5", formatted: "0.5"},
+		{input: "7", decimals: 0, expected: "7", formatted: "7"},
+		{input: "1.234", decimals: 2, expectedErr: ErrPrecisionLoss},
+		{input: "", decimals: 2, expectedErr: ErrInvalidAmount},
+		{input: ".", decimals: 2, expectedErr: ErrInvalidAmount},
+		{input: "1e3", decimals: 2, expectedErr: ErrInvalidAmount},
+		{input: "0x10", decimals: 2, expectedErr: ErrInvalidAmount},
+		{input: "1.2.3", decimals: 2, expectedErr: ErrInvalidAmount},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			t.Parallel()
+
+			amount, err := ParseAmount(test.input, test.decimals)
+			if test.expectedErr != nil {
+				require.ErrorIs(t, err, test.expectedErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, amount.Value.String())
+			assert.Equal(t, test.decimals, amount.Decimals)
+			assert.Equal(t, test.formatted, amount.String())
+		})
+	}
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	t.Parallel()
+
+	ethAmount, err := ParseAmount("1.5", ETHDecimals)
+	require.NoError(t, err)
+	usdcAmount, err := ParseAmount("0.25", 6)
+	require.NoError(t, err)
+
+	sum := ethAmount.Add(usdcAmount)
+	assert.Equal(t, ETHDecimals, sum.Decimals)
+	assert.Equal(t, "1.75", sum.String())
+	// the operands are not modified
+	assert.Equal(t, "1.5", ethAmount.String())
+	assert.Equal(t, "0.25", usdcAmount.String())
+
+	assert.Equal(t, "-1.25", usdcAmount.Sub(ethAmount).String())
+	assert.Equal(t, "4.5", ethAmount.Mul(big.NewInt(3)).String())
+	assert.Equal(t, "0.375", usdcAmount.MulRat(big.NewRat(3, 2)).String())
+
+	assert.Equal(t, 1, ethAmount.Cmp(usdcAmount))
+	assert.Equal(t, -1, usdcAmount.Cmp(ethAmount))
+	assert.Equal(t, 0, sum.Sub(usdcAmount).Cmp(ethAmount))
+	assert.True(t, Amount{}.IsZero())
+	assert.Equal(t, -1, usdcAmount.Sub(ethAmount).Sign())
+
+	rescaled, err := ethAmount.Rescale(1)
+	require.NoError(t, err)
+	assert.Equal(t, Amount{Value: big.NewInt(15), Decimals: 1}, rescaled)
+	_, err = usdcAmount.Rescale(1)
+	require.ErrorIs(t, err, ErrPrecisionLoss)
+}
+
+func TestAmountFelts(t *testing.T) {
+	t.Parallel()
+
+	// 2^128 + 5 base units
+	value := new(big.Int).Lsh(big.NewInt(1), 128)
+	value.Add(value, big.NewInt(5))
+	amount := NewAmount(value, STRKDecimals)
+
+	feltValue, err := amount.ToFelt()
+	require.NoError(t, err)
+	assert.Equal(t, value, feltValue.BigInt(new(big.Int)))
+	assert.Equal(t, amount, AmountFromFelt(feltValue, STRKDecimals))
+
+	u256, err := amount.ToU256Felts()
+	require.NoError(t, err)
+	assert.Equal(t, []*felt.Felt{new(felt.Felt).SetUint64(5), new(felt.Felt).SetUint64(1)}, u256)
+	fromU256, err := AmountFromU256(u256[0], u256[1], STRKDecimals)
+	require.NoError(t, err)
+	assert.Equal(t, 0, amount.Cmp(fromU256))
+
+	_, err = AmountFromU256(feltValue, new(felt.Felt), STRKDecimals)
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	negative := NewAmount(big.NewInt(-1), STRKDecimals)
+	_, err = negative.ToFelt()
+	require.ErrorIs(t, err, ErrAmountOverflow)
+	_, err = negative.ToU256Felts()
+	require.ErrorIs(t, err, ErrAmountOverflow)
+
+	tooLarge := NewAmount(new(big.Int).Lsh(big.NewInt(1), 252), STRKDecimals)
+	_, err = tooLarge.ToFelt()
+	require.ErrorIs(t, err, ErrAmountOverflow)
+	_, err = tooLarge.ToU256Felts()
+	require.NoError(t, err)
+}
+
+func TestAmountJSON(t *testing.T) {
+	t.Parallel()
+
+	amount := NewAmount(bigFromString(t, "12345678123456789012345678"), ETHDecimals)
+	data, err := json.Marshal(amount)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"value":"12345678123456789012345678","decimals":18}`, string(data))
+
+	var decoded Amount
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, amount, decoded)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"value":"0x10","decimals":6}`), &decoded))
+	assert.Equal(t, "0.000016", decoded.String())
+
+	err = json.Unmarshal([]byte(`{"value":"1.5","decimals":6}`), &decoded)
+	require.ErrorIs(t, err, ErrInvalidAmount)
+}
+
+func TestResBoundsMapToOverallFeeAmount(t *testing.T) {
+	t.Parallel()
+
+	resBounds := &rpc.ResourceBoundsMapping{
+		L1Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		L1DataGas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		// 10^9 * 10^10 = 10^19 fri
+		L2Gas: rpc.ResourceBounds{MaxAmount: "0x3b9aca00", MaxPricePerUnit: "0x2540be400"},
+	}
+
+	fee, err := ResBoundsMapToOverallFeeAmount(resBounds, 1, "0x0")
+	require.NoError(t, err)
+	assert.Equal(t, STRKDecimals, fee.Decimals)
+	assert.Equal(t, "10", fee.String())
+
+	_, err = ResBoundsMapToOverallFeeAmount(nil, 1, "0x0")
+	require.Error(t, err)
+}
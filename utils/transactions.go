@@ -500,6 +500,31 @@ func ResBoundsMapToOverallFee(
 	return new(felt.Felt).SetString(fmt.Sprintf("%#x", overallFeeInt))
 }
 
+// ResBoundsMapToOverallFeeAmount is like ResBoundsMapToOverallFee, but returns
+// the overall fee as an exact STRK amount.
+//
+// Parameters:
+//   - resBounds: The resource bounds to calculate the fee for
+//   - multiplier: Multiplier for max amount and max price per unit. Recommended to be 1.5,
+//     but at least greater than 0
+//   - tip: The tip amount in FRI in hexadecimal string format
+//
+// Returns:
+//   - Amount: The overall fee, in FRI with STRKDecimals
+//   - error: An error if any
+func ResBoundsMapToOverallFeeAmount(
+	resBounds *rpc.ResourceBoundsMapping,
+	multiplier float64,
+	tip rpc.U64,
+) (Amount, error) {
+	fee, err := ResBoundsMapToOverallFee(resBounds, multiplier, tip)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	return AmountFromFelt(fee, STRKDecimals), nil
+}
+
 // FillHexWithZeroes normalises a hex string to have a '0x' prefix and pads it with leading zeros
 // to a total length of 66 characters (including the '0x' prefix).
 func FillHexWithZeroes(hex string) string {
@@ -508,24 +533,34 @@ func FillHexWithZeroes(hex string) string {
 
 // WeiToETH converts a Wei amount to ETH
 // Returns the ETH value as a float64
+//
+// Deprecated: the float64 loses precision. Use AmountFromFelt(wei, ETHDecimals) instead.
 func WeiToETH(wei *felt.Felt) float64 {
 	return internalUtils.WeiToETH(wei)
 }
 
 // ETHToWei converts an ETH amount to Wei
 // Returns the Wei value as a *felt.Felt
+//
+// Deprecated: the float64 loses precision. Use ParseAmount(eth, ETHDecimals) and
+// Amount.ToFelt instead.
 func ETHToWei(eth float64) *felt.Felt {
 	return internalUtils.ETHToWei(eth)
 }
 
 // FRIToSTRK converts a FRI amount to STRK
 // Returns the STRK value as a float64
+//
+// Deprecated: the float64 loses precision. Use AmountFromFelt(fri, STRKDecimals) instead.
 func FRIToSTRK(fri *felt.Felt) float64 {
 	return internalUtils.WeiToETH(fri)
 }
 
 // STRKToFRI converts a STRK amount to FRI
 // Returns the FRI value as a *felt.Felt
+//
+// Deprecated: the float64 loses precision. Use ParseAmount(strk, STRKDecimals) and
+// Amount.ToFelt instead.
 func STRKToFRI(strk float64) *felt.Felt {
 	return internalUtils.ETHToWei(strk)
 }